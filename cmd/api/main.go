@@ -1,105 +1,391 @@
-package main
-
-import (
-	"fmt"
-	"log"
-
-	"healthcare-app/config"
-	"healthcare-app/internal/handlers"
-	"healthcare-app/internal/repositories"
-	"healthcare-app/internal/services"
-
-	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-	_ "healthcare-app/docs" // Generated Swagger docs
-)
-
-// @title           Healthcare Management API
-// @version         1.0
-// @description     API for healthcare management with receptionist and doctor portals
-// @host            localhost:8080
-// @BasePath        /api/v1
-
-func main() {
-	// Load configuration
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
-	}
-
-	// Initialize database
-	db, err := config.InitDB(cfg)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-
-	// Initialize repositories
-	userRepo := repositories.NewUserRepository(db)
-	patientRepo := repositories.NewPatientRepository(db)
-
-	// Initialize services
-	authService := services.NewAuthService(userRepo, cfg.JWTSecret)
-	userService := services.NewUserService(userRepo)
-	patientService := services.NewPatientService(patientRepo)
-
-	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(authService)
-	userHandler := handlers.NewUserHandler(userService)
-	patientHandler := handlers.NewPatientHandler(patientService)
-
-	// Set up the router
-	r := gin.Default()
-
-	// CORS middleware
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
-
-	// API v1 routes
-	v1 := r.Group("/api/v1")
-	{
-		// Auth routes
-		v1.POST("/login", authHandler.Login)
-
-		// User routes
-		v1.POST("/users", authHandler.RequireAuth(userHandler.CreateUser))
-		
-		// Patient routes - Receptionist access
-		receptionistRoutes := v1.Group("/patients")
-		receptionistRoutes.Use(authHandler.RequireAuth(authHandler.RequireReceptionist))
-		{
-			receptionistRoutes.POST("", patientHandler.CreatePatient)
-			receptionistRoutes.GET("", patientHandler.GetAllPatients)
-			receptionistRoutes.GET("/:id", patientHandler.GetPatient)
-			receptionistRoutes.PUT("/:id", patientHandler.UpdatePatient)
-			receptionistRoutes.DELETE("/:id", patientHandler.DeletePatient)
-		}
-
-		// Patient routes - Doctor access
-		doctorRoutes := v1.Group("/doctor/patients")
-		doctorRoutes.Use(authHandler.RequireAuth(authHandler.RequireDoctor))
-		{
-			doctorRoutes.GET("", patientHandler.GetAllPatients)
-			doctorRoutes.GET("/:id", patientHandler.GetPatient)
-			doctorRoutes.PUT("/:id/medical", patientHandler.UpdatePatientMedicalInfo)
-		}
-	}
-
-	// Swagger documentation
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Start the server
-	addr := fmt.Sprintf(":%d", cfg.ServerPort)
-	log.Printf("Server starting on %s", addr)
-	if err := r.Run(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
-	}
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"healthcare-app/config"
+	"healthcare-app/internal/handlers"
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/repositories"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	_ "healthcare-app/docs" // Generated Swagger docs
+)
+
+// @title           Healthcare Management API
+// @version         1.0
+// @description     API for healthcare management with receptionist and doctor portals
+// @host            localhost:8080
+// @BasePath        /api/v1
+
+func main() {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize database
+	db, err := config.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// Initialize repositories
+	userRepo := repositories.NewUserRepository(db)
+	patientRepo := repositories.NewPatientRepository(db)
+	sessionRepo := repositories.NewSessionRepository(db)
+	mfaRepo := repositories.NewMFARepository(db)
+	passwordResetRepo := repositories.NewPasswordResetRepository(db)
+	permissionRepo := repositories.NewPermissionRepository(db)
+	careTeamRepo := repositories.NewCareTeamRepository(db)
+	emergencyAccessRepo := repositories.NewEmergencyAccessRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	allergyRepo := repositories.NewAllergyRepository(db)
+	medicationRepo := repositories.NewMedicationRepository(db)
+	conditionRepo := repositories.NewConditionRepository(db)
+	vitalRepo := repositories.NewVitalRepository(db)
+	encounterRepo := repositories.NewEncounterRepository(db)
+	clinicalNoteRepo := repositories.NewClinicalNoteRepository(db)
+	scheduleRepo := repositories.NewScheduleRepository(db)
+	appointmentRepo := repositories.NewAppointmentRepository(db)
+	queueRepo := repositories.NewQueueRepository(db)
+	waitlistRepo := repositories.NewWaitlistRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+
+	var loginAttemptStore services.LoginAttemptStore = repositories.NewLoginAttemptRepository(db)
+	if cfg.LoginAttemptStore == "memory" {
+		loginAttemptStore = repositories.NewMemoryLoginAttemptStore()
+	}
+
+	// Load token signing keys; retired keys stay valid for one access token lifetime
+	keyManager, err := services.NewKeyManager(cfg.JWTKeysDir, cfg.JWTSigningAlgorithm, cfg.AccessTokenTTL)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	keyManager.StartRotation(cfg.JWTKeyRotationInterval, nil)
+
+	// Load the offline drug interaction dataset
+	interactionRules, err := services.LoadInteractionRules(cfg.InteractionsFile)
+	if err != nil {
+		log.Fatalf("Failed to load interaction dataset: %v", err)
+	}
+
+	// Load the ICD-10 code set
+	icd10Codes, err := services.LoadICD10Codes(cfg.ICD10File)
+	if err != nil {
+		log.Fatalf("Failed to load ICD-10 codes: %v", err)
+	}
+
+	// Load the clinic time zone that doctors' hours are given in
+	clinicLocation, err := time.LoadLocation(cfg.ClinicTimezone)
+	if err != nil {
+		log.Fatalf("Failed to load clinic time zone: %v", err)
+	}
+
+	// Initialize services
+	loginThrottle := services.NewLoginThrottle(loginAttemptStore, services.LockoutPolicy{
+		AccountThreshold: cfg.LockoutThreshold,
+		IPThreshold:      cfg.LockoutIPThreshold,
+		LockoutDuration:  cfg.LockoutDuration,
+		FailureWindow:    cfg.LoginFailureWindow,
+		BaseDelay:        cfg.LoginThrottleBaseDelay,
+		MaxDelay:         cfg.LoginThrottleMaxDelay,
+	})
+	mfaService := services.NewMFAService(userRepo, mfaRepo, cfg.MFAIssuer)
+	permissionService := services.NewPermissionService(permissionRepo)
+	if err := permissionService.SeedDefaults(); err != nil {
+		log.Fatalf("Failed to seed role permissions: %v", err)
+	}
+	authService := services.NewAuthService(userRepo, sessionRepo, mfaService, permissionService, loginThrottle, keyManager, cfg.AccessTokenTTL, cfg.RefreshTokenTTL)
+	userService := services.NewUserService(userRepo, sessionRepo, loginThrottle)
+	// Send email through SMTP and text messages through the SMS gateway when
+	// they are configured, otherwise write them to the notifier log
+	logNotifier := services.NewLogNotifier(cfg.NotifierLogFile)
+	var emailNotifier services.Notifier = logNotifier
+	if cfg.SMTPHost != "" {
+		emailNotifier = services.NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	}
+	var smsNotifier services.Notifier = logNotifier
+	if cfg.SMSGatewayURL != "" {
+		smsNotifier = services.NewHTTPSMSNotifier(cfg.SMSGatewayURL, cfg.SMSGatewayToken, cfg.SMSSender)
+	}
+	passwordService := services.NewPasswordService(userRepo, passwordResetRepo, sessionRepo, loginThrottle,
+		emailNotifier, cfg.PasswordResetTTL, cfg.PasswordResetURL)
+	patientService := services.NewPatientService(patientRepo, careTeamRepo, auditRepo)
+	careTeamService := services.NewCareTeamService(careTeamRepo, patientService, patientRepo, userRepo, auditRepo)
+	emergencyAccessService := services.NewEmergencyAccessService(emergencyAccessRepo, patientRepo, careTeamRepo, medicationRepo, auditRepo, cfg.EmergencyAccessTTL)
+	auditService := services.NewAuditService(auditRepo)
+	allergyService := services.NewAllergyService(allergyRepo, patientService, auditRepo)
+	encounterService := services.NewEncounterService(encounterRepo, patientService, userRepo, auditRepo)
+	interactionService := services.NewInteractionService(interactionRules)
+	medicationService := services.NewMedicationService(medicationRepo, allergyRepo, patientService, encounterService, userRepo, interactionService, auditRepo)
+	icd10Service := services.NewICD10Service(icd10Codes)
+	conditionService := services.NewConditionService(conditionRepo, patientService, encounterService, icd10Service, auditRepo)
+	vitalService := services.NewVitalService(vitalRepo, patientService, encounterService, auditRepo)
+	clinicalNoteService := services.NewClinicalNoteService(clinicalNoteRepo, patientService, encounterService, auditRepo)
+	scheduleService := services.NewScheduleService(scheduleRepo, appointmentRepo, waitlistRepo, userRepo, clinicLocation)
+	notificationService := services.NewNotificationService(notificationRepo, appointmentRepo, patientService, patientRepo, userRepo,
+		emailNotifier, smsNotifier, auditRepo, clinicLocation, cfg.AppointmentReminders, cfg.NotificationMaxAttempts)
+	waitlistService := services.NewWaitlistService(waitlistRepo, scheduleService, patientService, patientRepo, notificationService, auditRepo, cfg.WaitlistOfferTTL)
+	appointmentService := services.NewAppointmentService(appointmentRepo, scheduleService, patientService, waitlistService, auditRepo)
+	appointmentSeriesService := services.NewAppointmentSeriesService(appointmentRepo, scheduleService, patientService, waitlistService, auditRepo)
+	queueService := services.NewQueueService(queueRepo, appointmentRepo, scheduleService, patientService, services.NewQueueBroker(), auditRepo)
+
+	// Pass expired slot offers on to the next waitlisted patient
+	waitlistService.StartExpiry(time.Minute, nil)
+
+	// Queue appointment reminders and deliver the notification outbox
+	notificationService.StartDelivery(time.Minute, nil)
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(authService, emergencyAccessService)
+	mfaHandler := handlers.NewMFAHandler(mfaService)
+	passwordHandler := handlers.NewPasswordHandler(passwordService)
+	permissionHandler := handlers.NewPermissionHandler(permissionService)
+	userHandler := handlers.NewUserHandler(userService)
+	patientHandler := handlers.NewPatientHandler(patientService)
+	careTeamHandler := handlers.NewCareTeamHandler(careTeamService)
+	emergencyAccessHandler := handlers.NewEmergencyAccessHandler(emergencyAccessService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	allergyHandler := handlers.NewAllergyHandler(allergyService)
+	medicationHandler := handlers.NewMedicationHandler(medicationService)
+	conditionHandler := handlers.NewConditionHandler(conditionService)
+	vitalHandler := handlers.NewVitalHandler(vitalService)
+	encounterHandler := handlers.NewEncounterHandler(encounterService)
+	clinicalNoteHandler := handlers.NewClinicalNoteHandler(clinicalNoteService)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleService)
+	appointmentHandler := handlers.NewAppointmentHandler(appointmentService)
+	appointmentSeriesHandler := handlers.NewAppointmentSeriesHandler(appointmentSeriesService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
+	queueHandler := handlers.NewQueueHandler(queueService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+
+	// Set up the router
+	r := gin.Default()
+
+	// CORS middleware
+	r.Use(func(c *gin.Context) {
+		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, "+
+			handlers.AccessOverrideHeader+", "+handlers.EmergencyGrantHeader+", "+handlers.RequestIDHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, "+handlers.RequestIDHeader)
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+		c.Next()
+	})
+
+	// Request ID middleware
+	r.Use(handlers.RequestID())
+
+	// API v1 routes
+	v1 := r.Group("/api/v1")
+	{
+		// Auth routes
+		v1.POST("/login", authHandler.Login)
+		v1.POST("/login/mfa", authHandler.LoginMFA)
+		v1.POST("/token/refresh", authHandler.Refresh)
+		v1.POST("/logout", authHandler.RequireAuthAllowingPending(authHandler.Logout))
+
+		// Password routes
+		v1.POST("/me/password", authHandler.RequireAuthAllowingPending(passwordHandler.ChangePassword))
+		v1.POST("/password/forgot", passwordHandler.ForgotPassword)
+		v1.POST("/password/reset", passwordHandler.ResetPassword)
+
+		// MFA routes
+		v1.POST("/mfa/enroll", authHandler.RequireAuthAllowingPending(mfaHandler.Enroll))
+		v1.POST("/mfa/enroll/confirm", authHandler.RequireAuthAllowingPending(mfaHandler.ConfirmEnrollment))
+		v1.GET("/mfa/policies", authHandler.Authorize(models.PermUsersManage), mfaHandler.GetRolePolicies)
+		v1.PUT("/mfa/policies/:role", authHandler.Authorize(models.PermUsersManage), mfaHandler.UpdateRolePolicy)
+
+		// Role permission routes
+		v1.GET("/roles/permissions", authHandler.Authorize(models.PermUsersManage), permissionHandler.GetRolePermissions)
+		v1.PUT("/roles/:role/permissions", authHandler.Authorize(models.PermUsersManage), permissionHandler.UpdateRolePermissions)
+
+		// User routes
+		userRoutes := v1.Group("/users")
+		userRoutes.Use(authHandler.Authorize(models.PermUsersManage))
+		{
+			userRoutes.POST("", userHandler.CreateUser)
+			userRoutes.GET("", userHandler.GetAllUsers)
+			userRoutes.GET("/:id", userHandler.GetUser)
+			userRoutes.PUT("/:id", userHandler.UpdateUser)
+			userRoutes.DELETE("/:id", userHandler.DeleteUser)
+			userRoutes.POST("/:id/deactivate", userHandler.DeactivateUser)
+			userRoutes.POST("/:id/activate", userHandler.ActivateUser)
+			userRoutes.POST("/:id/unlock", userHandler.UnlockUser)
+			userRoutes.POST("/:id/force-password-change", passwordHandler.ForcePasswordChange)
+		}
+
+		// Patient routes
+		patientRoutes := v1.Group("/patients")
+		{
+			patientRoutes.POST("", authHandler.Authorize(models.PermPatientsWrite), patientHandler.CreatePatient)
+			patientRoutes.GET("", authHandler.Authorize(models.PermPatientsRead), patientHandler.GetAllPatients)
+			patientRoutes.GET("/search", authHandler.Authorize(models.PermPatientsRead), patientHandler.SearchPatients)
+			patientRoutes.GET("/:id", authHandler.Authorize(models.PermPatientsRead), patientHandler.GetPatient)
+			patientRoutes.PUT("/:id", authHandler.Authorize(models.PermPatientsWrite), patientHandler.UpdatePatient)
+			patientRoutes.PUT("/:id/medical", authHandler.Authorize(models.PermMedicalWrite), patientHandler.UpdatePatientMedicalInfo)
+			patientRoutes.PATCH("/:id", authHandler.Authorize(models.PermPatientsWrite), patientHandler.PatchPatient)
+			patientRoutes.PATCH("/:id/medical", authHandler.Authorize(models.PermMedicalWrite), patientHandler.PatchPatientMedicalInfo)
+			patientRoutes.DELETE("/:id", authHandler.Authorize(models.PermPatientsDelete), patientHandler.DeletePatient)
+			patientRoutes.GET("/:id/history", authHandler.Authorize(models.PermPatientsRead), patientHandler.GetPatientHistory)
+			patientRoutes.POST("/:id/revert", authHandler.Authorize(models.PermPatientsWrite), patientHandler.RevertPatient)
+
+			// Care team routes
+			patientRoutes.GET("/:id/care-team", authHandler.Authorize(models.PermPatientsRead), careTeamHandler.GetCareTeam)
+			patientRoutes.POST("/:id/care-team", authHandler.Authorize(models.PermCareTeamManage), careTeamHandler.AssignClinician)
+			patientRoutes.DELETE("/:id/care-team/:assignmentId", authHandler.Authorize(models.PermCareTeamManage), careTeamHandler.UnassignClinician)
+
+			// Allergy routes
+			patientRoutes.GET("/:id/allergies", authHandler.Authorize(models.PermPatientsRead), allergyHandler.GetAllergies)
+			patientRoutes.GET("/:id/allergies/:allergyId", authHandler.Authorize(models.PermPatientsRead), allergyHandler.GetAllergy)
+			patientRoutes.POST("/:id/allergies", authHandler.Authorize(models.PermMedicalWrite), allergyHandler.CreateAllergy)
+			patientRoutes.PUT("/:id/allergies/:allergyId", authHandler.Authorize(models.PermMedicalWrite), allergyHandler.UpdateAllergy)
+			patientRoutes.DELETE("/:id/allergies/:allergyId", authHandler.Authorize(models.PermMedicalWrite), allergyHandler.DeleteAllergy)
+
+			// Problem list routes
+			patientRoutes.GET("/:id/conditions", authHandler.Authorize(models.PermPatientsRead), conditionHandler.GetConditions)
+			patientRoutes.GET("/:id/conditions/:conditionId", authHandler.Authorize(models.PermPatientsRead), conditionHandler.GetCondition)
+			patientRoutes.POST("/:id/conditions", authHandler.Authorize(models.PermMedicalWrite), conditionHandler.CreateCondition)
+			patientRoutes.PUT("/:id/conditions/:conditionId", authHandler.Authorize(models.PermMedicalWrite), conditionHandler.UpdateCondition)
+			patientRoutes.DELETE("/:id/conditions/:conditionId", authHandler.Authorize(models.PermMedicalWrite), conditionHandler.DeleteCondition)
+
+			// Vital sign routes
+			patientRoutes.GET("/:id/vitals", authHandler.Authorize(models.PermPatientsRead), vitalHandler.GetVitals)
+			patientRoutes.POST("/:id/vitals", authHandler.Authorize(models.PermMedicalWrite), vitalHandler.RecordVitals)
+
+			// Encounter routes
+			patientRoutes.GET("/:id/encounters", authHandler.Authorize(models.PermPatientsRead), encounterHandler.GetEncounters)
+			patientRoutes.GET("/:id/encounters/:encounterId", authHandler.Authorize(models.PermPatientsRead), encounterHandler.GetEncounter)
+			patientRoutes.POST("/:id/encounters", authHandler.Authorize(models.PermEncountersManage), encounterHandler.CreateEncounter)
+			patientRoutes.POST("/:id/encounters/:encounterId/check-in", authHandler.Authorize(models.PermEncountersManage), encounterHandler.CheckIn)
+			patientRoutes.POST("/:id/encounters/:encounterId/cancel", authHandler.Authorize(models.PermEncountersManage), encounterHandler.CancelEncounter)
+			patientRoutes.POST("/:id/encounters/:encounterId/start", authHandler.Authorize(models.PermMedicalWrite), encounterHandler.StartEncounter)
+			patientRoutes.POST("/:id/encounters/:encounterId/finish", authHandler.Authorize(models.PermMedicalWrite), encounterHandler.FinishEncounter)
+
+			// Clinical note routes
+			patientRoutes.GET("/:id/notes", authHandler.Authorize(models.PermNotesRead), clinicalNoteHandler.GetNotes)
+			patientRoutes.GET("/:id/notes/:noteId", authHandler.Authorize(models.PermNotesRead), clinicalNoteHandler.GetNote)
+			patientRoutes.GET("/:id/legacy-notes", authHandler.Authorize(models.PermNotesRead), clinicalNoteHandler.GetLegacyNotes)
+			patientRoutes.POST("/:id/notes", authHandler.Authorize(models.PermMedicalWrite), clinicalNoteHandler.CreateNote)
+			patientRoutes.PUT("/:id/notes/:noteId", authHandler.Authorize(models.PermMedicalWrite), clinicalNoteHandler.UpdateNote)
+			patientRoutes.POST("/:id/notes/:noteId/sign", authHandler.Authorize(models.PermMedicalWrite), clinicalNoteHandler.SignNote)
+			patientRoutes.POST("/:id/notes/:noteId/addenda", authHandler.Authorize(models.PermMedicalWrite), clinicalNoteHandler.AddAddendum)
+
+			// Contact preference routes
+			patientRoutes.GET("/:id/contact-preferences", authHandler.Authorize(models.PermPatientsRead), notificationHandler.GetContactPreference)
+			patientRoutes.PUT("/:id/contact-preferences", authHandler.Authorize(models.PermPatientsWrite), notificationHandler.UpdateContactPreference)
+
+			// Break-the-glass routes
+			patientRoutes.POST("/:id/emergency-access", authHandler.Authorize(models.PermPatientsEmergency), emergencyAccessHandler.BreakGlass)
+			patientRoutes.GET("/:id/emergency-summary", authHandler.Authorize(models.PermPatientsEmergency), emergencyAccessHandler.GetEmergencySummary)
+		}
+
+		// Medication routes across patients
+		v1.GET("/medications/active", authHandler.Authorize(models.PermPatientsRead), medicationHandler.GetActiveMedications)
+
+		// Standalone interaction check for the doctor portal
+		v1.POST("/doctor/interactions/check", authHandler.Authorize(models.PermMedicalWrite), medicationHandler.CheckInteractions)
+
+		// Doctor portal medication routes
+		doctorPatientRoutes := v1.Group("/doctor/patients")
+		{
+			doctorPatientRoutes.GET("/:id/medications", authHandler.Authorize(models.PermPatientsRead), medicationHandler.GetMedications)
+			doctorPatientRoutes.GET("/:id/medications/:medicationId", authHandler.Authorize(models.PermPatientsRead), medicationHandler.GetMedication)
+			doctorPatientRoutes.GET("/:id/medications/:medicationId/prescription", authHandler.Authorize(models.PermPatientsRead), medicationHandler.PrintPrescription)
+			doctorPatientRoutes.POST("/:id/medications", authHandler.Authorize(models.PermMedicalWrite), medicationHandler.CreateMedication)
+			doctorPatientRoutes.PUT("/:id/medications/:medicationId", authHandler.Authorize(models.PermMedicalWrite), medicationHandler.UpdateMedication)
+		}
+
+		// Doctor schedule routes; doctors may read their own schedule
+		doctorRoutes := v1.Group("/doctors")
+		{
+			doctorRoutes.GET("/:id/schedule", authHandler.RequireAuth(scheduleHandler.GetSchedule))
+			doctorRoutes.PUT("/:id/schedule", authHandler.Authorize(models.PermAppointmentsManage), scheduleHandler.SetWorkingHours)
+			doctorRoutes.POST("/:id/schedule/exceptions", authHandler.Authorize(models.PermAppointmentsManage), scheduleHandler.AddException)
+			doctorRoutes.DELETE("/:id/schedule/exceptions/:exceptionId", authHandler.Authorize(models.PermAppointmentsManage), scheduleHandler.DeleteException)
+			doctorRoutes.POST("/:id/leave", authHandler.Authorize(models.PermAppointmentsManage), scheduleHandler.AddLeave)
+			doctorRoutes.DELETE("/:id/leave/:leaveId", authHandler.Authorize(models.PermAppointmentsManage), scheduleHandler.DeleteLeave)
+			doctorRoutes.GET("/:id/availability", authHandler.RequireAuth(scheduleHandler.GetAvailability))
+		}
+
+		// Appointment routes
+		appointmentRoutes := v1.Group("/appointments")
+		appointmentRoutes.Use(authHandler.Authorize(models.PermAppointmentsManage))
+		{
+			appointmentRoutes.POST("", appointmentHandler.BookAppointment)
+			appointmentRoutes.GET("", appointmentHandler.GetAppointments)
+			appointmentRoutes.GET("/:id", appointmentHandler.GetAppointment)
+			appointmentRoutes.POST("/:id/reschedule", appointmentHandler.RescheduleAppointment)
+			appointmentRoutes.POST("/:id/cancel", appointmentHandler.CancelAppointment)
+		}
+		v1.GET("/doctor/appointments", authHandler.RequireAuth(appointmentHandler.GetMyAppointments))
+
+		// Recurring appointment routes; single occurrences use the appointment routes
+		seriesRoutes := v1.Group("/appointment-series")
+		seriesRoutes.Use(authHandler.Authorize(models.PermAppointmentsManage))
+		{
+			seriesRoutes.POST("", appointmentSeriesHandler.BookSeries)
+			seriesRoutes.GET("/:id", appointmentSeriesHandler.GetSeries)
+			seriesRoutes.POST("/:id/reschedule", appointmentSeriesHandler.RescheduleSeries)
+			seriesRoutes.POST("/:id/cancel", appointmentSeriesHandler.CancelSeries)
+		}
+
+		// Waitlist routes; slots freed by cancellations are offered automatically
+		waitlistRoutes := v1.Group("/waitlist")
+		waitlistRoutes.Use(authHandler.Authorize(models.PermAppointmentsManage))
+		{
+			waitlistRoutes.POST("", waitlistHandler.AddEntry)
+			waitlistRoutes.GET("", waitlistHandler.GetEntries)
+			waitlistRoutes.DELETE("/:id", waitlistHandler.RemoveEntry)
+			waitlistRoutes.GET("/offers", waitlistHandler.GetOffers)
+			waitlistRoutes.POST("/offers/:id/accept", waitlistHandler.AcceptOffer)
+			waitlistRoutes.POST("/offers/:id/decline", waitlistHandler.DeclineOffer)
+		}
+
+		// Front-desk check-in queue routes
+		queueRoutes := v1.Group("/queue")
+		{
+			queueRoutes.GET("", authHandler.Authorize(models.PermQueueRead), queueHandler.GetQueue)
+			queueRoutes.GET("/stream", authHandler.Authorize(models.PermQueueRead), queueHandler.StreamQueue)
+			queueRoutes.POST("", authHandler.Authorize(models.PermQueueManage), queueHandler.CheckIn)
+			queueRoutes.POST("/:id/call", authHandler.Authorize(models.PermQueueManage), queueHandler.CallPatient)
+			queueRoutes.POST("/:id/start", authHandler.Authorize(models.PermQueueManage), queueHandler.StartConsultation)
+			queueRoutes.POST("/:id/complete", authHandler.Authorize(models.PermQueueManage), queueHandler.CompleteConsultation)
+			queueRoutes.POST("/:id/assign", authHandler.Authorize(models.PermQueueManage), queueHandler.AssignDoctor)
+		}
+
+		// Notification outbox; messages are queued and delivered automatically
+		v1.GET("/notifications", authHandler.Authorize(models.PermAppointmentsManage), notificationHandler.GetNotifications)
+
+		// ICD-10 code lookup
+		v1.GET("/icd10", authHandler.Authorize(models.PermPatientsRead), conditionHandler.SearchICD10Codes)
+
+		// Compliance routes
+		v1.GET("/emergency-access", authHandler.Authorize(models.PermAuditRead), emergencyAccessHandler.GetEmergencyAccessReport)
+		v1.GET("/audit", authHandler.Authorize(models.PermAuditRead), auditHandler.GetAuditEvents)
+		v1.GET("/audit/verify", authHandler.Authorize(models.PermAuditRead), auditHandler.VerifyAuditChain)
+	}
+
+	// Public keys for verifying issued tokens
+	r.GET("/.well-known/jwks.json", authHandler.JWKS)
+
+	// Swagger documentation
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Start the server
+	addr := fmt.Sprintf(":%d", cfg.ServerPort)
+	log.Printf("Server starting on %s", addr)
+	if err := r.Run(addr); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
 } 
\ No newline at end of file
@@ -1,73 +1,264 @@
-package config
-
-import (
-	"fmt"
-	"os"
-	"strconv"
-
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
-	"healthcare-app/internal/models"
-)
-
-// Config holds all configuration for the application
-type Config struct {
-	DBHost     string
-	DBPort     int
-	DBUser     string
-	DBPassword string
-	DBName     string
-	JWTSecret  string
-	ServerPort int
-}
-
-// LoadConfig loads the configuration from environment variables
-func LoadConfig() (*Config, error) {
-	dbPort, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
-	if err != nil {
-		return nil, fmt.Errorf("invalid DB_PORT: %v", err)
-	}
-
-	serverPort, err := strconv.Atoi(getEnv("SERVER_PORT", "8080"))
-	if err != nil {
-		return nil, fmt.Errorf("invalid SERVER_PORT: %v", err)
-	}
-
-	return &Config{
-		DBHost:     getEnv("DB_HOST", "localhost"),
-		DBPort:     dbPort,
-		DBUser:     getEnv("DB_USER", "postgres"),
-		DBPassword: getEnv("DB_PASSWORD", "postgres"),
-		DBName:     getEnv("DB_NAME", "healthcare"),
-		JWTSecret:  getEnv("JWT_SECRET", "your-256-bit-secret"),
-		ServerPort: serverPort,
-	}, nil
-}
-
-// InitDB initializes the database connection
-func InitDB(cfg *Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		return nil, err
-	}
-
-	// Run migrations
-	err = db.AutoMigrate(&models.User{}, &models.Patient{})
-	if err != nil {
-		return nil, err
-	}
-
-	return db, nil
-}
-
-// getEnv gets an environment variable or returns a default value
-func getEnv(key, defaultValue string) string {
-	value, exists := os.LookupEnv(key)
-	if !exists {
-		return defaultValue
-	}
-	return value
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"healthcare-app/internal/models"
+)
+
+// Config holds all configuration for the application
+type Config struct {
+	DBHost     string
+	DBPort     int
+	DBUser     string
+	DBPassword string
+	DBName     string
+	ServerPort int
+
+	JWTSigningAlgorithm    string
+	JWTKeysDir             string
+	JWTKeyRotationInterval time.Duration
+
+	MFAIssuer string
+
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	LoginAttemptStore      string
+	LockoutThreshold       int
+	LockoutIPThreshold     int
+	LockoutDuration        time.Duration
+	LoginFailureWindow     time.Duration
+	LoginThrottleBaseDelay time.Duration
+	LoginThrottleMaxDelay  time.Duration
+
+	PasswordResetTTL time.Duration
+	PasswordResetURL string
+	NotifierLogFile  string
+
+	EmergencyAccessTTL time.Duration
+
+	InteractionsFile string
+	ICD10File        string
+
+	ClinicTimezone string
+
+	WaitlistOfferTTL time.Duration
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	SMSGatewayURL   string
+	SMSGatewayToken string
+	SMSSender       string
+
+	AppointmentReminders    []time.Duration
+	NotificationMaxAttempts int
+}
+
+// LoadConfig loads the configuration from environment variables
+func LoadConfig() (*Config, error) {
+	dbPort, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_PORT: %v", err)
+	}
+
+	serverPort, err := strconv.Atoi(getEnv("SERVER_PORT", "8080"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SERVER_PORT: %v", err)
+	}
+
+	accessTokenTTL, err := time.ParseDuration(getEnv("ACCESS_TOKEN_TTL", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ACCESS_TOKEN_TTL: %v", err)
+	}
+
+	refreshTokenTTL, err := time.ParseDuration(getEnv("REFRESH_TOKEN_TTL", "168h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REFRESH_TOKEN_TTL: %v", err)
+	}
+
+	keyRotationInterval, err := time.ParseDuration(getEnv("JWT_KEY_ROTATION_INTERVAL", "720h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEY_ROTATION_INTERVAL: %v", err)
+	}
+
+	lockoutThreshold, err := strconv.Atoi(getEnv("LOCKOUT_THRESHOLD", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOCKOUT_THRESHOLD: %v", err)
+	}
+
+	lockoutIPThreshold, err := strconv.Atoi(getEnv("LOCKOUT_IP_THRESHOLD", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOCKOUT_IP_THRESHOLD: %v", err)
+	}
+
+	lockoutDuration, err := time.ParseDuration(getEnv("LOCKOUT_DURATION", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOCKOUT_DURATION: %v", err)
+	}
+
+	loginFailureWindow, err := time.ParseDuration(getEnv("LOGIN_FAILURE_WINDOW", "15m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_FAILURE_WINDOW: %v", err)
+	}
+
+	throttleBaseDelay, err := time.ParseDuration(getEnv("LOGIN_THROTTLE_BASE_DELAY", "1s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_THROTTLE_BASE_DELAY: %v", err)
+	}
+
+	throttleMaxDelay, err := time.ParseDuration(getEnv("LOGIN_THROTTLE_MAX_DELAY", "30s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid LOGIN_THROTTLE_MAX_DELAY: %v", err)
+	}
+
+	passwordResetTTL, err := time.ParseDuration(getEnv("PASSWORD_RESET_TTL", "30m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PASSWORD_RESET_TTL: %v", err)
+	}
+
+	emergencyAccessTTL, err := time.ParseDuration(getEnv("EMERGENCY_ACCESS_TTL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EMERGENCY_ACCESS_TTL: %v", err)
+	}
+
+	waitlistOfferTTL, err := time.ParseDuration(getEnv("WAITLIST_OFFER_TTL", "2h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WAITLIST_OFFER_TTL: %v", err)
+	}
+
+	smtpPort, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %v", err)
+	}
+
+	appointmentReminders, err := parseDurations(getEnv("APPOINTMENT_REMINDERS", "24h,2h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid APPOINTMENT_REMINDERS: %v", err)
+	}
+
+	notificationMaxAttempts, err := strconv.Atoi(getEnv("NOTIFICATION_MAX_ATTEMPTS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NOTIFICATION_MAX_ATTEMPTS: %v", err)
+	}
+
+	return &Config{
+		DBHost:     getEnv("DB_HOST", "localhost"),
+		DBPort:     dbPort,
+		DBUser:     getEnv("DB_USER", "postgres"),
+		DBPassword: getEnv("DB_PASSWORD", "postgres"),
+		DBName:     getEnv("DB_NAME", "healthcare"),
+		ServerPort: serverPort,
+
+		JWTSigningAlgorithm:    getEnv("JWT_SIGNING_ALGORITHM", "RS256"),
+		JWTKeysDir:             getEnv("JWT_KEYS_DIR", "./keys"),
+		JWTKeyRotationInterval: keyRotationInterval,
+
+		MFAIssuer: getEnv("MFA_ISSUER", "Healthcare App"),
+
+		LoginAttemptStore:      getEnv("LOGIN_ATTEMPT_STORE", "postgres"),
+		LockoutThreshold:       lockoutThreshold,
+		LockoutIPThreshold:     lockoutIPThreshold,
+		LockoutDuration:        lockoutDuration,
+		LoginFailureWindow:     loginFailureWindow,
+		LoginThrottleBaseDelay: throttleBaseDelay,
+		LoginThrottleMaxDelay:  throttleMaxDelay,
+
+		PasswordResetTTL: passwordResetTTL,
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", ""),
+		NotifierLogFile:  getEnv("NOTIFIER_LOG_FILE", ""),
+
+		EmergencyAccessTTL: emergencyAccessTTL,
+
+		InteractionsFile: getEnv("INTERACTIONS_FILE", "./data/interactions.json"),
+		ICD10File:        getEnv("ICD10_FILE", "./data/icd10cm_codes.txt"),
+
+		ClinicTimezone: getEnv("CLINIC_TIMEZONE", "UTC"),
+
+		WaitlistOfferTTL: waitlistOfferTTL,
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     smtpPort,
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "no-reply@localhost"),
+
+		SMSGatewayURL:   getEnv("SMS_GATEWAY_URL", ""),
+		SMSGatewayToken: getEnv("SMS_GATEWAY_TOKEN", ""),
+		SMSSender:       getEnv("SMS_SENDER", ""),
+
+		AppointmentReminders:    appointmentReminders,
+		NotificationMaxAttempts: notificationMaxAttempts,
+
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+	}, nil
+}
+
+// InitDB initializes the database connection
+func InitDB(cfg *Config) (*gorm.DB, error) {
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Run migrations
+	err = db.AutoMigrate(&models.User{}, &models.Patient{}, &models.Session{}, &models.RefreshToken{},
+		&models.RecoveryCode{}, &models.RolePolicy{}, &models.LoginAttempt{},
+		&models.PasswordResetToken{}, &models.RolePermission{},
+		&models.CareTeamAssignment{}, &models.AccessOverride{}, &models.EmergencyAccessGrant{},
+		&models.AuditEvent{}, &models.PatientRevision{}, &models.Allergy{}, &models.Medication{},
+		&models.Condition{}, &models.Vital{}, &models.Encounter{},
+		&models.ClinicalNote{}, &models.NoteAddendum{}, &models.WorkingHours{},
+		&models.ScheduleException{}, &models.DoctorLeave{}, &models.AppointmentSeries{}, &models.Appointment{},
+		&models.QueueEntry{}, &models.WaitlistEntry{}, &models.SlotOffer{},
+		&models.Notification{}, &models.ContactPreference{})
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// parseDurations parses a comma-separated list of positive durations; an
+// empty list is allowed
+func parseDurations(value string) ([]time.Duration, error) {
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, err := time.ParseDuration(part)
+		if err != nil {
+			return nil, err
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("duration %s is not positive", part)
+		}
+		durations = append(durations, d)
+	}
+	return durations, nil
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+	return value
 } 
\ No newline at end of file
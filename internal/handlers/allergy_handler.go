@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AllergyHandler handles allergy requests
+type AllergyHandler struct {
+	allergyService *services.AllergyService
+}
+
+// NewAllergyHandler creates a new AllergyHandler
+func NewAllergyHandler(allergyService *services.AllergyService) *AllergyHandler {
+	return &AllergyHandler{
+		allergyService: allergyService,
+	}
+}
+
+// GetAllergies handles get allergies requests
+// @Summary Get allergies
+// @Description Get the structured allergy list of a patient
+// @Tags allergies
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.Allergy
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/allergies [get]
+func (h *AllergyHandler) GetAllergies(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	allergies, err := h.allergyService.GetAllergies(patientAccessor(c), uint(patientID))
+	if err != nil {
+		respondWithAllergyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, allergies)
+}
+
+// GetAllergy handles get allergy requests
+// @Summary Get allergy
+// @Description Get an allergy of a patient
+// @Tags allergies
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param allergyId path int true "Allergy ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Allergy
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/allergies/{allergyId} [get]
+func (h *AllergyHandler) GetAllergy(c *gin.Context) {
+	patientID, allergyID, ok := allergyParams(c)
+	if !ok {
+		return
+	}
+
+	allergy, err := h.allergyService.GetAllergy(patientAccessor(c), patientID, allergyID)
+	if err != nil {
+		respondWithAllergyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, allergy)
+}
+
+// CreateAllergy handles create allergy requests
+// @Summary Record allergy
+// @Description Record an allergy of a patient (requires medical:write)
+// @Tags allergies
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.AllergyRequest true "Allergy Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.Allergy
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/allergies [post]
+func (h *AllergyHandler) CreateAllergy(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.AllergyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	allergy, err := h.allergyService.CreateAllergy(patientAccessor(c), uint(patientID), req)
+	if err != nil {
+		respondWithAllergyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, allergy)
+}
+
+// UpdateAllergy handles update allergy requests
+// @Summary Update allergy
+// @Description Replace the details of an allergy of a patient (requires medical:write)
+// @Tags allergies
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param allergyId path int true "Allergy ID"
+// @Param request body models.AllergyRequest true "Allergy Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Allergy
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/allergies/{allergyId} [put]
+func (h *AllergyHandler) UpdateAllergy(c *gin.Context) {
+	patientID, allergyID, ok := allergyParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.AllergyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	allergy, err := h.allergyService.UpdateAllergy(patientAccessor(c), patientID, allergyID, req)
+	if err != nil {
+		respondWithAllergyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, allergy)
+}
+
+// DeleteAllergy handles delete allergy requests
+// @Summary Delete allergy
+// @Description Delete an allergy of a patient (requires medical:write)
+// @Tags allergies
+// @Param id path int true "Patient ID"
+// @Param allergyId path int true "Allergy ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/allergies/{allergyId} [delete]
+func (h *AllergyHandler) DeleteAllergy(c *gin.Context) {
+	patientID, allergyID, ok := allergyParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.allergyService.DeleteAllergy(patientAccessor(c), patientID, allergyID); err != nil {
+		respondWithAllergyError(c, err)
+		return
+	}
+
+	RespondWithSuccess(c, "Allergy deleted successfully", nil)
+}
+
+// allergyParams parses the patient and allergy IDs from the path
+func allergyParams(c *gin.Context) (patientID, allergyID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return 0, 0, false
+	}
+	aid, err := strconv.ParseUint(c.Param("allergyId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid allergy ID")
+		return 0, 0, false
+	}
+	return uint(id), uint(aid), true
+}
+
+// respondWithAllergyError maps allergy service errors to responses
+func respondWithAllergyError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrAllergyNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	}
+	RespondWithError(c, status, err.Error())
+}
@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppointmentHandler handles appointment requests
+type AppointmentHandler struct {
+	appointmentService *services.AppointmentService
+}
+
+// NewAppointmentHandler creates a new AppointmentHandler
+func NewAppointmentHandler(appointmentService *services.AppointmentService) *AppointmentHandler {
+	return &AppointmentHandler{
+		appointmentService: appointmentService,
+	}
+}
+
+// BookAppointment handles book appointment requests
+// @Summary Book appointment
+// @Description Book a free slot of a doctor for a patient; start_time must be the start of a slot returned by the doctor's availability (requires appointments:manage)
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param request body models.CreateAppointmentRequest true "Create Appointment Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.Appointment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointments [post]
+func (h *AppointmentHandler) BookAppointment(c *gin.Context) {
+	var req models.CreateAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	appointment, err := h.appointmentService.BookAppointment(patientAccessor(c), req)
+	if err != nil {
+		respondWithAppointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, appointment)
+}
+
+// GetAppointments handles get appointments requests
+// @Summary Get appointments
+// @Description List appointments in start time order, filtered by doctor, patient, status and date (requires appointments:manage)
+// @Tags appointments
+// @Produce json
+// @Param doctor_id query int false "Doctor ID"
+// @Param patient_id query int false "Patient ID"
+// @Param status query string false "Status (booked, cancelled)"
+// @Param date query string false "Date (YYYY-MM-DD)"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.PatientAppointment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointments [get]
+func (h *AppointmentHandler) GetAppointments(c *gin.Context) {
+	var filter models.AppointmentFilter
+	if v := c.Query("doctor_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid doctor ID")
+			return
+		}
+		filter.DoctorID = uint(id)
+	}
+	if v := c.Query("patient_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+			return
+		}
+		filter.PatientID = uint(id)
+	}
+	if v := c.Query("status"); v != "" {
+		filter.Status = models.AppointmentStatus(v)
+		if filter.Status != models.AppointmentStatusBooked && filter.Status != models.AppointmentStatusCancelled {
+			RespondWithError(c, http.StatusBadRequest, "Invalid status")
+			return
+		}
+	}
+
+	appointments, err := h.appointmentService.GetAppointments(patientAccessor(c), filter, c.Query("date"))
+	if err != nil {
+		respondWithAppointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointments)
+}
+
+// GetMyAppointments handles get own appointments requests
+// @Summary Get own day list
+// @Description List the booked appointments of the signed-in doctor on a date
+// @Tags appointments
+// @Produce json
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {array} models.PatientAppointment
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /doctor/appointments [get]
+func (h *AppointmentHandler) GetMyAppointments(c *gin.Context) {
+	appointments, err := h.appointmentService.GetDayList(patientAccessor(c), c.Query("date"))
+	if err != nil {
+		respondWithAppointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointments)
+}
+
+// GetAppointment handles get appointment requests
+// @Summary Get appointment
+// @Description Get an appointment (requires appointments:manage)
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Appointment
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointments/{id} [get]
+func (h *AppointmentHandler) GetAppointment(c *gin.Context) {
+	id, ok := appointmentParam(c)
+	if !ok {
+		return
+	}
+
+	appointment, err := h.appointmentService.GetAppointment(patientAccessor(c), id)
+	if err != nil {
+		respondWithAppointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointment)
+}
+
+// RescheduleAppointment handles reschedule appointment requests
+// @Summary Reschedule appointment
+// @Description Move a booked appointment to another free slot of the same doctor, giving a reason (requires appointments:manage)
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param request body models.RescheduleAppointmentRequest true "Reschedule Appointment Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Appointment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointments/{id}/reschedule [post]
+func (h *AppointmentHandler) RescheduleAppointment(c *gin.Context) {
+	id, ok := appointmentParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	appointment, err := h.appointmentService.RescheduleAppointment(patientAccessor(c), id, req)
+	if err != nil {
+		respondWithAppointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointment)
+}
+
+// CancelAppointment handles cancel appointment requests
+// @Summary Cancel appointment
+// @Description Cancel a booked appointment, giving a reason, and free its slot (requires appointments:manage)
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Appointment ID"
+// @Param request body models.CancelAppointmentRequest true "Cancel Appointment Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Appointment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointments/{id}/cancel [post]
+func (h *AppointmentHandler) CancelAppointment(c *gin.Context) {
+	id, ok := appointmentParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CancelAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	appointment, err := h.appointmentService.CancelAppointment(patientAccessor(c), id, req)
+	if err != nil {
+		respondWithAppointmentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, appointment)
+}
+
+// appointmentParam parses the appointment ID from the path
+func appointmentParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid appointment ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondWithAppointmentError maps appointment service errors to responses
+func respondWithAppointmentError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrAppointmentNotFound) ||
+		errors.Is(err, services.ErrDoctorNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrInvalidDate) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrSlotUnavailable) ||
+		errors.Is(err, services.ErrAppointmentCancelled) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppointmentSeriesHandler handles recurring appointment requests
+type AppointmentSeriesHandler struct {
+	seriesService *services.AppointmentSeriesService
+}
+
+// NewAppointmentSeriesHandler creates a new AppointmentSeriesHandler
+func NewAppointmentSeriesHandler(seriesService *services.AppointmentSeriesService) *AppointmentSeriesHandler {
+	return &AppointmentSeriesHandler{
+		seriesService: seriesService,
+	}
+}
+
+// BookSeries handles book appointment series requests
+// @Summary Book appointment series
+// @Description Book recurring appointments from an RRULE (FREQ=DAILY or WEEKLY, INTERVAL, BYDAY, COUNT or UNTIL) at the time of day of start_time; occurrences on leave or not free are skipped and listed in failed (requires appointments:manage)
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param request body models.CreateAppointmentSeriesRequest true "Create Appointment Series Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.AppointmentSeriesResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointment-series [post]
+func (h *AppointmentSeriesHandler) BookSeries(c *gin.Context) {
+	var req models.CreateAppointmentSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	result, err := h.seriesService.BookSeries(patientAccessor(c), req)
+	if err != nil {
+		respondWithSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+// GetSeries handles get appointment series requests
+// @Summary Get appointment series
+// @Description Get an appointment series with all its occurrences (requires appointments:manage)
+// @Tags appointments
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.AppointmentSeries
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointment-series/{id} [get]
+func (h *AppointmentSeriesHandler) GetSeries(c *gin.Context) {
+	id, ok := seriesParam(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(patientAccessor(c), id)
+	if err != nil {
+		respondWithSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// RescheduleSeries handles reschedule appointment series requests
+// @Summary Reschedule occurrence and following
+// @Description Move an occurrence to start_time and each following booked occurrence by the same number of days to the same time of day; occurrences that cannot move are listed in failed (requires appointments:manage)
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param request body models.RescheduleSeriesRequest true "Reschedule Series Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.AppointmentSeriesResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointment-series/{id}/reschedule [post]
+func (h *AppointmentSeriesHandler) RescheduleSeries(c *gin.Context) {
+	id, ok := seriesParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	result, err := h.seriesService.RescheduleSeries(patientAccessor(c), id, req)
+	if err != nil {
+		respondWithSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// CancelSeries handles cancel appointment series requests
+// @Summary Cancel appointment series
+// @Description Cancel the whole series and its occurrences that have not started, or with appointment_id that occurrence and the following ones (requires appointments:manage)
+// @Tags appointments
+// @Accept json
+// @Produce json
+// @Param id path int true "Series ID"
+// @Param request body models.CancelSeriesRequest true "Cancel Series Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.AppointmentSeriesResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /appointment-series/{id}/cancel [post]
+func (h *AppointmentSeriesHandler) CancelSeries(c *gin.Context) {
+	id, ok := seriesParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.CancelSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	result, err := h.seriesService.CancelSeries(patientAccessor(c), id, req)
+	if err != nil {
+		respondWithSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// seriesParam parses the series ID from the path
+func seriesParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid series ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondWithSeriesError maps appointment series service errors to responses
+func respondWithSeriesError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrAppointmentSeriesNotFound) ||
+		errors.Is(err, services.ErrAppointmentNotFound) || errors.Is(err, services.ErrDoctorNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrInvalidRecurrence) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrSlotUnavailable) || errors.Is(err, services.ErrDoctorOnLeave) ||
+		errors.Is(err, services.ErrAppointmentCancelled) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
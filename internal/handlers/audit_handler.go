@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles audit trail requests
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAuditEvents handles audit trail listing requests
+// @Summary List audit events
+// @Description List patient record reads and writes, most recent first
+// @Tags audit
+// @Produce json
+// @Param actor_id query int false "Actor user ID"
+// @Param patient_id query int false "Patient ID"
+// @Param action query string false "Action"
+// @Param from query string false "Only events at or after this time (RFC 3339)"
+// @Param to query string false "Only events before this time (RFC 3339)"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} services.PaginationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /audit [get]
+func (h *AuditHandler) GetAuditEvents(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	filter := models.AuditFilter{Action: models.AuditAction(c.Query("action"))}
+	if v := c.Query("actor_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid actor ID")
+			return
+		}
+		filter.ActorID = uint(id)
+	}
+	if v := c.Query("patient_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+			return
+		}
+		filter.PatientID = uint(id)
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid from time")
+			return
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid to time")
+			return
+		}
+		filter.To = &to
+	}
+
+	events, err := h.auditService.GetAuditEvents(filter, page, pageSize)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// VerifyAuditChain handles audit chain verification requests
+// @Summary Verify audit trail
+// @Description Check the audit hash chain and report the first event that was altered or removed
+// @Tags audit
+// @Produce json
+// @Success 200 {object} models.AuditChainVerification
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /audit/verify [get]
+func (h *AuditHandler) VerifyAuditChain(c *gin.Context) {
+	result, err := h.auditService.VerifyChain()
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
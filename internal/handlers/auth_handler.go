@@ -1,112 +1,286 @@
-package handlers
-
-import (
-	"errors"
-	"net/http"
-	"strings"
-
-	"healthcare-app/internal/models"
-	"healthcare-app/internal/services"
-
-	"github.com/gin-gonic/gin"
-)
-
-// AuthHandler handles authentication requests
-type AuthHandler struct {
-	authService *services.AuthService
-}
-
-// NewAuthHandler creates a new AuthHandler
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
-	return &AuthHandler{
-		authService: authService,
-	}
-}
-
-// Login handles login requests
-// @Summary Login
-// @Description Login with email and password
-// @Tags auth
-// @Accept json
-// @Produce json
-// @Param request body models.LoginRequest true "Login Request"
-// @Success 200 {object} models.LoginResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /login [post]
-func (h *AuthHandler) Login(c *gin.Context) {
-	var req models.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request"})
-		return
-	}
-
-	res, err := h.authService.Login(req)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, services.ErrInvalidCredentials) {
-			status = http.StatusUnauthorized
-		}
-		c.JSON(status, ErrorResponse{Error: err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, res)
-}
-
-// RequireAuth is a middleware to require authentication
-func (h *AuthHandler) RequireAuth(next gin.HandlerFunc) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get the Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Missing authorization header"})
-			c.Abort()
-			return
-		}
-
-		// Check if the header is in the correct format
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid authorization header format"})
-			c.Abort()
-			return
-		}
-
-		// Validate the token
-		claims, err := h.authService.ValidateToken(parts[1])
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid token"})
-			c.Abort()
-			return
-		}
-
-		// Set the user in the context
-		c.Set("userID", claims.UserID)
-		c.Set("userRole", claims.Role)
-
-		next(c)
-	}
-}
-
-// RequireReceptionist is a middleware to require receptionist role
-func (h *AuthHandler) RequireReceptionist(c *gin.Context) {
-	role := c.GetString("userRole")
-	if role != string(models.RoleReceptionist) {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Receptionist role required"})
-		c.Abort()
-		return
-	}
-	c.Next()
-}
-
-// RequireDoctor is a middleware to require doctor role
-func (h *AuthHandler) RequireDoctor(c *gin.Context) {
-	role := c.GetString("userRole")
-	if role != string(models.RoleDoctor) {
-		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Doctor role required"})
-		c.Abort()
-		return
-	}
-	c.Next()
-} 
\ No newline at end of file
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmergencyGrantHeader carries the ID of a break-the-glass grant held by the caller
+const EmergencyGrantHeader = "X-Emergency-Grant"
+
+// AuthHandler handles authentication requests
+type AuthHandler struct {
+	authService      *services.AuthService
+	emergencyService *services.EmergencyAccessService
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(authService *services.AuthService, emergencyService *services.EmergencyAccessService) *AuthHandler {
+	return &AuthHandler{
+		authService:      authService,
+		emergencyService: emergencyService,
+	}
+}
+
+// Login handles login requests
+// @Summary Login
+// @Description Login with email and password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.LoginRequest true "Login Request"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid request"})
+		return
+	}
+
+	res, err := h.authService.Login(req, c.ClientIP())
+	if err != nil {
+		if respondIfThrottled(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			status = http.StatusUnauthorized
+		} else if errors.Is(err, services.ErrAccountDeactivated) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// LoginMFA handles the second step of a login requiring MFA
+// @Summary Login with second factor
+// @Description Exchange an MFA challenge token and a TOTP or recovery code for tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.MFALoginRequest true "MFA Login Request"
+// @Success 200 {object} models.LoginResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /login/mfa [post]
+func (h *AuthHandler) LoginMFA(c *gin.Context) {
+	var req models.MFALoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	res, err := h.authService.LoginMFA(req, c.ClientIP())
+	if err != nil {
+		if respondIfThrottled(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidToken) ||
+			errors.Is(err, services.ErrInvalidMFACode) ||
+			errors.Is(err, services.ErrMFANotEnrolled) {
+			status = http.StatusUnauthorized
+		} else if errors.Is(err, services.ErrAccountDeactivated) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Refresh handles refresh token requests
+// @Summary Refresh token
+// @Description Exchange a refresh token for a new access and refresh token pair
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshTokenRequest true "Refresh Token Request"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /token/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	res, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidToken) ||
+			errors.Is(err, services.ErrSessionRevoked) ||
+			errors.Is(err, services.ErrRefreshTokenReused) ||
+			errors.Is(err, services.ErrRefreshTokenExpired) ||
+			errors.Is(err, services.ErrAccountDeactivated) {
+			status = http.StatusUnauthorized
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// Logout handles logout requests
+// @Summary Logout
+// @Description Revoke the current session and all of its refresh tokens
+// @Tags auth
+// @Produce json
+// @Success 200 {object} SuccessResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(c.GetString("sessionID")); err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, "Logged out successfully", nil)
+}
+
+// JWKS serves the public token verification keys
+// @Summary JSON Web Key Set
+// @Description Public keys for verifying access tokens issued by this service
+// @Tags auth
+// @Produce json
+// @Success 200 {object} models.JSONWebKeySet
+// @Router /.well-known/jwks.json [get]
+func (h *AuthHandler) JWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.authService.JWKS())
+}
+
+// respondIfThrottled responds with 429 and a Retry-After header when err is a
+// throttled or locked login
+func respondIfThrottled(c *gin.Context, err error) bool {
+	var retryErr *services.RetryAfterError
+	if !errors.As(err, &retryErr) {
+		return false
+	}
+
+	seconds := int(math.Ceil(retryErr.RetryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	RespondWithError(c, http.StatusTooManyRequests, retryErr.Error())
+	return true
+}
+
+// RequireAuth is a middleware to require authentication
+func (h *AuthHandler) RequireAuth(next gin.HandlerFunc) gin.HandlerFunc {
+	return h.authenticate(next, false)
+}
+
+// RequireAuthAllowingPending is like RequireAuth but also accepts tokens
+// restricted until the user completes MFA enrollment or a password change
+func (h *AuthHandler) RequireAuthAllowingPending(next gin.HandlerFunc) gin.HandlerFunc {
+	return h.authenticate(next, true)
+}
+
+// authenticate validates the bearer token and stores its claims in the context
+func (h *AuthHandler) authenticate(next gin.HandlerFunc, allowPending bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Get the Authorization header
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Missing authorization header"})
+			c.Abort()
+			return
+		}
+
+		// Check if the header is in the correct format
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid authorization header format"})
+			c.Abort()
+			return
+		}
+
+		// Validate the token
+		claims, err := h.authService.ValidateToken(parts[1])
+		if err != nil {
+			message := "Invalid token"
+			if errors.Is(err, services.ErrSessionRevoked) {
+				message = "Session has been revoked"
+			}
+			c.JSON(http.StatusUnauthorized, ErrorResponse{Error: message})
+			c.Abort()
+			return
+		}
+
+		// Restricted tokens only reach the endpoints that lift the restriction
+		if !allowPending {
+			if claims.MFAEnrollmentRequired {
+				c.JSON(http.StatusForbidden, ErrorResponse{Error: "MFA enrollment required"})
+				c.Abort()
+				return
+			}
+			if claims.PasswordChangeRequired {
+				c.JSON(http.StatusForbidden, ErrorResponse{Error: "Password change required"})
+				c.Abort()
+				return
+			}
+		}
+
+		// Set the user in the context
+		c.Set("userID", claims.UserID)
+		c.Set("userRole", string(claims.Role))
+		c.Set("sessionID", claims.SessionID)
+		c.Set("permissions", claims.Permissions)
+
+		// Honour a break-the-glass grant presented with the request
+		if header := c.GetHeader(EmergencyGrantHeader); header != "" {
+			grantID, err := strconv.ParseUint(header, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusForbidden, ErrorResponse{Error: services.ErrInvalidEmergencyGrant.Error()})
+				c.Abort()
+				return
+			}
+			grant, err := h.emergencyService.ActiveGrant(claims.UserID, uint(grantID))
+			if err != nil {
+				c.JSON(http.StatusForbidden, ErrorResponse{Error: err.Error()})
+				c.Abort()
+				return
+			}
+			c.Set("emergencyGrant", grant)
+		}
+
+		next(c)
+	}
+}
+
+// Authorize is a middleware to require authentication and a permission
+func (h *AuthHandler) Authorize(permission models.Permission) gin.HandlerFunc {
+	return h.RequireAuth(h.RequirePermission(permission))
+}
+
+// RequirePermission is a middleware to require a permission
+func (h *AuthHandler) RequirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Permission " + string(permission) + " required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
\ No newline at end of file
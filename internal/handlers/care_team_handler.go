@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CareTeamHandler handles care team requests
+type CareTeamHandler struct {
+	careTeamService *services.CareTeamService
+}
+
+// NewCareTeamHandler creates a new CareTeamHandler
+func NewCareTeamHandler(careTeamService *services.CareTeamService) *CareTeamHandler {
+	return &CareTeamHandler{
+		careTeamService: careTeamService,
+	}
+}
+
+// GetCareTeam handles get care team requests
+// @Summary Get care team
+// @Description Get the current and past care team assignments of a patient
+// @Tags care-team
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.CareTeamAssignment
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/care-team [get]
+func (h *CareTeamHandler) GetCareTeam(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	assignments, err := h.careTeamService.GetCareTeam(patientAccessor(c), uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrPatientAccessDenied) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
+
+// AssignClinician handles assign clinician requests
+// @Summary Assign clinician
+// @Description Add a doctor to a patient's care team; a new primary physician replaces the previous one
+// @Tags care-team
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.AssignClinicianRequest true "Assign Clinician Request"
+// @Success 201 {object} models.CareTeamAssignment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /patients/{id}/care-team [post]
+func (h *CareTeamHandler) AssignClinician(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.AssignClinicianRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	assignment, err := h.careTeamService.Assign(patientAccessor(c), uint(id), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrInvalidClinician) ||
+			errors.Is(err, services.ErrInvalidDateRange) {
+			status = http.StatusBadRequest
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
+}
+
+// UnassignClinician handles unassign clinician requests
+// @Summary Unassign clinician
+// @Description End a care team assignment; it is kept as history
+// @Tags care-team
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param assignmentId path int true "Assignment ID"
+// @Success 200 {object} models.CareTeamAssignment
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /patients/{id}/care-team/{assignmentId} [delete]
+func (h *CareTeamHandler) UnassignClinician(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	assignmentID, err := strconv.ParseUint(c.Param("assignmentId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid assignment ID")
+		return
+	}
+
+	assignment, err := h.careTeamService.Unassign(patientAccessor(c), uint(id), uint(assignmentID))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrAssignmentNotFound) {
+			status = http.StatusNotFound
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClinicalNoteHandler handles clinical note requests
+type ClinicalNoteHandler struct {
+	noteService *services.ClinicalNoteService
+}
+
+// NewClinicalNoteHandler creates a new ClinicalNoteHandler
+func NewClinicalNoteHandler(noteService *services.ClinicalNoteService) *ClinicalNoteHandler {
+	return &ClinicalNoteHandler{
+		noteService: noteService,
+	}
+}
+
+// GetNotes handles get notes requests
+// @Summary Get clinical notes
+// @Description Get the clinical notes of a patient with their addenda, most recent first (requires notes:read)
+// @Tags notes
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param encounter_id query int false "Only notes of this encounter"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.ClinicalNote
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/notes [get]
+func (h *ClinicalNoteHandler) GetNotes(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var encounterID *uint
+	if v := c.Query("encounter_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid encounter ID")
+			return
+		}
+		eid := uint(id)
+		encounterID = &eid
+	}
+
+	notes, err := h.noteService.GetNotes(patientAccessor(c), uint(patientID), encounterID)
+	if err != nil {
+		respondWithClinicalNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// GetNote handles get note requests
+// @Summary Get clinical note
+// @Description Get a clinical note of a patient with its addenda (requires notes:read)
+// @Tags notes
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param noteId path int true "Note ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.ClinicalNote
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/notes/{noteId} [get]
+func (h *ClinicalNoteHandler) GetNote(c *gin.Context) {
+	patientID, noteID, ok := clinicalNoteParams(c)
+	if !ok {
+		return
+	}
+
+	note, err := h.noteService.GetNote(patientAccessor(c), patientID, noteID)
+	if err != nil {
+		respondWithClinicalNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// GetLegacyNotes handles get legacy notes requests
+// @Summary Get legacy notes
+// @Description Get the read-only free-text notes of a patient written before clinical notes existed (requires notes:read)
+// @Tags notes
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.LegacyNotes
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/legacy-notes [get]
+func (h *ClinicalNoteHandler) GetLegacyNotes(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	notes, err := h.noteService.GetLegacyNotes(patientAccessor(c), uint(patientID))
+	if err != nil {
+		respondWithClinicalNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notes)
+}
+
+// CreateNote handles create note requests
+// @Summary Create clinical note
+// @Description Start a draft SOAP note for an encounter of a patient (requires medical:write)
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.CreateClinicalNoteRequest true "Create Clinical Note Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.ClinicalNote
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/notes [post]
+func (h *ClinicalNoteHandler) CreateNote(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.CreateClinicalNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	note, err := h.noteService.CreateNote(patientAccessor(c), uint(patientID), req)
+	if err != nil {
+		respondWithClinicalNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// UpdateNote handles update note requests
+// @Summary Update clinical note
+// @Description Replace the SOAP sections of a draft note; only its author may edit it (requires medical:write)
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param noteId path int true "Note ID"
+// @Param request body models.ClinicalNoteRequest true "Clinical Note Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.ClinicalNote
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/notes/{noteId} [put]
+func (h *ClinicalNoteHandler) UpdateNote(c *gin.Context) {
+	patientID, noteID, ok := clinicalNoteParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.ClinicalNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	note, err := h.noteService.UpdateNote(patientAccessor(c), patientID, noteID, req)
+	if err != nil {
+		respondWithClinicalNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// SignNote handles sign note requests
+// @Summary Sign clinical note
+// @Description Sign a draft note, locking it against further edits (requires medical:write)
+// @Tags notes
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param noteId path int true "Note ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.ClinicalNote
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/notes/{noteId}/sign [post]
+func (h *ClinicalNoteHandler) SignNote(c *gin.Context) {
+	patientID, noteID, ok := clinicalNoteParams(c)
+	if !ok {
+		return
+	}
+
+	note, err := h.noteService.SignNote(patientAccessor(c), patientID, noteID)
+	if err != nil {
+		respondWithClinicalNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+// AddAddendum handles add addendum requests
+// @Summary Add addendum
+// @Description Add a correction or addition to a signed note (requires medical:write)
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param noteId path int true "Note ID"
+// @Param request body models.NoteAddendumRequest true "Note Addendum Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.ClinicalNote
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/notes/{noteId}/addenda [post]
+func (h *ClinicalNoteHandler) AddAddendum(c *gin.Context) {
+	patientID, noteID, ok := clinicalNoteParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.NoteAddendumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	note, err := h.noteService.AddAddendum(patientAccessor(c), patientID, noteID, req)
+	if err != nil {
+		respondWithClinicalNoteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// clinicalNoteParams parses the patient and note IDs from the path
+func clinicalNoteParams(c *gin.Context) (patientID, noteID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return 0, 0, false
+	}
+	nid, err := strconv.ParseUint(c.Param("noteId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid note ID")
+		return 0, 0, false
+	}
+	return uint(id), uint(nid), true
+}
+
+// respondWithClinicalNoteError maps clinical note service errors to responses
+func respondWithClinicalNoteError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrClinicalNoteNotFound) ||
+		errors.Is(err, services.ErrEncounterNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) || errors.Is(err, services.ErrNotNoteAuthor) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrEmptyClinicalNote) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrClinicalNoteSigned) ||
+		errors.Is(err, services.ErrClinicalNoteNotSigned) ||
+		errors.Is(err, services.ErrEncounterNotOpen) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
@@ -1,53 +1,67 @@
-package handlers
-
-import (
-	"net/http"
-	"strconv"
-
-	"github.com/gin-gonic/gin"
-)
-
-// ErrorResponse represents an error response
-type ErrorResponse struct {
-	Error string `json:"error"`
-}
-
-// SuccessResponse represents a success response
-type SuccessResponse struct {
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
-}
-
-// GetUserIDFromContext gets the user ID from the context
-func GetUserIDFromContext(c *gin.Context) uint {
-	userID, _ := c.Get("userID")
-	return userID.(uint)
-}
-
-// GetPaginationParams gets pagination parameters from the request
-func GetPaginationParams(c *gin.Context) (page, pageSize int) {
-	pageStr := c.DefaultQuery("page", "1")
-	pageSizeStr := c.DefaultQuery("pageSize", "10")
-
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page < 1 {
-		page = 1
-	}
-
-	pageSize, err = strconv.Atoi(pageSizeStr)
-	if err != nil || pageSize < 1 || pageSize > 100 {
-		pageSize = 10
-	}
-
-	return page, pageSize
-}
-
-// RespondWithError responds with an error
-func RespondWithError(c *gin.Context, code int, message string) {
-	c.JSON(code, ErrorResponse{Error: message})
-}
-
-// RespondWithSuccess responds with success
-func RespondWithSuccess(c *gin.Context, message string, data interface{}) {
-	c.JSON(http.StatusOK, SuccessResponse{Message: message, Data: data})
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse represents an error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// SuccessResponse represents a success response
+type SuccessResponse struct {
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// GetUserIDFromContext gets the user ID from the context
+func GetUserIDFromContext(c *gin.Context) uint {
+	userID, _ := c.Get("userID")
+	return userID.(uint)
+}
+
+// HasPermission checks if the authenticated user was granted a permission
+func HasPermission(c *gin.Context, permission models.Permission) bool {
+	permissions, _ := c.Get("permissions")
+	granted, _ := permissions.([]models.Permission)
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPaginationParams gets pagination parameters from the request
+func GetPaginationParams(c *gin.Context) (page, pageSize int) {
+	pageStr := c.DefaultQuery("page", "1")
+	pageSizeStr := c.DefaultQuery("pageSize", "10")
+
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err = strconv.Atoi(pageSizeStr)
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	return page, pageSize
+}
+
+// RespondWithError responds with an error
+func RespondWithError(c *gin.Context, code int, message string) {
+	c.JSON(code, ErrorResponse{Error: message})
+}
+
+// RespondWithSuccess responds with success
+func RespondWithSuccess(c *gin.Context, message string, data interface{}) {
+	c.JSON(http.StatusOK, SuccessResponse{Message: message, Data: data})
 } 
\ No newline at end of file
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionHandler handles condition requests
+type ConditionHandler struct {
+	conditionService *services.ConditionService
+}
+
+// NewConditionHandler creates a new ConditionHandler
+func NewConditionHandler(conditionService *services.ConditionService) *ConditionHandler {
+	return &ConditionHandler{
+		conditionService: conditionService,
+	}
+}
+
+// GetConditions handles get conditions requests
+// @Summary Get conditions
+// @Description Get the problem list of a patient
+// @Tags conditions
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.Condition
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/conditions [get]
+func (h *ConditionHandler) GetConditions(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	conditions, err := h.conditionService.GetConditions(patientAccessor(c), uint(patientID))
+	if err != nil {
+		respondWithConditionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, conditions)
+}
+
+// GetCondition handles get condition requests
+// @Summary Get condition
+// @Description Get a condition of a patient
+// @Tags conditions
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param conditionId path int true "Condition ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Condition
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/conditions/{conditionId} [get]
+func (h *ConditionHandler) GetCondition(c *gin.Context) {
+	patientID, conditionID, ok := conditionParams(c)
+	if !ok {
+		return
+	}
+
+	condition, err := h.conditionService.GetCondition(patientAccessor(c), patientID, conditionID)
+	if err != nil {
+		respondWithConditionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, condition)
+}
+
+// CreateCondition handles create condition requests
+// @Summary Record condition
+// @Description Diagnose a patient with an ICD-10 coded condition (requires medical:write)
+// @Tags conditions
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.ConditionRequest true "Condition Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.Condition
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/conditions [post]
+func (h *ConditionHandler) CreateCondition(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.ConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	condition, err := h.conditionService.CreateCondition(patientAccessor(c), uint(patientID), req)
+	if err != nil {
+		respondWithConditionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, condition)
+}
+
+// UpdateCondition handles update condition requests
+// @Summary Update condition
+// @Description Replace the details of a condition of a patient (requires medical:write)
+// @Tags conditions
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param conditionId path int true "Condition ID"
+// @Param request body models.ConditionRequest true "Condition Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Condition
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/conditions/{conditionId} [put]
+func (h *ConditionHandler) UpdateCondition(c *gin.Context) {
+	patientID, conditionID, ok := conditionParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.ConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	condition, err := h.conditionService.UpdateCondition(patientAccessor(c), patientID, conditionID, req)
+	if err != nil {
+		respondWithConditionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, condition)
+}
+
+// DeleteCondition handles delete condition requests
+// @Summary Delete condition
+// @Description Delete a condition entered in error (requires medical:write)
+// @Tags conditions
+// @Param id path int true "Patient ID"
+// @Param conditionId path int true "Condition ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/conditions/{conditionId} [delete]
+func (h *ConditionHandler) DeleteCondition(c *gin.Context) {
+	patientID, conditionID, ok := conditionParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.conditionService.DeleteCondition(patientAccessor(c), patientID, conditionID); err != nil {
+		respondWithConditionError(c, err)
+		return
+	}
+
+	RespondWithSuccess(c, "Condition deleted successfully", nil)
+}
+
+// SearchICD10Codes handles ICD-10 code lookup requests
+// @Summary Search ICD-10 codes
+// @Description Find ICD-10 codes starting with the query, followed by codes whose description contains every word of it
+// @Tags conditions
+// @Produce json
+// @Param q query string true "Code prefix or description words"
+// @Param limit query int false "Maximum number of codes (default 20, at most 100)"
+// @Success 200 {array} models.ICD10Code
+// @Failure 401 {object} ErrorResponse
+// @Router /icd10 [get]
+func (h *ConditionHandler) SearchICD10Codes(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	c.JSON(http.StatusOK, h.conditionService.SearchICD10Codes(c.Query("q"), limit))
+}
+
+// conditionParams parses the patient and condition IDs from the path
+func conditionParams(c *gin.Context) (patientID, conditionID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return 0, 0, false
+	}
+	cid, err := strconv.ParseUint(c.Param("conditionId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid condition ID")
+		return 0, 0, false
+	}
+	return uint(id), uint(cid), true
+}
+
+// respondWithConditionError maps condition service errors to responses
+func respondWithConditionError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrConditionNotFound) ||
+		errors.Is(err, services.ErrEncounterNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrUnknownICD10Code) ||
+		errors.Is(err, services.ErrInvalidDateRange) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrEncounterNotOpen) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
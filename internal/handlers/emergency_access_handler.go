@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmergencyAccessHandler handles break-the-glass requests
+type EmergencyAccessHandler struct {
+	emergencyService *services.EmergencyAccessService
+}
+
+// NewEmergencyAccessHandler creates a new EmergencyAccessHandler
+func NewEmergencyAccessHandler(emergencyService *services.EmergencyAccessService) *EmergencyAccessHandler {
+	return &EmergencyAccessHandler{
+		emergencyService: emergencyService,
+	}
+}
+
+// BreakGlass handles emergency access requests
+// @Summary Break the glass
+// @Description Obtain time-boxed emergency access to a patient outside your care team. Send the returned grant ID in the X-Emergency-Grant header.
+// @Tags emergency-access
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.BreakGlassRequest true "Break Glass Request"
+// @Success 201 {object} models.EmergencyAccessGrant
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/emergency-access [post]
+func (h *EmergencyAccessHandler) BreakGlass(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.BreakGlassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request: a justification of at least 10 characters is required")
+		return
+	}
+
+	grant, err := h.emergencyService.BreakGlass(patientAccessor(c), uint(id), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrEmergencyReason) {
+			status = http.StatusBadRequest
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, grant)
+}
+
+// GetEmergencySummary handles emergency summary requests
+// @Summary Get emergency summary
+// @Description Get a patient's allergies and active medications under an emergency access grant
+// @Tags emergency-access
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param X-Emergency-Grant header int true "Emergency access grant ID"
+// @Success 200 {object} models.EmergencySummary
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/emergency-summary [get]
+func (h *EmergencyAccessHandler) GetEmergencySummary(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	accessor := patientAccessor(c)
+	summary, err := h.emergencyService.GetEmergencySummary(accessor, accessor.EmergencyGrant, uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrInvalidEmergencyGrant) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetEmergencyAccessReport handles emergency access report requests
+// @Summary Emergency access report
+// @Description List break-the-glass grants with their justifications for compliance review
+// @Tags emergency-access
+// @Produce json
+// @Param user_id query int false "User ID"
+// @Param patient_id query int false "Patient ID"
+// @Param from query string false "Only grants created at or after this time (RFC 3339)"
+// @Param to query string false "Only grants created before this time (RFC 3339)"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} services.PaginationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /emergency-access [get]
+func (h *EmergencyAccessHandler) GetEmergencyAccessReport(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	var filter models.EmergencyAccessFilter
+	if v := c.Query("user_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		filter.UserID = uint(id)
+	}
+	if v := c.Query("patient_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+			return
+		}
+		filter.PatientID = uint(id)
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid from time")
+			return
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid to time")
+			return
+		}
+		filter.To = &to
+	}
+
+	report, err := h.emergencyService.GetEmergencyAccessReport(filter, page, pageSize)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EncounterHandler handles encounter requests
+type EncounterHandler struct {
+	encounterService *services.EncounterService
+}
+
+// NewEncounterHandler creates a new EncounterHandler
+func NewEncounterHandler(encounterService *services.EncounterService) *EncounterHandler {
+	return &EncounterHandler{
+		encounterService: encounterService,
+	}
+}
+
+// GetEncounters handles get encounters requests
+// @Summary Get encounters
+// @Description Get the encounters of a patient, most recent first
+// @Tags encounters
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.Encounter
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/encounters [get]
+func (h *EncounterHandler) GetEncounters(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	encounters, err := h.encounterService.GetEncounters(patientAccessor(c), uint(patientID))
+	if err != nil {
+		respondWithEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, encounters)
+}
+
+// GetEncounter handles get encounter requests
+// @Summary Get encounter
+// @Description Get an encounter of a patient with the vitals, diagnoses and prescriptions recorded during it
+// @Tags encounters
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param encounterId path int true "Encounter ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Encounter
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/encounters/{encounterId} [get]
+func (h *EncounterHandler) GetEncounter(c *gin.Context) {
+	patientID, encounterID, ok := encounterParams(c)
+	if !ok {
+		return
+	}
+
+	encounter, err := h.encounterService.GetEncounter(patientAccessor(c), patientID, encounterID)
+	if err != nil {
+		respondWithEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, encounter)
+}
+
+// CreateEncounter handles create encounter requests
+// @Summary Create encounter
+// @Description Plan a visit of a patient to a doctor, or register a walk-in as arrived (requires encounters:manage)
+// @Tags encounters
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.CreateEncounterRequest true "Create Encounter Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.Encounter
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/encounters [post]
+func (h *EncounterHandler) CreateEncounter(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.CreateEncounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	encounter, err := h.encounterService.CreateEncounter(patientAccessor(c), uint(patientID), req)
+	if err != nil {
+		respondWithEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, encounter)
+}
+
+// CheckIn handles check-in requests
+// @Summary Check in encounter
+// @Description Record that the patient of a planned encounter has arrived (requires encounters:manage)
+// @Tags encounters
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param encounterId path int true "Encounter ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Encounter
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/encounters/{encounterId}/check-in [post]
+func (h *EncounterHandler) CheckIn(c *gin.Context) {
+	h.transition(c, h.encounterService.CheckIn)
+}
+
+// StartEncounter handles start encounter requests
+// @Summary Start encounter
+// @Description Record that the doctor has started seeing an arrived patient (requires medical:write)
+// @Tags encounters
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param encounterId path int true "Encounter ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Encounter
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/encounters/{encounterId}/start [post]
+func (h *EncounterHandler) StartEncounter(c *gin.Context) {
+	h.transition(c, h.encounterService.StartEncounter)
+}
+
+// FinishEncounter handles finish encounter requests
+// @Summary Finish encounter
+// @Description Close an encounter in progress (requires medical:write)
+// @Tags encounters
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param encounterId path int true "Encounter ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Encounter
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/encounters/{encounterId}/finish [post]
+func (h *EncounterHandler) FinishEncounter(c *gin.Context) {
+	h.transition(c, h.encounterService.FinishEncounter)
+}
+
+// CancelEncounter handles cancel encounter requests
+// @Summary Cancel encounter
+// @Description Cancel an encounter that has not started (requires encounters:manage)
+// @Tags encounters
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param encounterId path int true "Encounter ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Encounter
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/encounters/{encounterId}/cancel [post]
+func (h *EncounterHandler) CancelEncounter(c *gin.Context) {
+	h.transition(c, h.encounterService.CancelEncounter)
+}
+
+// transition handles a request moving an encounter to another status
+func (h *EncounterHandler) transition(c *gin.Context, move func(services.PatientAccessor, uint, uint) (*models.Encounter, error)) {
+	patientID, encounterID, ok := encounterParams(c)
+	if !ok {
+		return
+	}
+
+	encounter, err := move(patientAccessor(c), patientID, encounterID)
+	if err != nil {
+		respondWithEncounterError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, encounter)
+}
+
+// encounterParams parses the patient and encounter IDs from the path
+func encounterParams(c *gin.Context) (patientID, encounterID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return 0, 0, false
+	}
+	eid, err := strconv.ParseUint(c.Param("encounterId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid encounter ID")
+		return 0, 0, false
+	}
+	return uint(id), uint(eid), true
+}
+
+// respondWithEncounterError maps encounter service errors to responses
+func respondWithEncounterError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrEncounterNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrInvalidEncounterDoctor) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrInvalidEncounterTransition) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
@@ -0,0 +1,343 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// prescriptionTemplate renders a prescription as a printable HTML page
+var prescriptionTemplate = template.Must(template.New("prescription").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Prescription #{{.Medication.ID}}</title>
+<style>
+body { font-family: serif; max-width: 40em; margin: 2em auto; }
+table { border-collapse: collapse; width: 100%; }
+th { text-align: left; width: 30%; }
+th, td { padding: 0.3em; border-bottom: 1px solid #ccc; }
+.signature { margin-top: 4em; border-top: 1px solid #000; width: 20em; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Prescription</h1>
+<p>Prescription #{{.Medication.ID}}, issued {{.Medication.StartDate.Format "2006-01-02"}}</p>
+<h2>Patient</h2>
+<table>
+<tr><th>Name</th><td>{{.Patient.FirstName}} {{.Patient.LastName}}</td></tr>
+<tr><th>Date of birth</th><td>{{.Patient.DateOfBirth.Format "2006-01-02"}}</td></tr>
+<tr><th>Address</th><td>{{.Patient.Address}}</td></tr>
+</table>
+<h2>Medication</h2>
+<table>
+<tr><th>Drug</th><td>{{.Medication.DrugName}}{{with .Medication.DrugCode}} ({{.}}){{end}}</td></tr>
+<tr><th>Dose</th><td>{{.Medication.Dose}}</td></tr>
+<tr><th>Route</th><td>{{.Medication.Route}}</td></tr>
+<tr><th>Frequency</th><td>{{.Medication.Frequency}}</td></tr>
+{{with .Medication.Duration}}<tr><th>Duration</th><td>{{.}}</td></tr>{{end}}
+{{with .Medication.Instructions}}<tr><th>Instructions</th><td>{{.}}</td></tr>{{end}}
+{{if not .Medication.IsActive}}<tr><th>Status</th><td>{{.Medication.Status}}</td></tr>{{end}}
+</table>
+<div class="signature">{{.Medication.PrescriberName}}</div>
+<p><small>Printed {{.PrintedAt.Format "2006-01-02 15:04"}}</small></p>
+</body>
+</html>
+`))
+
+// InteractionOverrideResponse represents a prescription blocked by
+// high-severity interactions
+type InteractionOverrideResponse struct {
+	Error    string                      `json:"error"`
+	Warnings []models.InteractionWarning `json:"warnings"`
+}
+
+// MedicationHandler handles medication requests
+type MedicationHandler struct {
+	medicationService *services.MedicationService
+}
+
+// NewMedicationHandler creates a new MedicationHandler
+func NewMedicationHandler(medicationService *services.MedicationService) *MedicationHandler {
+	return &MedicationHandler{
+		medicationService: medicationService,
+	}
+}
+
+// GetMedications handles get medications requests
+// @Summary Get medications
+// @Description Get the medication statements and prescriptions of a patient
+// @Tags medications
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param status query string false "Only medications with this status (active, stopped, completed)"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.Medication
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctor/patients/{id}/medications [get]
+func (h *MedicationHandler) GetMedications(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	status := models.MedicationStatus(c.Query("status"))
+	switch status {
+	case "", models.MedicationStatusActive, models.MedicationStatusStopped, models.MedicationStatusCompleted:
+	default:
+		RespondWithError(c, http.StatusBadRequest, "Invalid status")
+		return
+	}
+
+	medications, err := h.medicationService.GetMedications(patientAccessor(c), uint(patientID), status)
+	if err != nil {
+		respondWithMedicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, medications)
+}
+
+// GetMedication handles get medication requests
+// @Summary Get medication
+// @Description Get a medication of a patient
+// @Tags medications
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param medicationId path int true "Medication ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Medication
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctor/patients/{id}/medications/{medicationId} [get]
+func (h *MedicationHandler) GetMedication(c *gin.Context) {
+	patientID, medicationID, ok := medicationParams(c)
+	if !ok {
+		return
+	}
+
+	medication, err := h.medicationService.GetMedication(patientAccessor(c), patientID, medicationID)
+	if err != nil {
+		respondWithMedicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, medication)
+}
+
+// GetActiveMedications handles get active medications requests
+// @Summary Get active medications
+// @Description List the active medications across the patients the user may see, e.g. everyone on a drug
+// @Tags medications
+// @Produce json
+// @Param drug query string false "Drug name to match"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} services.PaginationResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /medications/active [get]
+func (h *MedicationHandler) GetActiveMedications(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+
+	response, err := h.medicationService.GetActiveMedications(patientAccessor(c), c.Query("drug"), page, pageSize)
+	if err != nil {
+		respondWithMedicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateMedication handles create medication requests
+// @Summary Record medication
+// @Description Record a medication statement or prescribe a medication for a patient; prescriptions with high-severity interactions require an override_reason (requires medical:write)
+// @Tags medications
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.CreateMedicationRequest true "Create Medication Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.Medication
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} InteractionOverrideResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctor/patients/{id}/medications [post]
+func (h *MedicationHandler) CreateMedication(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.CreateMedicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	medication, err := h.medicationService.CreateMedication(patientAccessor(c), uint(patientID), req)
+	if err != nil {
+		var overrideErr *services.InteractionOverrideError
+		if errors.As(err, &overrideErr) {
+			c.JSON(http.StatusConflict, InteractionOverrideResponse{Error: err.Error(), Warnings: overrideErr.Warnings})
+			return
+		}
+		respondWithMedicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, medication)
+}
+
+// UpdateMedication handles update medication requests
+// @Summary Update medication
+// @Description Change the details of an active medication, or stop or complete it; stopping requires a reason (requires medical:write)
+// @Tags medications
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param medicationId path int true "Medication ID"
+// @Param request body models.UpdateMedicationRequest true "Update Medication Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Medication
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctor/patients/{id}/medications/{medicationId} [put]
+func (h *MedicationHandler) UpdateMedication(c *gin.Context) {
+	patientID, medicationID, ok := medicationParams(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateMedicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	medication, err := h.medicationService.UpdateMedication(patientAccessor(c), patientID, medicationID, req)
+	if err != nil {
+		respondWithMedicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, medication)
+}
+
+// CheckInteractions handles interaction check requests
+// @Summary Check interactions
+// @Description Check a drug for interactions with other drugs and allergies, and with a patient's active medications and allergies when patient_id is given (requires medical:write)
+// @Tags medications
+// @Accept json
+// @Produce json
+// @Param request body models.InteractionCheckRequest true "Interaction Check Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.InteractionCheckResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctor/interactions/check [post]
+func (h *MedicationHandler) CheckInteractions(c *gin.Context) {
+	var req models.InteractionCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	result, err := h.medicationService.CheckInteractions(patientAccessor(c), req)
+	if err != nil {
+		respondWithMedicationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// PrintPrescription handles print prescription requests
+// @Summary Print prescription
+// @Description Render a prescription as a printable HTML document
+// @Tags medications
+// @Produce html
+// @Param id path int true "Patient ID"
+// @Param medicationId path int true "Medication ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {string} string "Prescription document"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctor/patients/{id}/medications/{medicationId}/prescription [get]
+func (h *MedicationHandler) PrintPrescription(c *gin.Context) {
+	patientID, medicationID, ok := medicationParams(c)
+	if !ok {
+		return
+	}
+
+	document, err := h.medicationService.GetPrescription(patientAccessor(c), patientID, medicationID)
+	if err != nil {
+		respondWithMedicationError(c, err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := prescriptionTemplate.Execute(&buf, document); err != nil {
+		RespondWithError(c, http.StatusInternalServerError, "Failed to render prescription")
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// medicationParams parses the patient and medication IDs from the path
+func medicationParams(c *gin.Context) (patientID, medicationID uint, ok bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return 0, 0, false
+	}
+	mid, err := strconv.ParseUint(c.Param("medicationId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid medication ID")
+		return 0, 0, false
+	}
+	return uint(id), uint(mid), true
+}
+
+// respondWithMedicationError maps medication service errors to responses
+func respondWithMedicationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrMedicationNotFound) ||
+		errors.Is(err, services.ErrEncounterNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrStopReasonRequired) ||
+		errors.Is(err, services.ErrInvalidStopDate) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrMedicationNotActive) ||
+		errors.Is(err, services.ErrNotAPrescription) ||
+		errors.Is(err, services.ErrEncounterNotOpen) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAHandler handles multi-factor authentication requests
+type MFAHandler struct {
+	mfaService *services.MFAService
+}
+
+// NewMFAHandler creates a new MFAHandler
+func NewMFAHandler(mfaService *services.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// Enroll handles TOTP enrollment requests
+// @Summary Start MFA enrollment
+// @Description Generate a TOTP secret and otpauth:// provisioning URI for the current user
+// @Tags mfa
+// @Produce json
+// @Success 200 {object} models.MFAEnrollmentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /mfa/enroll [post]
+func (h *MFAHandler) Enroll(c *gin.Context) {
+	res, err := h.mfaService.Enroll(GetUserIDFromContext(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrMFAAlreadyEnabled) {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// ConfirmEnrollment handles TOTP enrollment confirmation requests
+// @Summary Confirm MFA enrollment
+// @Description Activate MFA with a code from the authenticator app and receive recovery codes
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param request body models.ConfirmMFARequest true "Confirm MFA Request"
+// @Success 200 {object} models.RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /mfa/enroll/confirm [post]
+func (h *MFAHandler) ConfirmEnrollment(c *gin.Context) {
+	var req models.ConfirmMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	res, err := h.mfaService.ConfirmEnrollment(GetUserIDFromContext(c), req.Code)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrMFAAlreadyEnabled) ||
+			errors.Is(err, services.ErrMFANotEnrolled) ||
+			errors.Is(err, services.ErrInvalidMFACode) {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
+
+// GetRolePolicies handles get role policies requests
+// @Summary Get role MFA policies
+// @Description List which roles require MFA
+// @Tags mfa
+// @Produce json
+// @Success 200 {array} models.RolePolicy
+// @Failure 401 {object} ErrorResponse
+// @Router /mfa/policies [get]
+func (h *MFAHandler) GetRolePolicies(c *gin.Context) {
+	policies, err := h.mfaService.GetRolePolicies()
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+// UpdateRolePolicy handles update role policy requests
+// @Summary Update role MFA policy
+// @Description Require or stop requiring MFA for a role
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param role path string true "Role"
+// @Param request body models.UpdateRolePolicyRequest true "Update Role Policy Request"
+// @Success 200 {object} models.RolePolicy
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /mfa/policies/{role} [put]
+func (h *MFAHandler) UpdateRolePolicy(c *gin.Context) {
+	role := models.UserRole(c.Param("role"))
+	if !role.IsValid() {
+		RespondWithError(c, http.StatusBadRequest, "Invalid role")
+		return
+	}
+
+	var req models.UpdateRolePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	policy, err := h.mfaService.UpdateRolePolicy(role, req)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
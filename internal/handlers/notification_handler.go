@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler handles notification outbox and contact preference
+// requests
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications handles get notifications requests
+// @Summary Get notifications
+// @Description List the messages queued to patients newest first with their delivery status, filtered by patient, kind and status (requires appointments:manage)
+// @Tags notifications
+// @Produce json
+// @Param patient_id query int false "Patient ID"
+// @Param kind query string false "Kind (appointment_reminder, slot_offer)"
+// @Param status query string false "Status (pending, sent, failed, suppressed)"
+// @Success 200 {array} models.Notification
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	var filter models.NotificationFilter
+	if v := c.Query("patient_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+			return
+		}
+		filter.PatientID = uint(id)
+	}
+	if v := c.Query("kind"); v != "" {
+		filter.Kind = models.NotificationKind(v)
+		if filter.Kind != models.NotificationKindAppointmentReminder && filter.Kind != models.NotificationKindSlotOffer {
+			RespondWithError(c, http.StatusBadRequest, "Invalid notification kind")
+			return
+		}
+	}
+	if v := c.Query("status"); v != "" {
+		filter.Status = models.NotificationStatus(v)
+		if filter.Status != models.NotificationStatusPending && filter.Status != models.NotificationStatusSent &&
+			filter.Status != models.NotificationStatusFailed && filter.Status != models.NotificationStatusSuppressed {
+			RespondWithError(c, http.StatusBadRequest, "Invalid status")
+			return
+		}
+	}
+
+	notifications, err := h.notificationService.GetNotifications(patientAccessor(c), filter)
+	if err != nil {
+		respondWithNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+// GetContactPreference handles get contact preferences requests
+// @Summary Get contact preferences
+// @Description Get the channel a patient prefers to be contacted on and the channels and messages they opted out of (requires patients:read)
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.ContactPreference
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/contact-preferences [get]
+func (h *NotificationHandler) GetContactPreference(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	preference, err := h.notificationService.GetContactPreference(patientAccessor(c), uint(patientID))
+	if err != nil {
+		respondWithNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+// UpdateContactPreference handles update contact preferences requests
+// @Summary Update contact preferences
+// @Description Replace the channel a patient prefers to be contacted on and their opt-outs of email, SMS and appointment reminders (requires patients:write)
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.UpdateContactPreferenceRequest true "Update Contact Preference Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.ContactPreference
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/contact-preferences [put]
+func (h *NotificationHandler) UpdateContactPreference(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.UpdateContactPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	preference, err := h.notificationService.UpdateContactPreference(patientAccessor(c), uint(patientID), req)
+	if err != nil {
+		respondWithNotificationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preference)
+}
+
+// respondWithNotificationError maps notification service errors to
+// responses
+func respondWithNotificationError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	}
+	RespondWithError(c, status, err.Error())
+}
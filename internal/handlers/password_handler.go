@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PasswordHandler handles password change and reset requests
+type PasswordHandler struct {
+	passwordService *services.PasswordService
+}
+
+// NewPasswordHandler creates a new PasswordHandler
+func NewPasswordHandler(passwordService *services.PasswordService) *PasswordHandler {
+	return &PasswordHandler{
+		passwordService: passwordService,
+	}
+}
+
+// ChangePassword handles change own password requests
+// @Summary Change password
+// @Description Change the current user's password; other sessions are revoked
+// @Tags password
+// @Accept json
+// @Produce json
+// @Param request body models.ChangePasswordRequest true "Change Password Request"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /me/password [post]
+func (h *PasswordHandler) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	err := h.passwordService.ChangePassword(GetUserIDFromContext(c), c.GetString("sessionID"), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidCurrentPassword) ||
+			errors.Is(err, services.ErrPasswordUnchanged) {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, "Password changed successfully", nil)
+}
+
+// ForcePasswordChange handles force password change requests
+// @Summary Force password change
+// @Description Require a user to change their password on next login
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /users/{id}/force-password-change [post]
+func (h *PasswordHandler) ForcePasswordChange(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if err := h.passwordService.ForcePasswordChange(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, "User must change password on next login", nil)
+}
+
+// ForgotPassword handles password reset token requests
+// @Summary Request password reset
+// @Description Send a single-use password reset token to the account's email
+// @Tags password
+// @Accept json
+// @Produce json
+// @Param request body models.ForgotPasswordRequest true "Forgot Password Request"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /password/forgot [post]
+func (h *PasswordHandler) ForgotPassword(c *gin.Context) {
+	var req models.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := h.passwordService.RequestReset(req.Email); err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, "If the account exists, a reset token has been sent", nil)
+}
+
+// ResetPassword handles password reset requests
+// @Summary Reset password
+// @Description Set a new password with a reset token; all sessions are revoked
+// @Tags password
+// @Accept json
+// @Produce json
+// @Param request body models.ResetPasswordRequest true "Reset Password Request"
+// @Success 200 {object} SuccessResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /password/reset [post]
+func (h *PasswordHandler) ResetPassword(c *gin.Context) {
+	var req models.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	if err := h.passwordService.ResetPassword(req); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			status = http.StatusBadRequest
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, "Password reset successfully", nil)
+}
@@ -1,235 +1,544 @@
-package handlers
-
-import (
-	"errors"
-	"net/http"
-	"strconv"
-
-	"healthcare-app/internal/models"
-	"healthcare-app/internal/services"
-
-	"github.com/gin-gonic/gin"
-)
-
-// PatientHandler handles patient requests
-type PatientHandler struct {
-	patientService *services.PatientService
-}
-
-// NewPatientHandler creates a new PatientHandler
-func NewPatientHandler(patientService *services.PatientService) *PatientHandler {
-	return &PatientHandler{
-		patientService: patientService,
-	}
-}
-
-// CreatePatient handles create patient requests
-// @Summary Create patient
-// @Description Create a new patient (Receptionist only)
-// @Tags patients
-// @Accept json
-// @Produce json
-// @Param request body models.CreatePatientRequest true "Create Patient Request"
-// @Success 201 {object} models.Patient
-// @Failure 400 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /patients [post]
-func (h *PatientHandler) CreatePatient(c *gin.Context) {
-	var req models.CreatePatientRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		RespondWithError(c, http.StatusBadRequest, "Invalid request")
-		return
-	}
-
-	userID := GetUserIDFromContext(c)
-	patient, err := h.patientService.CreatePatient(req, userID)
-	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusCreated, patient)
-}
-
-// GetPatient handles get patient requests
-// @Summary Get patient
-// @Description Get a patient by ID
-// @Tags patients
-// @Produce json
-// @Param id path int true "Patient ID"
-// @Success 200 {object} models.Patient
-// @Failure 404 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /patients/{id} [get]
-func (h *PatientHandler) GetPatient(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
-		return
-	}
-
-	patient, err := h.patientService.GetPatient(uint(id))
-	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, services.ErrPatientNotFound) {
-			status = http.StatusNotFound
-		}
-		RespondWithError(c, status, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusOK, patient)
-}
-
-// GetAllPatients handles get all patients requests
-// @Summary Get all patients
-// @Description Get all patients with pagination
-// @Tags patients
-// @Produce json
-// @Param page query int false "Page number"
-// @Param pageSize query int false "Page size"
-// @Success 200 {object} services.PaginationResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /patients [get]
-func (h *PatientHandler) GetAllPatients(c *gin.Context) {
-	page, pageSize := GetPaginationParams(c)
-	
-	patients, err := h.patientService.GetAllPatients(page, pageSize)
-	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusOK, patients)
-}
-
-// UpdatePatient handles update patient requests
-// @Summary Update patient
-// @Description Update a patient (Receptionist only)
-// @Tags patients
-// @Accept json
-// @Produce json
-// @Param id path int true "Patient ID"
-// @Param request body models.UpdatePatientRequest true "Update Patient Request"
-// @Success 200 {object} models.Patient
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /patients/{id} [put]
-func (h *PatientHandler) UpdatePatient(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
-		return
-	}
-
-	var req models.UpdatePatientRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		RespondWithError(c, http.StatusBadRequest, "Invalid request")
-		return
-	}
-
-	patient, err := h.patientService.UpdatePatient(uint(id), req)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, services.ErrPatientNotFound) {
-			status = http.StatusNotFound
-		}
-		RespondWithError(c, status, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusOK, patient)
-}
-
-// UpdatePatientMedicalInfo handles update patient medical info requests
-// @Summary Update patient medical info
-// @Description Update a patient's medical information (Doctor only)
-// @Tags doctor
-// @Accept json
-// @Produce json
-// @Param id path int true "Patient ID"
-// @Param request body models.UpdatePatientMedicalRequest true "Update Patient Medical Request"
-// @Success 200 {object} models.Patient
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /doctor/patients/{id}/medical [put]
-func (h *PatientHandler) UpdatePatientMedicalInfo(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
-		return
-	}
-
-	var req models.UpdatePatientMedicalRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		RespondWithError(c, http.StatusBadRequest, "Invalid request")
-		return
-	}
-
-	patient, err := h.patientService.UpdatePatientMedicalInfo(uint(id), req)
-	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, services.ErrPatientNotFound) {
-			status = http.StatusNotFound
-		}
-		RespondWithError(c, status, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusOK, patient)
-}
-
-// DeletePatient handles delete patient requests
-// @Summary Delete patient
-// @Description Delete a patient (Receptionist only)
-// @Tags patients
-// @Param id path int true "Patient ID"
-// @Success 200 {object} SuccessResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /patients/{id} [delete]
-func (h *PatientHandler) DeletePatient(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
-		return
-	}
-
-	err = h.patientService.DeletePatient(uint(id))
-	if err != nil {
-		status := http.StatusInternalServerError
-		if errors.Is(err, services.ErrPatientNotFound) {
-			status = http.StatusNotFound
-		}
-		RespondWithError(c, status, err.Error())
-		return
-	}
-
-	RespondWithSuccess(c, "Patient deleted successfully", nil)
-}
-
-// SearchPatients handles search patients requests
-// @Summary Search patients
-// @Description Search patients by search term
-// @Tags patients
-// @Produce json
-// @Param q query string true "Search term"
-// @Param page query int false "Page number"
-// @Param pageSize query int false "Page size"
-// @Success 200 {object} services.PaginationResponse
-// @Failure 401 {object} ErrorResponse
-// @Router /patients/search [get]
-func (h *PatientHandler) SearchPatients(c *gin.Context) {
-	searchTerm := c.Query("q")
-	page, pageSize := GetPaginationParams(c)
-	
-	patients, err := h.patientService.SearchPatients(searchTerm, page, pageSize)
-	if err != nil {
-		RespondWithError(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	c.JSON(http.StatusOK, patients)
-} 
\ No newline at end of file
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PatientHandler handles patient requests
+type PatientHandler struct {
+	patientService *services.PatientService
+}
+
+// NewPatientHandler creates a new PatientHandler
+func NewPatientHandler(patientService *services.PatientService) *PatientHandler {
+	return &PatientHandler{
+		patientService: patientService,
+	}
+}
+
+// AccessOverrideHeader carries the justification for accessing a patient
+// outside the caller's care team
+const AccessOverrideHeader = "X-Access-Override-Reason"
+
+// MergePatchContentType is the media type of JSON merge patches (RFC 7396)
+const MergePatchContentType = "application/merge-patch+json"
+
+// PatientConflictResponse represents a rejected update based on an outdated
+// version, with the current version of the patient
+type PatientConflictResponse struct {
+	Error   string          `json:"error"`
+	Current *models.Patient `json:"current"`
+}
+
+// CreatePatient handles create patient requests
+// @Summary Create patient
+// @Description Create a new patient (requires patients:write)
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param request body models.CreatePatientRequest true "Create Patient Request"
+// @Success 201 {object} models.Patient
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /patients [post]
+func (h *PatientHandler) CreatePatient(c *gin.Context) {
+	var req models.CreatePatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	patient, err := h.patientService.CreatePatient(patientAccessor(c), req)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.Header("ETag", patient.ETag())
+	c.JSON(http.StatusCreated, patient)
+}
+
+// GetPatient handles get patient requests
+// @Summary Get patient
+// @Description Get a patient by ID, optionally as the record was at a point in time
+// @Tags patients
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param asOf query string false "Reconstruct the record at this time (RFC 3339)"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.Patient
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id} [get]
+func (h *PatientHandler) GetPatient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var patient *models.Patient
+	if v := c.Query("asOf"); v != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, v)
+		if parseErr != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid asOf time")
+			return
+		}
+		patient, err = h.patientService.GetPatientAsOf(patientAccessor(c), uint(id), asOf)
+	} else {
+		patient, err = h.patientService.GetPatient(patientAccessor(c), uint(id))
+		if err == nil {
+			c.Header("ETag", patient.ETag())
+		}
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrRevisionNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrPatientAccessDenied) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, patient)
+}
+
+// GetAllPatients handles get all patients requests
+// @Summary Get all patients
+// @Description Get patients with pagination; clinicians without patients:all only see their care teams
+// @Tags patients
+// @Produce json
+// @Param condition query string false "ICD-10 code prefix of a current condition, e.g. E11"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} services.PaginationResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /patients [get]
+func (h *PatientHandler) GetAllPatients(c *gin.Context) {
+	page, pageSize := GetPaginationParams(c)
+	
+	filter := models.PatientFilter{
+		ConditionCode: models.NormalizeICD10Code(c.Query("condition")),
+	}
+
+	patients, err := h.patientService.GetAllPatients(patientAccessor(c), filter, page, pageSize)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, patients)
+}
+
+// UpdatePatient handles update patient requests
+// @Summary Update patient
+// @Description Update a patient (requires patients:write)
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.UpdatePatientRequest true "Update Patient Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Param If-Match header string true "ETag of the version the update is based on"
+// @Success 200 {object} models.Patient
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 412 {object} PatientConflictResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /patients/{id} [put]
+func (h *PatientHandler) UpdatePatient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.UpdatePatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	patient, err := h.patientService.UpdatePatient(patientAccessor(c), uint(id), version, req)
+	if err != nil {
+		if respondIfConflict(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrPatientAccessDenied) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.Header("ETag", patient.ETag())
+	c.JSON(http.StatusOK, patient)
+}
+
+// UpdatePatientMedicalInfo handles update patient medical info requests
+// @Summary Update patient medical info
+// @Description Update a patient's medical information (requires medical:write)
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.UpdatePatientMedicalRequest true "Update Patient Medical Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Param If-Match header string true "ETag of the version the update is based on"
+// @Success 200 {object} models.Patient
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 412 {object} PatientConflictResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /patients/{id}/medical [put]
+func (h *PatientHandler) UpdatePatientMedicalInfo(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.UpdatePatientMedicalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	patient, err := h.patientService.UpdatePatientMedicalInfo(patientAccessor(c), uint(id), version, req)
+	if err != nil {
+		if respondIfConflict(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrPatientAccessDenied) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.Header("ETag", patient.ETag())
+	c.JSON(http.StatusOK, patient)
+}
+
+// PatchPatient handles partial patient update requests
+// @Summary Patch patient
+// @Description Apply a JSON merge patch (RFC 7396) to a patient: omitted fields are untouched and null clears a field. Demographic fields require patients:write and medical fields medical:write.
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body object true "Merge patch"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Param If-Match header string true "ETag of the version the update is based on"
+// @Success 200 {object} models.Patient
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 412 {object} PatientConflictResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /patients/{id} [patch]
+func (h *PatientHandler) PatchPatient(c *gin.Context) {
+	h.patchPatient(c, false)
+}
+
+// PatchPatientMedicalInfo handles partial patient medical info requests
+// @Summary Patch patient medical info
+// @Description Apply a JSON merge patch (RFC 7396) to a patient's medical fields (requires medical:write)
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body object true "Merge patch"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Param If-Match header string true "ETag of the version the update is based on"
+// @Success 200 {object} models.Patient
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 412 {object} PatientConflictResponse
+// @Failure 415 {object} ErrorResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /patients/{id}/medical [patch]
+func (h *PatientHandler) PatchPatientMedicalInfo(c *gin.Context) {
+	h.patchPatient(c, true)
+}
+
+// patchPatient applies a merge patch from the request body
+func (h *PatientHandler) patchPatient(c *gin.Context, medicalOnly bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	if ct := c.ContentType(); ct != MergePatchContentType && ct != "application/json" {
+		RespondWithError(c, http.StatusUnsupportedMediaType, "Content-Type must be "+MergePatchContentType)
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil || patch == nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request: the patch must be a JSON object")
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	patient, err := h.patientService.PatchPatient(patientAccessor(c), uint(id), version, patch, medicalOnly)
+	if err != nil {
+		if respondIfConflict(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrInvalidPatch) {
+			status = http.StatusBadRequest
+		} else if errors.Is(err, services.ErrPatientAccessDenied) || errors.Is(err, services.ErrFieldNotPermitted) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.Header("ETag", patient.ETag())
+	c.JSON(http.StatusOK, patient)
+}
+
+// DeletePatient handles delete patient requests
+// @Summary Delete patient
+// @Description Delete a patient (requires patients:delete)
+// @Tags patients
+// @Param id path int true "Patient ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id} [delete]
+func (h *PatientHandler) DeletePatient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	err = h.patientService.DeletePatient(patientAccessor(c), uint(id))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrPatientAccessDenied) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	RespondWithSuccess(c, "Patient deleted successfully", nil)
+}
+
+// SearchPatients handles search patients requests
+// @Summary Search patients
+// @Description Search patients by search term
+// @Tags patients
+// @Produce json
+// @Param q query string true "Search term"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Success 200 {object} services.PaginationResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /patients/search [get]
+func (h *PatientHandler) SearchPatients(c *gin.Context) {
+	searchTerm := c.Query("q")
+	page, pageSize := GetPaginationParams(c)
+	
+	patients, err := h.patientService.SearchPatients(patientAccessor(c), searchTerm, page, pageSize)
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, patients)
+}
+
+// GetPatientHistory handles patient history requests
+// @Summary Get patient history
+// @Description List the revisions of a patient record with their authors and changed fields, most recent first
+// @Tags patients
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} services.PaginationResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/history [get]
+func (h *PatientHandler) GetPatientHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+	page, pageSize := GetPaginationParams(c)
+
+	history, err := h.patientService.GetPatientHistory(patientAccessor(c), uint(id), page, pageSize)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrPatientAccessDenied) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// RevertPatient handles patient revert requests
+// @Summary Revert patient
+// @Description Restore a patient record to an earlier revision (requires patients:write, and medical:write when medical fields change)
+// @Tags patients
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.RevertPatientRequest true "Revert Patient Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Param If-Match header string true "ETag of the version the update is based on"
+// @Success 200 {object} models.Patient
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 412 {object} PatientConflictResponse
+// @Failure 428 {object} ErrorResponse
+// @Router /patients/{id}/revert [post]
+func (h *PatientHandler) RevertPatient(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.RevertPatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	patient, err := h.patientService.RevertPatient(patientAccessor(c), uint(id), version, req.Revision)
+	if err != nil {
+		if respondIfConflict(c, err) {
+			return
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrRevisionNotFound) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, services.ErrPatientAccessDenied) || errors.Is(err, services.ErrMedicalFieldsForbidden) {
+			status = http.StatusForbidden
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.Header("ETag", patient.ETag())
+	c.JSON(http.StatusOK, patient)
+}
+
+// ifMatchVersion reads the patient version an update is based on from the
+// If-Match header, responding with an error when it is missing or malformed
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		RespondWithError(c, http.StatusPreconditionRequired, "If-Match header required")
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid If-Match header")
+		return 0, false
+	}
+	return version, true
+}
+
+// respondIfConflict responds with 412 and the current patient when err
+// reports an update based on an outdated version
+func respondIfConflict(c *gin.Context, err error) bool {
+	var conflictErr *services.PatientConflictError
+	if !errors.As(err, &conflictErr) {
+		return false
+	}
+
+	c.Header("ETag", conflictErr.Current.ETag())
+	c.JSON(http.StatusPreconditionFailed, PatientConflictResponse{Error: conflictErr.Error(), Current: conflictErr.Current})
+	return true
+}
+
+// patientAccessor describes the authenticated user's reach over patient records
+func patientAccessor(c *gin.Context) services.PatientAccessor {
+	// RequireAuth only stores a grant that is active and held by the user
+	grant, _ := c.Get("emergencyGrant")
+	emergencyGrant, _ := grant.(*models.EmergencyAccessGrant)
+
+	return services.PatientAccessor{
+		UserID:              GetUserIDFromContext(c),
+		Role:                models.UserRole(c.GetString("userRole")),
+		AllPatients:         HasPermission(c, models.PermPatientsAll),
+		CanOverride:         HasPermission(c, models.PermPatientsOverride),
+		OverrideReason:      strings.TrimSpace(c.GetHeader(AccessOverrideHeader)),
+		CanEditMedical:      HasPermission(c, models.PermMedicalWrite),
+		CanEditDemographics: HasPermission(c, models.PermPatientsWrite),
+		EmergencyGrant:      emergencyGrant,
+		RequestID:           GetRequestIDFromContext(c),
+		ClientIP:            c.ClientIP(),
+	}
+}
\ No newline at end of file
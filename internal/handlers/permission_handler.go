@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionHandler handles role permission requests
+type PermissionHandler struct {
+	permissionService *services.PermissionService
+}
+
+// NewPermissionHandler creates a new PermissionHandler
+func NewPermissionHandler(permissionService *services.PermissionService) *PermissionHandler {
+	return &PermissionHandler{
+		permissionService: permissionService,
+	}
+}
+
+// GetRolePermissions handles get role permissions requests
+// @Summary Get role permissions
+// @Description List the permission set of every role
+// @Tags roles
+// @Produce json
+// @Success 200 {array} models.RolePermissionsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /roles/permissions [get]
+func (h *PermissionHandler) GetRolePermissions(c *gin.Context) {
+	permissions, err := h.permissionService.GetRolePermissions()
+	if err != nil {
+		RespondWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, permissions)
+}
+
+// UpdateRolePermissions handles update role permissions requests
+// @Summary Update role permissions
+// @Description Replace the permission set of a role; users receive it with their next access token
+// @Tags roles
+// @Accept json
+// @Produce json
+// @Param role path string true "Role"
+// @Param request body models.UpdateRolePermissionsRequest true "Update Role Permissions Request"
+// @Success 200 {object} models.RolePermissionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /roles/{role}/permissions [put]
+func (h *PermissionHandler) UpdateRolePermissions(c *gin.Context) {
+	var req models.UpdateRolePermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	res, err := h.permissionService.UpdateRolePermissions(models.UserRole(c.Param("role")), req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidRole) ||
+			errors.Is(err, services.ErrUnknownPermission) ||
+			errors.Is(err, services.ErrAdminLockout) {
+			status = http.StatusBadRequest
+		}
+		RespondWithError(c, status, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+}
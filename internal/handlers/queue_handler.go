@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queueKeepAlive is how often an idle queue stream sends a comment so
+// proxies do not close it
+const queueKeepAlive = 30 * time.Second
+
+// QueueHandler handles check-in queue requests
+type QueueHandler struct {
+	queueService *services.QueueService
+}
+
+// NewQueueHandler creates a new QueueHandler
+func NewQueueHandler(queueService *services.QueueService) *QueueHandler {
+	return &QueueHandler{
+		queueService: queueService,
+	}
+}
+
+// CheckIn handles check-in requests
+// @Summary Check patient in
+// @Description Add a patient to the end of a doctor's queue for today, optionally for one of today's appointments whose doctor is used when doctor_id is left out (requires queue:manage)
+// @Tags queue
+// @Accept json
+// @Produce json
+// @Param request body models.CheckInRequest true "Check In Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.QueueEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /queue [post]
+func (h *QueueHandler) CheckIn(c *gin.Context) {
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	entry, err := h.queueService.CheckIn(patientAccessor(c), req)
+	if err != nil {
+		respondWithQueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetQueue handles get queue requests
+// @Summary Get queue
+// @Description Get today's queue of a doctor, or of every doctor, in check-in order with waiting and consultation times in minutes (requires queue:read)
+// @Tags queue
+// @Produce json
+// @Param doctor query int false "Doctor ID"
+// @Success 200 {object} models.DoctorQueue
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /queue [get]
+func (h *QueueHandler) GetQueue(c *gin.Context) {
+	doctorID, ok := doctorQuery(c)
+	if !ok {
+		return
+	}
+
+	queue, err := h.queueService.GetQueue(patientAccessor(c), doctorID)
+	if err != nil {
+		respondWithQueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// StreamQueue handles queue stream requests
+// @Summary Stream queue
+// @Description Server-Sent Events stream of today's queue of a doctor, or of every doctor; a queue event carries the whole queue on connect and after every change (requires queue:read)
+// @Tags queue
+// @Produce text/event-stream
+// @Param doctor query int false "Doctor ID"
+// @Success 200 {object} models.DoctorQueue
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /queue/stream [get]
+func (h *QueueHandler) StreamQueue(c *gin.Context) {
+	doctorID, ok := doctorQuery(c)
+	if !ok {
+		return
+	}
+
+	// Subscribe before the first read so no change is missed in between
+	changes, unsubscribe := h.queueService.Subscribe(doctorID)
+	defer unsubscribe()
+
+	queue, err := h.queueService.GetQueue(patientAccessor(c), doctorID)
+	if err != nil {
+		respondWithQueueError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("queue", queue)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(queueKeepAlive)
+	defer keepAlive.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-changes:
+			queue, err := h.queueService.RefreshQueue(doctorID)
+			if err != nil {
+				return false
+			}
+			c.SSEvent("queue", queue)
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// CallPatient handles call patient requests
+// @Summary Call patient
+// @Description Call a waiting patient in to the doctor (requires queue:manage)
+// @Tags queue
+// @Produce json
+// @Param id path int true "Queue entry ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.QueueEntry
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /queue/{id}/call [post]
+func (h *QueueHandler) CallPatient(c *gin.Context) {
+	h.transition(c, h.queueService.Call)
+}
+
+// StartConsultation handles start consultation requests
+// @Summary Start consultation
+// @Description Record that the doctor has started seeing a called patient (requires queue:manage)
+// @Tags queue
+// @Produce json
+// @Param id path int true "Queue entry ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.QueueEntry
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /queue/{id}/start [post]
+func (h *QueueHandler) StartConsultation(c *gin.Context) {
+	h.transition(c, h.queueService.StartConsultation)
+}
+
+// CompleteConsultation handles complete consultation requests
+// @Summary Complete consultation
+// @Description Record that a consultation has ended (requires queue:manage)
+// @Tags queue
+// @Produce json
+// @Param id path int true "Queue entry ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.QueueEntry
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /queue/{id}/complete [post]
+func (h *QueueHandler) CompleteConsultation(c *gin.Context) {
+	h.transition(c, h.queueService.Complete)
+}
+
+// AssignDoctor handles assign doctor requests
+// @Summary Assign to doctor
+// @Description Move a waiting patient to the end of another doctor's queue (requires queue:manage)
+// @Tags queue
+// @Accept json
+// @Produce json
+// @Param id path int true "Queue entry ID"
+// @Param request body models.AssignQueueRequest true "Assign Queue Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.QueueEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /queue/{id}/assign [post]
+func (h *QueueHandler) AssignDoctor(c *gin.Context) {
+	id, ok := queueEntryParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssignQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	entry, err := h.queueService.Assign(patientAccessor(c), id, req)
+	if err != nil {
+		respondWithQueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// transition runs a queue status change for the entry in the path
+func (h *QueueHandler) transition(c *gin.Context, move func(services.PatientAccessor, uint) (*models.QueueEntry, error)) {
+	id, ok := queueEntryParam(c)
+	if !ok {
+		return
+	}
+
+	entry, err := move(patientAccessor(c), id)
+	if err != nil {
+		respondWithQueueError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// queueEntryParam parses the queue entry ID from the path
+func queueEntryParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid queue entry ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// doctorQuery parses the optional doctor ID from the query, returning 0
+// when it is left out
+func doctorQuery(c *gin.Context) (uint, bool) {
+	value := c.Query("doctor")
+	if value == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid doctor ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondWithQueueError maps queue service errors to responses
+func respondWithQueueError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrQueueEntryNotFound) ||
+		errors.Is(err, services.ErrAppointmentNotFound) || errors.Is(err, services.ErrDoctorNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrAppointmentOtherPatient) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrAlreadyQueued) || errors.Is(err, services.ErrInvalidQueueTransition) ||
+		errors.Is(err, services.ErrAppointmentCancelled) || errors.Is(err, services.ErrAppointmentNotToday) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID correlating a request with its audit events
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds request IDs supplied by clients
+const maxRequestIDLength = 64
+
+// RequestID is a middleware that assigns every request an ID, reusing the one
+// sent by the client when present, and echoes it in the response
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			buf := make([]byte, 16)
+			if _, err := rand.Read(buf); err == nil {
+				requestID = hex.EncodeToString(buf)
+			}
+		}
+
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// GetRequestIDFromContext gets the request ID from the context
+func GetRequestIDFromContext(c *gin.Context) string {
+	return c.GetString("requestID")
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleHandler handles doctor schedule requests
+type ScheduleHandler struct {
+	scheduleService *services.ScheduleService
+}
+
+// NewScheduleHandler creates a new ScheduleHandler
+func NewScheduleHandler(scheduleService *services.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+	}
+}
+
+// GetSchedule handles get schedule requests
+// @Summary Get doctor schedule
+// @Description Get the weekly hours of a doctor with the exceptions and leave from today on (requires appointments:manage, except for the doctor's own schedule)
+// @Tags schedules
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Success 200 {object} models.DoctorSchedule
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctors/{id}/schedule [get]
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	doctorID, ok := doctorParam(c)
+	if !ok || !canReadSchedule(c, doctorID) {
+		return
+	}
+
+	schedule, err := h.scheduleService.GetSchedule(doctorID)
+	if err != nil {
+		respondWithScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// SetWorkingHours handles set working hours requests
+// @Summary Set doctor working hours
+// @Description Replace the weekly hours of a doctor; each block has a weekday (0 is Sunday), start and end times (HH:MM) and a slot length in minutes (requires appointments:manage)
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param request body models.SetWorkingHoursRequest true "Set Working Hours Request"
+// @Success 200 {array} models.WorkingHours
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctors/{id}/schedule [put]
+func (h *ScheduleHandler) SetWorkingHours(c *gin.Context) {
+	doctorID, ok := doctorParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetWorkingHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	hours, err := h.scheduleService.SetWorkingHours(doctorID, req)
+	if err != nil {
+		respondWithScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// AddException handles add schedule exception requests
+// @Summary Add schedule exception
+// @Description Replace the hours of a doctor on one date, or mark the day off by leaving out the hours (requires appointments:manage)
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param request body models.ScheduleExceptionRequest true "Schedule Exception Request"
+// @Success 201 {object} models.ScheduleException
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctors/{id}/schedule/exceptions [post]
+func (h *ScheduleHandler) AddException(c *gin.Context) {
+	doctorID, ok := doctorParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.ScheduleExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	exception, err := h.scheduleService.AddException(doctorID, req)
+	if err != nil {
+		respondWithScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, exception)
+}
+
+// DeleteException handles delete schedule exception requests
+// @Summary Delete schedule exception
+// @Description Restore the weekly hours of a doctor on the date of an exception (requires appointments:manage)
+// @Tags schedules
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param exceptionId path int true "Exception ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctors/{id}/schedule/exceptions/{exceptionId} [delete]
+func (h *ScheduleHandler) DeleteException(c *gin.Context) {
+	doctorID, ok := doctorParam(c)
+	if !ok {
+		return
+	}
+	exceptionID, err := strconv.ParseUint(c.Param("exceptionId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid exception ID")
+		return
+	}
+
+	if err := h.scheduleService.DeleteException(doctorID, uint(exceptionID)); err != nil {
+		respondWithScheduleError(c, err)
+		return
+	}
+
+	RespondWithSuccess(c, "Schedule exception deleted successfully", nil)
+}
+
+// AddLeave handles add leave requests
+// @Summary Add doctor leave
+// @Description Record whole days a doctor is away, from start_date to end_date inclusive (requires appointments:manage)
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param request body models.DoctorLeaveRequest true "Doctor Leave Request"
+// @Success 201 {object} models.DoctorLeave
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctors/{id}/leave [post]
+func (h *ScheduleHandler) AddLeave(c *gin.Context) {
+	doctorID, ok := doctorParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.DoctorLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	leave, err := h.scheduleService.AddLeave(doctorID, req, GetUserIDFromContext(c))
+	if err != nil {
+		respondWithScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, leave)
+}
+
+// DeleteLeave handles delete leave requests
+// @Summary Delete doctor leave
+// @Description Delete a leave of a doctor (requires appointments:manage)
+// @Tags schedules
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param leaveId path int true "Leave ID"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctors/{id}/leave/{leaveId} [delete]
+func (h *ScheduleHandler) DeleteLeave(c *gin.Context) {
+	doctorID, ok := doctorParam(c)
+	if !ok {
+		return
+	}
+	leaveID, err := strconv.ParseUint(c.Param("leaveId"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid leave ID")
+		return
+	}
+
+	if err := h.scheduleService.DeleteLeave(doctorID, uint(leaveID)); err != nil {
+		respondWithScheduleError(c, err)
+		return
+	}
+
+	RespondWithSuccess(c, "Leave deleted successfully", nil)
+}
+
+// GetAvailability handles get availability requests
+// @Summary Get doctor availability
+// @Description Get the free slots of a doctor on a date that have not started yet (requires appointments:manage, except for the doctor's own availability)
+// @Tags schedules
+// @Produce json
+// @Param id path int true "Doctor ID"
+// @Param date query string true "Date (YYYY-MM-DD)"
+// @Success 200 {object} models.DoctorAvailability
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /doctors/{id}/availability [get]
+func (h *ScheduleHandler) GetAvailability(c *gin.Context) {
+	doctorID, ok := doctorParam(c)
+	if !ok || !canReadSchedule(c, doctorID) {
+		return
+	}
+
+	availability, err := h.scheduleService.GetAvailability(doctorID, c.Query("date"))
+	if err != nil {
+		respondWithScheduleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
+
+// doctorParam parses the doctor ID from the path
+func doctorParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid doctor ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// canReadSchedule checks that the user manages appointments or is the doctor
+// whose schedule is read
+func canReadSchedule(c *gin.Context, doctorID uint) bool {
+	if HasPermission(c, models.PermAppointmentsManage) || GetUserIDFromContext(c) == doctorID {
+		return true
+	}
+	RespondWithError(c, http.StatusForbidden, "Permission "+string(models.PermAppointmentsManage)+" required")
+	return false
+}
+
+// respondWithScheduleError maps schedule service errors to responses
+func respondWithScheduleError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrDoctorNotFound) || errors.Is(err, services.ErrScheduleExceptionNotFound) ||
+		errors.Is(err, services.ErrLeaveNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrInvalidWorkingHours) ||
+		errors.Is(err, services.ErrInvalidDate) ||
+		errors.Is(err, services.ErrInvalidDateRange) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrScheduleExceptionExists) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
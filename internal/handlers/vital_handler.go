@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VitalHandler handles vital sign requests
+type VitalHandler struct {
+	vitalService *services.VitalService
+}
+
+// NewVitalHandler creates a new VitalHandler
+func NewVitalHandler(vitalService *services.VitalService) *VitalHandler {
+	return &VitalHandler{
+		vitalService: vitalService,
+	}
+}
+
+// RecordVitals handles record vitals requests
+// @Summary Record vital signs
+// @Description Record a set of vital signs taken together; weight may be given in kg or lb, temperature in C or F and height in cm, m or in (requires medical:write)
+// @Tags vitals
+// @Accept json
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param request body models.RecordVitalsRequest true "Record Vitals Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {array} models.Vital
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/vitals [post]
+func (h *VitalHandler) RecordVitals(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var req models.RecordVitalsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	vitals, err := h.vitalService.RecordVitals(patientAccessor(c), uint(patientID), req)
+	if err != nil {
+		respondWithVitalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, vitals)
+}
+
+// GetVitals handles get vitals requests
+// @Summary Get vital signs
+// @Description Get the vital signs of a patient oldest first for trend charts, flagged as abnormal outside adult reference ranges
+// @Tags vitals
+// @Produce json
+// @Param id path int true "Patient ID"
+// @Param type query string false "Vital sign type (bp, pulse, temperature, spo2, height, weight, bmi)"
+// @Param from query string false "Only observations recorded at or after this time (RFC 3339)"
+// @Param to query string false "Only observations recorded before this time (RFC 3339)"
+// @Param units query string false "metric (default) or imperial"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {array} models.Vital
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /patients/{id}/vitals [get]
+func (h *VitalHandler) GetVitals(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid patient ID")
+		return
+	}
+
+	var filter models.VitalFilter
+	if v := c.Query("type"); v != "" {
+		filter.Type = models.VitalType(v)
+		if _, ok := models.VitalUnits[filter.Type]; !ok {
+			RespondWithError(c, http.StatusBadRequest, "Invalid vital sign type")
+			return
+		}
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid from time")
+			return
+		}
+		filter.From = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid to time")
+			return
+		}
+		filter.To = &to
+	}
+
+	units := c.DefaultQuery("units", "metric")
+	if units != "metric" && units != "imperial" {
+		RespondWithError(c, http.StatusBadRequest, "Invalid units")
+		return
+	}
+
+	vitals, err := h.vitalService.GetVitals(patientAccessor(c), uint(patientID), filter, units == "imperial")
+	if err != nil {
+		respondWithVitalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, vitals)
+}
+
+// respondWithVitalError maps vital sign service errors to responses
+func respondWithVitalError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrEncounterNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrImplausibleVital) ||
+		errors.Is(err, services.ErrInvalidVitalUnit) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrEncounterNotOpen) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
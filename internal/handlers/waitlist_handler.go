@@ -0,0 +1,254 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WaitlistHandler handles waitlist and slot offer requests
+type WaitlistHandler struct {
+	waitlistService *services.WaitlistService
+}
+
+// NewWaitlistHandler creates a new WaitlistHandler
+func NewWaitlistHandler(waitlistService *services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{
+		waitlistService: waitlistService,
+	}
+}
+
+// AddEntry handles add waitlist entry requests
+// @Summary Add to waitlist
+// @Description Put a patient on a doctor's waitlist for a type of appointment, with the weekdays (0 is Sunday) and times of day (HH:MM) they can come; slots freed by cancellations are offered in the order patients were added (requires appointments:manage)
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Param request body models.CreateWaitlistEntryRequest true "Create Waitlist Entry Request"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.WaitlistEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /waitlist [post]
+func (h *WaitlistHandler) AddEntry(c *gin.Context) {
+	var req models.CreateWaitlistEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		RespondWithError(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	entry, err := h.waitlistService.AddEntry(patientAccessor(c), req)
+	if err != nil {
+		respondWithWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetEntries handles get waitlist requests
+// @Summary Get waitlist
+// @Description List waitlist entries oldest first, filtered by doctor, appointment type and status (requires appointments:manage)
+// @Tags waitlist
+// @Produce json
+// @Param doctor_id query int false "Doctor ID"
+// @Param type query string false "Appointment type (consultation, follow-up, procedure)"
+// @Param status query string false "Status (waiting, offered, booked, removed)"
+// @Success 200 {array} models.PatientWaitlistEntry
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /waitlist [get]
+func (h *WaitlistHandler) GetEntries(c *gin.Context) {
+	var filter models.WaitlistFilter
+	if v := c.Query("doctor_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid doctor ID")
+			return
+		}
+		filter.DoctorID = uint(id)
+	}
+	if v := c.Query("type"); v != "" {
+		filter.Type = models.AppointmentType(v)
+		if filter.Type != models.AppointmentTypeConsultation && filter.Type != models.AppointmentTypeFollowUp &&
+			filter.Type != models.AppointmentTypeProcedure {
+			RespondWithError(c, http.StatusBadRequest, "Invalid appointment type")
+			return
+		}
+	}
+	if v := c.Query("status"); v != "" {
+		filter.Status = models.WaitlistStatus(v)
+		if filter.Status != models.WaitlistStatusWaiting && filter.Status != models.WaitlistStatusOffered &&
+			filter.Status != models.WaitlistStatusBooked && filter.Status != models.WaitlistStatusRemoved {
+			RespondWithError(c, http.StatusBadRequest, "Invalid status")
+			return
+		}
+	}
+
+	entries, err := h.waitlistService.GetEntries(patientAccessor(c), filter)
+	if err != nil {
+		respondWithWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// RemoveEntry handles remove waitlist entry requests
+// @Summary Remove from waitlist
+// @Description Take a waiting patient off the waitlist (requires appointments:manage)
+// @Tags waitlist
+// @Produce json
+// @Param id path int true "Waitlist entry ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.WaitlistEntry
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /waitlist/{id} [delete]
+func (h *WaitlistHandler) RemoveEntry(c *gin.Context) {
+	id, ok := waitlistParam(c, "Invalid waitlist entry ID")
+	if !ok {
+		return
+	}
+
+	entry, err := h.waitlistService.RemoveEntry(patientAccessor(c), id)
+	if err != nil {
+		respondWithWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetOffers handles get slot offers requests
+// @Summary Get slot offers
+// @Description List slots offered from the waitlist newest first, filtered by doctor and status (requires appointments:manage)
+// @Tags waitlist
+// @Produce json
+// @Param doctor_id query int false "Doctor ID"
+// @Param status query string false "Status (pending, accepted, declined, expired)"
+// @Success 200 {array} models.SlotOffer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /waitlist/offers [get]
+func (h *WaitlistHandler) GetOffers(c *gin.Context) {
+	var filter models.SlotOfferFilter
+	if v := c.Query("doctor_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			RespondWithError(c, http.StatusBadRequest, "Invalid doctor ID")
+			return
+		}
+		filter.DoctorID = uint(id)
+	}
+	if v := c.Query("status"); v != "" {
+		filter.Status = models.SlotOfferStatus(v)
+		if filter.Status != models.SlotOfferStatusPending && filter.Status != models.SlotOfferStatusAccepted &&
+			filter.Status != models.SlotOfferStatusDeclined && filter.Status != models.SlotOfferStatusExpired {
+			RespondWithError(c, http.StatusBadRequest, "Invalid status")
+			return
+		}
+	}
+
+	offers, err := h.waitlistService.GetOffers(patientAccessor(c), filter)
+	if err != nil {
+		respondWithWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, offers)
+}
+
+// AcceptOffer handles accept slot offer requests
+// @Summary Accept slot offer
+// @Description Book the slot of an open offer for its patient (requires appointments:manage)
+// @Tags waitlist
+// @Produce json
+// @Param id path int true "Slot offer ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 201 {object} models.Appointment
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /waitlist/offers/{id}/accept [post]
+func (h *WaitlistHandler) AcceptOffer(c *gin.Context) {
+	id, ok := waitlistParam(c, "Invalid slot offer ID")
+	if !ok {
+		return
+	}
+
+	appointment, err := h.waitlistService.AcceptOffer(patientAccessor(c), id)
+	if err != nil {
+		respondWithWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, appointment)
+}
+
+// DeclineOffer handles decline slot offer requests
+// @Summary Decline slot offer
+// @Description Decline an open offer, keeping the patient on the waitlist, and offer the slot to the next patient (requires appointments:manage)
+// @Tags waitlist
+// @Produce json
+// @Param id path int true "Slot offer ID"
+// @Param X-Access-Override-Reason header string false "Justification for access outside the care team"
+// @Success 200 {object} models.SlotOffer
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Router /waitlist/offers/{id}/decline [post]
+func (h *WaitlistHandler) DeclineOffer(c *gin.Context) {
+	id, ok := waitlistParam(c, "Invalid slot offer ID")
+	if !ok {
+		return
+	}
+
+	offer, err := h.waitlistService.DeclineOffer(patientAccessor(c), id)
+	if err != nil {
+		respondWithWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// waitlistParam parses a waitlist entry or slot offer ID from the path
+func waitlistParam(c *gin.Context, invalid string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		RespondWithError(c, http.StatusBadRequest, invalid)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondWithWaitlistError maps waitlist service errors to responses
+func respondWithWaitlistError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, services.ErrPatientNotFound) || errors.Is(err, services.ErrWaitlistEntryNotFound) ||
+		errors.Is(err, services.ErrSlotOfferNotFound) || errors.Is(err, services.ErrDoctorNotFound) {
+		status = http.StatusNotFound
+	} else if errors.Is(err, services.ErrPatientAccessDenied) {
+		status = http.StatusForbidden
+	} else if errors.Is(err, services.ErrInvalidWaitlistPreferences) {
+		status = http.StatusBadRequest
+	} else if errors.Is(err, services.ErrWaitlistEntryNotWaiting) || errors.Is(err, services.ErrSlotOfferClosed) ||
+		errors.Is(err, services.ErrDoctorOnLeave) {
+		status = http.StatusConflict
+	}
+	RespondWithError(c, status, err.Error())
+}
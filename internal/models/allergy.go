@@ -0,0 +1,126 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AllergyCategory represents the kind of substance causing an allergy
+type AllergyCategory string
+
+// Allergy categories
+const (
+	AllergyCategoryFood        AllergyCategory = "food"
+	AllergyCategoryMedication  AllergyCategory = "medication"
+	AllergyCategoryEnvironment AllergyCategory = "environment"
+	AllergyCategoryBiologic    AllergyCategory = "biologic"
+)
+
+// AllergyCriticality represents the potential severity of future reactions
+type AllergyCriticality string
+
+// Allergy criticalities
+const (
+	AllergyCriticalityLow            AllergyCriticality = "low"
+	AllergyCriticalityHigh           AllergyCriticality = "high"
+	AllergyCriticalityUnableToAssess AllergyCriticality = "unable-to-assess"
+)
+
+// AllergyStatus represents the clinical status of an allergy
+type AllergyStatus string
+
+// Allergy statuses
+const (
+	AllergyStatusActive   AllergyStatus = "active"
+	AllergyStatusInactive AllergyStatus = "inactive"
+	AllergyStatusResolved AllergyStatus = "resolved"
+)
+
+// StringList is a list of strings stored as JSON
+type StringList []string
+
+// Value stores the list as JSON
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the list from JSON
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return errors.New("unsupported string list value")
+	}
+}
+
+// Allergy is a documented allergy or intolerance of a patient
+type Allergy struct {
+	ID              uint               `json:"id" gorm:"primaryKey"`
+	PatientID       uint               `json:"patient_id" gorm:"not null;index"`
+	Substance       string             `json:"substance" gorm:"not null"`
+	SubstanceCode   string             `json:"substance_code,omitempty"`
+	SubstanceSystem string             `json:"substance_system,omitempty"`
+	Category        AllergyCategory    `json:"category,omitempty"`
+	Criticality     AllergyCriticality `json:"criticality" gorm:"not null"`
+	Reactions       StringList         `json:"reactions" gorm:"type:jsonb;not null"`
+	Onset           *time.Time         `json:"onset,omitempty"`
+	Note            string             `json:"note,omitempty"`
+	Status          AllergyStatus      `json:"status" gorm:"not null;index"`
+	RecordedBy      uint               `json:"recorded_by" gorm:"not null"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+// AllergyRequest represents a request to record or update an allergy
+type AllergyRequest struct {
+	Substance       string             `json:"substance" binding:"required"`
+	SubstanceCode   string             `json:"substance_code"`
+	SubstanceSystem string             `json:"substance_system"`
+	Category        AllergyCategory    `json:"category" binding:"omitempty,oneof=food medication environment biologic"`
+	Criticality     AllergyCriticality `json:"criticality" binding:"omitempty,oneof=low high unable-to-assess"`
+	Reactions       []string           `json:"reactions"`
+	Onset           *time.Time         `json:"onset"`
+	Note            string             `json:"note"`
+	Status          AllergyStatus      `json:"status" binding:"omitempty,oneof=active inactive resolved"`
+}
+
+// Apply copies the request onto an allergy, filling in the defaults for
+// omitted criticality and status
+func (a *Allergy) Apply(req AllergyRequest) {
+	a.Substance = req.Substance
+	a.SubstanceCode = req.SubstanceCode
+	a.SubstanceSystem = req.SubstanceSystem
+	a.Category = req.Category
+	a.Criticality = req.Criticality
+	if a.Criticality == "" {
+		a.Criticality = AllergyCriticalityUnableToAssess
+	}
+	a.Reactions = StringList(req.Reactions)
+	if a.Reactions == nil {
+		a.Reactions = StringList{}
+	}
+	a.Onset = req.Onset
+	a.Note = req.Note
+	a.Status = req.Status
+	if a.Status == "" {
+		a.Status = AllergyStatusActive
+	}
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+)
+
+// AppointmentType represents the kind of a booked visit
+type AppointmentType string
+
+// Appointment types
+const (
+	AppointmentTypeConsultation AppointmentType = "consultation"
+	AppointmentTypeFollowUp     AppointmentType = "follow-up"
+	AppointmentTypeProcedure    AppointmentType = "procedure"
+)
+
+// AppointmentStatus represents whether an appointment still holds its slot
+type AppointmentStatus string
+
+// Appointment statuses
+const (
+	AppointmentStatusBooked    AppointmentStatus = "booked"
+	AppointmentStatusCancelled AppointmentStatus = "cancelled"
+)
+
+// Appointment is a slot of a doctor's time booked for a patient. The
+// database rejects booked appointments of a doctor that overlap.
+type Appointment struct {
+	ID                 uint              `json:"id" gorm:"primaryKey"`
+	PatientID          uint              `json:"patient_id" gorm:"not null;index"`
+	DoctorID           uint              `json:"doctor_id" gorm:"not null;index:idx_appointments_doctor_start"`
+	SeriesID           *uint             `json:"series_id,omitempty" gorm:"index"`
+	Type               AppointmentType   `json:"type" gorm:"not null"`
+	Status             AppointmentStatus `json:"status" gorm:"not null;index"`
+	StartTime          time.Time         `json:"start_time" gorm:"not null;index:idx_appointments_doctor_start"`
+	EndTime            time.Time         `json:"end_time" gorm:"not null"`
+	Reason             string            `json:"reason"`
+	RescheduleReason   string            `json:"reschedule_reason,omitempty"`
+	CancellationReason string            `json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time        `json:"cancelled_at,omitempty"`
+	BookedBy           uint              `json:"booked_by" gorm:"not null"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// PatientAppointment is an appointment with the name of its patient, as
+// listed in day lists
+type PatientAppointment struct {
+	Appointment      `gorm:"embedded"`
+	PatientFirstName string `json:"patient_first_name"`
+	PatientLastName  string `json:"patient_last_name"`
+}
+
+// AppointmentFilter narrows an appointment listing. Empty fields match all
+// appointments.
+type AppointmentFilter struct {
+	DoctorID  uint
+	PatientID uint
+	Status    AppointmentStatus
+	// From and To bound the start time; To is exclusive
+	From *time.Time
+	To   *time.Time
+}
+
+// CreateAppointmentRequest represents a request to book a free slot
+type CreateAppointmentRequest struct {
+	PatientID uint            `json:"patient_id" binding:"required"`
+	DoctorID  uint            `json:"doctor_id" binding:"required"`
+	StartTime time.Time       `json:"start_time" binding:"required"`
+	Type      AppointmentType `json:"type" binding:"required,oneof=consultation follow-up procedure"`
+	Reason    string          `json:"reason"`
+}
+
+// RescheduleAppointmentRequest represents a request to move an appointment
+// to another free slot of the same doctor
+type RescheduleAppointmentRequest struct {
+	StartTime time.Time `json:"start_time" binding:"required"`
+	Reason    string    `json:"reason" binding:"required"`
+}
+
+// CancelAppointmentRequest represents a request to cancel an appointment
+type CancelAppointmentRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
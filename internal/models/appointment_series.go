@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+)
+
+// AppointmentSeries is a set of appointments booked together from a
+// recurrence rule. Its occurrences are ordinary appointments that can be
+// rescheduled or cancelled one by one.
+type AppointmentSeries struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	PatientID uint            `json:"patient_id" gorm:"not null;index"`
+	DoctorID  uint            `json:"doctor_id" gorm:"not null;index"`
+	Type      AppointmentType `json:"type" gorm:"not null"`
+	// Rule is the RRULE the occurrences were generated from
+	Rule               string            `json:"rule" gorm:"not null"`
+	Status             AppointmentStatus `json:"status" gorm:"not null"`
+	StartTime          time.Time         `json:"start_time" gorm:"not null"`
+	Reason             string            `json:"reason"`
+	CancellationReason string            `json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time        `json:"cancelled_at,omitempty"`
+	CreatedBy          uint              `json:"created_by" gorm:"not null"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	Appointments       []Appointment     `json:"appointments" gorm:"-"`
+}
+
+// TableName sets the table name of appointment series
+func (AppointmentSeries) TableName() string {
+	return "appointment_series"
+}
+
+// FailedOccurrence is an occurrence of a series that could not be booked or
+// moved, with the reason
+type FailedOccurrence struct {
+	AppointmentID uint      `json:"appointment_id,omitempty"`
+	StartTime     time.Time `json:"start_time"`
+	Reason        string    `json:"reason"`
+}
+
+// AppointmentSeriesResult is a series after a change to its occurrences,
+// with the occurrences the change could not be applied to
+type AppointmentSeriesResult struct {
+	Series *AppointmentSeries `json:"series"`
+	Failed []FailedOccurrence `json:"failed"`
+}
+
+// CreateAppointmentSeriesRequest represents a request to book recurring
+// appointments. StartTime is the first occurrence and sets the time of day
+// of the others; Rule is an RRULE such as FREQ=WEEKLY;BYDAY=MO,TH;COUNT=16.
+type CreateAppointmentSeriesRequest struct {
+	PatientID uint            `json:"patient_id" binding:"required"`
+	DoctorID  uint            `json:"doctor_id" binding:"required"`
+	StartTime time.Time       `json:"start_time" binding:"required"`
+	Rule      string          `json:"rule" binding:"required"`
+	Type      AppointmentType `json:"type" binding:"required,oneof=consultation follow-up procedure"`
+	Reason    string          `json:"reason"`
+}
+
+// RescheduleSeriesRequest represents a request to move an occurrence and
+// the following ones by the same number of days to a new time of day
+type RescheduleSeriesRequest struct {
+	AppointmentID uint      `json:"appointment_id" binding:"required"`
+	StartTime     time.Time `json:"start_time" binding:"required"`
+	Reason        string    `json:"reason" binding:"required"`
+}
+
+// CancelSeriesRequest represents a request to cancel a whole series, or an
+// occurrence and the following ones when AppointmentID is set
+type CancelSeriesRequest struct {
+	AppointmentID uint   `json:"appointment_id"`
+	Reason        string `json:"reason" binding:"required"`
+}
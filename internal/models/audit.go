@@ -0,0 +1,224 @@
+package models
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// AuditAction is an action recorded in the audit trail
+type AuditAction string
+
+// Audit actions
+const (
+	AuditActionCreate             AuditAction = "create"
+	AuditActionRead               AuditAction = "read"
+	AuditActionList               AuditAction = "list"
+	AuditActionSearch             AuditAction = "search"
+	AuditActionUpdate             AuditAction = "update"
+	AuditActionUpdateMedical      AuditAction = "update_medical"
+	AuditActionDelete             AuditAction = "delete"
+	AuditActionRevert             AuditAction = "revert"
+	AuditActionCareTeamAssign     AuditAction = "care_team_assign"
+	AuditActionCareTeamUnassign   AuditAction = "care_team_unassign"
+	AuditActionAllergyCreate      AuditAction = "allergy_create"
+	AuditActionAllergyUpdate      AuditAction = "allergy_update"
+	AuditActionAllergyDelete      AuditAction = "allergy_delete"
+	AuditActionMedicationCreate   AuditAction = "medication_create"
+	AuditActionMedicationUpdate   AuditAction = "medication_update"
+	AuditActionConditionCreate    AuditAction = "condition_create"
+	AuditActionConditionUpdate    AuditAction = "condition_update"
+	AuditActionConditionDelete    AuditAction = "condition_delete"
+	AuditActionVitalsRecord       AuditAction = "vitals_record"
+	AuditActionEncounterCreate    AuditAction = "encounter_create"
+	AuditActionEncounterUpdate    AuditAction = "encounter_update"
+	AuditActionNoteCreate         AuditAction = "note_create"
+	AuditActionNoteUpdate         AuditAction = "note_update"
+	AuditActionNoteSign           AuditAction = "note_sign"
+	AuditActionNoteAddendum       AuditAction = "note_addendum"
+	AuditActionAppointmentCreate  AuditAction = "appointment_create"
+	AuditActionAppointmentUpdate  AuditAction = "appointment_update"
+	AuditActionQueueCreate        AuditAction = "queue_create"
+	AuditActionQueueUpdate        AuditAction = "queue_update"
+	AuditActionWaitlistCreate     AuditAction = "waitlist_create"
+	AuditActionWaitlistUpdate     AuditAction = "waitlist_update"
+	AuditActionNotificationCreate AuditAction = "notification_create"
+	AuditActionContactUpdate      AuditAction = "contact_update"
+	AuditActionAccessDenied       AuditAction = "access_denied"
+	AuditActionEmergencyRead      AuditAction = "emergency_read"
+	AuditActionBreakGlass         AuditAction = "break_glass"
+)
+
+// FieldChange holds the value of a field before and after a write
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges maps the JSON names of changed fields to their changes
+type AuditChanges map[string]FieldChange
+
+// Value stores the changes as JSON
+func (c AuditChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the changes from JSON
+func (c *AuditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return errors.New("unsupported audit changes value")
+	}
+}
+
+// AuditEvent is an entry of the append-only audit trail. Each event carries
+// the hash of the previous one, so altering or removing an event breaks the
+// chain.
+type AuditEvent struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	ActorID   uint         `json:"actor_id" gorm:"not null;index"`
+	ActorRole UserRole     `json:"actor_role" gorm:"not null"`
+	Action    AuditAction  `json:"action" gorm:"not null;index"`
+	PatientID *uint        `json:"patient_id,omitempty" gorm:"index"`
+	Changes   AuditChanges `json:"changes,omitempty" gorm:"type:jsonb"`
+	Details   string       `json:"details,omitempty"`
+	RequestID string       `json:"request_id"`
+	ClientIP  string       `json:"client_ip"`
+	CreatedAt time.Time    `json:"created_at" gorm:"not null;index"`
+	PrevHash  string       `json:"prev_hash" gorm:"not null"`
+	Hash      string       `json:"hash" gorm:"not null;uniqueIndex"`
+}
+
+// ComputeHash hashes the event's content together with PrevHash
+func (e *AuditEvent) ComputeHash() string {
+	changes, _ := json.Marshal(e.Changes)
+	patientID := ""
+	if e.PatientID != nil {
+		patientID = fmt.Sprint(*e.PatientID)
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		e.PrevHash,
+		fmt.Sprint(e.ActorID),
+		string(e.ActorRole),
+		string(e.Action),
+		patientID,
+		string(changes),
+		e.Details,
+		e.RequestID,
+		e.ClientIP,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditFilter narrows an audit trail listing. Empty fields match all events.
+type AuditFilter struct {
+	ActorID   uint
+	PatientID uint
+	Action    AuditAction
+	From      *time.Time
+	To        *time.Time
+}
+
+// AuditChainVerification reports the result of checking the audit hash chain
+type AuditChainVerification struct {
+	Valid          bool  `json:"valid"`
+	EventsChecked  int64 `json:"events_checked"`
+	FirstInvalidID *uint `json:"first_invalid_id,omitempty"`
+}
+
+// DiffPatients lists the fields that differ between two versions of a
+// patient. A nil version stands for a record that does not exist, as before a
+// create or after a delete.
+func DiffPatients(before, after *Patient) AuditChanges {
+	return DiffRecords(before, after)
+}
+
+// DiffRecords lists the fields that differ between two versions of a record.
+// A nil version stands for a record that does not exist.
+func DiffRecords[T any](before, after *T) AuditChanges {
+	changes := AuditChanges{}
+	for _, field := range auditFields(reflect.TypeOf((*T)(nil)).Elem()) {
+		var oldValue, newValue interface{}
+		if before != nil {
+			oldValue = reflect.ValueOf(before).Elem().FieldByIndex(field.index).Interface()
+		}
+		if after != nil {
+			newValue = reflect.ValueOf(after).Elem().FieldByIndex(field.index).Interface()
+		}
+		if before != nil && after != nil && valuesEqual(oldValue, newValue) {
+			continue
+		}
+		changes[field.name] = FieldChange{Before: oldValue, After: newValue}
+	}
+	return changes
+}
+
+// auditField is a record field tracked by the audit trail
+type auditField struct {
+	name  string
+	index []int
+}
+
+// patientAuditFields lists the patient fields tracked by the audit trail
+func patientAuditFields() []auditField {
+	return auditFields(reflect.TypeOf(Patient{}))
+}
+
+// auditFields lists the fields of a record type tracked by the audit trail,
+// leaving out the bookkeeping columns and fields that are not stored with
+// the record
+func auditFields(t reflect.Type) []auditField {
+	var fields []auditField
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("gorm") == "-" {
+			continue
+		}
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		switch name {
+		case "", "-", "id", "version", "created_at", "updated_at":
+			continue
+		}
+		fields = append(fields, auditField{name: name, index: t.Field(i).Index})
+	}
+	return fields
+}
+
+// valuesEqual compares two field values, treating equal instants as equal
+// regardless of location
+func valuesEqual(a, b interface{}) bool {
+	switch at := a.(type) {
+	case time.Time:
+		bt, ok := b.(time.Time)
+		return ok && at.Equal(bt)
+	case *time.Time:
+		bt, ok := b.(*time.Time)
+		if !ok || at == nil || bt == nil {
+			return ok && at == bt
+		}
+		return at.Equal(*bt)
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
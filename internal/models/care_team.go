@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// CareTeamRole represents a clinician's role in a patient's care team
+type CareTeamRole string
+
+const (
+	CareTeamPrimary    CareTeamRole = "primary"
+	CareTeamConsulting CareTeamRole = "consulting"
+)
+
+// CareTeamAssignment assigns a clinician to a patient's care team for a
+// period of time. An assignment without an end date lasts until it is ended.
+type CareTeamAssignment struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	PatientID   uint         `json:"patient_id" gorm:"not null;index"`
+	ClinicianID uint         `json:"clinician_id" gorm:"not null;index"`
+	Role        CareTeamRole `json:"role" gorm:"size:20;not null"`
+	StartDate   time.Time    `json:"start_date" gorm:"not null"`
+	EndDate     *time.Time   `json:"end_date,omitempty"`
+	AssignedBy  uint         `json:"assigned_by" gorm:"not null"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// IsActiveAt checks if the assignment is in effect at a point in time
+func (a *CareTeamAssignment) IsActiveAt(t time.Time) bool {
+	return !a.StartDate.After(t) && (a.EndDate == nil || a.EndDate.After(t))
+}
+
+// AccessOverride records access to a patient outside the user's care team
+type AccessOverride struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	PatientID uint      `json:"patient_id" gorm:"not null;index"`
+	Action    string    `json:"action" gorm:"size:50;not null"`
+	Reason    string    `json:"reason" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AssignClinicianRequest represents a request to add a clinician to a care team
+type AssignClinicianRequest struct {
+	ClinicianID uint         `json:"clinician_id" binding:"required"`
+	Role        CareTeamRole `json:"role" binding:"required,oneof=primary consulting"`
+	StartDate   *time.Time   `json:"start_date"`
+	EndDate     *time.Time   `json:"end_date"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// ClinicalNoteStatus represents where a clinical note is in its lifecycle
+type ClinicalNoteStatus string
+
+// Clinical note statuses
+const (
+	ClinicalNoteStatusDraft  ClinicalNoteStatus = "draft"
+	ClinicalNoteStatusSigned ClinicalNoteStatus = "signed"
+)
+
+// ClinicalNote is a SOAP note written during an encounter. Drafts may be
+// edited by their author; signing locks the note, after which corrections
+// are made with addenda.
+type ClinicalNote struct {
+	ID          uint               `json:"id" gorm:"primaryKey"`
+	PatientID   uint               `json:"patient_id" gorm:"not null;index"`
+	EncounterID uint               `json:"encounter_id" gorm:"not null;index"`
+	AuthorID    uint               `json:"author_id" gorm:"not null"`
+	Status      ClinicalNoteStatus `json:"status" gorm:"not null"`
+	Subjective  string             `json:"subjective"`
+	Objective   string             `json:"objective"`
+	Assessment  string             `json:"assessment"`
+	Plan        string             `json:"plan"`
+	SignedBy    *uint              `json:"signed_by,omitempty"`
+	SignedAt    *time.Time         `json:"signed_at,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+
+	// Addenda holds the corrections made after signing, oldest first
+	Addenda []NoteAddendum `json:"addenda" gorm:"-"`
+}
+
+// IsEmpty checks if none of the SOAP sections of a note have been written
+func (n *ClinicalNote) IsEmpty() bool {
+	return strings.TrimSpace(n.Subjective+n.Objective+n.Assessment+n.Plan) == ""
+}
+
+// Apply replaces the SOAP sections of a note
+func (n *ClinicalNote) Apply(req ClinicalNoteRequest) {
+	n.Subjective = req.Subjective
+	n.Objective = req.Objective
+	n.Assessment = req.Assessment
+	n.Plan = req.Plan
+}
+
+// NoteAddendum is a correction or addition to a signed clinical note. It
+// cannot be changed once written.
+type NoteAddendum struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	NoteID    uint      `json:"note_id" gorm:"not null;index"`
+	AuthorID  uint      `json:"author_id" gorm:"not null"`
+	Body      string    `json:"body" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName gives the addenda table its plural
+func (NoteAddendum) TableName() string {
+	return "note_addenda"
+}
+
+// CreateClinicalNoteRequest represents a request to start a note for an
+// encounter
+type CreateClinicalNoteRequest struct {
+	EncounterID uint `json:"encounter_id" binding:"required"`
+	ClinicalNoteRequest
+}
+
+// ClinicalNoteRequest represents the SOAP sections of a draft note
+type ClinicalNoteRequest struct {
+	Subjective string `json:"subjective"`
+	Objective  string `json:"objective"`
+	Assessment string `json:"assessment"`
+	Plan       string `json:"plan"`
+}
+
+// NoteAddendumRequest represents a request to add an addendum to a signed
+// note
+type NoteAddendumRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// LegacyNotes holds the free-text notes of a patient written before clinical
+// notes existed
+type LegacyNotes struct {
+	PatientID uint   `json:"patient_id"`
+	Notes     string `json:"notes"`
+}
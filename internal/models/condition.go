@@ -0,0 +1,92 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ConditionClinicalStatus represents the clinical status of a condition
+type ConditionClinicalStatus string
+
+// Condition clinical statuses
+const (
+	ConditionStatusActive     ConditionClinicalStatus = "active"
+	ConditionStatusRecurrence ConditionClinicalStatus = "recurrence"
+	ConditionStatusRelapse    ConditionClinicalStatus = "relapse"
+	ConditionStatusInactive   ConditionClinicalStatus = "inactive"
+	ConditionStatusRemission  ConditionClinicalStatus = "remission"
+	ConditionStatusResolved   ConditionClinicalStatus = "resolved"
+)
+
+// CurrentConditionStatuses are the clinical statuses of conditions the
+// patient currently has
+var CurrentConditionStatuses = []ConditionClinicalStatus{
+	ConditionStatusActive,
+	ConditionStatusRecurrence,
+	ConditionStatusRelapse,
+}
+
+// Condition is an ICD-10 coded entry of a patient's problem list
+type Condition struct {
+	ID             uint                    `json:"id" gorm:"primaryKey"`
+	PatientID      uint                    `json:"patient_id" gorm:"not null;index"`
+	EncounterID    *uint                   `json:"encounter_id,omitempty" gorm:"index"`
+	Code           string                  `json:"code" gorm:"not null;index"`
+	Description    string                  `json:"description" gorm:"not null"`
+	ClinicalStatus ConditionClinicalStatus `json:"clinical_status" gorm:"not null;index"`
+	OnsetDate      *time.Time              `json:"onset_date,omitempty"`
+	ResolutionDate *time.Time              `json:"resolution_date,omitempty"`
+	Note           string                  `json:"note,omitempty"`
+	DiagnosedBy    uint                    `json:"diagnosed_by" gorm:"not null"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt          `json:"-" gorm:"index"`
+}
+
+// ConditionRequest represents a request to record or replace a condition
+type ConditionRequest struct {
+	Code string `json:"code" binding:"required"`
+	// Description defaults to the ICD-10 description of the code
+	Description    string                  `json:"description"`
+	ClinicalStatus ConditionClinicalStatus `json:"clinical_status" binding:"omitempty,oneof=active recurrence relapse inactive remission resolved"`
+	OnsetDate      *time.Time              `json:"onset_date"`
+	ResolutionDate *time.Time              `json:"resolution_date"`
+	Note           string                  `json:"note"`
+	EncounterID    *uint                   `json:"encounter_id"`
+}
+
+// Apply applies a ConditionRequest whose code has been resolved to an
+// ICD-10 code, filling in defaults for omitted fields
+func (c *Condition) Apply(req ConditionRequest, code ICD10Code) {
+	c.Code = code.Code
+	c.Description = strings.TrimSpace(req.Description)
+	if c.Description == "" {
+		c.Description = code.Description
+	}
+	c.ClinicalStatus = req.ClinicalStatus
+	if c.ClinicalStatus == "" {
+		c.ClinicalStatus = ConditionStatusActive
+	}
+	c.OnsetDate = req.OnsetDate
+	c.ResolutionDate = req.ResolutionDate
+	c.Note = req.Note
+	c.EncounterID = req.EncounterID
+}
+
+// ICD10Code is an entry of the ICD-10 code set
+type ICD10Code struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+// NormalizeICD10Code formats an ICD-10 code in upper case with a dot after
+// the category, e.g. "e119" becomes "E11.9"
+func NormalizeICD10Code(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), ".", ""))
+	if len(code) > 3 {
+		return code[:3] + "." + code[3:]
+	}
+	return code
+}
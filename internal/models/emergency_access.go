@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+// EmergencyAccessGrant is a time-boxed break-the-glass grant giving one user
+// emergency access to one patient. Grants are never updated or deleted.
+type EmergencyAccessGrant struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	PatientID uint      `json:"patient_id" gorm:"not null;index"`
+	Reason    string    `json:"reason" gorm:"not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// IsActiveAt checks if the grant has not expired at a point in time
+func (g *EmergencyAccessGrant) IsActiveAt(t time.Time) bool {
+	return t.Before(g.ExpiresAt)
+}
+
+// BreakGlassRequest represents a request for emergency access to a patient
+type BreakGlassRequest struct {
+	Reason string `json:"reason" binding:"required,min=10"`
+}
+
+// EmergencySummary represents the patient information available under an
+// emergency access grant: the patient's allergies and active medications
+type EmergencySummary struct {
+	PatientID      uint         `json:"patient_id"`
+	FirstName      string       `json:"first_name"`
+	LastName       string       `json:"last_name"`
+	DateOfBirth    time.Time    `json:"date_of_birth"`
+	AllergyList    []Allergy    `json:"allergy_list"`
+	Medications    []Medication `json:"medications"`
+	GrantExpiresAt time.Time    `json:"grant_expires_at"`
+}
+
+// EmergencyAccessFilter narrows the emergency access report. Empty fields
+// match all grants.
+type EmergencyAccessFilter struct {
+	UserID    uint
+	PatientID uint
+	From      *time.Time
+	To        *time.Time
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EncounterType represents the kind of a visit
+type EncounterType string
+
+// Encounter types
+const (
+	EncounterTypeOutpatient EncounterType = "outpatient"
+	EncounterTypeEmergency  EncounterType = "emergency"
+	EncounterTypeFollowUp   EncounterType = "follow-up"
+)
+
+// EncounterStatus represents where a visit is in its lifecycle
+type EncounterStatus string
+
+// Encounter statuses
+const (
+	EncounterStatusPlanned    EncounterStatus = "planned"
+	EncounterStatusArrived    EncounterStatus = "arrived"
+	EncounterStatusInProgress EncounterStatus = "in-progress"
+	EncounterStatusFinished   EncounterStatus = "finished"
+	EncounterStatusCancelled  EncounterStatus = "cancelled"
+)
+
+// encounterTransitions lists the statuses each status may move to
+var encounterTransitions = map[EncounterStatus][]EncounterStatus{
+	EncounterStatusPlanned:    {EncounterStatusArrived, EncounterStatusCancelled},
+	EncounterStatusArrived:    {EncounterStatusInProgress, EncounterStatusCancelled},
+	EncounterStatusInProgress: {EncounterStatusFinished},
+}
+
+// CanTransitionTo checks if an encounter may move from this status to another
+func (s EncounterStatus) CanTransitionTo(next EncounterStatus) bool {
+	for _, allowed := range encounterTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptsRecords checks if vitals, diagnoses, prescriptions and notes may be
+// attached to an encounter in this status
+func (s EncounterStatus) AcceptsRecords() bool {
+	return s == EncounterStatusArrived || s == EncounterStatusInProgress || s == EncounterStatusFinished
+}
+
+// Encounter is a visit of a patient to a doctor
+type Encounter struct {
+	ID             uint            `json:"id" gorm:"primaryKey"`
+	PatientID      uint            `json:"patient_id" gorm:"not null;index"`
+	DoctorID       uint            `json:"doctor_id" gorm:"not null;index"`
+	Type           EncounterType   `json:"type" gorm:"not null"`
+	Status         EncounterStatus `json:"status" gorm:"not null;index"`
+	PlannedStart   *time.Time      `json:"planned_start,omitempty"`
+	StartedAt      *time.Time      `json:"started_at,omitempty"`
+	EndedAt        *time.Time      `json:"ended_at,omitempty"`
+	ChiefComplaint string          `json:"chief_complaint"`
+	Location       string          `json:"location"`
+	CreatedBy      uint            `json:"created_by" gorm:"not null"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt  `json:"-" gorm:"index"`
+
+	// Records attached to the encounter, loaded with a single encounter
+	Vitals      []Vital      `json:"vitals,omitempty" gorm:"-"`
+	Conditions  []Condition  `json:"conditions,omitempty" gorm:"-"`
+	Medications []Medication `json:"medications,omitempty" gorm:"-"`
+}
+
+// CreateEncounterRequest represents a request to plan a visit, or to record
+// a walk-in that has already arrived
+type CreateEncounterRequest struct {
+	DoctorID       uint          `json:"doctor_id" binding:"required"`
+	Type           EncounterType `json:"type" binding:"required,oneof=outpatient emergency follow-up"`
+	PlannedStart   *time.Time    `json:"planned_start"`
+	ChiefComplaint string        `json:"chief_complaint"`
+	Location       string        `json:"location"`
+	// Arrived checks the patient in straight away
+	Arrived bool `json:"arrived"`
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// InteractionSeverity represents how serious an interaction is
+type InteractionSeverity string
+
+// Interaction severities
+const (
+	InteractionSeverityLow      InteractionSeverity = "low"
+	InteractionSeverityModerate InteractionSeverity = "moderate"
+	InteractionSeverityHigh     InteractionSeverity = "high"
+)
+
+// IsValid checks if the severity is one of the known severities
+func (s InteractionSeverity) IsValid() bool {
+	return s == InteractionSeverityLow || s == InteractionSeverityModerate || s == InteractionSeverityHigh
+}
+
+// InteractionType tells what a drug interacts with
+type InteractionType string
+
+// Interaction types
+const (
+	// InteractionTypeDrug is an interaction between two drugs
+	InteractionTypeDrug InteractionType = "drug"
+	// InteractionTypeAllergy is a drug the patient may react to because of a
+	// documented allergy
+	InteractionTypeAllergy InteractionType = "allergy"
+)
+
+// InteractionRule is an entry of the interaction dataset. Drug and
+// Interactant are matched case-insensitively as substrings of drug names and
+// allergy substances.
+type InteractionRule struct {
+	Type        InteractionType     `json:"type"`
+	Drug        string              `json:"drug"`
+	Interactant string              `json:"interactant"`
+	Severity    InteractionSeverity `json:"severity"`
+	Description string              `json:"description"`
+}
+
+// InteractionWarning reports an interaction of a drug with another active
+// medication or a documented allergy
+type InteractionWarning struct {
+	Type        InteractionType     `json:"type"`
+	Drug        string              `json:"drug"`
+	Interactant string              `json:"interactant"`
+	Severity    InteractionSeverity `json:"severity"`
+	Description string              `json:"description"`
+}
+
+// InteractionWarningList is a list of interaction warnings stored as JSON
+type InteractionWarningList []InteractionWarning
+
+// Value stores the list as JSON
+func (l InteractionWarningList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the list from JSON
+func (l *InteractionWarningList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return errors.New("unsupported interaction warning list value")
+	}
+}
+
+// InteractionCheckRequest represents a request to check a drug for
+// interactions, either with a patient's active medications and allergies or
+// with the given drugs and allergies
+type InteractionCheckRequest struct {
+	DrugName  string   `json:"drug_name" binding:"required"`
+	PatientID uint     `json:"patient_id"`
+	Drugs     []string `json:"drugs"`
+	Allergies []string `json:"allergies"`
+}
+
+// InteractionCheckResult holds the warnings found for a drug
+type InteractionCheckResult struct {
+	Warnings []InteractionWarning `json:"warnings"`
+	// OverrideRequired is true when a warning is severe enough that
+	// prescribing the drug requires an override reason
+	OverrideRequired bool `json:"override_required"`
+}
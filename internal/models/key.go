@@ -0,0 +1,18 @@
+package models
+
+// JSONWebKey represents a public signing key in JWK format (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JSONWebKeySet represents a set of public signing keys
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginAttempt tracks failed logins for an account or client IP
+type LoginAttempt struct {
+	Key           string     `json:"key" gorm:"primaryKey;size:320"`
+	Failures      int        `json:"failures" gorm:"not null;default:0"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// IsLocked reports whether the attempt key is locked at the given time
+func (a *LoginAttempt) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MedicationKind tells whether a medication was prescribed here or reported
+type MedicationKind string
+
+// Medication kinds
+const (
+	// MedicationStatement records a medication the patient takes, as reported
+	// by the patient or another provider
+	MedicationStatement MedicationKind = "statement"
+	// MedicationPrescription records a medication prescribed by a clinician
+	MedicationPrescription MedicationKind = "prescription"
+)
+
+// MedicationStatus represents the status of a medication
+type MedicationStatus string
+
+// Medication statuses
+const (
+	MedicationStatusActive    MedicationStatus = "active"
+	MedicationStatusStopped   MedicationStatus = "stopped"
+	MedicationStatusCompleted MedicationStatus = "completed"
+)
+
+// Medication is a medication statement or prescription of a patient
+type Medication struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	PatientID      uint             `json:"patient_id" gorm:"not null;index"`
+	EncounterID    *uint            `json:"encounter_id,omitempty" gorm:"index"`
+	Kind           MedicationKind   `json:"kind" gorm:"not null"`
+	DrugName       string           `json:"drug_name" gorm:"not null;index"`
+	DrugCode       string           `json:"drug_code,omitempty"`
+	Dose           string           `json:"dose"`
+	Route          string           `json:"route"`
+	Frequency      string           `json:"frequency"`
+	Duration       string           `json:"duration,omitempty"`
+	Instructions   string           `json:"instructions,omitempty"`
+	PrescriberID   *uint            `json:"prescriber_id,omitempty"`
+	PrescriberName string           `json:"prescriber_name,omitempty"`
+	StartDate      time.Time        `json:"start_date" gorm:"not null"`
+	StopDate       *time.Time       `json:"stop_date,omitempty"`
+	Status         MedicationStatus `json:"status" gorm:"not null;index"`
+	StopReason     string           `json:"stop_reason,omitempty"`
+	// InteractionOverrideReason justifies prescribing despite a high-severity
+	// interaction
+	InteractionOverrideReason string `json:"interaction_override_reason,omitempty"`
+	// InteractionWarnings holds the interactions found when the medication
+	// was prescribed
+	InteractionWarnings InteractionWarningList `json:"interaction_warnings,omitempty" gorm:"type:jsonb"`
+	RecordedBy          uint                   `json:"recorded_by" gorm:"not null"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt         `json:"-" gorm:"index"`
+}
+
+// IsActive checks if the patient is currently taking the medication
+func (m *Medication) IsActive() bool {
+	return m.Status == MedicationStatusActive
+}
+
+// CreateMedicationRequest represents a request to record a medication
+// statement or prescribe a medication
+type CreateMedicationRequest struct {
+	Kind           MedicationKind `json:"kind" binding:"required,oneof=statement prescription"`
+	DrugName       string         `json:"drug_name" binding:"required"`
+	DrugCode       string         `json:"drug_code"`
+	Dose           string         `json:"dose" binding:"required"`
+	Route          string         `json:"route" binding:"required"`
+	Frequency      string         `json:"frequency" binding:"required"`
+	Duration       string         `json:"duration"`
+	Instructions   string         `json:"instructions"`
+	PrescriberName string         `json:"prescriber_name"`
+	StartDate      *time.Time     `json:"start_date"`
+	EncounterID    *uint          `json:"encounter_id"`
+	// OverrideReason is required to prescribe a drug with a high-severity
+	// interaction
+	OverrideReason string `json:"override_reason"`
+}
+
+// UpdateMedicationRequest represents a request to change the details or the
+// status of a medication
+type UpdateMedicationRequest struct {
+	Dose         string           `json:"dose"`
+	Route        string           `json:"route"`
+	Frequency    string           `json:"frequency"`
+	Duration     string           `json:"duration"`
+	Instructions string           `json:"instructions"`
+	Status       MedicationStatus `json:"status" binding:"omitempty,oneof=active stopped completed"`
+	StopReason   string           `json:"stop_reason"`
+	StopDate     *time.Time       `json:"stop_date"`
+}
+
+// ApplyUpdates applies the detail changes of an UpdateMedicationRequest,
+// leaving empty fields unchanged
+func (m *Medication) ApplyUpdates(req UpdateMedicationRequest) {
+	if req.Dose != "" {
+		m.Dose = req.Dose
+	}
+	if req.Route != "" {
+		m.Route = req.Route
+	}
+	if req.Frequency != "" {
+		m.Frequency = req.Frequency
+	}
+	if req.Duration != "" {
+		m.Duration = req.Duration
+	}
+	if req.Instructions != "" {
+		m.Instructions = req.Instructions
+	}
+}
+
+// PatientMedication is an active medication together with the patient
+// taking it
+type PatientMedication struct {
+	Medication       `gorm:"embedded"`
+	PatientFirstName string `json:"patient_first_name"`
+	PatientLastName  string `json:"patient_last_name"`
+}
+
+// MedicationFilter narrows a medication listing across patients. Empty
+// fields match all medications.
+type MedicationFilter struct {
+	// DrugName matches medications whose drug name contains it
+	DrugName string
+	Status   MedicationStatus
+	// CareTeamMemberID limits the listing to patients whose care team
+	// currently includes this clinician
+	CareTeamMemberID uint
+}
+
+// PrescriptionDocument holds what is printed on a prescription
+type PrescriptionDocument struct {
+	Medication Medication
+	Patient    Patient
+	PrintedAt  time.Time
+}
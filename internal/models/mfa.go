@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// RecoveryCode represents a one-time MFA recovery code, stored hashed
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RolePolicy represents security settings applied to every user of a role
+type RolePolicy struct {
+	Role       UserRole  `json:"role" gorm:"primaryKey;size:50"`
+	RequireMFA bool      `json:"require_mfa" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// MFALoginRequest represents the second step of a login requiring MFA
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+// MFAEnrollmentResponse represents a pending TOTP enrollment
+type MFAEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// ConfirmMFARequest represents a request to confirm a TOTP enrollment
+type ConfirmMFARequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// RecoveryCodesResponse represents freshly generated recovery codes
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// UpdateRolePolicyRequest represents a request to change a role's policy
+type UpdateRolePolicyRequest struct {
+	RequireMFA bool `json:"require_mfa"`
+}
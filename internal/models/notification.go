@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+)
+
+// NotificationChannel is a way of reaching a patient
+type NotificationChannel string
+
+// Notification channels
+const (
+	NotificationChannelEmail NotificationChannel = "email"
+	NotificationChannelSMS   NotificationChannel = "sms"
+)
+
+// NotificationKind is what a notification tells a patient about
+type NotificationKind string
+
+// Notification kinds
+const (
+	NotificationKindAppointmentReminder NotificationKind = "appointment_reminder"
+	NotificationKindSlotOffer           NotificationKind = "slot_offer"
+)
+
+// NotificationStatus represents where a notification is in delivery
+type NotificationStatus string
+
+// Notification statuses
+const (
+	NotificationStatusPending NotificationStatus = "pending"
+	NotificationStatusSent    NotificationStatus = "sent"
+	NotificationStatusFailed  NotificationStatus = "failed"
+	// NotificationStatusSuppressed marks a notification that was not sent
+	// because the patient opted out or cannot be reached
+	NotificationStatusSuppressed NotificationStatus = "suppressed"
+)
+
+// Notification is a message to a patient in the outbox. Pending
+// notifications are delivered in the background and retried until they are
+// sent or run out of attempts.
+type Notification struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	PatientID uint             `json:"patient_id" gorm:"not null;index"`
+	Kind      NotificationKind `json:"kind" gorm:"not null"`
+	// EventKey identifies what the notification is about, such as one
+	// reminder of an appointment, so that it is queued only once
+	EventKey string `json:"event_key" gorm:"not null;uniqueIndex"`
+	// AppointmentID and AppointmentStart tie a reminder to the booking it is
+	// about, so it is not sent once the appointment is cancelled or moved
+	AppointmentID    *uint               `json:"appointment_id,omitempty" gorm:"index"`
+	AppointmentStart *time.Time          `json:"appointment_start,omitempty"`
+	Channel          NotificationChannel `json:"channel,omitempty"`
+	Recipient        string              `json:"recipient,omitempty"`
+	Subject          string              `json:"subject,omitempty"`
+	Body             string              `json:"body" gorm:"type:text"`
+	// MessageData is what the message was rendered from, kept as JSON so it
+	// can be rendered again for another channel
+	MessageData *string            `json:"-" gorm:"type:jsonb"`
+	Status      NotificationStatus `json:"status" gorm:"not null;index:idx_notifications_status_next_attempt"`
+	Attempts    int                `json:"attempts" gorm:"not null;default:0"`
+	// NextAttemptAt is when a pending notification is next delivered
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_notifications_status_next_attempt"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// ContactPreference is how a patient wants to be contacted. Patients
+// without one are contacted by email when they have an address, otherwise
+// by SMS.
+type ContactPreference struct {
+	PatientID uint `json:"patient_id" gorm:"primaryKey;autoIncrement:false"`
+	// PreferredChannel is tried first; the other channel is used when the
+	// patient has no address for it or opted out of it. Empty means email.
+	PreferredChannel NotificationChannel `json:"preferred_channel,omitempty"`
+	EmailOptOut      bool                `json:"email_opt_out" gorm:"not null;default:false"`
+	SMSOptOut        bool                `json:"sms_opt_out" gorm:"not null;default:false"`
+	// RemindersOptOut stops appointment reminders only; slot offers the
+	// patient asked for by joining a waitlist are still sent
+	RemindersOptOut bool      `json:"reminders_opt_out" gorm:"not null;default:false"`
+	UpdatedBy       uint      `json:"updated_by,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NotificationFilter narrows a notification listing. Empty fields match all
+// notifications.
+type NotificationFilter struct {
+	PatientID uint
+	Kind      NotificationKind
+	Status    NotificationStatus
+}
+
+// UpdateContactPreferenceRequest represents a request to replace a
+// patient's contact preferences
+type UpdateContactPreferenceRequest struct {
+	PreferredChannel NotificationChannel `json:"preferred_channel" binding:"omitempty,oneof=email sms"`
+	EmailOptOut      bool                `json:"email_opt_out"`
+	SMSOptOut        bool                `json:"sms_opt_out"`
+	RemindersOptOut  bool                `json:"reminders_opt_out"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// PatientFieldPermissions maps the JSON names of the patient fields that can
+// be patched to the permission needed to change them. Fields missing from the
+// map cannot be patched.
+var PatientFieldPermissions = map[string]Permission{
+	"first_name":       PermPatientsWrite,
+	"last_name":        PermPatientsWrite,
+	"date_of_birth":    PermPatientsWrite,
+	"gender":           PermPatientsWrite,
+	"contact_number":   PermPatientsWrite,
+	"email":            PermPatientsWrite,
+	"address":          PermPatientsWrite,
+	"emergency_name":   PermPatientsWrite,
+	"emergency_number": PermPatientsWrite,
+	"blood_group":      PermMedicalWrite,
+	"medical_history":  PermMedicalWrite,
+}
+
+// RequiredPatientFields lists the patient fields that cannot be cleared
+var RequiredPatientFields = map[string]bool{
+	"first_name":     true,
+	"last_name":      true,
+	"date_of_birth":  true,
+	"gender":         true,
+	"contact_number": true,
+	"address":        true,
+}
+
+// ErrUnknownPatientField is returned when setting a field the patient does
+// not have
+var ErrUnknownPatientField = errors.New("unknown patient field")
+
+// SetField sets a patient field by its JSON name from a JSON value. A null
+// value clears the field.
+func (p *Patient) SetField(name string, value json.RawMessage) error {
+	for _, field := range patientAuditFields() {
+		if field.name != name {
+			continue
+		}
+		target := reflect.ValueOf(p).Elem().FieldByIndex(field.index)
+		if IsNull(value) {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		decoded := reflect.New(target.Type())
+		if err := json.Unmarshal(value, decoded.Interface()); err != nil {
+			return err
+		}
+		target.Set(decoded.Elem())
+		return nil
+	}
+	return ErrUnknownPatientField
+}
+
+// IsNull checks if a JSON value is null
+func IsNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// MedicalFields lists the JSON names of the patient fields holding medical
+// information
+var MedicalFields = []string{"blood_group", "medical_history"}
+
+// PatientRevision is a stored version of a patient record, written with every
+// change. Snapshot holds the whole record as JSON after the change.
+type PatientRevision struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	PatientID     uint         `json:"patient_id" gorm:"not null;uniqueIndex:idx_patient_revisions_patient_revision"`
+	Revision      int          `json:"revision" gorm:"not null;uniqueIndex:idx_patient_revisions_patient_revision"`
+	Action        AuditAction  `json:"action" gorm:"not null"`
+	ChangedBy     uint         `json:"changed_by" gorm:"not null"`
+	ChangedByRole UserRole     `json:"changed_by_role"`
+	Changes       AuditChanges `json:"changes" gorm:"type:jsonb"`
+	Snapshot      string       `json:"-" gorm:"type:jsonb;not null"`
+	CreatedAt     time.Time    `json:"created_at" gorm:"index"`
+}
+
+// NewPatientRevision creates a revision capturing a patient as written by
+// the change an audit event describes
+func NewPatientRevision(patient *Patient, event *AuditEvent) (*PatientRevision, error) {
+	// Structured allergies are stored apart from the record
+	record := *patient
+	record.AllergyList = nil
+	snapshot, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return &PatientRevision{
+		PatientID:     patient.ID,
+		Action:        event.Action,
+		ChangedBy:     event.ActorID,
+		ChangedByRole: event.ActorRole,
+		Changes:       event.Changes,
+		Snapshot:      string(snapshot),
+	}, nil
+}
+
+// Patient decodes the patient record stored in the revision
+func (r *PatientRevision) Patient() (*Patient, error) {
+	var patient Patient
+	if err := json.Unmarshal([]byte(r.Snapshot), &patient); err != nil {
+		return nil, err
+	}
+	return &patient, nil
+}
+
+// RestoreFrom copies the tracked fields of an earlier version of the record
+func (p *Patient) RestoreFrom(snapshot *Patient) {
+	for _, field := range patientAuditFields() {
+		reflect.ValueOf(p).Elem().FieldByIndex(field.index).Set(
+			reflect.ValueOf(snapshot).Elem().FieldByIndex(field.index))
+	}
+}
+
+// TouchesMedicalFields checks if a set of changes includes medical fields
+func (c AuditChanges) TouchesMedicalFields() bool {
+	for _, name := range MedicalFields {
+		if _, ok := c[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RevertPatientRequest represents a request to restore an earlier revision
+type RevertPatientRequest struct {
+	Revision int `json:"revision" binding:"required,min=1"`
+}
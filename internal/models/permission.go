@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+)
+
+// Permission represents an action a user may perform
+type Permission string
+
+const (
+	PermPatientsRead   Permission = "patients:read"
+	PermPatientsWrite  Permission = "patients:write"
+	PermPatientsDelete Permission = "patients:delete"
+	// PermPatientsAll grants access to every patient regardless of care teams
+	PermPatientsAll Permission = "patients:all"
+	// PermPatientsOverride allows recorded access outside the care team
+	PermPatientsOverride Permission = "patients:override"
+	// PermPatientsEmergency allows break-the-glass access to any patient
+	PermPatientsEmergency Permission = "patients:emergency"
+	PermCareTeamManage    Permission = "care_team:manage"
+	// PermEncountersManage allows creating, checking in and cancelling visits
+	PermEncountersManage Permission = "encounters:manage"
+	// PermAppointmentsManage allows maintaining doctor schedules and booking,
+	// rescheduling and cancelling appointments
+	PermAppointmentsManage Permission = "appointments:manage"
+	// PermQueueRead allows following the check-in queues of the day
+	PermQueueRead Permission = "queue:read"
+	// PermQueueManage allows checking patients in and moving them through
+	// the queues
+	PermQueueManage Permission = "queue:manage"
+	// PermNotesRead allows reading the bodies of clinical notes
+	PermNotesRead    Permission = "notes:read"
+	PermMedicalWrite Permission = "medical:write"
+	PermUsersManage  Permission = "users:manage"
+	PermAuditRead    Permission = "audit:read"
+)
+
+// AllPermissions lists every known permission
+var AllPermissions = []Permission{
+	PermPatientsRead,
+	PermPatientsWrite,
+	PermPatientsDelete,
+	PermPatientsAll,
+	PermPatientsOverride,
+	PermPatientsEmergency,
+	PermCareTeamManage,
+	PermEncountersManage,
+	PermAppointmentsManage,
+	PermQueueRead,
+	PermQueueManage,
+	PermNotesRead,
+	PermMedicalWrite,
+	PermUsersManage,
+	PermAuditRead,
+}
+
+// DefaultRolePermissions are the permission sets seeded for each role
+var DefaultRolePermissions = map[UserRole][]Permission{
+	RoleAdmin: {PermUsersManage, PermAuditRead},
+	RoleReceptionist: {PermPatientsRead, PermPatientsWrite, PermPatientsDelete, PermPatientsAll, PermCareTeamManage, PermEncountersManage,
+		PermAppointmentsManage, PermQueueRead, PermQueueManage},
+	RoleDoctor: {PermPatientsRead, PermPatientsWrite, PermPatientsOverride, PermPatientsEmergency, PermNotesRead, PermMedicalWrite,
+		PermQueueRead, PermQueueManage},
+}
+
+// IsValid checks if the permission is one of the known permissions
+func (p Permission) IsValid() bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// RolePermission grants a permission to every user of a role
+type RolePermission struct {
+	Role       UserRole   `json:"role" gorm:"primaryKey;size:50"`
+	Permission Permission `json:"permission" gorm:"primaryKey;size:100"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// RolePermissionsResponse represents the permission set of a role
+type RolePermissionsResponse struct {
+	Role        UserRole     `json:"role"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// UpdateRolePermissionsRequest represents a request to replace a role's permissions
+type UpdateRolePermissionsRequest struct {
+	Permissions []Permission `json:"permissions" binding:"required"`
+}
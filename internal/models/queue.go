@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+)
+
+// QueueStatus represents where a checked-in patient is in a doctor's queue
+type QueueStatus string
+
+// Queue statuses
+const (
+	QueueStatusWaiting        QueueStatus = "waiting"
+	QueueStatusCalled         QueueStatus = "called"
+	QueueStatusInConsultation QueueStatus = "in-consultation"
+	QueueStatusDone           QueueStatus = "done"
+)
+
+// queueTransitions lists the statuses each queue status may move to
+var queueTransitions = map[QueueStatus]QueueStatus{
+	QueueStatusWaiting:        QueueStatusCalled,
+	QueueStatusCalled:         QueueStatusInConsultation,
+	QueueStatusInConsultation: QueueStatusDone,
+}
+
+// CanTransitionTo checks if a queue entry may move from this status to another
+func (s QueueStatus) CanTransitionTo(next QueueStatus) bool {
+	allowed, ok := queueTransitions[s]
+	return ok && allowed == next
+}
+
+// QueueEntry is a patient checked in at the front desk and waiting for, or
+// seeing, a doctor on the day of the visit
+type QueueEntry struct {
+	ID            uint  `json:"id" gorm:"primaryKey"`
+	PatientID     uint  `json:"patient_id" gorm:"not null;index"`
+	DoctorID      uint  `json:"doctor_id" gorm:"not null;index:idx_queue_entries_doctor_date"`
+	AppointmentID *uint `json:"appointment_id,omitempty" gorm:"index"`
+	// Date is the clinic's calendar date of the check-in
+	Date        time.Time   `json:"date" gorm:"type:date;not null;index:idx_queue_entries_doctor_date"`
+	Status      QueueStatus `json:"status" gorm:"not null"`
+	CheckedInAt time.Time   `json:"checked_in_at" gorm:"not null"`
+	CalledAt    *time.Time  `json:"called_at,omitempty"`
+	StartedAt   *time.Time  `json:"started_at,omitempty"`
+	DoneAt      *time.Time  `json:"done_at,omitempty"`
+	CheckedInBy uint        `json:"checked_in_by" gorm:"not null"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// PatientQueueEntry is a queue entry with the name of its patient and its
+// waiting and consultation times in whole minutes, counted up to now while
+// they last
+type PatientQueueEntry struct {
+	QueueEntry          `gorm:"embedded"`
+	PatientFirstName    string `json:"patient_first_name"`
+	PatientLastName     string `json:"patient_last_name"`
+	WaitMinutes         int    `json:"wait_minutes" gorm:"-"`
+	ConsultationMinutes int    `json:"consultation_minutes" gorm:"-"`
+}
+
+// DoctorQueue is the queue of a doctor, or of every doctor, on a date in
+// check-in order
+type DoctorQueue struct {
+	DoctorID uint                `json:"doctor_id,omitempty"`
+	Date     string              `json:"date"`
+	Entries  []PatientQueueEntry `json:"entries"`
+	Waiting  int                 `json:"waiting"`
+	// AverageWaitMinutes is the average wait of the patients called so far
+	AverageWaitMinutes int `json:"average_wait_minutes"`
+}
+
+// CheckInRequest represents a request to check a patient in to a doctor's
+// queue. The doctor defaults to the one of the appointment.
+type CheckInRequest struct {
+	PatientID     uint  `json:"patient_id" binding:"required"`
+	DoctorID      uint  `json:"doctor_id"`
+	AppointmentID *uint `json:"appointment_id"`
+}
+
+// AssignQueueRequest represents a request to move a waiting patient to
+// another doctor's queue
+type AssignQueueRequest struct {
+	DoctorID uint `json:"doctor_id" binding:"required"`
+}
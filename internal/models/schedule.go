@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+)
+
+// ClockLayout is the layout of the times of day in doctor schedules
+const ClockLayout = "15:04"
+
+// DateLayout is the layout of calendar dates in schedules and queries
+const DateLayout = "2006-01-02"
+
+// WorkingHours is a weekly block of a doctor's working time, split into
+// appointment slots. Times of day are in the clinic's time zone.
+type WorkingHours struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	DoctorID    uint         `json:"doctor_id" gorm:"not null;index"`
+	Weekday     time.Weekday `json:"weekday" gorm:"not null"`
+	StartTime   string       `json:"start_time" gorm:"size:5;not null"`
+	EndTime     string       `json:"end_time" gorm:"size:5;not null"`
+	SlotMinutes int          `json:"slot_minutes" gorm:"not null"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// ScheduleException replaces a doctor's weekly hours on one date. An
+// exception without hours marks a day the doctor does not work.
+type ScheduleException struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	DoctorID    uint      `json:"doctor_id" gorm:"not null;uniqueIndex:idx_schedule_exceptions_doctor_date"`
+	Date        time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_schedule_exceptions_doctor_date"`
+	StartTime   string    `json:"start_time,omitempty" gorm:"size:5"`
+	EndTime     string    `json:"end_time,omitempty" gorm:"size:5"`
+	SlotMinutes int       `json:"slot_minutes,omitempty"`
+	Reason      string    `json:"reason"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// IsClosed checks if the exception marks a day off
+func (e *ScheduleException) IsClosed() bool {
+	return e.StartTime == ""
+}
+
+// DoctorLeave is a period of whole days a doctor is away
+type DoctorLeave struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	DoctorID  uint      `json:"doctor_id" gorm:"not null;index"`
+	StartDate time.Time `json:"start_date" gorm:"type:date;not null"`
+	// EndDate is the last day of the leave
+	EndDate   time.Time `json:"end_date" gorm:"type:date;not null"`
+	Reason    string    `json:"reason"`
+	CreatedBy uint      `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName keeps the leave table name singular, as leave is uncountable
+func (DoctorLeave) TableName() string {
+	return "doctor_leave"
+}
+
+// DoctorSchedule is the weekly hours of a doctor with the exceptions and
+// leave from a date on
+type DoctorSchedule struct {
+	DoctorID   uint                `json:"doctor_id"`
+	Hours      []WorkingHours      `json:"hours"`
+	Exceptions []ScheduleException `json:"exceptions"`
+	Leave      []DoctorLeave       `json:"leave"`
+}
+
+// WorkingHoursInput is a weekly block of working time
+type WorkingHoursInput struct {
+	Weekday     time.Weekday `json:"weekday" binding:"min=0,max=6"`
+	StartTime   string       `json:"start_time" binding:"required"`
+	EndTime     string       `json:"end_time" binding:"required"`
+	SlotMinutes int          `json:"slot_minutes" binding:"required"`
+}
+
+// SetWorkingHoursRequest represents a request to replace a doctor's weekly
+// hours
+type SetWorkingHoursRequest struct {
+	Hours []WorkingHoursInput `json:"hours" binding:"dive"`
+}
+
+// ScheduleExceptionRequest represents a request to change a doctor's hours
+// on one date. Leaving out the hours marks the day off.
+type ScheduleExceptionRequest struct {
+	Date        string `json:"date" binding:"required"`
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+	SlotMinutes int    `json:"slot_minutes"`
+	Reason      string `json:"reason"`
+}
+
+// DoctorLeaveRequest represents a request to record a doctor's leave
+type DoctorLeaveRequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+// Slot is a bookable period of a doctor's time
+type Slot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// DoctorAvailability lists the free slots of a doctor on a date
+type DoctorAvailability struct {
+	DoctorID uint   `json:"doctor_id"`
+	Date     string `json:"date"`
+	Slots    []Slot `json:"slots"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Session represents a login session shared by a chain of refresh tokens
+type Session struct {
+	ID        string     `json:"id" gorm:"primaryKey;size:64"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// IsRevoked reports whether the session has been revoked
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// RefreshToken represents a single-use refresh token belonging to a session
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	SessionID string     `json:"session_id" gorm:"size:64;not null;index"`
+	TokenHash string     `json:"-" gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RotatedAt *time.Time `json:"rotated_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RefreshTokenRequest represents a request to exchange a refresh token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// TokenResponse represents a freshly issued access/refresh token pair
+type TokenResponse struct {
+	Token                  string    `json:"token"`
+	RefreshToken           string    `json:"refresh_token"`
+	ExpiresAt              time.Time `json:"expires_at"`
+	MFAEnrollmentRequired  bool      `json:"mfa_enrollment_required,omitempty"`
+	PasswordChangeRequired bool      `json:"password_change_required,omitempty"`
+}
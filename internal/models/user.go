@@ -1,94 +1,156 @@
-package models
-
-import (
-	"time"
-
-	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
-)
-
-// UserRole represents the role of a user
-type UserRole string
-
-const (
-	RoleReceptionist UserRole = "receptionist"
-	RoleDoctor       UserRole = "doctor"
-)
-
-// User represents a user in the system
-type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"not null"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	Role      UserRole       `json:"role" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
-}
-
-// LoginRequest represents a login request
-type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
-}
-
-// CreateUserRequest represents a request to create a user
-type CreateUserRequest struct {
-	Name     string   `json:"name" binding:"required"`
-	Email    string   `json:"email" binding:"required,email"`
-	Password string   `json:"password" binding:"required,min=6"`
-	Role     UserRole `json:"role" binding:"required,oneof=receptionist doctor"`
-}
-
-// UserResponse represents a user response without sensitive data
-type UserResponse struct {
-	ID        uint      `json:"id"`
-	Name      string    `json:"name"`
-	Email     string    `json:"email"`
-	Role      UserRole  `json:"role"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-}
-
-// ToUserResponse converts a User to a UserResponse
-func (u *User) ToUserResponse() UserResponse {
-	return UserResponse{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		Role:      u.Role,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
-	}
-}
-
-// LoginResponse represents a login response
-type LoginResponse struct {
-	Token string       `json:"token"`
-	User  UserResponse `json:"user"`
-}
-
-// HashPassword hashes a password
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	return string(bytes), err
-}
-
-// CheckPasswordHash checks if a password matches a hash
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
-}
-
-// BeforeSave runs before saving a User model
-func (u *User) BeforeSave(tx *gorm.DB) error {
-	if u.Password != "" {
-		hashedPassword, err := HashPassword(u.Password)
-		if err != nil {
-			return err
-		}
-		u.Password = hashedPassword
-	}
-	return nil
-} 
\ No newline at end of file
+package models
+
+import (
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// UserRole represents the role of a user
+type UserRole string
+
+const (
+	RoleAdmin        UserRole = "admin"
+	RoleReceptionist UserRole = "receptionist"
+	RoleDoctor       UserRole = "doctor"
+)
+
+// IsValid checks if the role is one of the known roles
+func (r UserRole) IsValid() bool {
+	return r == RoleAdmin || r == RoleReceptionist || r == RoleDoctor
+}
+
+// User status filters
+const (
+	UserStatusActive      = "active"
+	UserStatusDeactivated = "deactivated"
+)
+
+// User represents a user in the system
+type User struct {
+	ID           uint     `json:"id" gorm:"primaryKey"`
+	Name         string   `json:"name" gorm:"not null"`
+	Email        string   `json:"email" gorm:"uniqueIndex;not null"`
+	Password     string   `json:"-" gorm:"not null"`
+	Role         UserRole `json:"role" gorm:"not null"`
+	MFAEnabled   bool     `json:"mfa_enabled" gorm:"not null;default:false"`
+	TOTPSecret   string   `json:"-"`
+	TOTPLastStep int64    `json:"-" gorm:"not null;default:0"`
+	// MustChangePassword forces the user to set a new password on next login
+	MustChangePassword bool `json:"must_change_password" gorm:"not null;default:false"`
+	// DeactivatedAt is set while the user is not allowed to log in
+	DeactivatedAt *time.Time     `json:"deactivated_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// IsActive checks if the user has not been deactivated
+func (u *User) IsActive() bool {
+	return u.DeactivatedAt == nil
+}
+
+// LoginRequest represents a login request
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// CreateUserRequest represents a request to create a user
+type CreateUserRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Email    string   `json:"email" binding:"required,email"`
+	Password string   `json:"password" binding:"required,min=6"`
+	Role     UserRole `json:"role" binding:"required,oneof=admin receptionist doctor"`
+}
+
+// UpdateUserRequest represents a request to update a user. The password is
+// only changed when one is supplied.
+type UpdateUserRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Email    string   `json:"email" binding:"required,email"`
+	Password string   `json:"password" binding:"omitempty,min=6"`
+	Role     UserRole `json:"role" binding:"required,oneof=admin receptionist doctor"`
+}
+
+// UserFilter narrows a user listing. Empty fields match all users.
+type UserFilter struct {
+	Role   UserRole
+	Status string
+}
+
+// UserResponse represents a user response without sensitive data
+type UserResponse struct {
+	ID                 uint      `json:"id"`
+	Name               string    `json:"name"`
+	Email              string    `json:"email"`
+	Role               UserRole  `json:"role"`
+	MFAEnabled         bool      `json:"mfa_enabled"`
+	MustChangePassword bool       `json:"must_change_password"`
+	Active             bool       `json:"active"`
+	DeactivatedAt      *time.Time `json:"deactivated_at,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Login lockout state
+	Locked              bool       `json:"locked"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+	FailedLoginAttempts int        `json:"failed_login_attempts"`
+}
+
+// ToUserResponse converts a User to a UserResponse
+func (u *User) ToUserResponse() UserResponse {
+	return UserResponse{
+		ID:                 u.ID,
+		Name:               u.Name,
+		Email:              u.Email,
+		Role:               u.Role,
+		MFAEnabled:         u.MFAEnabled,
+		MustChangePassword: u.MustChangePassword,
+		Active:             u.IsActive(),
+		DeactivatedAt:      u.DeactivatedAt,
+		CreatedAt:          u.CreatedAt,
+		UpdatedAt:          u.UpdatedAt,
+	}
+}
+
+// LoginResponse represents a login response
+type LoginResponse struct {
+	Token        string       `json:"token,omitempty"`
+	RefreshToken string       `json:"refresh_token,omitempty"`
+	ExpiresAt    *time.Time   `json:"expires_at,omitempty"`
+	User         UserResponse `json:"user"`
+
+	// Set when a second factor must be presented to POST /login/mfa
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+	// Set when the user's role requires MFA but no factor is enrolled yet
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"`
+	// Set when the user must change their password before continuing
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"`
+}
+
+// HashPassword hashes a password
+func HashPassword(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
+	return string(bytes), err
+}
+
+// CheckPasswordHash checks if a password matches a hash
+func CheckPasswordHash(password, hash string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil
+}
+
+// BeforeSave runs before saving a User model
+func (u *User) BeforeSave(tx *gorm.DB) error {
+	if u.Password != "" {
+		hashedPassword, err := HashPassword(u.Password)
+		if err != nil {
+			return err
+		}
+		u.Password = hashedPassword
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// VitalType represents the kind of a vital sign observation
+type VitalType string
+
+// Vital sign types
+const (
+	// VitalBloodPressure holds the systolic pressure in Value and the
+	// diastolic pressure in Diastolic
+	VitalBloodPressure VitalType = "bp"
+	VitalPulse         VitalType = "pulse"
+	VitalTemperature   VitalType = "temperature"
+	VitalSpO2          VitalType = "spo2"
+	VitalHeight        VitalType = "height"
+	VitalWeight        VitalType = "weight"
+	VitalBMI           VitalType = "bmi"
+)
+
+// VitalUnits are the units vital signs are stored in
+var VitalUnits = map[VitalType]string{
+	VitalBloodPressure: "mmHg",
+	VitalPulse:         "bpm",
+	VitalTemperature:   "C",
+	VitalSpO2:          "%",
+	VitalHeight:        "cm",
+	VitalWeight:        "kg",
+	VitalBMI:           "kg/m2",
+}
+
+// Vital sign interpretations
+const (
+	VitalInterpretationNormal = "normal"
+	VitalInterpretationLow    = "low"
+	VitalInterpretationHigh   = "high"
+)
+
+// Vital is a vital sign observation of a patient
+type Vital struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	PatientID   uint      `json:"patient_id" gorm:"not null;index:idx_vitals_patient_type_time"`
+	EncounterID *uint     `json:"encounter_id,omitempty" gorm:"index"`
+	Type        VitalType `json:"type" gorm:"not null;index:idx_vitals_patient_type_time"`
+	Value       float64   `json:"value" gorm:"not null"`
+	Diastolic   *float64  `json:"diastolic,omitempty"`
+	Unit        string    `json:"unit" gorm:"not null"`
+	RecordedAt  time.Time `json:"recorded_at" gorm:"not null;index:idx_vitals_patient_type_time"`
+	RecordedBy  uint      `json:"recorded_by" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Interpretation compares the value with the adult reference range
+	Interpretation string `json:"interpretation" gorm:"-"`
+	// Abnormal is true when the value is outside the reference range
+	Abnormal bool `json:"abnormal" gorm:"-"`
+}
+
+// VitalInput is a single observation of a RecordVitalsRequest. Unit
+// defaults to the stored unit of the type.
+type VitalInput struct {
+	Type      VitalType `json:"type" binding:"required,oneof=bp pulse temperature spo2 height weight bmi"`
+	Value     float64   `json:"value" binding:"required"`
+	Diastolic *float64  `json:"diastolic"`
+	Unit      string    `json:"unit"`
+}
+
+// RecordVitalsRequest represents a request to record a set of vital signs
+// taken together
+type RecordVitalsRequest struct {
+	// RecordedAt defaults to now
+	RecordedAt   *time.Time   `json:"recorded_at"`
+	EncounterID  *uint        `json:"encounter_id"`
+	Observations []VitalInput `json:"observations" binding:"required,min=1,dive"`
+}
+
+// VitalFilter narrows a vital sign listing. Empty fields match all
+// observations.
+type VitalFilter struct {
+	Type VitalType
+	From *time.Time
+	To   *time.Time
+}
+
+// ToVitalUnit converts a value of a vital sign type from a unit to the
+// stored unit. ok is false for units the type cannot be given in.
+func ToVitalUnit(vitalType VitalType, value float64, unit string) (converted float64, ok bool) {
+	unit = normalizeUnit(unit)
+	if unit == "" || unit == normalizeUnit(VitalUnits[vitalType]) {
+		return value, true
+	}
+
+	switch {
+	case vitalType == VitalTemperature && unit == "f":
+		return roundVital((value - 32) * 5 / 9), true
+	case vitalType == VitalWeight && (unit == "lb" || unit == "lbs"):
+		return roundVital(value * 0.45359237), true
+	case vitalType == VitalHeight && unit == "in":
+		return roundVital(value * 2.54), true
+	case vitalType == VitalHeight && unit == "m":
+		return roundVital(value * 100), true
+	}
+	return 0, false
+}
+
+// InImperialUnits returns the observation with temperature in Fahrenheit,
+// weight in pounds and height in inches
+func (v Vital) InImperialUnits() Vital {
+	switch v.Type {
+	case VitalTemperature:
+		v.Value, v.Unit = roundVital(v.Value*9/5+32), "F"
+	case VitalWeight:
+		v.Value, v.Unit = roundVital(v.Value/0.45359237), "lb"
+	case VitalHeight:
+		v.Value, v.Unit = roundVital(v.Value/2.54), "in"
+	}
+	return v
+}
+
+// CalculateBMI calculates the body mass index from a weight in kilograms
+// and a height in centimetres
+func CalculateBMI(weightKg, heightCm float64) float64 {
+	meters := heightCm / 100
+	return roundVital(weightKg / (meters * meters))
+}
+
+// Interpret flags the observation against adult reference ranges
+func (v *Vital) Interpret() {
+	v.Interpretation = VitalInterpretationNormal
+	low, high := false, false
+
+	switch v.Type {
+	case VitalBloodPressure:
+		low = v.Value < 90 || (v.Diastolic != nil && *v.Diastolic < 60)
+		high = v.Value >= 140 || (v.Diastolic != nil && *v.Diastolic >= 90)
+	case VitalPulse:
+		low, high = v.Value < 60, v.Value > 100
+	case VitalTemperature:
+		low, high = v.Value < 36, v.Value >= 38
+	case VitalSpO2:
+		low = v.Value < 95
+	case VitalBMI:
+		low, high = v.Value < 18.5, v.Value >= 30
+	}
+
+	if high {
+		v.Interpretation = VitalInterpretationHigh
+	} else if low {
+		v.Interpretation = VitalInterpretationLow
+	}
+	v.Abnormal = low || high
+}
+
+// normalizeUnit lower-cases a unit and strips degree signs
+func normalizeUnit(unit string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(unit), "°"))
+}
+
+// roundVital rounds a converted or calculated value to one decimal place
+func roundVital(value float64) float64 {
+	return math.Round(value*10) / 10
+}
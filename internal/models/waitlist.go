@@ -0,0 +1,163 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// WaitlistStatus represents where a patient is on a doctor's waitlist
+type WaitlistStatus string
+
+// Waitlist statuses
+const (
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+	WaitlistStatusOffered WaitlistStatus = "offered"
+	WaitlistStatusBooked  WaitlistStatus = "booked"
+	WaitlistStatusRemoved WaitlistStatus = "removed"
+)
+
+// SlotOfferStatus represents whether a slot offered from the waitlist is
+// still held for the patient
+type SlotOfferStatus string
+
+// Slot offer statuses
+const (
+	SlotOfferStatusPending  SlotOfferStatus = "pending"
+	SlotOfferStatusAccepted SlotOfferStatus = "accepted"
+	SlotOfferStatusDeclined SlotOfferStatus = "declined"
+	SlotOfferStatusExpired  SlotOfferStatus = "expired"
+)
+
+// Weekdays is a set of days of the week, stored as a JSON array
+type Weekdays []time.Weekday
+
+// Value stores the weekdays as JSON
+func (w Weekdays) Value() (driver.Value, error) {
+	if w == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the weekdays from JSON
+func (w *Weekdays) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	default:
+		return errors.New("unsupported weekdays value")
+	}
+}
+
+// WaitlistEntry is a patient waiting for an earlier appointment of a type
+// with a doctor, with the days and times of day they can come
+type WaitlistEntry struct {
+	ID        uint            `json:"id" gorm:"primaryKey"`
+	PatientID uint            `json:"patient_id" gorm:"not null;index"`
+	DoctorID  uint            `json:"doctor_id" gorm:"not null;index:idx_waitlist_entries_doctor_type"`
+	Type      AppointmentType `json:"type" gorm:"not null;index:idx_waitlist_entries_doctor_type"`
+	// Days lists the weekdays the patient can come (0 is Sunday); empty
+	// means any day
+	Days Weekdays `json:"days" gorm:"type:jsonb;not null"`
+	// FromTime and ToTime bound the times of day (HH:MM) the appointment
+	// may take; empty means any time
+	FromTime  string         `json:"from_time,omitempty"`
+	ToTime    string         `json:"to_time,omitempty"`
+	Status    WaitlistStatus `json:"status" gorm:"not null;index"`
+	Reason    string         `json:"reason"`
+	CreatedBy uint           `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// Accepts checks if a slot on a weekday between two times of day (HH:MM)
+// suits the patient's preferences
+func (e *WaitlistEntry) Accepts(day time.Weekday, startClock, endClock string) bool {
+	if len(e.Days) > 0 {
+		found := false
+		for _, d := range e.Days {
+			if d == day {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if e.FromTime != "" && startClock < e.FromTime {
+		return false
+	}
+	if e.ToTime != "" && endClock > e.ToTime {
+		return false
+	}
+	return true
+}
+
+// PatientWaitlistEntry is a waitlist entry with the name of its patient
+type PatientWaitlistEntry struct {
+	WaitlistEntry    `gorm:"embedded"`
+	PatientFirstName string `json:"patient_first_name"`
+	PatientLastName  string `json:"patient_last_name"`
+}
+
+// SlotOffer is a freed slot offered to a waitlisted patient. The slot is
+// held for the patient until the offer expires, and accepting it books the
+// appointment.
+type SlotOffer struct {
+	ID              uint            `json:"id" gorm:"primaryKey"`
+	WaitlistEntryID uint            `json:"waitlist_entry_id" gorm:"not null;index"`
+	PatientID       uint            `json:"patient_id" gorm:"not null;index"`
+	DoctorID        uint            `json:"doctor_id" gorm:"not null;index:idx_slot_offers_doctor_start"`
+	Type            AppointmentType `json:"type" gorm:"not null"`
+	StartTime       time.Time       `json:"start_time" gorm:"not null;index:idx_slot_offers_doctor_start"`
+	EndTime         time.Time       `json:"end_time" gorm:"not null"`
+	Status          SlotOfferStatus `json:"status" gorm:"not null;index"`
+	ExpiresAt       time.Time       `json:"expires_at" gorm:"not null"`
+	// CancelledAppointmentID is the appointment whose cancellation freed
+	// the slot
+	CancelledAppointmentID uint `json:"cancelled_appointment_id" gorm:"not null"`
+	// AppointmentID is the appointment booked when the offer was accepted
+	AppointmentID *uint      `json:"appointment_id,omitempty"`
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// WaitlistFilter narrows a waitlist listing. Empty fields match all
+// entries.
+type WaitlistFilter struct {
+	DoctorID uint
+	Type     AppointmentType
+	Status   WaitlistStatus
+}
+
+// SlotOfferFilter narrows a slot offer listing. Empty fields match all
+// offers.
+type SlotOfferFilter struct {
+	DoctorID uint
+	Status   SlotOfferStatus
+}
+
+// CreateWaitlistEntryRequest represents a request to put a patient on a
+// doctor's waitlist
+type CreateWaitlistEntryRequest struct {
+	PatientID uint            `json:"patient_id" binding:"required"`
+	DoctorID  uint            `json:"doctor_id" binding:"required"`
+	Type      AppointmentType `json:"type" binding:"required,oneof=consultation follow-up procedure"`
+	Days      []time.Weekday  `json:"days" binding:"dive,min=0,max=6"`
+	FromTime  string          `json:"from_time"`
+	ToTime    string          `json:"to_time"`
+	Reason    string          `json:"reason"`
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AllergyRepository handles allergy data operations
+type AllergyRepository struct {
+	db *gorm.DB
+}
+
+// NewAllergyRepository creates a new AllergyRepository
+func NewAllergyRepository(db *gorm.DB) *AllergyRepository {
+	return &AllergyRepository{db: db}
+}
+
+// Create creates a new allergy and records the audit event in the same
+// transaction
+func (r *AllergyRepository) Create(allergy *models.Allergy, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(allergy).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds an allergy by ID
+func (r *AllergyRepository) FindByID(id uint) (*models.Allergy, error) {
+	var allergy models.Allergy
+	err := r.db.Where("id = ?", id).First(&allergy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &allergy, nil
+}
+
+// FindByPatient finds the allergies of a patient
+func (r *AllergyRepository) FindByPatient(patientID uint) ([]models.Allergy, error) {
+	var allergies []models.Allergy
+	err := r.db.Where("patient_id = ?", patientID).Order("id").Find(&allergies).Error
+	return allergies, err
+}
+
+// Update updates an allergy and records the audit event in the same
+// transaction
+func (r *AllergyRepository) Update(allergy *models.Allergy, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(allergy).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// Delete deletes an allergy and records the audit event in the same
+// transaction
+func (r *AllergyRepository) Delete(id uint, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Allergy{}, id).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
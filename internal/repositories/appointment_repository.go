@@ -0,0 +1,264 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// exclusionViolation is the SQLSTATE of a row rejected by an exclusion
+// constraint, such as an overlapping booking
+const exclusionViolation = "23P01"
+
+// AppointmentRepository handles appointment data operations
+type AppointmentRepository struct {
+	db *gorm.DB
+}
+
+// NewAppointmentRepository creates a new AppointmentRepository
+func NewAppointmentRepository(db *gorm.DB) *AppointmentRepository {
+	return &AppointmentRepository{db: db}
+}
+
+// Create books an appointment and records the audit event in the same
+// transaction. booked is false when the slot overlaps another booking.
+func (r *AppointmentRepository) Create(appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(appointment).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	if isExclusionViolation(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// FindByID finds an appointment by ID
+func (r *AppointmentRepository) FindByID(id uint) (*models.Appointment, error) {
+	var appointment models.Appointment
+	err := r.db.Where("id = ?", id).First(&appointment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &appointment, nil
+}
+
+// FindAll finds the appointments matching a filter with the names of their
+// patients, in start time order
+func (r *AppointmentRepository) FindAll(filter models.AppointmentFilter) ([]models.PatientAppointment, error) {
+	query := r.db.Table("appointments").
+		Select("appointments.*, patients.first_name AS patient_first_name, patients.last_name AS patient_last_name").
+		Joins("JOIN patients ON patients.id = appointments.patient_id AND patients.deleted_at IS NULL")
+	if filter.DoctorID != 0 {
+		query = query.Where("appointments.doctor_id = ?", filter.DoctorID)
+	}
+	if filter.PatientID != 0 {
+		query = query.Where("appointments.patient_id = ?", filter.PatientID)
+	}
+	if filter.Status != "" {
+		query = query.Where("appointments.status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("appointments.start_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("appointments.start_time < ?", *filter.To)
+	}
+
+	var appointments []models.PatientAppointment
+	err := query.Order("appointments.start_time, appointments.id").Scan(&appointments).Error
+	return appointments, err
+}
+
+// FindBooked finds the booked appointments of a doctor overlapping a period
+func (r *AppointmentRepository) FindBooked(doctorID uint, from, to time.Time) ([]models.Appointment, error) {
+	var appointments []models.Appointment
+	err := r.db.Where("doctor_id = ? AND status = ? AND start_time < ? AND end_time > ?",
+		doctorID, models.AppointmentStatusBooked, to, from).
+		Order("start_time").Find(&appointments).Error
+	return appointments, err
+}
+
+// Reschedule stores the new times of a booked appointment and records the
+// audit event in the same transaction. rescheduled is false when the new
+// slot overlaps another booking or the appointment was cancelled first.
+func (r *AppointmentRepository) Reschedule(appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	return r.updateBooked(appointment, event, "start_time", "end_time", "reschedule_reason", "updated_at")
+}
+
+// Cancel stores the cancellation of a booked appointment and records the
+// audit event in the same transaction. cancelled is false when it was
+// cancelled first.
+func (r *AppointmentRepository) Cancel(appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	return r.updateBooked(appointment, event, "status", "cancellation_reason", "cancelled_at", "updated_at")
+}
+
+// updateBooked stores columns of an appointment that is still booked
+func (r *AppointmentRepository) updateBooked(appointment *models.Appointment, event *models.AuditEvent, columns ...string) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		updated, err = updateBookedColumns(tx, appointment, columns...)
+		if err != nil || !updated {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	if isExclusionViolation(err) {
+		return false, nil
+	}
+	return updated, err
+}
+
+// CreateSeries creates a series and books its occurrences, recording the
+// audit event in the same transaction. booked[i] reports whether
+// appointments[i] was booked; occurrences overlapping another booking are
+// left out, and nothing is stored when none could be booked.
+func (r *AppointmentRepository) CreateSeries(series *models.AppointmentSeries, appointments []models.Appointment, event *models.AuditEvent) ([]bool, error) {
+	var booked []bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(series).Error; err != nil {
+			return err
+		}
+		for i := range appointments {
+			appointments[i].SeriesID = &series.ID
+		}
+
+		var err error
+		booked, err = writeEach(tx, appointments, func(tx *gorm.DB, appointment *models.Appointment) (bool, error) {
+			return true, tx.Create(appointment).Error
+		})
+		if err != nil {
+			return err
+		}
+		if !anyTrue(booked) {
+			return errNothingWritten
+		}
+		return appendAuditEvent(tx, event)
+	})
+	if errors.Is(err, errNothingWritten) {
+		series.ID = 0
+		return booked, nil
+	}
+	return booked, err
+}
+
+// FindSeriesByID finds an appointment series by ID
+func (r *AppointmentRepository) FindSeriesByID(id uint) (*models.AppointmentSeries, error) {
+	var series models.AppointmentSeries
+	err := r.db.Where("id = ?", id).First(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// FindBySeries finds the occurrences of a series in start time order
+func (r *AppointmentRepository) FindBySeries(seriesID uint) ([]models.Appointment, error) {
+	var appointments []models.Appointment
+	err := r.db.Where("series_id = ?", seriesID).Order("start_time, id").Find(&appointments).Error
+	return appointments, err
+}
+
+// RescheduleSeries stores the new times of booked occurrences in the given
+// order and records the audit event in the same transaction. moved[i]
+// reports whether appointments[i] was moved; occurrences whose new slot
+// overlaps another booking or that were cancelled first keep their times.
+func (r *AppointmentRepository) RescheduleSeries(appointments []models.Appointment, event *models.AuditEvent) ([]bool, error) {
+	var moved []bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		moved, err = writeEach(tx, appointments, func(tx *gorm.DB, appointment *models.Appointment) (bool, error) {
+			return updateBookedColumns(tx, appointment, "start_time", "end_time", "reschedule_reason", "updated_at")
+		})
+		if err != nil {
+			return err
+		}
+		if !anyTrue(moved) {
+			return errNothingWritten
+		}
+		return appendAuditEvent(tx, event)
+	})
+	if errors.Is(err, errNothingWritten) {
+		return moved, nil
+	}
+	return moved, err
+}
+
+// CancelSeries cancels the occurrences that are still booked and, when the
+// series itself is cancelled, stores its cancellation, recording the audit
+// event in the same transaction
+func (r *AppointmentRepository) CancelSeries(series *models.AppointmentSeries, appointments []models.Appointment, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if series.Status == models.AppointmentStatusCancelled {
+			err := tx.Model(series).Select("status", "cancellation_reason", "cancelled_at", "updated_at").
+				Updates(series).Error
+			if err != nil {
+				return err
+			}
+		}
+		for i := range appointments {
+			if _, err := updateBookedColumns(tx, &appointments[i], "status", "cancellation_reason", "cancelled_at", "updated_at"); err != nil {
+				return err
+			}
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// errNothingWritten rolls back a series change none of whose occurrences
+// could be written
+var errNothingWritten = errors.New("no occurrence written")
+
+// updateBookedColumns stores columns of an appointment that is still booked
+func updateBookedColumns(tx *gorm.DB, appointment *models.Appointment, columns ...string) (bool, error) {
+	result := tx.Model(appointment).Where("status = ?", models.AppointmentStatusBooked).
+		Select(columns).Updates(appointment)
+	return result.RowsAffected > 0, result.Error
+}
+
+// writeEach writes appointments one at a time under a savepoint, so one
+// rejected for overlapping another booking does not undo the others
+func writeEach(tx *gorm.DB, appointments []models.Appointment, write func(tx *gorm.DB, appointment *models.Appointment) (bool, error)) ([]bool, error) {
+	written := make([]bool, len(appointments))
+	for i := range appointments {
+		if err := tx.SavePoint("occurrence").Error; err != nil {
+			return nil, err
+		}
+		ok, err := write(tx, &appointments[i])
+		if isExclusionViolation(err) {
+			if err := tx.RollbackTo("occurrence").Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		written[i] = ok
+	}
+	return written, nil
+}
+
+// anyTrue checks if any of the flags is set
+func anyTrue(flags []bool) bool {
+	for _, flag := range flags {
+		if flag {
+			return true
+		}
+	}
+	return false
+}
+
+// isExclusionViolation checks if a write was rejected by an exclusion
+// constraint
+func isExclusionViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == exclusionViolation
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// auditChainLockKey identifies the advisory lock serialising appends to the
+// audit hash chain
+const auditChainLockKey = 7421001
+
+// AuditRepository handles audit trail data operations. Events are
+// append-only, so there are no update or delete operations.
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new AuditRepository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Append chains an event to the audit trail
+func (r *AuditRepository) Append(event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindAll finds events matching a filter, most recent first
+func (r *AuditRepository) FindAll(filter models.AuditFilter, limit, offset int) ([]models.AuditEvent, int64, error) {
+	var events []models.AuditEvent
+	var count int64
+
+	query := r.db.Model(&models.AuditEvent{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.PatientID != 0 {
+		query = query.Where("patient_id = ?", filter.PatientID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	// Get total count
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get events with pagination
+	if err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, count, nil
+}
+
+// FindAfter finds up to limit events following an event ID, in chain order
+func (r *AuditRepository) FindAfter(afterID uint, limit int) ([]models.AuditEvent, error) {
+	var events []models.AuditEvent
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// appendAuditEvent links an event to the last one in the chain and stores it.
+// It must run inside a transaction: the advisory lock it takes is held until
+// the transaction ends, keeping the chain linear under concurrent writes.
+func appendAuditEvent(tx *gorm.DB, event *models.AuditEvent) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockKey).Error; err != nil {
+		return err
+	}
+
+	var last models.AuditEvent
+	if err := tx.Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+		return err
+	}
+
+	// Postgres keeps microseconds, so truncate to hash what is stored
+	event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	event.PrevHash = last.Hash
+	event.Hash = event.ComputeHash()
+
+	return tx.Create(event).Error
+}
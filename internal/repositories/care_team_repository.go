@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CareTeamRepository handles care team assignment data operations
+type CareTeamRepository struct {
+	db *gorm.DB
+}
+
+// NewCareTeamRepository creates a new CareTeamRepository
+func NewCareTeamRepository(db *gorm.DB) *CareTeamRepository {
+	return &CareTeamRepository{db: db}
+}
+
+// Assign creates a new assignment and records the audit event in the same
+// transaction. A new primary physician ends the primary physician
+// assignments of the patient that are still open when it starts.
+func (r *CareTeamRepository) Assign(assignment *models.CareTeamAssignment, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if assignment.Role == models.CareTeamPrimary {
+			err := tx.Model(&models.CareTeamAssignment{}).
+				Where("patient_id = ? AND role = ?", assignment.PatientID, models.CareTeamPrimary).
+				Where("end_date IS NULL OR end_date > ?", assignment.StartDate).
+				Update("end_date", assignment.StartDate).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(assignment).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds an assignment by ID
+func (r *CareTeamRepository) FindByID(id uint) (*models.CareTeamAssignment, error) {
+	var assignment models.CareTeamAssignment
+	err := r.db.Where("id = ?", id).First(&assignment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// FindByPatient finds the assignments of a patient, most recent first
+func (r *CareTeamRepository) FindByPatient(patientID uint) ([]models.CareTeamAssignment, error) {
+	var assignments []models.CareTeamAssignment
+	err := r.db.Where("patient_id = ?", patientID).Order("start_date DESC, id DESC").Find(&assignments).Error
+	if err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// IsMember checks if a clinician is on a patient's care team at a point in time
+func (r *CareTeamRepository) IsMember(patientID, clinicianID uint, at time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.CareTeamAssignment{}).
+		Where("patient_id = ? AND clinician_id = ?", patientID, clinicianID).
+		Where("start_date <= ? AND (end_date IS NULL OR end_date > ?)", at, at).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// End sets the end date of an assignment and records the audit event in the
+// same transaction
+func (r *CareTeamRepository) End(id uint, at time.Time, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.CareTeamAssignment{}).Where("id = ?", id).Update("end_date", at).Error
+		if err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// RecordOverride stores an access override
+func (r *CareTeamRepository) RecordOverride(override *models.AccessOverride) error {
+	return r.db.Create(override).Error
+}
+
+// careTeamPatientIDs builds a subquery selecting the patients whose care team
+// includes a clinician at a point in time
+func careTeamPatientIDs(db *gorm.DB, clinicianID uint, at time.Time) *gorm.DB {
+	return db.Model(&models.CareTeamAssignment{}).
+		Select("patient_id").
+		Where("clinician_id = ?", clinicianID).
+		Where("start_date <= ? AND (end_date IS NULL OR end_date > ?)", at, at)
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ClinicalNoteRepository handles clinical note data operations
+type ClinicalNoteRepository struct {
+	db *gorm.DB
+}
+
+// NewClinicalNoteRepository creates a new ClinicalNoteRepository
+func NewClinicalNoteRepository(db *gorm.DB) *ClinicalNoteRepository {
+	return &ClinicalNoteRepository{db: db}
+}
+
+// Create creates a new note and records the audit event in the same
+// transaction
+func (r *ClinicalNoteRepository) Create(note *models.ClinicalNote, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(note).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds a note by ID with its addenda
+func (r *ClinicalNoteRepository) FindByID(id uint) (*models.ClinicalNote, error) {
+	var note models.ClinicalNote
+	err := r.db.Where("id = ?", id).First(&note).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("note_id = ?", id).Order("id").Find(&note.Addenda).Error; err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// FindByPatient finds the notes of a patient with their addenda, optionally
+// only those of one encounter, most recent first
+func (r *ClinicalNoteRepository) FindByPatient(patientID uint, encounterID *uint) ([]models.ClinicalNote, error) {
+	var notes []models.ClinicalNote
+	query := r.db.Where("patient_id = ?", patientID)
+	if encounterID != nil {
+		query = query.Where("encounter_id = ?", *encounterID)
+	}
+	if err := query.Order("created_at DESC, id DESC").Find(&notes).Error; err != nil {
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return notes, nil
+	}
+
+	ids := make([]uint, len(notes))
+	byID := make(map[uint]*models.ClinicalNote, len(notes))
+	for i := range notes {
+		ids[i] = notes[i].ID
+		byID[notes[i].ID] = &notes[i]
+	}
+	var addenda []models.NoteAddendum
+	if err := r.db.Where("note_id IN ?", ids).Order("id").Find(&addenda).Error; err != nil {
+		return nil, err
+	}
+	for _, addendum := range addenda {
+		note := byID[addendum.NoteID]
+		note.Addenda = append(note.Addenda, addendum)
+	}
+	return notes, nil
+}
+
+// UpdateDraft stores the sections, status and signature of a note if it is
+// still a draft, and records the audit event in the same transaction.
+// updated is false when the note was signed first.
+func (r *ClinicalNoteRepository) UpdateDraft(note *models.ClinicalNote, event *models.AuditEvent) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(note).Where("status = ?", models.ClinicalNoteStatusDraft).
+			Select("subjective", "objective", "assessment", "plan", "status", "signed_by", "signed_at", "updated_at").
+			Updates(note)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		return appendAuditEvent(tx, event)
+	})
+	return updated, err
+}
+
+// CreateAddendum creates a new addendum and records the audit event in the
+// same transaction
+func (r *ClinicalNoteRepository) CreateAddendum(addendum *models.NoteAddendum, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(addendum).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
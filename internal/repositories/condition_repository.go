@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ConditionRepository handles condition data operations
+type ConditionRepository struct {
+	db *gorm.DB
+}
+
+// NewConditionRepository creates a new ConditionRepository
+func NewConditionRepository(db *gorm.DB) *ConditionRepository {
+	return &ConditionRepository{db: db}
+}
+
+// Create creates a new condition and records the audit event in the same
+// transaction
+func (r *ConditionRepository) Create(condition *models.Condition, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(condition).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds a condition by ID
+func (r *ConditionRepository) FindByID(id uint) (*models.Condition, error) {
+	var condition models.Condition
+	err := r.db.Where("id = ?", id).First(&condition).Error
+	if err != nil {
+		return nil, err
+	}
+	return &condition, nil
+}
+
+// FindByPatient finds the conditions of a patient, most recent onset first
+func (r *ConditionRepository) FindByPatient(patientID uint) ([]models.Condition, error) {
+	var conditions []models.Condition
+	err := r.db.Where("patient_id = ?", patientID).Order("onset_date DESC NULLS LAST, id DESC").Find(&conditions).Error
+	return conditions, err
+}
+
+// Update updates a condition and records the audit event in the same
+// transaction
+func (r *ConditionRepository) Update(condition *models.Condition, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(condition).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// Delete deletes a condition and records the audit event in the same
+// transaction
+func (r *ConditionRepository) Delete(id uint, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Condition{}, id).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
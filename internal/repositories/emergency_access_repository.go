@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EmergencyAccessRepository handles emergency access grant data operations.
+// Grants are append-only, so there are no update or delete operations.
+type EmergencyAccessRepository struct {
+	db *gorm.DB
+}
+
+// NewEmergencyAccessRepository creates a new EmergencyAccessRepository
+func NewEmergencyAccessRepository(db *gorm.DB) *EmergencyAccessRepository {
+	return &EmergencyAccessRepository{db: db}
+}
+
+// Create creates a new grant and records it in the audit trail
+func (r *EmergencyAccessRepository) Create(grant *models.EmergencyAccessGrant, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(grant).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds a grant by ID
+func (r *EmergencyAccessRepository) FindByID(id uint) (*models.EmergencyAccessGrant, error) {
+	var grant models.EmergencyAccessGrant
+	err := r.db.Where("id = ?", id).First(&grant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// FindAll finds grants matching a filter, most recent first
+func (r *EmergencyAccessRepository) FindAll(filter models.EmergencyAccessFilter, limit, offset int) ([]models.EmergencyAccessGrant, int64, error) {
+	var grants []models.EmergencyAccessGrant
+	var count int64
+
+	query := r.db.Model(&models.EmergencyAccessGrant{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.PatientID != 0 {
+		query = query.Where("patient_id = ?", filter.PatientID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	// Get total count
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get grants with pagination
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&grants).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return grants, count, nil
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// EncounterRepository handles encounter data operations
+type EncounterRepository struct {
+	db *gorm.DB
+}
+
+// NewEncounterRepository creates a new EncounterRepository
+func NewEncounterRepository(db *gorm.DB) *EncounterRepository {
+	return &EncounterRepository{db: db}
+}
+
+// Create creates a new encounter and records the audit event in the same
+// transaction
+func (r *EncounterRepository) Create(encounter *models.Encounter, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(encounter).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds an encounter by ID
+func (r *EncounterRepository) FindByID(id uint) (*models.Encounter, error) {
+	var encounter models.Encounter
+	err := r.db.Where("id = ?", id).First(&encounter).Error
+	if err != nil {
+		return nil, err
+	}
+	return &encounter, nil
+}
+
+// FindRecords loads the vitals, diagnoses and prescriptions attached to an
+// encounter
+func (r *EncounterRepository) FindRecords(encounter *models.Encounter) error {
+	if err := r.db.Where("encounter_id = ?", encounter.ID).Order("recorded_at, id").Find(&encounter.Vitals).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("encounter_id = ?", encounter.ID).Order("id").Find(&encounter.Conditions).Error; err != nil {
+		return err
+	}
+	return r.db.Where("encounter_id = ?", encounter.ID).Order("id").Find(&encounter.Medications).Error
+}
+
+// FindByPatient finds the encounters of a patient, most recent first
+func (r *EncounterRepository) FindByPatient(patientID uint) ([]models.Encounter, error) {
+	var encounters []models.Encounter
+	err := r.db.Where("patient_id = ?", patientID).
+		Order("COALESCE(started_at, planned_start, created_at) DESC, id DESC").
+		Find(&encounters).Error
+	return encounters, err
+}
+
+// UpdateStatus stores the status and times of an encounter if it is still in
+// the status it was loaded in, and records the audit event in the same
+// transaction. updated is false when another request moved it first.
+func (r *EncounterRepository) UpdateStatus(encounter *models.Encounter, from models.EncounterStatus, event *models.AuditEvent) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(encounter).Where("status = ?", from).
+			Select("status", "started_at", "ended_at", "updated_at").Updates(encounter)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		return appendAuditEvent(tx, event)
+	})
+	return updated, err
+}
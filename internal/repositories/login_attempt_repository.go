@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptRepository stores login failure counters in the database
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new LoginAttemptRepository
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// Get finds the counters for a key, returning nil when none exist
+func (r *LoginAttemptRepository) Get(key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := r.db.Where("key = ?", key).First(&attempt).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RecordFailure atomically increments the failure counter of a key. Failures
+// older than window are forgotten before counting the new one.
+func (r *LoginAttemptRepository) RecordFailure(key string, now time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	attempt := models.LoginAttempt{
+		Key:           key,
+		Failures:      1,
+		LastFailureAt: now,
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures": gorm.Expr("CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END",
+				now.Add(-window)),
+			"last_failure_at": now,
+			"updated_at":      now,
+		}),
+	}).Create(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Get(key)
+}
+
+// SetLockedUntil locks a key until the given time, or unlocks it when nil
+func (r *LoginAttemptRepository) SetLockedUntil(key string, until *time.Time) error {
+	return r.db.Model(&models.LoginAttempt{}).
+		Where("key = ?", key).
+		Update("locked_until", until).Error
+}
+
+// Reset clears the counters and lock of a key
+func (r *LoginAttemptRepository) Reset(key string) error {
+	return r.db.Where("key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MedicationRepository handles medication data operations
+type MedicationRepository struct {
+	db *gorm.DB
+}
+
+// NewMedicationRepository creates a new MedicationRepository
+func NewMedicationRepository(db *gorm.DB) *MedicationRepository {
+	return &MedicationRepository{db: db}
+}
+
+// Create creates a new medication and records the audit event in the same
+// transaction
+func (r *MedicationRepository) Create(medication *models.Medication, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(medication).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds a medication by ID
+func (r *MedicationRepository) FindByID(id uint) (*models.Medication, error) {
+	var medication models.Medication
+	err := r.db.Where("id = ?", id).First(&medication).Error
+	if err != nil {
+		return nil, err
+	}
+	return &medication, nil
+}
+
+// FindByPatient finds the medications of a patient, most recently started
+// first. An empty status matches every medication.
+func (r *MedicationRepository) FindByPatient(patientID uint, status models.MedicationStatus) ([]models.Medication, error) {
+	var medications []models.Medication
+	query := r.db.Where("patient_id = ?", patientID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("start_date DESC, id DESC").Find(&medications).Error
+	return medications, err
+}
+
+// FindAll finds medications across patients with pagination, together with
+// the patient taking each one
+func (r *MedicationRepository) FindAll(filter models.MedicationFilter, limit, offset int) ([]models.PatientMedication, int64, error) {
+	var medications []models.PatientMedication
+	var count int64
+
+	query := r.db.Table("medications").
+		Joins("JOIN patients ON patients.id = medications.patient_id AND patients.deleted_at IS NULL").
+		Where("medications.deleted_at IS NULL")
+	if filter.DrugName != "" {
+		query = query.Where("medications.drug_name ILIKE ?", "%"+filter.DrugName+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("medications.status = ?", filter.Status)
+	}
+	if filter.CareTeamMemberID != 0 {
+		query = query.Where("medications.patient_id IN (?)", careTeamPatientIDs(r.db, filter.CareTeamMemberID, time.Now()))
+	}
+
+	// Get total count
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get medications with pagination
+	err := query.Select("medications.*, patients.first_name AS patient_first_name, patients.last_name AS patient_last_name").
+		Order("patients.last_name, patients.first_name, medications.id").
+		Limit(limit).Offset(offset).
+		Scan(&medications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return medications, count, nil
+}
+
+// Update updates a medication and records the audit event in the same
+// transaction
+func (r *MedicationRepository) Update(medication *models.Medication, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(medication).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// MemoryLoginAttemptStore keeps login failure counters in process memory.
+// Counters are lost on restart and not shared between instances.
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]models.LoginAttempt
+}
+
+// NewMemoryLoginAttemptStore creates a new MemoryLoginAttemptStore
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]models.LoginAttempt)}
+}
+
+// Get finds the counters for a key, returning nil when none exist
+func (s *MemoryLoginAttemptStore) Get(key string) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+// RecordFailure increments the failure counter of a key. Failures older than
+// window are forgotten before counting the new one.
+func (s *MemoryLoginAttemptStore) RecordFailure(key string, now time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok || attempt.LastFailureAt.Before(now.Add(-window)) {
+		attempt.Key = key
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	attempt.UpdatedAt = now
+	s.attempts[key] = attempt
+
+	return &attempt, nil
+}
+
+// SetLockedUntil locks a key until the given time, or unlocks it when nil
+func (s *MemoryLoginAttemptStore) SetLockedUntil(key string, until *time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if attempt, ok := s.attempts[key]; ok {
+		attempt.LockedUntil = until
+		s.attempts[key] = attempt
+	}
+	return nil
+}
+
+// Reset clears the counters and lock of a key
+func (s *MemoryLoginAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
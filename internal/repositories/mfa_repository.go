@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MFARepository handles recovery code and role policy data operations
+type MFARepository struct {
+	db *gorm.DB
+}
+
+// NewMFARepository creates a new MFARepository
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// ReplaceRecoveryCodes deletes a user's recovery codes and stores new ones
+func (r *MFARepository) ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks an unused recovery code as used. It reports whether
+// a matching unused code was found.
+func (r *MFARepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RoleRequiresMFA checks whether the policy of a role requires MFA
+func (r *MFARepository) RoleRequiresMFA(role models.UserRole) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RolePolicy{}).
+		Where("role = ? AND require_mfa = ?", role, true).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindAllRolePolicies finds all role policies
+func (r *MFARepository) FindAllRolePolicies() ([]models.RolePolicy, error) {
+	var policies []models.RolePolicy
+	err := r.db.Order("role").Find(&policies).Error
+	if err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SaveRolePolicy creates or updates a role policy
+func (r *MFARepository) SaveRolePolicy(policy *models.RolePolicy) error {
+	return r.db.Save(policy).Error
+}
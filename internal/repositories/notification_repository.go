@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository handles notification outbox and contact preference
+// data operations
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create queues a notification and records the audit event in the same
+// transaction. created is false when a notification about the same event
+// was queued first.
+func (r *NotificationRepository) Create(notification *models.Notification, event *models.AuditEvent) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(notification).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		notification.ID = 0
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// FindEventKeys finds which of a set of event keys have been queued
+func (r *NotificationRepository) FindEventKeys(keys []string) ([]string, error) {
+	var found []string
+	if len(keys) == 0 {
+		return found, nil
+	}
+	err := r.db.Model(&models.Notification{}).Where("event_key IN ?", keys).Pluck("event_key", &found).Error
+	return found, err
+}
+
+// ClaimDue claims up to limit pending notifications due for delivery at a
+// time, the longest waiting first. Claimed notifications are not due again
+// until the lease has passed, and rows another instance is claiming are
+// skipped, so each notification is delivered by one instance at a time.
+func (r *NotificationRepository) ClaimDue(at time.Time, lease time.Duration, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.NotificationStatusPending, at).
+			Order("next_attempt_at, id").Limit(limit).Find(&notifications).Error
+		if err != nil || len(notifications) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(notifications))
+		for i := range notifications {
+			ids[i] = notifications[i].ID
+		}
+		return tx.Model(&models.Notification{}).Where("id IN ?", ids).
+			Update("next_attempt_at", at.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt of a pending
+// notification, or why it was suppressed
+func (r *NotificationRepository) UpdateDelivery(notification *models.Notification) error {
+	return r.db.Model(notification).Where("status = ?", models.NotificationStatusPending).
+		Select("status", "channel", "recipient", "subject", "body", "attempts", "next_attempt_at", "last_error", "sent_at", "updated_at").
+		Updates(notification).Error
+}
+
+// FindAll finds the notifications matching a filter, newest first
+func (r *NotificationRepository) FindAll(filter models.NotificationFilter) ([]models.Notification, error) {
+	query := r.db.Model(&models.Notification{})
+	if filter.PatientID != 0 {
+		query = query.Where("patient_id = ?", filter.PatientID)
+	}
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var notifications []models.Notification
+	err := query.Order("created_at DESC, id DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+// FindPreference finds the contact preferences of a patient, or nil when
+// none were set
+func (r *NotificationRepository) FindPreference(patientID uint) (*models.ContactPreference, error) {
+	var preference models.ContactPreference
+	err := r.db.Where("patient_id = ?", patientID).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+// SavePreference stores the contact preferences of a patient and records
+// the audit event in the same transaction
+func (r *NotificationRepository) SavePreference(preference *models.ContactPreference, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(preference).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
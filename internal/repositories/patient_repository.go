@@ -1,85 +1,210 @@
-package repositories
-
-import (
-	"healthcare-app/internal/models"
-
-	"gorm.io/gorm"
-)
-
-// PatientRepository handles patient data operations
-type PatientRepository struct {
-	db *gorm.DB
-}
-
-// NewPatientRepository creates a new PatientRepository
-func NewPatientRepository(db *gorm.DB) *PatientRepository {
-	return &PatientRepository{db: db}
-}
-
-// Create creates a new patient
-func (r *PatientRepository) Create(patient *models.Patient) error {
-	return r.db.Create(patient).Error
-}
-
-// FindByID finds a patient by ID
-func (r *PatientRepository) FindByID(id uint) (*models.Patient, error) {
-	var patient models.Patient
-	err := r.db.Where("id = ?", id).First(&patient).Error
-	if err != nil {
-		return nil, err
-	}
-	return &patient, nil
-}
-
-// FindAll finds all patients
-func (r *PatientRepository) FindAll(limit, offset int) ([]models.Patient, int64, error) {
-	var patients []models.Patient
-	var count int64
-
-	query := r.db.Model(&models.Patient{})
-	
-	// Get total count
-	if err := query.Count(&count).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Get patients with pagination
-	if err := query.Limit(limit).Offset(offset).Find(&patients).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return patients, count, nil
-}
-
-// Update updates a patient
-func (r *PatientRepository) Update(patient *models.Patient) error {
-	return r.db.Save(patient).Error
-}
-
-// Delete deletes a patient
-func (r *PatientRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Patient{}, id).Error
-}
-
-// SearchPatients searches for patients
-func (r *PatientRepository) SearchPatients(searchTerm string, limit, offset int) ([]models.Patient, int64, error) {
-	var patients []models.Patient
-	var count int64
-
-	query := r.db.Model(&models.Patient{}).Where(
-		"first_name ILIKE ? OR last_name ILIKE ? OR email ILIKE ? OR contact_number LIKE ?",
-		"%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%",
-	)
-	
-	// Get total count
-	if err := query.Count(&count).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Get patients with pagination
-	if err := query.Limit(limit).Offset(offset).Find(&patients).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return patients, count, nil
-} 
\ No newline at end of file
+package repositories
+
+import (
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PatientRepository handles patient data operations
+type PatientRepository struct {
+	db *gorm.DB
+}
+
+// NewPatientRepository creates a new PatientRepository
+func NewPatientRepository(db *gorm.DB) *PatientRepository {
+	return &PatientRepository{db: db}
+}
+
+// Create creates a new patient and records its first revision and the audit
+// event in the same transaction. A primary physician assignment, when given,
+// is created in the same transaction as well.
+func (r *PatientRepository) Create(patient *models.Patient, primary *models.CareTeamAssignment, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(patient).Error; err != nil {
+			return err
+		}
+		if primary != nil {
+			primary.PatientID = patient.ID
+			if err := tx.Create(primary).Error; err != nil {
+				return err
+			}
+		}
+		event.PatientID = &patient.ID
+		if err := appendPatientRevision(tx, patient, event); err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds a patient by ID with its structured allergies
+func (r *PatientRepository) FindByID(id uint) (*models.Patient, error) {
+	var patient models.Patient
+	err := r.db.Where("id = ?", id).First(&patient).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("patient_id = ?", id).Order("id").Find(&patient.AllergyList).Error; err != nil {
+		return nil, err
+	}
+	return &patient, nil
+}
+
+// FindAll finds all patients matching a filter
+func (r *PatientRepository) FindAll(filter models.PatientFilter, limit, offset int) ([]models.Patient, int64, error) {
+	var patients []models.Patient
+	var count int64
+
+	query := r.applyFilter(r.db.Model(&models.Patient{}), filter)
+	
+	// Get total count
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get patients with pagination
+	if err := query.Limit(limit).Offset(offset).Find(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return patients, count, nil
+}
+
+// Update updates a patient if it is still at the version it was loaded with,
+// incrementing the version, and records the revision and audit event in the
+// same transaction. updated is false when another write got there first.
+func (r *PatientRepository) Update(patient *models.Patient, event *models.AuditEvent) (bool, error) {
+	expected := patient.Version
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		patient.Version = expected + 1
+		result := tx.Model(patient).Where("version = ?", expected).
+			Select("*").Omit("id", "created_at", "deleted_at").Updates(patient)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+
+		if err := appendPatientRevision(tx, patient, event); err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	if err != nil || !updated {
+		patient.Version = expected
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete deletes a patient and records the audit event in the same
+// transaction
+func (r *PatientRepository) Delete(id uint, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Patient{}, id).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// SearchPatients searches for patients matching a filter
+func (r *PatientRepository) SearchPatients(searchTerm string, filter models.PatientFilter, limit, offset int) ([]models.Patient, int64, error) {
+	var patients []models.Patient
+	var count int64
+
+	query := r.applyFilter(r.db.Model(&models.Patient{}), filter).Where(
+		"first_name ILIKE ? OR last_name ILIKE ? OR email ILIKE ? OR contact_number LIKE ?",
+		"%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%", "%"+searchTerm+"%",
+	)
+	
+	// Get total count
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get patients with pagination
+	if err := query.Limit(limit).Offset(offset).Find(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return patients, count, nil
+}
+
+// FindRevisions finds the revisions of a patient, most recent first
+func (r *PatientRepository) FindRevisions(patientID uint, limit, offset int) ([]models.PatientRevision, int64, error) {
+	var revisions []models.PatientRevision
+	var count int64
+
+	query := r.db.Model(&models.PatientRevision{}).Where("patient_id = ?", patientID)
+
+	// Get total count
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get revisions with pagination
+	if err := query.Order("revision DESC").Limit(limit).Offset(offset).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, count, nil
+}
+
+// FindRevision finds a revision of a patient by its number
+func (r *PatientRepository) FindRevision(patientID uint, revision int) (*models.PatientRevision, error) {
+	var rev models.PatientRevision
+	err := r.db.Where("patient_id = ? AND revision = ?", patientID, revision).First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// FindRevisionAsOf finds the revision of a patient that was current at a
+// point in time
+func (r *PatientRepository) FindRevisionAsOf(patientID uint, at time.Time) (*models.PatientRevision, error) {
+	var rev models.PatientRevision
+	err := r.db.Where("patient_id = ? AND created_at <= ?", patientID, at).
+		Order("revision DESC").First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// appendPatientRevision stores the next revision of a patient. It runs in the
+// transaction of the write it records; the unique revision number rejects a
+// concurrent write that raced it.
+func appendPatientRevision(tx *gorm.DB, patient *models.Patient, event *models.AuditEvent) error {
+	revision, err := models.NewPatientRevision(patient, event)
+	if err != nil {
+		return err
+	}
+
+	var last int
+	if err := tx.Model(&models.PatientRevision{}).Where("patient_id = ?", patient.ID).
+		Select("COALESCE(MAX(revision), 0)").Scan(&last).Error; err != nil {
+		return err
+	}
+	revision.Revision = last + 1
+
+	return tx.Create(revision).Error
+}
+
+// applyFilter adds the conditions of a patient filter to a query
+func (r *PatientRepository) applyFilter(query *gorm.DB, filter models.PatientFilter) *gorm.DB {
+	if filter.CareTeamMemberID != 0 {
+		query = query.Where("id IN (?)", careTeamPatientIDs(r.db, filter.CareTeamMemberID, time.Now()))
+	}
+	if filter.ConditionCode != "" {
+		query = query.Where("id IN (?)", r.db.Model(&models.Condition{}).
+			Select("patient_id").
+			Where("code LIKE ?", filter.ConditionCode+"%").
+			Where("clinical_status IN ?", models.CurrentConditionStatuses))
+	}
+	return query
+}
\ No newline at end of file
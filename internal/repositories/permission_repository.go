@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PermissionRepository handles role permission data operations
+type PermissionRepository struct {
+	db *gorm.DB
+}
+
+// NewPermissionRepository creates a new PermissionRepository
+func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
+	return &PermissionRepository{db: db}
+}
+
+// FindByRole finds the permissions granted to a role
+func (r *PermissionRepository) FindByRole(role models.UserRole) ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.Model(&models.RolePermission{}).
+		Where("role = ?", role).
+		Order("permission").
+		Pluck("permission", &permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// FindAll finds all role permissions
+func (r *PermissionRepository) FindAll() ([]models.RolePermission, error) {
+	var rolePermissions []models.RolePermission
+	err := r.db.Order("role, permission").Find(&rolePermissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return rolePermissions, nil
+}
+
+// ReplaceForRole replaces the permissions granted to a role
+func (r *PermissionRepository) ReplaceForRole(role models.UserRole, permissions []models.Permission) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role = ?", role).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		rows := make([]models.RolePermission, 0, len(permissions))
+		for _, permission := range permissions {
+			rows = append(rows, models.RolePermission{Role: role, Permission: permission})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Count counts all role permissions
+func (r *PermissionRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RolePermission{}).Count(&count).Error
+	return count, err
+}
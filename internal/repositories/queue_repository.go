@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"errors"
+
+	"healthcare-app/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation is the SQLSTATE of a row rejected by a unique constraint
+const uniqueViolation = "23505"
+
+// QueueRepository handles check-in queue data operations
+type QueueRepository struct {
+	db *gorm.DB
+}
+
+// NewQueueRepository creates a new QueueRepository
+func NewQueueRepository(db *gorm.DB) *QueueRepository {
+	return &QueueRepository{db: db}
+}
+
+// Create checks a patient in and records the audit event in the same
+// transaction. created is false when the patient is already in a queue that
+// day.
+func (r *QueueRepository) Create(entry *models.QueueEntry, event *models.AuditEvent) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// FindByID finds a queue entry by ID
+func (r *QueueRepository) FindByID(id uint) (*models.QueueEntry, error) {
+	var entry models.QueueEntry
+	err := r.db.Where("id = ?", id).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindByDate finds the queue entries of a date with the names of their
+// patients in check-in order, for one doctor or every doctor when doctorID
+// is 0
+func (r *QueueRepository) FindByDate(doctorID uint, date string) ([]models.PatientQueueEntry, error) {
+	query := r.db.Table("queue_entries").
+		Select("queue_entries.*, patients.first_name AS patient_first_name, patients.last_name AS patient_last_name").
+		Joins("JOIN patients ON patients.id = queue_entries.patient_id AND patients.deleted_at IS NULL").
+		Where("queue_entries.date = ?", date)
+	if doctorID != 0 {
+		query = query.Where("queue_entries.doctor_id = ?", doctorID)
+	}
+
+	var entries []models.PatientQueueEntry
+	err := query.Order("queue_entries.checked_in_at, queue_entries.id").Scan(&entries).Error
+	return entries, err
+}
+
+// UpdateStatus stores the status and times of a queue entry if it still has
+// the status it was read with, and records the audit event in the same
+// transaction. updated is false when another request moved it first.
+func (r *QueueRepository) UpdateStatus(entry *models.QueueEntry, from models.QueueStatus, event *models.AuditEvent) (bool, error) {
+	return r.update(entry, from, event, "status", "called_at", "started_at", "done_at", "updated_at")
+}
+
+// UpdateDoctor stores the doctor of a waiting queue entry and records the
+// audit event in the same transaction. updated is false when the patient
+// was called first.
+func (r *QueueRepository) UpdateDoctor(entry *models.QueueEntry, event *models.AuditEvent) (bool, error) {
+	return r.update(entry, models.QueueStatusWaiting, event, "doctor_id", "updated_at")
+}
+
+// update stores columns of a queue entry that still has a status
+func (r *QueueRepository) update(entry *models.QueueEntry, from models.QueueStatus, event *models.AuditEvent, columns ...string) (bool, error) {
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(entry).Where("status = ?", from).Select(columns).Updates(entry)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+		return appendAuditEvent(tx, event)
+	})
+	return updated, err
+}
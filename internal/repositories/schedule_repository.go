@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"errors"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ScheduleRepository handles doctor schedule data operations. Dates are
+// passed as strings in models.DateLayout so they compare as calendar dates.
+type ScheduleRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduleRepository creates a new ScheduleRepository
+func NewScheduleRepository(db *gorm.DB) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// FindWorkingHours finds the weekly hours of a doctor
+func (r *ScheduleRepository) FindWorkingHours(doctorID uint) ([]models.WorkingHours, error) {
+	var hours []models.WorkingHours
+	err := r.db.Where("doctor_id = ?", doctorID).Order("weekday, start_time").Find(&hours).Error
+	return hours, err
+}
+
+// ReplaceWorkingHours replaces the weekly hours of a doctor
+func (r *ScheduleRepository) ReplaceWorkingHours(doctorID uint, hours []models.WorkingHours) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("doctor_id = ?", doctorID).Delete(&models.WorkingHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+// FindException finds the exception of a doctor on a date, returning nil
+// when there is none
+func (r *ScheduleRepository) FindException(doctorID uint, date string) (*models.ScheduleException, error) {
+	var exception models.ScheduleException
+	err := r.db.Where("doctor_id = ? AND date = ?", doctorID, date).First(&exception).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &exception, nil
+}
+
+// FindExceptionByID finds an exception by ID
+func (r *ScheduleRepository) FindExceptionByID(id uint) (*models.ScheduleException, error) {
+	var exception models.ScheduleException
+	err := r.db.Where("id = ?", id).First(&exception).Error
+	if err != nil {
+		return nil, err
+	}
+	return &exception, nil
+}
+
+// FindExceptionsFrom finds the exceptions of a doctor on or after a date
+func (r *ScheduleRepository) FindExceptionsFrom(doctorID uint, from string) ([]models.ScheduleException, error) {
+	var exceptions []models.ScheduleException
+	err := r.db.Where("doctor_id = ? AND date >= ?", doctorID, from).Order("date").Find(&exceptions).Error
+	return exceptions, err
+}
+
+// CreateException creates a new exception
+func (r *ScheduleRepository) CreateException(exception *models.ScheduleException) error {
+	return r.db.Create(exception).Error
+}
+
+// DeleteException deletes an exception
+func (r *ScheduleRepository) DeleteException(id uint) error {
+	return r.db.Delete(&models.ScheduleException{}, id).Error
+}
+
+// IsOnLeave checks if a doctor is on leave on a date
+func (r *ScheduleRepository) IsOnLeave(doctorID uint, date string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.DoctorLeave{}).
+		Where("doctor_id = ? AND start_date <= ? AND end_date >= ?", doctorID, date, date).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindLeaveByID finds a leave by ID
+func (r *ScheduleRepository) FindLeaveByID(id uint) (*models.DoctorLeave, error) {
+	var leave models.DoctorLeave
+	err := r.db.Where("id = ?", id).First(&leave).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+// FindLeaveFrom finds the leave of a doctor ending on or after a date
+func (r *ScheduleRepository) FindLeaveFrom(doctorID uint, from string) ([]models.DoctorLeave, error) {
+	var leave []models.DoctorLeave
+	err := r.db.Where("doctor_id = ? AND end_date >= ?", doctorID, from).Order("start_date").Find(&leave).Error
+	return leave, err
+}
+
+// CreateLeave creates a new leave
+func (r *ScheduleRepository) CreateLeave(leave *models.DoctorLeave) error {
+	return r.db.Create(leave).Error
+}
+
+// DeleteLeave deletes a leave
+func (r *ScheduleRepository) DeleteLeave(id uint) error {
+	return r.db.Delete(&models.DoctorLeave{}, id).Error
+}
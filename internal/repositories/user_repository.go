@@ -1,74 +1,110 @@
-package repositories
-
-import (
-	"fmt"
-
-	"healthcare-app/internal/models"
-
-	"gorm.io/gorm"
-)
-
-// UserRepository handles user data operations
-type UserRepository struct {
-	db *gorm.DB
-}
-
-// NewUserRepository creates a new UserRepository
-func NewUserRepository(db *gorm.DB) *UserRepository {
-	return &UserRepository{db: db}
-}
-
-// Create creates a new user
-func (r *UserRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
-}
-
-// FindByID finds a user by ID
-func (r *UserRepository) FindByID(id uint) (*models.User, error) {
-	var user models.User
-	err := r.db.Where("id = ?", id).First(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-// FindByEmail finds a user by email
-func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
-	var user models.User
-	err := r.db.Where("email = ?", email).First(&user).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
-}
-
-// FindAll finds all users
-func (r *UserRepository) FindAll() ([]models.User, error) {
-	var users []models.User
-	err := r.db.Find(&users).Error
-	if err != nil {
-		return nil, err
-	}
-	return users, nil
-}
-
-// Update updates a user
-func (r *UserRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
-}
-
-// Delete deletes a user
-func (r *UserRepository) Delete(id uint) error {
-	return r.db.Delete(&models.User{}, id).Error
-}
-
-// EmailExists checks if an email exists
-func (r *UserRepository) EmailExists(email string) (bool, error) {
-	var count int64
-	err := r.db.Model(&models.User{}).Where("email = ?", email).Count(&count).Error
-	if err != nil {
-		return false, fmt.Errorf("error checking email existence: %w", err)
-	}
-	return count > 0, nil
+package repositories
+
+import (
+	"fmt"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository handles user data operations
+type UserRepository struct {
+	db *gorm.DB
+}
+
+// NewUserRepository creates a new UserRepository
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+// FindByID finds a user by ID
+func (r *UserRepository) FindByID(id uint) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByEmail finds a user by email
+func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	err := r.db.Where("email = ?", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindAll finds users matching a filter with pagination
+func (r *UserRepository) FindAll(filter models.UserFilter, limit, offset int) ([]models.User, int64, error) {
+	var users []models.User
+	var count int64
+
+	query := r.db.Model(&models.User{})
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	switch filter.Status {
+	case models.UserStatusActive:
+		query = query.Where("deactivated_at IS NULL")
+	case models.UserStatusDeactivated:
+		query = query.Where("deactivated_at IS NOT NULL")
+	}
+
+	// Get total count
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get users with pagination
+	if err := query.Order("id").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, count, nil
+}
+
+// Update updates a user
+func (r *UserRepository) Update(user *models.User) error {
+	return r.db.Save(user).Error
+}
+
+// UpdateColumns updates the given columns of a user without running the
+// password hashing hook on the stored hash
+func (r *UserRepository) UpdateColumns(id uint, columns map[string]interface{}) error {
+	return r.db.Model(&models.User{ID: id}).Updates(columns).Error
+}
+
+// AdvanceTOTPStep records the time step of an accepted TOTP code unless a
+// code of that step or a later one was accepted already, and reports
+// whether it was recorded
+func (r *UserRepository) AdvanceTOTPStep(id uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).Where("id = ? AND totp_last_step < ?", id, step).Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Delete deletes a user
+func (r *UserRepository) Delete(id uint) error {
+	return r.db.Delete(&models.User{}, id).Error
+}
+
+// EmailExists checks if an email exists
+func (r *UserRepository) EmailExists(email string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("email = ?", email).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("error checking email existence: %w", err)
+	}
+	return count > 0, nil
 } 
\ No newline at end of file
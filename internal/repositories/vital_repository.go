@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// VitalRepository handles vital sign data operations
+type VitalRepository struct {
+	db *gorm.DB
+}
+
+// NewVitalRepository creates a new VitalRepository
+func NewVitalRepository(db *gorm.DB) *VitalRepository {
+	return &VitalRepository{db: db}
+}
+
+// CreateBatch creates a set of vital sign observations and records the audit
+// event in the same transaction
+func (r *VitalRepository) CreateBatch(vitals []models.Vital, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&vitals).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByPatient finds the vital sign observations of a patient matching a
+// filter, oldest first
+func (r *VitalRepository) FindByPatient(patientID uint, filter models.VitalFilter) ([]models.Vital, error) {
+	var vitals []models.Vital
+	query := r.db.Where("patient_id = ?", patientID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.From != nil {
+		query = query.Where("recorded_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("recorded_at < ?", *filter.To)
+	}
+	err := query.Order("recorded_at, id").Find(&vitals).Error
+	return vitals, err
+}
@@ -0,0 +1,228 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// errOfferClosed rolls back a slot offer change when the offer or its
+// waitlist entry was changed first
+var errOfferClosed = errors.New("slot offer no longer pending")
+
+// WaitlistRepository handles waitlist and slot offer data operations
+type WaitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository creates a new WaitlistRepository
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+// Create puts a patient on a waitlist and records the audit event in the
+// same transaction
+func (r *WaitlistRepository) Create(entry *models.WaitlistEntry, event *models.AuditEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+}
+
+// FindByID finds a waitlist entry by ID
+func (r *WaitlistRepository) FindByID(id uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := r.db.Where("id = ?", id).First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindAll finds the waitlist entries matching a filter with the names of
+// their patients, oldest first
+func (r *WaitlistRepository) FindAll(filter models.WaitlistFilter) ([]models.PatientWaitlistEntry, error) {
+	query := r.db.Table("waitlist_entries").
+		Select("waitlist_entries.*, patients.first_name AS patient_first_name, patients.last_name AS patient_last_name").
+		Joins("JOIN patients ON patients.id = waitlist_entries.patient_id AND patients.deleted_at IS NULL")
+	if filter.DoctorID != 0 {
+		query = query.Where("waitlist_entries.doctor_id = ?", filter.DoctorID)
+	}
+	if filter.Type != "" {
+		query = query.Where("waitlist_entries.type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("waitlist_entries.status = ?", filter.Status)
+	}
+
+	var entries []models.PatientWaitlistEntry
+	err := query.Order("waitlist_entries.created_at, waitlist_entries.id").Scan(&entries).Error
+	return entries, err
+}
+
+// FindWaiting finds the waiting entries for a type of appointment with a
+// doctor that have not been offered the slot starting at a time yet, oldest
+// first
+func (r *WaitlistRepository) FindWaiting(doctorID uint, appointmentType models.AppointmentType, start time.Time) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.Where("doctor_id = ? AND type = ? AND status = ?", doctorID, appointmentType, models.WaitlistStatusWaiting).
+		Where("EXISTS (SELECT 1 FROM patients WHERE patients.id = waitlist_entries.patient_id AND patients.deleted_at IS NULL)").
+		Where("NOT EXISTS (SELECT 1 FROM slot_offers WHERE slot_offers.waitlist_entry_id = waitlist_entries.id AND slot_offers.start_time = ?)", start).
+		Order("created_at, id").Find(&entries).Error
+	return entries, err
+}
+
+// Remove takes a waiting patient off the waitlist and records the audit
+// event in the same transaction. removed is false when the patient was
+// offered a slot first.
+func (r *WaitlistRepository) Remove(entry *models.WaitlistEntry, event *models.AuditEvent) (bool, error) {
+	removed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(entry).Where("status = ?", models.WaitlistStatusWaiting).
+			Select("status", "updated_at").Updates(entry)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		removed = true
+		return appendAuditEvent(tx, event)
+	})
+	return removed, err
+}
+
+// FindHeld finds the pending offers of a doctor that have not expired and
+// overlap a period
+func (r *WaitlistRepository) FindHeld(doctorID uint, from, to time.Time) ([]models.SlotOffer, error) {
+	var offers []models.SlotOffer
+	err := r.db.Where("doctor_id = ? AND status = ? AND expires_at > ? AND start_time < ? AND end_time > ?",
+		doctorID, models.SlotOfferStatusPending, time.Now(), to, from).
+		Order("start_time").Find(&offers).Error
+	return offers, err
+}
+
+// CreateOffer offers a slot to a waiting patient and records the audit
+// event in the same transaction. created is false when the patient is no
+// longer waiting or the slot is already offered to someone else.
+func (r *WaitlistRepository) CreateOffer(offer *models.SlotOffer, event *models.AuditEvent) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ?", offer.WaitlistEntryID, models.WaitlistStatusWaiting).
+			Updates(map[string]interface{}{"status": models.WaitlistStatusOffered, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errOfferClosed
+		}
+		if err := tx.Create(offer).Error; err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	var pgErr *pgconn.PgError
+	if errors.Is(err, errOfferClosed) || (errors.As(err, &pgErr) && pgErr.Code == uniqueViolation) {
+		offer.ID = 0
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// FindOfferByID finds a slot offer by ID
+func (r *WaitlistRepository) FindOfferByID(id uint) (*models.SlotOffer, error) {
+	var offer models.SlotOffer
+	err := r.db.Where("id = ?", id).First(&offer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// FindOffers finds the slot offers matching a filter, newest first
+func (r *WaitlistRepository) FindOffers(filter models.SlotOfferFilter) ([]models.SlotOffer, error) {
+	query := r.db.Model(&models.SlotOffer{})
+	if filter.DoctorID != 0 {
+		query = query.Where("doctor_id = ?", filter.DoctorID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var offers []models.SlotOffer
+	err := query.Order("created_at DESC, id DESC").Find(&offers).Error
+	return offers, err
+}
+
+// FindExpiredOffers finds the pending offers whose hold ended before a time
+func (r *WaitlistRepository) FindExpiredOffers(before time.Time) ([]models.SlotOffer, error) {
+	var offers []models.SlotOffer
+	err := r.db.Where("status = ? AND expires_at <= ?", models.SlotOfferStatusPending, before).
+		Order("expires_at, id").Find(&offers).Error
+	return offers, err
+}
+
+// AcceptOffer books the appointment of a pending offer that has not
+// expired, marks the offer accepted and the patient booked, and records the
+// audit event, all in one transaction. accepted is false when the offer was
+// closed first or the slot overlaps another booking.
+func (r *WaitlistRepository) AcceptOffer(offer *models.SlotOffer, appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(appointment).Error; err != nil {
+			return err
+		}
+		offer.AppointmentID = &appointment.ID
+		result := tx.Model(offer).Where("status = ? AND expires_at > ?", models.SlotOfferStatusPending, time.Now()).
+			Select("status", "appointment_id", "responded_at", "updated_at").Updates(offer)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errOfferClosed
+		}
+		if err := setOfferedEntryStatus(tx, offer.WaitlistEntryID, models.WaitlistStatusBooked); err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	if errors.Is(err, errOfferClosed) || isExclusionViolation(err) {
+		offer.AppointmentID = nil
+		appointment.ID = 0
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CloseOffer stores a pending offer as declined or expired, puts the
+// patient back on the waitlist and records the audit event in the same
+// transaction. closed is false when the offer was closed first.
+func (r *WaitlistRepository) CloseOffer(offer *models.SlotOffer, event *models.AuditEvent) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(offer).Where("status = ?", models.SlotOfferStatusPending).
+			Select("status", "responded_at", "updated_at").Updates(offer)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errOfferClosed
+		}
+		if err := setOfferedEntryStatus(tx, offer.WaitlistEntryID, models.WaitlistStatusWaiting); err != nil {
+			return err
+		}
+		return appendAuditEvent(tx, event)
+	})
+	if errors.Is(err, errOfferClosed) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// setOfferedEntryStatus moves an offered waitlist entry to another status
+func setOfferedEntryStatus(tx *gorm.DB, id uint, status models.WaitlistStatus) error {
+	return tx.Model(&models.WaitlistEntry{}).
+		Where("id = ? AND status = ?", id, models.WaitlistStatusOffered).
+		Updates(map[string]interface{}{"status": status, "updated_at": time.Now()}).Error
+}
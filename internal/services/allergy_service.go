@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrAllergyNotFound = errors.New("allergy not found")
+)
+
+// AllergyRepository defines the allergy data operations used by the AllergyService
+type AllergyRepository interface {
+	Create(allergy *models.Allergy, event *models.AuditEvent) error
+	FindByID(id uint) (*models.Allergy, error)
+	FindByPatient(patientID uint) ([]models.Allergy, error)
+	Update(allergy *models.Allergy, event *models.AuditEvent) error
+	Delete(id uint, event *models.AuditEvent) error
+}
+
+// AllergyService handles the structured allergy list of patients
+type AllergyService struct {
+	allergyRepo    AllergyRepository
+	patientService *PatientService
+	auditRepo      AuditRepository
+}
+
+// NewAllergyService creates a new AllergyService
+func NewAllergyService(allergyRepo AllergyRepository, patientService *PatientService, auditRepo AuditRepository) *AllergyService {
+	return &AllergyService{
+		allergyRepo:    allergyRepo,
+		patientService: patientService,
+		auditRepo:      auditRepo,
+	}
+}
+
+// GetAllergies gets the allergies of a patient
+func (s *AllergyService) GetAllergies(accessor PatientAccessor, patientID uint) ([]models.Allergy, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	allergies, err := s.allergyRepo.FindByPatient(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "allergies"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return allergies, nil
+}
+
+// GetAllergy gets an allergy of a patient
+func (s *AllergyService) GetAllergy(accessor PatientAccessor, patientID, allergyID uint) (*models.Allergy, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	allergy, err := s.findAllergy(patientID, allergyID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = fmt.Sprintf("allergy=%d", allergy.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return allergy, nil
+}
+
+// CreateAllergy records an allergy of a patient
+func (s *AllergyService) CreateAllergy(accessor PatientAccessor, patientID uint, req models.AllergyRequest) (*models.Allergy, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionAllergyCreate); err != nil {
+		return nil, err
+	}
+
+	allergy := &models.Allergy{
+		PatientID:  patientID,
+		RecordedBy: accessor.UserID,
+	}
+	allergy.Apply(req)
+
+	event := newAuditEvent(accessor, models.AuditActionAllergyCreate, patientID, models.DiffRecords(nil, allergy))
+	if err := s.allergyRepo.Create(allergy, event); err != nil {
+		return nil, err
+	}
+
+	return allergy, nil
+}
+
+// UpdateAllergy replaces the details of an allergy of a patient
+func (s *AllergyService) UpdateAllergy(accessor PatientAccessor, patientID, allergyID uint, req models.AllergyRequest) (*models.Allergy, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionAllergyUpdate); err != nil {
+		return nil, err
+	}
+
+	allergy, err := s.findAllergy(patientID, allergyID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *allergy
+	allergy.Apply(req)
+
+	event := newAuditEvent(accessor, models.AuditActionAllergyUpdate, patientID, models.DiffRecords(&before, allergy))
+	event.Details = fmt.Sprintf("allergy=%d", allergy.ID)
+	if err := s.allergyRepo.Update(allergy, event); err != nil {
+		return nil, err
+	}
+
+	return allergy, nil
+}
+
+// DeleteAllergy deletes an allergy of a patient
+func (s *AllergyService) DeleteAllergy(accessor PatientAccessor, patientID, allergyID uint) error {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionAllergyDelete); err != nil {
+		return err
+	}
+
+	allergy, err := s.findAllergy(patientID, allergyID)
+	if err != nil {
+		return err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionAllergyDelete, patientID, models.DiffRecords(allergy, nil))
+	event.Details = fmt.Sprintf("allergy=%d", allergy.ID)
+	return s.allergyRepo.Delete(allergy.ID, event)
+}
+
+// findAllergy finds an allergy belonging to a patient
+func (s *AllergyService) findAllergy(patientID, allergyID uint) (*models.Allergy, error) {
+	allergy, err := s.allergyRepo.FindByID(allergyID)
+	if err != nil || allergy.PatientID != patientID {
+		return nil, ErrAllergyNotFound
+	}
+	return allergy, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"testing"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAllergyRepository is a mock implementation of AllergyRepository
+type MockAllergyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAllergyRepository) Create(allergy *models.Allergy, event *models.AuditEvent) error {
+	args := m.Called(allergy, event)
+	return args.Error(0)
+}
+
+func (m *MockAllergyRepository) FindByID(id uint) (*models.Allergy, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Allergy), args.Error(1)
+}
+
+func (m *MockAllergyRepository) FindByPatient(patientID uint) ([]models.Allergy, error) {
+	args := m.Called(patientID)
+	return args.Get(0).([]models.Allergy), args.Error(1)
+}
+
+func (m *MockAllergyRepository) Update(allergy *models.Allergy, event *models.AuditEvent) error {
+	args := m.Called(allergy, event)
+	return args.Error(0)
+}
+
+func (m *MockAllergyRepository) Delete(id uint, event *models.AuditEvent) error {
+	args := m.Called(id, event)
+	return args.Error(0)
+}
+
+// newTestAllergyService creates an AllergyService for a patient on the
+// doctor's care team
+func newTestAllergyService(allergyRepo *MockAllergyRepository) *AllergyService {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(true, nil)
+	auditRepo := newMockAuditRepository()
+
+	patientService := NewPatientService(mockPatientRepo, mockCareTeamRepo, auditRepo)
+	return NewAllergyService(allergyRepo, patientService, auditRepo)
+}
+
+func TestCreateAllergy_Defaults(t *testing.T) {
+	mockAllergyRepo := new(MockAllergyRepository)
+	mockAllergyRepo.On("Create", mock.AnythingOfType("*models.Allergy"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionAllergyCreate && e.Changes["substance"].After == "Penicillin"
+	})).Return(nil)
+
+	service := newTestAllergyService(mockAllergyRepo)
+
+	allergy, err := service.CreateAllergy(doctorAccessor, 1, models.AllergyRequest{
+		Substance: "Penicillin",
+		Category:  models.AllergyCategoryMedication,
+		Reactions: []string{"hives"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), allergy.PatientID)
+	assert.Equal(t, uint(5), allergy.RecordedBy)
+	assert.Equal(t, models.AllergyStatusActive, allergy.Status)
+	assert.Equal(t, models.AllergyCriticalityUnableToAssess, allergy.Criticality)
+	assert.Equal(t, models.StringList{"hives"}, allergy.Reactions)
+	mockAllergyRepo.AssertExpectations(t)
+}
+
+func TestUpdateAllergy_OtherPatient(t *testing.T) {
+	mockAllergyRepo := new(MockAllergyRepository)
+	mockAllergyRepo.On("FindByID", uint(9)).Return(&models.Allergy{ID: 9, PatientID: 2}, nil)
+
+	service := newTestAllergyService(mockAllergyRepo)
+
+	_, err := service.UpdateAllergy(doctorAccessor, 1, 9, models.AllergyRequest{Substance: "Latex"})
+
+	assert.Equal(t, ErrAllergyNotFound, err)
+	mockAllergyRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateAllergy_AuditsChangedFields(t *testing.T) {
+	mockAllergyRepo := new(MockAllergyRepository)
+	existing := &models.Allergy{
+		ID:          9,
+		PatientID:   1,
+		Substance:   "Penicillin",
+		Criticality: models.AllergyCriticalityLow,
+		Reactions:   models.StringList{"rash"},
+		Status:      models.AllergyStatusActive,
+		RecordedBy:  5,
+	}
+	mockAllergyRepo.On("FindByID", uint(9)).Return(existing, nil)
+	mockAllergyRepo.On("Update", existing, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		_, criticalityChanged := e.Changes["criticality"]
+		_, substanceChanged := e.Changes["substance"]
+		return criticalityChanged && !substanceChanged
+	})).Return(nil)
+
+	service := newTestAllergyService(mockAllergyRepo)
+
+	allergy, err := service.UpdateAllergy(doctorAccessor, 1, 9, models.AllergyRequest{
+		Substance:   "Penicillin",
+		Criticality: models.AllergyCriticalityHigh,
+		Reactions:   []string{"rash"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AllergyCriticalityHigh, allergy.Criticality)
+	mockAllergyRepo.AssertExpectations(t)
+}
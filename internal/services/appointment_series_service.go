@@ -0,0 +1,330 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrAppointmentSeriesNotFound = errors.New("appointment series not found")
+	ErrInvalidRecurrence         = errors.New("invalid recurrence rule")
+	ErrDoctorOnLeave             = errors.New("the doctor is on leave that day")
+)
+
+// AppointmentSeriesService handles recurring appointments booked from a
+// recurrence rule. Single occurrences are changed through the
+// AppointmentService; this service changes an occurrence together with the
+// following ones, or the whole series.
+type AppointmentSeriesService struct {
+	appointmentRepo AppointmentRepository
+	scheduleService *ScheduleService
+	patientService  *PatientService
+	waitlistService *WaitlistService
+	auditRepo       AuditRepository
+}
+
+// NewAppointmentSeriesService creates a new AppointmentSeriesService
+func NewAppointmentSeriesService(appointmentRepo AppointmentRepository, scheduleService *ScheduleService, patientService *PatientService, waitlistService *WaitlistService, auditRepo AuditRepository) *AppointmentSeriesService {
+	return &AppointmentSeriesService{
+		appointmentRepo: appointmentRepo,
+		scheduleService: scheduleService,
+		patientService:  patientService,
+		waitlistService: waitlistService,
+		auditRepo:       auditRepo,
+	}
+}
+
+// BookSeries books every occurrence of a recurrence rule at the time of day
+// of the first one. Occurrences on the doctor's leave or outside free slots
+// are skipped and reported; the series is rejected when none can be booked.
+func (s *AppointmentSeriesService) BookSeries(accessor PatientAccessor, req models.CreateAppointmentSeriesRequest) (*models.AppointmentSeriesResult, error) {
+	if err := s.patientService.CheckAccess(accessor, req.PatientID, models.AuditActionAppointmentCreate); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleService.findDoctor(req.DoctorID); err != nil {
+		return nil, err
+	}
+	rec, err := parseRecurrence(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	dates, err := rec.dates(s.scheduleService.dayOf(req.StartTime))
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) == 0 {
+		return nil, fmt.Errorf("%w: the rule has no occurrences", ErrInvalidRecurrence)
+	}
+
+	clock := req.StartTime.In(s.scheduleService.location).Format(models.ClockLayout)
+	failed := []models.FailedOccurrence{}
+	var appointments []models.Appointment
+	for _, date := range dates {
+		start, err := s.scheduleService.clockOn(date, clock)
+		if err != nil {
+			return nil, err
+		}
+		slot, err := s.occurrenceSlot(req.DoctorID, start)
+		if errors.Is(err, ErrDoctorOnLeave) || errors.Is(err, ErrSlotUnavailable) {
+			failed = append(failed, models.FailedOccurrence{StartTime: start, Reason: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		appointments = append(appointments, models.Appointment{
+			PatientID: req.PatientID,
+			DoctorID:  req.DoctorID,
+			Type:      req.Type,
+			Status:    models.AppointmentStatusBooked,
+			StartTime: slot.Start,
+			EndTime:   slot.End,
+			Reason:    req.Reason,
+			BookedBy:  accessor.UserID,
+		})
+	}
+	if len(appointments) == 0 {
+		return nil, fmt.Errorf("%w: none of the %d occurrences is free", ErrSlotUnavailable, len(dates))
+	}
+
+	series := &models.AppointmentSeries{
+		PatientID: req.PatientID,
+		DoctorID:  req.DoctorID,
+		Type:      req.Type,
+		Rule:      strings.TrimSpace(req.Rule),
+		Status:    models.AppointmentStatusBooked,
+		StartTime: req.StartTime,
+		Reason:    req.Reason,
+		CreatedBy: accessor.UserID,
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionAppointmentCreate, req.PatientID, models.DiffRecords(nil, series))
+	event.Details = fmt.Sprintf("series occurrences=%d", len(appointments))
+	booked, err := s.appointmentRepo.CreateSeries(series, appointments, event)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, appointment := range appointments {
+		if booked[i] {
+			series.Appointments = append(series.Appointments, appointment)
+		} else {
+			failed = append(failed, models.FailedOccurrence{StartTime: appointment.StartTime, Reason: ErrSlotUnavailable.Error()})
+		}
+	}
+	if len(series.Appointments) == 0 {
+		return nil, fmt.Errorf("%w: none of the %d occurrences is free", ErrSlotUnavailable, len(dates))
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].StartTime.Before(failed[j].StartTime) })
+
+	return &models.AppointmentSeriesResult{Series: series, Failed: failed}, nil
+}
+
+// GetSeries gets a series with all its occurrences
+func (s *AppointmentSeriesService) GetSeries(accessor PatientAccessor, id uint) (*models.AppointmentSeries, error) {
+	series, err := s.findSeries(accessor, id, models.AuditActionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, series.PatientID, nil)
+	event.Details = fmt.Sprintf("series=%d", series.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// RescheduleSeries moves an occurrence to a new time and each following
+// booked occurrence by the same number of days to the same time of day.
+// Following occurrences that cannot be moved keep their times and are
+// reported.
+func (s *AppointmentSeriesService) RescheduleSeries(accessor PatientAccessor, id uint, req models.RescheduleSeriesRequest) (*models.AppointmentSeriesResult, error) {
+	series, err := s.findSeries(accessor, id, models.AuditActionAppointmentUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status != models.AppointmentStatusBooked {
+		return nil, ErrAppointmentCancelled
+	}
+	following, err := followingOccurrences(series, req.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := following[0]
+	days := int(s.scheduleService.dayOf(req.StartTime).Sub(s.scheduleService.dayOf(anchor.StartTime)).Hours() / 24)
+	clock := req.StartTime.In(s.scheduleService.location).Format(models.ClockLayout)
+	movingIDs := make([]uint, len(following))
+	for i, appointment := range following {
+		movingIDs[i] = appointment.ID
+	}
+
+	failed := []models.FailedOccurrence{}
+	var moves []models.Appointment
+	for _, appointment := range following {
+		start, err := s.scheduleService.clockOn(s.scheduleService.dayOf(appointment.StartTime).AddDate(0, 0, days), clock)
+		if err != nil {
+			return nil, err
+		}
+		slot, err := s.occurrenceSlot(series.DoctorID, start, movingIDs...)
+		if appointment.ID != anchor.ID && (errors.Is(err, ErrDoctorOnLeave) || errors.Is(err, ErrSlotUnavailable)) {
+			failed = append(failed, models.FailedOccurrence{AppointmentID: appointment.ID, StartTime: start, Reason: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		appointment.StartTime = slot.Start
+		appointment.EndTime = slot.End
+		appointment.RescheduleReason = req.Reason
+		moves = append(moves, appointment)
+	}
+
+	// Moving the occurrences furthest in the direction of the move first
+	// keeps each one clear of the slots of those not yet moved
+	later := req.StartTime.After(anchor.StartTime)
+	sort.Slice(moves, func(i, j int) bool {
+		if later {
+			return moves[i].StartTime.After(moves[j].StartTime)
+		}
+		return moves[i].StartTime.Before(moves[j].StartTime)
+	})
+
+	ids := make([]string, len(moves))
+	for i, appointment := range moves {
+		ids[i] = fmt.Sprint(appointment.ID)
+	}
+	event := newAuditEvent(accessor, models.AuditActionAppointmentUpdate, series.PatientID, nil)
+	event.Details = fmt.Sprintf("series=%d rescheduled=[%s]", series.ID, strings.Join(ids, ","))
+	moved, err := s.appointmentRepo.RescheduleSeries(moves, event)
+	if err != nil {
+		return nil, err
+	}
+	for i, appointment := range moves {
+		if !moved[i] {
+			failed = append(failed, models.FailedOccurrence{AppointmentID: appointment.ID, StartTime: appointment.StartTime, Reason: ErrSlotUnavailable.Error()})
+		}
+	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].StartTime.Before(failed[j].StartTime) })
+
+	if series.Appointments, err = s.appointmentRepo.FindBySeries(series.ID); err != nil {
+		return nil, err
+	}
+	return &models.AppointmentSeriesResult{Series: series, Failed: failed}, nil
+}
+
+// CancelSeries cancels an occurrence and the following booked ones, or,
+// without an occurrence, the whole series and its occurrences that have not
+// started
+func (s *AppointmentSeriesService) CancelSeries(accessor PatientAccessor, id uint, req models.CancelSeriesRequest) (*models.AppointmentSeriesResult, error) {
+	series, err := s.findSeries(accessor, id, models.AuditActionAppointmentUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status != models.AppointmentStatusBooked {
+		return nil, ErrAppointmentCancelled
+	}
+
+	before := *series
+	now := time.Now()
+	var cancelling []models.Appointment
+	if req.AppointmentID != 0 {
+		if cancelling, err = followingOccurrences(series, req.AppointmentID); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, appointment := range series.Appointments {
+			if appointment.Status == models.AppointmentStatusBooked && appointment.StartTime.After(now) {
+				cancelling = append(cancelling, appointment)
+			}
+		}
+		series.Status = models.AppointmentStatusCancelled
+		series.CancellationReason = req.Reason
+		series.CancelledAt = &now
+	}
+
+	ids := make([]string, len(cancelling))
+	for i := range cancelling {
+		cancelling[i].Status = models.AppointmentStatusCancelled
+		cancelling[i].CancellationReason = req.Reason
+		cancelling[i].CancelledAt = &now
+		ids[i] = fmt.Sprint(cancelling[i].ID)
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionAppointmentUpdate, series.PatientID, models.DiffRecords(&before, series))
+	event.Details = fmt.Sprintf("series=%d cancelled=[%s]", series.ID, strings.Join(ids, ","))
+	if err := s.appointmentRepo.CancelSeries(series, cancelling, event); err != nil {
+		return nil, err
+	}
+	for i := range cancelling {
+		s.waitlistService.OfferFreedSlot(accessor, &cancelling[i])
+	}
+
+	if series.Appointments, err = s.appointmentRepo.FindBySeries(series.ID); err != nil {
+		return nil, err
+	}
+	return &models.AppointmentSeriesResult{Series: series, Failed: []models.FailedOccurrence{}}, nil
+}
+
+// occurrenceSlot finds the free slot of a doctor starting at a time,
+// telling leave apart from other reasons the doctor is not available
+func (s *AppointmentSeriesService) occurrenceSlot(doctorID uint, start time.Time, movingIDs ...uint) (*models.Slot, error) {
+	onLeave, err := s.scheduleService.isOnLeave(doctorID, s.scheduleService.dayOf(start))
+	if err != nil {
+		return nil, err
+	}
+	if onLeave {
+		return nil, ErrDoctorOnLeave
+	}
+	return s.scheduleService.freeSlot(doctorID, start, movingIDs...)
+}
+
+// findSeries finds a series of a patient the accessor may reach, with its
+// occurrences
+func (s *AppointmentSeriesService) findSeries(accessor PatientAccessor, id uint, action models.AuditAction) (*models.AppointmentSeries, error) {
+	series, err := s.appointmentRepo.FindSeriesByID(id)
+	if err != nil {
+		return nil, ErrAppointmentSeriesNotFound
+	}
+	if err := s.patientService.CheckAccess(accessor, series.PatientID, action); err != nil {
+		return nil, err
+	}
+	if series.Appointments, err = s.appointmentRepo.FindBySeries(series.ID); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// followingOccurrences lists a booked occurrence of a series and the booked
+// occurrences after it, in start time order
+func followingOccurrences(series *models.AppointmentSeries, appointmentID uint) ([]models.Appointment, error) {
+	var anchor *models.Appointment
+	for i := range series.Appointments {
+		if series.Appointments[i].ID == appointmentID {
+			anchor = &series.Appointments[i]
+		}
+	}
+	if anchor == nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if anchor.Status != models.AppointmentStatusBooked {
+		return nil, ErrAppointmentCancelled
+	}
+
+	var following []models.Appointment
+	for _, appointment := range series.Appointments {
+		if appointment.Status == models.AppointmentStatusBooked && !appointment.StartTime.Before(anchor.StartTime) {
+			following = append(following, appointment)
+		}
+	}
+	sort.SliceStable(following, func(i, j int) bool { return following[i].StartTime.Before(following[j].StartTime) })
+	return following, nil
+}
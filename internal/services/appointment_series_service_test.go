@@ -0,0 +1,264 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestAppointmentSeriesService creates an AppointmentSeriesService for
+// patient 1 and doctor 5, who works on Mondays and has no exceptions
+func newTestAppointmentSeriesService(scheduleRepo *MockScheduleRepository, appointmentRepo *MockAppointmentRepository) *AppointmentSeriesService {
+	scheduleRepo.On("FindException", uint(5), mock.Anything).Return(nil, nil).Maybe()
+	scheduleService := newTestScheduleService(scheduleRepo, appointmentRepo)
+
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	auditRepo := newMockAuditRepository()
+
+	patientService := NewPatientService(mockPatientRepo, new(MockCareTeamRepository), auditRepo)
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindWaiting", uint(5), mock.Anything, mock.Anything).Return([]models.WaitlistEntry{}, nil).Maybe()
+	waitlistService := newTestWaitlistService(mockWaitlistRepo, scheduleService, patientService, mockPatientRepo, new(MockNotificationRepository))
+	return NewAppointmentSeriesService(appointmentRepo, scheduleService, patientService, waitlistService, auditRepo)
+}
+
+// seriesSlot returns the time of day a number of weeks after testScheduleDate
+func seriesSlot(weeks int, clock string) time.Time {
+	return testSlot(clock).AddDate(0, 0, 7*weeks)
+}
+
+func TestParseRecurrence_Dates(t *testing.T) {
+	first, _ := time.Parse(models.DateLayout, testScheduleDate)
+
+	tests := []struct {
+		rule  string
+		count int
+		last  time.Time
+	}{
+		{"FREQ=WEEKLY;BYDAY=MO,TH;COUNT=16", 16, first.AddDate(0, 0, 7*7+3)},
+		{"RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=TH;UNTIL=20990331", 2, first.AddDate(0, 0, 2*7+3)},
+		{"FREQ=WEEKLY;UNTIL=20990316T235959Z", 3, first.AddDate(0, 0, 2*7)},
+		{"FREQ=DAILY;INTERVAL=2;COUNT=3", 3, first.AddDate(0, 0, 4)},
+	}
+
+	for _, tt := range tests {
+		rec, err := parseRecurrence(tt.rule)
+		assert.NoError(t, err, tt.rule)
+		dates, err := rec.dates(first)
+		assert.NoError(t, err, tt.rule)
+		if assert.Len(t, dates, tt.count, tt.rule) {
+			assert.Equal(t, tt.last, dates[len(dates)-1], tt.rule)
+		}
+	}
+}
+
+func TestParseRecurrence_Invalid(t *testing.T) {
+	for _, rule := range []string{
+		"FREQ=MONTHLY;COUNT=3",
+		"FREQ=WEEKLY",
+		"FREQ=WEEKLY;COUNT=3;UNTIL=20990331",
+		"FREQ=WEEKLY;BYDAY=XX;COUNT=3",
+		"FREQ=WEEKLY;COUNT=500",
+		"FREQ=DAILY;BYDAY=MO;COUNT=2",
+		"FREQ=WEEKLY;COUNT",
+	} {
+		_, err := parseRecurrence(rule)
+		assert.ErrorIs(t, err, ErrInvalidRecurrence, rule)
+	}
+
+	rec, err := parseRecurrence("FREQ=DAILY;UNTIL=21100101")
+	assert.NoError(t, err)
+	first, _ := time.Parse(models.DateLayout, testScheduleDate)
+	_, err = rec.dates(first)
+	assert.ErrorIs(t, err, ErrInvalidRecurrence)
+}
+
+func TestBookSeries_SkipsLeave(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), "2099-03-09").Return(true, nil)
+	mockScheduleRepo.On("IsOnLeave", uint(5), mock.Anything).Return(false, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{}, nil)
+	mockAppointmentRepo.On("CreateSeries", mock.AnythingOfType("*models.AppointmentSeries"), mock.MatchedBy(func(appointments []models.Appointment) bool {
+		return len(appointments) == 2 && appointments[0].StartTime.Equal(seriesSlot(0, "09:20")) &&
+			appointments[1].StartTime.Equal(seriesSlot(2, "09:20"))
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionAppointmentCreate
+	})).Return([]bool{true, true}, nil)
+
+	service := newTestAppointmentSeriesService(mockScheduleRepo, mockAppointmentRepo)
+
+	result, err := service.BookSeries(receptionistAccessor, models.CreateAppointmentSeriesRequest{
+		PatientID: 1,
+		DoctorID:  5,
+		StartTime: seriesSlot(0, "09:20"),
+		Rule:      "FREQ=WEEKLY;BYDAY=MO;COUNT=3",
+		Type:      models.AppointmentTypeProcedure,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Series.Appointments, 2)
+	assert.Equal(t, []models.FailedOccurrence{
+		{StartTime: seriesSlot(1, "09:20"), Reason: ErrDoctorOnLeave.Error()},
+	}, result.Failed)
+	mockAppointmentRepo.AssertExpectations(t)
+}
+
+func TestBookSeries_ReportsConflicts(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), mock.Anything).Return(false, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), seriesSlot(1, "09:00"), seriesSlot(1, "10:00")).Return([]models.Appointment{
+		{ID: 9, DoctorID: 5, StartTime: seriesSlot(1, "09:00"), EndTime: seriesSlot(1, "09:20")},
+	}, nil)
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{}, nil)
+	// The last occurrence was booked by someone else before the series was stored
+	mockAppointmentRepo.On("CreateSeries", mock.Anything, mock.Anything, mock.Anything).Return([]bool{true, false}, nil)
+
+	service := newTestAppointmentSeriesService(mockScheduleRepo, mockAppointmentRepo)
+
+	result, err := service.BookSeries(receptionistAccessor, models.CreateAppointmentSeriesRequest{
+		PatientID: 1,
+		DoctorID:  5,
+		StartTime: seriesSlot(0, "09:00"),
+		Rule:      "FREQ=WEEKLY;COUNT=3",
+		Type:      models.AppointmentTypeProcedure,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Series.Appointments, 1)
+	assert.Equal(t, []models.FailedOccurrence{
+		{StartTime: seriesSlot(1, "09:00"), Reason: ErrSlotUnavailable.Error()},
+		{StartTime: seriesSlot(2, "09:00"), Reason: ErrSlotUnavailable.Error()},
+	}, result.Failed)
+}
+
+func TestBookSeries_NothingFree(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), mock.Anything).Return(true, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+
+	service := newTestAppointmentSeriesService(mockScheduleRepo, mockAppointmentRepo)
+
+	_, err := service.BookSeries(receptionistAccessor, models.CreateAppointmentSeriesRequest{
+		PatientID: 1,
+		DoctorID:  5,
+		StartTime: seriesSlot(0, "09:00"),
+		Rule:      "FREQ=WEEKLY;COUNT=2",
+		Type:      models.AppointmentTypeProcedure,
+	})
+
+	assert.ErrorIs(t, err, ErrSlotUnavailable)
+	mockAppointmentRepo.AssertNotCalled(t, "CreateSeries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// testSeries returns a weekly series of doctor 5 with booked occurrences 21
+// to 23 on the first three Mondays from testScheduleDate
+func testSeries() (*models.AppointmentSeries, []models.Appointment) {
+	seriesID := uint(4)
+	appointments := make([]models.Appointment, 3)
+	for i := range appointments {
+		appointments[i] = models.Appointment{
+			ID: uint(21 + i), PatientID: 1, DoctorID: 5, SeriesID: &seriesID, Status: models.AppointmentStatusBooked,
+			StartTime: seriesSlot(i, "09:00"), EndTime: seriesSlot(i, "09:20"),
+		}
+	}
+	return &models.AppointmentSeries{
+		ID: seriesID, PatientID: 1, DoctorID: 5, Status: models.AppointmentStatusBooked, Rule: "FREQ=WEEKLY;COUNT=3",
+	}, appointments
+}
+
+func TestRescheduleSeries_ThisAndFollowing(t *testing.T) {
+	series, appointments := testSeries()
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), "2099-03-16").Return(true, nil)
+	mockScheduleRepo.On("IsOnLeave", uint(5), mock.Anything).Return(false, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindSeriesByID", uint(4)).Return(series, nil)
+	mockAppointmentRepo.On("FindBySeries", uint(4)).Return(appointments, nil)
+	// The occurrences being moved do not block the slots next to them
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return(appointments, nil)
+	mockAppointmentRepo.On("RescheduleSeries", mock.MatchedBy(func(moves []models.Appointment) bool {
+		return len(moves) == 1 && moves[0].ID == 22 && moves[0].StartTime.Equal(seriesSlot(1, "09:20")) &&
+			moves[0].RescheduleReason == "Clinic moved"
+	}), mock.Anything).Return([]bool{true}, nil)
+
+	service := newTestAppointmentSeriesService(mockScheduleRepo, mockAppointmentRepo)
+
+	result, err := service.RescheduleSeries(receptionistAccessor, 4, models.RescheduleSeriesRequest{
+		AppointmentID: 22,
+		StartTime:     seriesSlot(1, "09:20"),
+		Reason:        "Clinic moved",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.FailedOccurrence{
+		{AppointmentID: 23, StartTime: seriesSlot(2, "09:20"), Reason: ErrDoctorOnLeave.Error()},
+	}, result.Failed)
+	mockAppointmentRepo.AssertExpectations(t)
+}
+
+func TestRescheduleSeries_UnknownOccurrence(t *testing.T) {
+	series, appointments := testSeries()
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindSeriesByID", uint(4)).Return(series, nil)
+	mockAppointmentRepo.On("FindBySeries", uint(4)).Return(appointments, nil)
+
+	service := newTestAppointmentSeriesService(new(MockScheduleRepository), mockAppointmentRepo)
+
+	_, err := service.RescheduleSeries(receptionistAccessor, 4, models.RescheduleSeriesRequest{
+		AppointmentID: 99,
+		StartTime:     seriesSlot(1, "09:20"),
+		Reason:        "Clinic moved",
+	})
+
+	assert.ErrorIs(t, err, ErrAppointmentNotFound)
+}
+
+func TestCancelSeries_Whole(t *testing.T) {
+	series, appointments := testSeries()
+	appointments[0].StartTime = time.Now().Add(-time.Hour)
+	appointments[2].Status = models.AppointmentStatusCancelled
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindSeriesByID", uint(4)).Return(series, nil)
+	mockAppointmentRepo.On("FindBySeries", uint(4)).Return(appointments, nil)
+	mockAppointmentRepo.On("CancelSeries", mock.MatchedBy(func(s *models.AppointmentSeries) bool {
+		return s.Status == models.AppointmentStatusCancelled && s.CancellationReason == "Course finished"
+	}), mock.MatchedBy(func(cancelled []models.Appointment) bool {
+		return len(cancelled) == 1 && cancelled[0].ID == 22 && cancelled[0].Status == models.AppointmentStatusCancelled
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionAppointmentUpdate && e.Changes["status"].After == models.AppointmentStatusCancelled
+	})).Return(nil)
+
+	service := newTestAppointmentSeriesService(new(MockScheduleRepository), mockAppointmentRepo)
+
+	result, err := service.CancelSeries(receptionistAccessor, 4, models.CancelSeriesRequest{Reason: "Course finished"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppointmentStatusCancelled, result.Series.Status)
+	mockAppointmentRepo.AssertExpectations(t)
+}
+
+func TestCancelSeries_ThisAndFollowing(t *testing.T) {
+	series, appointments := testSeries()
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindSeriesByID", uint(4)).Return(series, nil)
+	mockAppointmentRepo.On("FindBySeries", uint(4)).Return(appointments, nil)
+	mockAppointmentRepo.On("CancelSeries", mock.MatchedBy(func(s *models.AppointmentSeries) bool {
+		return s.Status == models.AppointmentStatusBooked
+	}), mock.MatchedBy(func(cancelled []models.Appointment) bool {
+		return len(cancelled) == 2 && cancelled[0].ID == 22 && cancelled[1].ID == 23
+	}), mock.Anything).Return(nil)
+
+	service := newTestAppointmentSeriesService(new(MockScheduleRepository), mockAppointmentRepo)
+
+	_, err := service.CancelSeries(receptionistAccessor, 4, models.CancelSeriesRequest{AppointmentID: 22, Reason: "Discharged"})
+
+	assert.NoError(t, err)
+	mockAppointmentRepo.AssertExpectations(t)
+}
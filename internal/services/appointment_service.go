@@ -0,0 +1,236 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrAppointmentNotFound  = errors.New("appointment not found")
+	ErrSlotUnavailable      = errors.New("the doctor is not available at that time")
+	ErrAppointmentCancelled = errors.New("appointment is cancelled")
+)
+
+// AppointmentRepository defines the appointment data operations used by the services
+type AppointmentRepository interface {
+	Create(appointment *models.Appointment, event *models.AuditEvent) (bool, error)
+	FindByID(id uint) (*models.Appointment, error)
+	FindAll(filter models.AppointmentFilter) ([]models.PatientAppointment, error)
+	FindBooked(doctorID uint, from, to time.Time) ([]models.Appointment, error)
+	Reschedule(appointment *models.Appointment, event *models.AuditEvent) (bool, error)
+	Cancel(appointment *models.Appointment, event *models.AuditEvent) (bool, error)
+	CreateSeries(series *models.AppointmentSeries, appointments []models.Appointment, event *models.AuditEvent) ([]bool, error)
+	FindSeriesByID(id uint) (*models.AppointmentSeries, error)
+	FindBySeries(seriesID uint) ([]models.Appointment, error)
+	RescheduleSeries(appointments []models.Appointment, event *models.AuditEvent) ([]bool, error)
+	CancelSeries(series *models.AppointmentSeries, appointments []models.Appointment, event *models.AuditEvent) error
+}
+
+// AppointmentService handles the booking of patients into doctors' slots
+type AppointmentService struct {
+	appointmentRepo AppointmentRepository
+	scheduleService *ScheduleService
+	patientService  *PatientService
+	waitlistService *WaitlistService
+	auditRepo       AuditRepository
+}
+
+// NewAppointmentService creates a new AppointmentService
+func NewAppointmentService(appointmentRepo AppointmentRepository, scheduleService *ScheduleService, patientService *PatientService, waitlistService *WaitlistService, auditRepo AuditRepository) *AppointmentService {
+	return &AppointmentService{
+		appointmentRepo: appointmentRepo,
+		scheduleService: scheduleService,
+		patientService:  patientService,
+		waitlistService: waitlistService,
+		auditRepo:       auditRepo,
+	}
+}
+
+// BookAppointment books a free slot of a doctor for a patient
+func (s *AppointmentService) BookAppointment(accessor PatientAccessor, req models.CreateAppointmentRequest) (*models.Appointment, error) {
+	if err := s.patientService.CheckAccess(accessor, req.PatientID, models.AuditActionAppointmentCreate); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleService.findDoctor(req.DoctorID); err != nil {
+		return nil, err
+	}
+
+	slot, err := s.scheduleService.freeSlot(req.DoctorID, req.StartTime)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment := &models.Appointment{
+		PatientID: req.PatientID,
+		DoctorID:  req.DoctorID,
+		Type:      req.Type,
+		Status:    models.AppointmentStatusBooked,
+		StartTime: slot.Start,
+		EndTime:   slot.End,
+		Reason:    req.Reason,
+		BookedBy:  accessor.UserID,
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionAppointmentCreate, req.PatientID, models.DiffRecords(nil, appointment))
+	booked, err := s.appointmentRepo.Create(appointment, event)
+	if err != nil {
+		return nil, err
+	}
+	if !booked {
+		return nil, ErrSlotUnavailable
+	}
+
+	return appointment, nil
+}
+
+// GetAppointment gets an appointment
+func (s *AppointmentService) GetAppointment(accessor PatientAccessor, id uint) (*models.Appointment, error) {
+	appointment, err := s.findAppointment(accessor, id, models.AuditActionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, appointment.PatientID, nil)
+	event.Details = fmt.Sprintf("appointment=%d", appointment.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+// GetAppointments lists the appointments matching a filter, optionally on
+// one date
+func (s *AppointmentService) GetAppointments(accessor PatientAccessor, filter models.AppointmentFilter, date string) ([]models.PatientAppointment, error) {
+	if filter.PatientID != 0 {
+		if err := s.patientService.CheckAccess(accessor, filter.PatientID, models.AuditActionRead); err != nil {
+			return nil, err
+		}
+	}
+	if date != "" {
+		from, to, err := s.scheduleService.dayBounds(date)
+		if err != nil {
+			return nil, err
+		}
+		filter.From, filter.To = &from, &to
+	}
+
+	return s.listAppointments(accessor, filter)
+}
+
+// GetDayList lists the booked appointments of a doctor on a date
+func (s *AppointmentService) GetDayList(accessor PatientAccessor, date string) ([]models.PatientAppointment, error) {
+	from, to, err := s.scheduleService.dayBounds(date)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listAppointments(accessor, models.AppointmentFilter{
+		DoctorID: accessor.UserID,
+		Status:   models.AppointmentStatusBooked,
+		From:     &from,
+		To:       &to,
+	})
+}
+
+// RescheduleAppointment moves a booked appointment to another free slot of
+// the same doctor
+func (s *AppointmentService) RescheduleAppointment(accessor PatientAccessor, id uint, req models.RescheduleAppointmentRequest) (*models.Appointment, error) {
+	appointment, err := s.findAppointment(accessor, id, models.AuditActionAppointmentUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != models.AppointmentStatusBooked {
+		return nil, ErrAppointmentCancelled
+	}
+
+	slot, err := s.scheduleService.freeSlot(appointment.DoctorID, req.StartTime, appointment.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *appointment
+	appointment.StartTime = slot.Start
+	appointment.EndTime = slot.End
+	appointment.RescheduleReason = req.Reason
+
+	event := newAuditEvent(accessor, models.AuditActionAppointmentUpdate, appointment.PatientID, models.DiffRecords(&before, appointment))
+	event.Details = fmt.Sprintf("appointment=%d", appointment.ID)
+	rescheduled, err := s.appointmentRepo.Reschedule(appointment, event)
+	if err != nil {
+		return nil, err
+	}
+	if !rescheduled {
+		return nil, ErrSlotUnavailable
+	}
+
+	return appointment, nil
+}
+
+// CancelAppointment cancels a booked appointment, freeing its slot for the
+// waitlist
+func (s *AppointmentService) CancelAppointment(accessor PatientAccessor, id uint, req models.CancelAppointmentRequest) (*models.Appointment, error) {
+	appointment, err := s.findAppointment(accessor, id, models.AuditActionAppointmentUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.Status != models.AppointmentStatusBooked {
+		return nil, ErrAppointmentCancelled
+	}
+
+	before := *appointment
+	now := time.Now()
+	appointment.Status = models.AppointmentStatusCancelled
+	appointment.CancellationReason = req.Reason
+	appointment.CancelledAt = &now
+
+	event := newAuditEvent(accessor, models.AuditActionAppointmentUpdate, appointment.PatientID, models.DiffRecords(&before, appointment))
+	event.Details = fmt.Sprintf("appointment=%d", appointment.ID)
+	cancelled, err := s.appointmentRepo.Cancel(appointment, event)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrAppointmentCancelled
+	}
+
+	s.waitlistService.OfferFreedSlot(accessor, appointment)
+	return appointment, nil
+}
+
+// listAppointments lists appointments and records which patients were listed
+func (s *AppointmentService) listAppointments(accessor PatientAccessor, filter models.AppointmentFilter) ([]models.PatientAppointment, error) {
+	appointments, err := s.appointmentRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(appointments))
+	for i, appointment := range appointments {
+		ids[i] = fmt.Sprint(appointment.PatientID)
+	}
+	event := newAuditEvent(accessor, models.AuditActionSearch, 0, nil)
+	event.Details = fmt.Sprintf("appointments doctor=%d patient=%d patients=[%s]", filter.DoctorID, filter.PatientID, strings.Join(ids, ","))
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+// findAppointment finds an appointment of a patient the accessor may reach
+func (s *AppointmentService) findAppointment(accessor PatientAccessor, id uint, action models.AuditAction) (*models.Appointment, error) {
+	appointment, err := s.appointmentRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrAppointmentNotFound
+	}
+	if err := s.patientService.CheckAccess(accessor, appointment.PatientID, action); err != nil {
+		return nil, err
+	}
+	return appointment, nil
+}
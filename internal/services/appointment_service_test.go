@@ -0,0 +1,220 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAppointmentRepository is a mock implementation of AppointmentRepository
+type MockAppointmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAppointmentRepository) Create(appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	args := m.Called(appointment, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindByID(id uint) (*models.Appointment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindAll(filter models.AppointmentFilter) ([]models.PatientAppointment, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.PatientAppointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindBooked(doctorID uint, from, to time.Time) ([]models.Appointment, error) {
+	args := m.Called(doctorID, from, to)
+	return args.Get(0).([]models.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Reschedule(appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	args := m.Called(appointment, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) Cancel(appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	args := m.Called(appointment, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) CreateSeries(series *models.AppointmentSeries, appointments []models.Appointment, event *models.AuditEvent) ([]bool, error) {
+	args := m.Called(series, appointments, event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]bool), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindSeriesByID(id uint) (*models.AppointmentSeries, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AppointmentSeries), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) FindBySeries(seriesID uint) ([]models.Appointment, error) {
+	args := m.Called(seriesID)
+	return args.Get(0).([]models.Appointment), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) RescheduleSeries(appointments []models.Appointment, event *models.AuditEvent) ([]bool, error) {
+	args := m.Called(appointments, event)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]bool), args.Error(1)
+}
+
+func (m *MockAppointmentRepository) CancelSeries(series *models.AppointmentSeries, appointments []models.Appointment, event *models.AuditEvent) error {
+	args := m.Called(series, appointments, event)
+	return args.Error(0)
+}
+
+// newTestAppointmentService creates an AppointmentService for patient 1 and
+// doctor 5, who has no exceptions or leave on testScheduleDate, with nobody
+// on the waitlist
+func newTestAppointmentService(appointmentRepo *MockAppointmentRepository) *AppointmentService {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), testScheduleDate).Return(false, nil).Maybe()
+	mockScheduleRepo.On("FindException", uint(5), testScheduleDate).Return(nil, nil).Maybe()
+	scheduleService := newTestScheduleService(mockScheduleRepo, appointmentRepo)
+
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	auditRepo := newMockAuditRepository()
+
+	patientService := NewPatientService(mockPatientRepo, new(MockCareTeamRepository), auditRepo)
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindWaiting", uint(5), mock.Anything, mock.Anything).Return([]models.WaitlistEntry{}, nil).Maybe()
+	waitlistService := newTestWaitlistService(mockWaitlistRepo, scheduleService, patientService, mockPatientRepo, new(MockNotificationRepository))
+	return NewAppointmentService(appointmentRepo, scheduleService, patientService, waitlistService, auditRepo)
+}
+
+func TestBookAppointment(t *testing.T) {
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{}, nil)
+	mockAppointmentRepo.On("Create", mock.AnythingOfType("*models.Appointment"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionAppointmentCreate && *e.PatientID == 1
+	})).Return(true, nil)
+
+	service := newTestAppointmentService(mockAppointmentRepo)
+
+	appointment, err := service.BookAppointment(receptionistAccessor, models.CreateAppointmentRequest{
+		PatientID: 1,
+		DoctorID:  5,
+		StartTime: testSlot("09:20"),
+		Type:      models.AppointmentTypeConsultation,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppointmentStatusBooked, appointment.Status)
+	assert.Equal(t, testSlot("09:40"), appointment.EndTime)
+	assert.Equal(t, uint(1), appointment.BookedBy)
+	mockAppointmentRepo.AssertExpectations(t)
+}
+
+func TestBookAppointment_OutsideHours(t *testing.T) {
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{}, nil)
+
+	service := newTestAppointmentService(mockAppointmentRepo)
+
+	for _, clock := range []string{"08:40", "09:10", "10:00"} {
+		_, err := service.BookAppointment(receptionistAccessor, models.CreateAppointmentRequest{
+			PatientID: 1,
+			DoctorID:  5,
+			StartTime: testSlot(clock),
+			Type:      models.AppointmentTypeConsultation,
+		})
+		assert.ErrorIs(t, err, ErrSlotUnavailable)
+	}
+	mockAppointmentRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestBookAppointment_DoubleBooked(t *testing.T) {
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{}, nil)
+	// Another booking took the slot between the availability check and the insert
+	mockAppointmentRepo.On("Create", mock.Anything, mock.Anything).Return(false, nil)
+
+	service := newTestAppointmentService(mockAppointmentRepo)
+
+	_, err := service.BookAppointment(receptionistAccessor, models.CreateAppointmentRequest{
+		PatientID: 1,
+		DoctorID:  5,
+		StartTime: testSlot("09:00"),
+		Type:      models.AppointmentTypeFollowUp,
+	})
+
+	assert.ErrorIs(t, err, ErrSlotUnavailable)
+}
+
+func TestRescheduleAppointment(t *testing.T) {
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindByID", uint(7)).Return(&models.Appointment{
+		ID: 7, PatientID: 1, DoctorID: 5, Status: models.AppointmentStatusBooked,
+		StartTime: testSlot("09:00"), EndTime: testSlot("09:20"),
+	}, nil)
+	// The appointment being moved does not block the slot next to it
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{
+		{ID: 7, DoctorID: 5, StartTime: testSlot("09:00"), EndTime: testSlot("09:20")},
+	}, nil)
+	mockAppointmentRepo.On("Reschedule", mock.AnythingOfType("*models.Appointment"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionAppointmentUpdate && e.Changes["reschedule_reason"].After == "Patient request"
+	})).Return(true, nil)
+
+	service := newTestAppointmentService(mockAppointmentRepo)
+
+	appointment, err := service.RescheduleAppointment(receptionistAccessor, 7, models.RescheduleAppointmentRequest{
+		StartTime: testSlot("09:40"),
+		Reason:    "Patient request",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, testSlot("09:40"), appointment.StartTime)
+	assert.Equal(t, testSlot("10:00"), appointment.EndTime)
+	mockAppointmentRepo.AssertExpectations(t)
+}
+
+func TestCancelAppointment_AlreadyCancelled(t *testing.T) {
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindByID", uint(7)).Return(&models.Appointment{
+		ID: 7, PatientID: 1, DoctorID: 5, Status: models.AppointmentStatusCancelled,
+	}, nil)
+
+	service := newTestAppointmentService(mockAppointmentRepo)
+
+	_, err := service.CancelAppointment(receptionistAccessor, 7, models.CancelAppointmentRequest{Reason: "Unwell"})
+
+	assert.ErrorIs(t, err, ErrAppointmentCancelled)
+	mockAppointmentRepo.AssertNotCalled(t, "Cancel", mock.Anything, mock.Anything)
+}
+
+func TestCancelAppointment(t *testing.T) {
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindByID", uint(7)).Return(&models.Appointment{
+		ID: 7, PatientID: 1, DoctorID: 5, Status: models.AppointmentStatusBooked,
+	}, nil)
+	mockAppointmentRepo.On("Cancel", mock.AnythingOfType("*models.Appointment"), mock.Anything).Return(true, nil)
+
+	service := newTestAppointmentService(mockAppointmentRepo)
+
+	appointment, err := service.CancelAppointment(receptionistAccessor, 7, models.CancelAppointmentRequest{Reason: "Unwell"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppointmentStatusCancelled, appointment.Status)
+	assert.Equal(t, "Unwell", appointment.CancellationReason)
+	assert.NotNil(t, appointment.CancelledAt)
+}
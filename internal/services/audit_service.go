@@ -0,0 +1,101 @@
+package services
+
+import (
+	"healthcare-app/internal/models"
+)
+
+// auditVerifyBatchSize is the number of events loaded at a time when
+// verifying the audit hash chain
+const auditVerifyBatchSize = 500
+
+// AuditRepository defines the audit trail data operations used by the services
+type AuditRepository interface {
+	Append(event *models.AuditEvent) error
+	FindAll(filter models.AuditFilter, limit, offset int) ([]models.AuditEvent, int64, error)
+	FindAfter(afterID uint, limit int) ([]models.AuditEvent, error)
+}
+
+// AuditService handles reading and verifying the audit trail
+type AuditService struct {
+	auditRepo AuditRepository
+}
+
+// NewAuditService creates a new AuditService
+func NewAuditService(auditRepo AuditRepository) *AuditService {
+	return &AuditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// GetAuditEvents gets audit events matching a filter with pagination
+func (s *AuditService) GetAuditEvents(filter models.AuditFilter, page, pageSize int) (*PaginationResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	events, totalItems, err := s.auditRepo.FindAll(filter, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(totalItems) + pageSize - 1) / pageSize
+
+	return &PaginationResponse{
+		TotalItems: totalItems,
+		Items:      events,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// VerifyChain walks the audit trail in order and checks that every event
+// still matches its hash and links to the one before it
+func (s *AuditService) VerifyChain() (*models.AuditChainVerification, error) {
+	result := &models.AuditChainVerification{Valid: true}
+
+	var lastID uint
+	prevHash := ""
+	for {
+		events, err := s.auditRepo.FindAfter(lastID, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range events {
+			event := &events[i]
+			result.EventsChecked++
+			if event.PrevHash != prevHash || event.Hash != event.ComputeHash() {
+				result.Valid = false
+				result.FirstInvalidID = &event.ID
+				return result, nil
+			}
+			prevHash = event.Hash
+			lastID = event.ID
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// newAuditEvent creates an audit event attributed to a patient accessor
+func newAuditEvent(accessor PatientAccessor, action models.AuditAction, patientID uint, changes models.AuditChanges) *models.AuditEvent {
+	event := &models.AuditEvent{
+		ActorID:   accessor.UserID,
+		ActorRole: accessor.Role,
+		Action:    action,
+		Changes:   changes,
+		RequestID: accessor.RequestID,
+		ClientIP:  accessor.ClientIP,
+	}
+	if patientID != 0 {
+		event.PatientID = &patientID
+	}
+	return event
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Append(event *models.AuditEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) FindAll(filter models.AuditFilter, limit, offset int) ([]models.AuditEvent, int64, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]models.AuditEvent), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAuditRepository) FindAfter(afterID uint, limit int) ([]models.AuditEvent, error) {
+	args := m.Called(afterID, limit)
+	return args.Get(0).([]models.AuditEvent), args.Error(1)
+}
+
+// newMockAuditRepository creates a MockAuditRepository accepting any event
+func newMockAuditRepository() *MockAuditRepository {
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("Append", mock.Anything).Return(nil)
+	return mockAuditRepo
+}
+
+// auditChain builds a correctly linked chain of audit events
+func auditChain(n int) []models.AuditEvent {
+	events := make([]models.AuditEvent, n)
+	prevHash := ""
+	for i := range events {
+		patientID := uint(1)
+		events[i] = models.AuditEvent{
+			ID:        uint(i + 1),
+			ActorID:   2,
+			ActorRole: models.RoleDoctor,
+			Action:    models.AuditActionRead,
+			PatientID: &patientID,
+			CreatedAt: time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC),
+			PrevHash:  prevHash,
+		}
+		events[i].Hash = events[i].ComputeHash()
+		prevHash = events[i].Hash
+	}
+	return events
+}
+
+func TestVerifyChain_Valid(t *testing.T) {
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("FindAfter", uint(0), auditVerifyBatchSize).Return(auditChain(3), nil)
+
+	service := NewAuditService(mockAuditRepo)
+
+	result, err := service.VerifyChain()
+
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(3), result.EventsChecked)
+	assert.Nil(t, result.FirstInvalidID)
+}
+
+func TestVerifyChain_DetectsTampering(t *testing.T) {
+	events := auditChain(3)
+	events[1].Action = models.AuditActionUpdate
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("FindAfter", uint(0), auditVerifyBatchSize).Return(events, nil)
+
+	service := NewAuditService(mockAuditRepo)
+
+	result, err := service.VerifyChain()
+
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, uint(2), *result.FirstInvalidID)
+}
+
+func TestVerifyChain_DetectsRemovedEvent(t *testing.T) {
+	events := auditChain(3)
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("FindAfter", uint(0), auditVerifyBatchSize).Return([]models.AuditEvent{events[0], events[2]}, nil)
+
+	service := NewAuditService(mockAuditRepo)
+
+	result, err := service.VerifyChain()
+
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, uint(3), *result.FirstInvalidID)
+}
+
+func TestUpdatePatientMedicalInfo_AuditsChangedFields(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	patient := &models.Patient{ID: 1, BloodGroup: "O+", MedicalHistory: "asthma", Version: 2}
+	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
+	mockRepo.On("Update", patient, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		change, ok := e.Changes["medical_history"]
+		_, bloodGroupChanged := e.Changes["blood_group"]
+		return e.Action == models.AuditActionUpdateMedical && *e.PatientID == 1 &&
+			e.ActorRole == models.RoleDoctor && e.RequestID == "req-1" &&
+			ok && change.Before == "asthma" && change.After == "asthma, eczema" && !bloodGroupChanged
+	})).Return(true, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(true, nil)
+
+	service := NewPatientService(mockRepo, mockCareTeamRepo, newMockAuditRepository())
+
+	accessor := doctorAccessor
+	accessor.Role = models.RoleDoctor
+	accessor.RequestID = "req-1"
+	_, err := service.UpdatePatientMedicalInfo(accessor, 1, 2, models.UpdatePatientMedicalRequest{
+		BloodGroup:     "O+",
+		MedicalHistory: "asthma, eczema",
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetPatient_AuditsRead(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("Append", mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionRead && e.ActorID == 1 && *e.PatientID == 1
+	})).Return(nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), mockAuditRepo)
+
+	_, err := service.GetPatient(receptionistAccessor, 1)
+
+	assert.NoError(t, err)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestGetPatient_AuditsDeniedAccess(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(false, nil)
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("Append", mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionAccessDenied && e.ActorID == 5 && e.Details == "read"
+	})).Return(nil)
+
+	service := NewPatientService(mockRepo, mockCareTeamRepo, mockAuditRepo)
+
+	_, err := service.GetPatient(doctorAccessor, 1)
+
+	assert.Equal(t, ErrPatientAccessDenied, err)
+	mockAuditRepo.AssertExpectations(t)
+}
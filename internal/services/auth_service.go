@@ -1,111 +1,433 @@
-package services
-
-import (
-	"errors"
-	"fmt"
-	"time"
-
-	"healthcare-app/internal/models"
-	"healthcare-app/internal/repositories"
-
-	"github.com/golang-jwt/jwt/v5"
-)
-
-// Predefined errors
-var (
-	ErrInvalidCredentials = errors.New("invalid email or password")
-	ErrTokenGeneration    = errors.New("could not generate token")
-	ErrInvalidToken       = errors.New("invalid token")
-)
-
-// AuthService handles authentication operations
-type AuthService struct {
-	userRepo  *repositories.UserRepository
-	jwtSecret string
-}
-
-// NewAuthService creates a new AuthService
-func NewAuthService(userRepo *repositories.UserRepository, jwtSecret string) *AuthService {
-	return &AuthService{
-		userRepo:  userRepo,
-		jwtSecret: jwtSecret,
-	}
-}
-
-// Claims represents the JWT claims
-type Claims struct {
-	UserID uint           `json:"user_id"`
-	Role   models.UserRole `json:"role"`
-	jwt.RegisteredClaims
-}
-
-// Login authenticates a user
-func (s *AuthService) Login(req models.LoginRequest) (*models.LoginResponse, error) {
-	user, err := s.userRepo.FindByEmail(req.Email)
-	if err != nil {
-		return nil, ErrInvalidCredentials
-	}
-
-	if !models.CheckPasswordHash(req.Password, user.Password) {
-		return nil, ErrInvalidCredentials
-	}
-
-	token, err := s.GenerateToken(user.ID, user.Role)
-	if err != nil {
-		return nil, ErrTokenGeneration
-	}
-
-	return &models.LoginResponse{
-		Token: token,
-		User:  user.ToUserResponse(),
-	}, nil
-}
-
-// GenerateToken generates a JWT token
-func (s *AuthService) GenerateToken(userID uint, role models.UserRole) (string, error) {
-	// Create claims with expiration
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	// Create token with claims
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign token with secret
-	tokenString, err := token.SignedString([]byte(s.jwtSecret))
-	if err != nil {
-		return "", err
-	}
-
-	return tokenString, nil
-}
-
-// ValidateToken validates a JWT token
-func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
-	// Parse token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Validate signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.jwtSecret), nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	// Validate claims
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
-		return claims, nil
-	}
-
-	return nil, ErrInvalidToken
-} 
\ No newline at end of file
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Predefined errors
+var (
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrTokenGeneration     = errors.New("could not generate token")
+	ErrInvalidToken        = errors.New("invalid token")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	ErrAccountDeactivated  = errors.New("account has been deactivated")
+)
+
+// mfaChallengePurpose marks tokens that only authorize the second login step
+const mfaChallengePurpose = "mfa_challenge"
+
+// mfaChallengeTTL is how long a user has to present their second factor
+const mfaChallengeTTL = 5 * time.Minute
+
+// SessionRepository defines the session data operations used by the AuthService
+type SessionRepository interface {
+	Create(session *models.Session) error
+	FindByID(id string) (*models.Session, error)
+	Revoke(id string) error
+	RevokeAllForUser(userID uint) error
+	RevokeAllForUserExcept(userID uint, keepID string) error
+	CreateRefreshToken(token *models.RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*models.RefreshToken, error)
+	RotateRefreshToken(id uint, next *models.RefreshToken) (bool, error)
+}
+
+// AuthService handles authentication operations
+type AuthService struct {
+	userRepo        UserRepository
+	sessionRepo     SessionRepository
+	mfaService      *MFAService
+	permissions     *PermissionService
+	throttle        *LoginThrottle
+	keys            *KeyManager
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+// NewAuthService creates a new AuthService
+func NewAuthService(userRepo UserRepository, sessionRepo SessionRepository, mfaService *MFAService, permissions *PermissionService, throttle *LoginThrottle, keys *KeyManager, accessTokenTTL, refreshTokenTTL time.Duration) *AuthService {
+	return &AuthService{
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		mfaService:      mfaService,
+		permissions:     permissions,
+		throttle:        throttle,
+		keys:            keys,
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+}
+
+// Claims represents the JWT claims
+type Claims struct {
+	UserID    uint            `json:"user_id"`
+	Role      models.UserRole `json:"role"`
+	SessionID string          `json:"sid,omitempty"`
+	// Permissions granted to the user's role when the token was issued
+	Permissions []models.Permission `json:"perms,omitempty"`
+	// Purpose is empty for access tokens and set for single-purpose tokens
+	Purpose string `json:"purpose,omitempty"`
+	// MFAEnrollmentRequired restricts the token to MFA enrollment
+	MFAEnrollmentRequired bool `json:"mfa_enroll,omitempty"`
+	// PasswordChangeRequired restricts the token to changing the password
+	PasswordChangeRequired bool `json:"pwd_change,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// HasPermission checks if the claims grant a permission
+func (c *Claims) HasPermission(permission models.Permission) bool {
+	for _, granted := range c.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Login authenticates a user. Failed attempts are counted per account and
+// per client IP, and throttled or locked logins return a *RetryAfterError.
+func (s *AuthService) Login(req models.LoginRequest, clientIP string) (*models.LoginResponse, error) {
+	if err := s.throttle.Check(req.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByEmail(req.Email)
+	if err != nil {
+		return nil, s.loginFailed(req.Email, clientIP, ErrInvalidCredentials)
+	}
+
+	if !models.CheckPasswordHash(req.Password, user.Password) {
+		return nil, s.loginFailed(req.Email, clientIP, ErrInvalidCredentials)
+	}
+
+	if !user.IsActive() {
+		return nil, ErrAccountDeactivated
+	}
+
+	// Users with MFA enabled get a challenge instead of a session
+	if user.MFAEnabled {
+		mfaToken, err := s.generateMFAChallenge(user.ID)
+		if err != nil {
+			return nil, ErrTokenGeneration
+		}
+		return &models.LoginResponse{
+			User:        user.ToUserResponse(),
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}, nil
+	}
+
+	if err := s.throttle.RecordSuccess(user.Email); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user)
+}
+
+// LoginMFA completes a login by exchanging an MFA challenge token and a
+// TOTP or recovery code for a session. Invalid codes count as failed logins.
+func (s *AuthService) LoginMFA(req models.MFALoginRequest, clientIP string) (*models.LoginResponse, error) {
+	claims, err := s.parseToken(req.MFAToken)
+	if err != nil || claims.Purpose != mfaChallengePurpose {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !user.IsActive() {
+		return nil, ErrAccountDeactivated
+	}
+
+	if err := s.throttle.Check(user.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaService.Verify(user, req.Code, req.RecoveryCode); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			return nil, s.loginFailed(user.Email, clientIP, err)
+		}
+		return nil, err
+	}
+
+	if err := s.throttle.RecordSuccess(user.Email); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user)
+}
+
+// loginFailed records a failed attempt and returns the error to report
+func (s *AuthService) loginFailed(email, clientIP string, reason error) error {
+	if err := s.throttle.RecordFailure(email, clientIP); err != nil {
+		return err
+	}
+	return reason
+}
+
+// startSession creates a session for an authenticated user and issues its
+// first token pair
+func (s *AuthService) startSession(user *models.User) (*models.LoginResponse, error) {
+	sessionID, err := generateRandomToken(16)
+	if err != nil {
+		return nil, ErrTokenGeneration
+	}
+
+	session := &models.Session{
+		ID:     sessionID,
+		UserID: user.ID,
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.issueTokens(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{
+		Token:                  tokens.Token,
+		RefreshToken:           tokens.RefreshToken,
+		ExpiresAt:              &tokens.ExpiresAt,
+		User:                   user.ToUserResponse(),
+		MFAEnrollmentRequired:  tokens.MFAEnrollmentRequired,
+		PasswordChangeRequired: tokens.PasswordChangeRequired,
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new access/refresh token pair.
+// Presenting a token that has already been rotated revokes the whole session.
+// An expired token is rejected without being used up, and the old token is
+// only marked used together with storing its replacement.
+func (s *AuthService) Refresh(refreshToken string) (*models.TokenResponse, error) {
+	stored, err := s.sessionRepo.FindRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	session, err := s.sessionRepo.FindByID(stored.SessionID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if session.IsRevoked() {
+		return nil, ErrSessionRevoked
+	}
+
+	if stored.RotatedAt != nil {
+		return nil, s.revokeReused(session.ID)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if !user.IsActive() {
+		return nil, ErrAccountDeactivated
+	}
+
+	tokens, next, err := s.newTokens(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.sessionRepo.RotateRefreshToken(stored.ID, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Another request rotated the token first
+		return nil, s.revokeReused(session.ID)
+	}
+
+	return tokens, nil
+}
+
+// revokeReused revokes a session whose refresh token was presented again
+// after it had been rotated
+func (s *AuthService) revokeReused(sessionID string) error {
+	if err := s.sessionRepo.Revoke(sessionID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Logout revokes a session together with all of its refresh tokens
+func (s *AuthService) Logout(sessionID string) error {
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// issueTokens creates an access token and a new refresh token for a session
+// and stores the refresh token
+func (s *AuthService) issueTokens(user *models.User, sessionID string) (*models.TokenResponse, error) {
+	tokens, stored, err := s.newTokens(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.CreateRefreshToken(stored); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// newTokens creates an access token and a new refresh token for a session,
+// returning the refresh token record still to be stored. Users whose role
+// requires MFA but who have not enrolled, or who must change their password,
+// get a restricted token until they have done so.
+func (s *AuthService) newTokens(user *models.User, sessionID string) (*models.TokenResponse, *models.RefreshToken, error) {
+	enrollmentRequired := false
+	if !user.MFAEnabled {
+		required, err := s.mfaService.RoleRequiresMFA(user.Role)
+		if err != nil {
+			return nil, nil, err
+		}
+		enrollmentRequired = required
+	}
+
+	permissions, err := s.permissions.PermissionsForRole(user.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claims := s.newAccessClaims(user.ID, user.Role, sessionID)
+	claims.Permissions = permissions
+	claims.MFAEnrollmentRequired = enrollmentRequired
+	claims.PasswordChangeRequired = user.MustChangePassword
+
+	accessToken, err := s.keys.Sign(claims)
+	if err != nil {
+		return nil, nil, ErrTokenGeneration
+	}
+
+	refreshToken, err := generateRandomToken(32)
+	if err != nil {
+		return nil, nil, ErrTokenGeneration
+	}
+
+	stored := &models.RefreshToken{
+		SessionID: sessionID,
+		TokenHash: hashToken(refreshToken),
+		ExpiresAt: time.Now().Add(s.refreshTokenTTL),
+	}
+	return &models.TokenResponse{
+		Token:                  accessToken,
+		RefreshToken:           refreshToken,
+		ExpiresAt:              claims.ExpiresAt.Time,
+		MFAEnrollmentRequired:  enrollmentRequired,
+		PasswordChangeRequired: user.MustChangePassword,
+	}, stored, nil
+}
+
+// GenerateToken generates a JWT token
+func (s *AuthService) GenerateToken(userID uint, role models.UserRole, sessionID string) (string, error) {
+	// Sign token with the current signing key
+	tokenString, err := s.keys.Sign(s.newAccessClaims(userID, role, sessionID))
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+// newAccessClaims creates access token claims with expiration
+func (s *AuthService) newAccessClaims(userID uint, role models.UserRole, sessionID string) *Claims {
+	now := time.Now()
+	return &Claims{
+		UserID:    userID,
+		Role:      role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	}
+}
+
+// generateMFAChallenge creates a short-lived token for the second login step
+func (s *AuthService) generateMFAChallenge(userID uint) (string, error) {
+	now := time.Now()
+	return s.keys.Sign(&Claims{
+		UserID:  userID,
+		Purpose: mfaChallengePurpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(mfaChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+	})
+}
+
+// parseToken verifies a JWT signature and expiry and returns its claims
+func (s *AuthService) parseToken(tokenString string) (*Claims, error) {
+	// Parse token, resolving the verification key from its kid header
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, s.keys.Keyfunc,
+		jwt.WithValidMethods(s.keys.ValidMethods()))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// ValidateToken validates an access token and checks that its session is still active
+func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	// Single-purpose tokens are not access tokens
+	if claims.Purpose != "" {
+		return nil, ErrInvalidToken
+	}
+
+	// Reject tokens whose session has been revoked
+	session, err := s.sessionRepo.FindByID(claims.SessionID)
+	if err != nil || session.UserID != claims.UserID {
+		return nil, ErrInvalidToken
+	}
+	if session.IsRevoked() {
+		return nil, ErrSessionRevoked
+	}
+
+	return claims, nil
+}
+
+// JWKS returns the public keys that verify tokens issued by this service
+func (s *AuthService) JWKS() models.JSONWebKeySet {
+	return s.keys.JWKS()
+}
+
+// generateRandomToken returns a URL-safe random string of n random bytes
+func generateRandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken returns the hex encoded SHA-256 hash of a token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
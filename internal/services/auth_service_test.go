@@ -1,181 +1,518 @@
-package services
-
-import (
-	"errors"
-	"testing"
-
-	"healthcare-app/internal/models"
-
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
-)
-
-// MockUserRepository is a mock implementation of UserRepository
-type MockUserRepository struct {
-	mock.Mock
-}
-
-func (m *MockUserRepository) Create(user *models.User) error {
-	args := m.Called(user)
-	return args.Error(0)
-}
-
-func (m *MockUserRepository) FindByID(id uint) (*models.User, error) {
-	args := m.Called(id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*models.User), args.Error(1)
-}
-
-func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
-	args := m.Called(email)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*models.User), args.Error(1)
-}
-
-func (m *MockUserRepository) FindAll() ([]models.User, error) {
-	args := m.Called()
-	return args.Get(0).([]models.User), args.Error(1)
-}
-
-func (m *MockUserRepository) Update(user *models.User) error {
-	args := m.Called(user)
-	return args.Error(0)
-}
-
-func (m *MockUserRepository) Delete(id uint) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockUserRepository) EmailExists(email string) (bool, error) {
-	args := m.Called(email)
-	return args.Bool(0), args.Error(1)
-}
-
-func TestLogin_Success(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockUserRepository)
-	
-	// Create test user with hashed password
-	hashedPassword, _ := models.HashPassword("password123")
-	user := &models.User{
-		ID:       1,
-		Name:     "Test User",
-		Email:    "test@example.com",
-		Password: hashedPassword,
-		Role:     models.RoleReceptionist,
-	}
-	
-	// Setup expectations
-	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
-	
-	// Create service with mock
-	service := NewAuthService(mockRepo, "test-secret")
-	
-	// Test login
-	req := models.LoginRequest{
-		Email:    "test@example.com",
-		Password: "password123",
-	}
-	
-	res, err := service.Login(req)
-	
-	// Assert results
-	assert.NoError(t, err)
-	assert.NotNil(t, res)
-	assert.NotEmpty(t, res.Token)
-	assert.Equal(t, user.ID, res.User.ID)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestLogin_InvalidCredentials(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockUserRepository)
-	
-	// Create test user with hashed password
-	hashedPassword, _ := models.HashPassword("password123")
-	user := &models.User{
-		ID:       1,
-		Name:     "Test User",
-		Email:    "test@example.com",
-		Password: hashedPassword,
-		Role:     models.RoleReceptionist,
-	}
-	
-	// Setup expectations
-	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
-	
-	// Create service with mock
-	service := NewAuthService(mockRepo, "test-secret")
-	
-	// Test login with wrong password
-	req := models.LoginRequest{
-		Email:    "test@example.com",
-		Password: "wrong-password",
-	}
-	
-	res, err := service.Login(req)
-	
-	// Assert results
-	assert.Error(t, err)
-	assert.Nil(t, res)
-	assert.Equal(t, ErrInvalidCredentials, err)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestLogin_UserNotFound(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockUserRepository)
-	
-	// Setup expectations
-	mockRepo.On("FindByEmail", "nonexistent@example.com").Return(nil, errors.New("user not found"))
-	
-	// Create service with mock
-	service := NewAuthService(mockRepo, "test-secret")
-	
-	// Test login with non-existent user
-	req := models.LoginRequest{
-		Email:    "nonexistent@example.com",
-		Password: "password123",
-	}
-	
-	res, err := service.Login(req)
-	
-	// Assert results
-	assert.Error(t, err)
-	assert.Nil(t, res)
-	assert.Equal(t, ErrInvalidCredentials, err)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestValidateToken(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockUserRepository)
-	
-	// Create service with mock
-	service := NewAuthService(mockRepo, "test-secret")
-	
-	// Generate a token
-	token, err := service.GenerateToken(1, models.RoleReceptionist)
-	assert.NoError(t, err)
-	assert.NotEmpty(t, token)
-	
-	// Validate the token
-	claims, err := service.ValidateToken(token)
-	
-	// Assert results
-	assert.NoError(t, err)
-	assert.NotNil(t, claims)
-	assert.Equal(t, uint(1), claims.UserID)
-	assert.Equal(t, models.RoleReceptionist, claims.Role)
-} 
\ No newline at end of file
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+	"healthcare-app/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByID(id uint) (*models.User, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindAll(filter models.UserFilter, limit, offset int) ([]models.User, int64, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) Update(user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateColumns(id uint, columns map[string]interface{}) error {
+	args := m.Called(id, columns)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) AdvanceTOTPStep(id uint, step int64) (bool, error) {
+	args := m.Called(id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) EmailExists(email string) (bool, error) {
+	args := m.Called(email)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockSessionRepository is a mock implementation of SessionRepository
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(session *models.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) FindByID(id string) (*models.Session, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Revoke(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAllForUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeAllForUserExcept(userID uint, keepID string) error {
+	args := m.Called(userID, keepID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) FindRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *MockSessionRepository) RotateRefreshToken(id uint, next *models.RefreshToken) (bool, error) {
+	args := m.Called(id, next)
+	return args.Bool(0), args.Error(1)
+}
+
+// newTestAuthService creates an AuthService with in-memory keys, no role MFA
+// requirements and test token lifetimes
+func newTestAuthService(userRepo UserRepository, sessionRepo SessionRepository) *AuthService {
+	mfaRepo := new(MockMFARepository)
+	mfaRepo.On("RoleRequiresMFA", mock.Anything).Return(false, nil)
+	return newTestAuthServiceWithMFA(userRepo, sessionRepo, mfaRepo)
+}
+
+// newTestAuthServiceWithMFA creates an AuthService using the given MFA repository
+func newTestAuthServiceWithMFA(userRepo UserRepository, sessionRepo SessionRepository, mfaRepo MFARepository) *AuthService {
+	keys, err := NewKeyManager("", AlgorithmEdDSA, 15*time.Minute)
+	if err != nil {
+		panic(err)
+	}
+	mfaService := NewMFAService(userRepo, mfaRepo, "Test")
+	return NewAuthService(userRepo, sessionRepo, mfaService, newTestPermissionService(), newTestLoginThrottle(), keys, 15*time.Minute, time.Hour)
+}
+
+// newTestLoginThrottle creates an in-memory LoginThrottle without delays
+func newTestLoginThrottle() *LoginThrottle {
+	return NewLoginThrottle(repositories.NewMemoryLoginAttemptStore(), LockoutPolicy{
+		AccountThreshold: 3,
+		IPThreshold:      10,
+		LockoutDuration:  time.Minute,
+		FailureWindow:    time.Minute,
+	})
+}
+
+func TestLogin_Success(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockUserRepository)
+	
+	// Create test user with hashed password
+	hashedPassword, _ := models.HashPassword("password123")
+	user := &models.User{
+		ID:       1,
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: hashedPassword,
+		Role:     models.RoleReceptionist,
+	}
+	
+	// Setup expectations
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockSessionRepo := new(MockSessionRepository)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*models.Session")).Return(nil)
+	mockSessionRepo.On("CreateRefreshToken", mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	
+	// Create service with mock
+	service := newTestAuthService(mockRepo, mockSessionRepo)
+	
+	// Test login
+	req := models.LoginRequest{
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+	
+	res, err := service.Login(req, "127.0.0.1")
+	
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+	assert.NotEmpty(t, res.Token)
+	assert.NotEmpty(t, res.RefreshToken)
+	assert.Equal(t, user.ID, res.User.ID)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestLogin_InvalidCredentials(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockUserRepository)
+	
+	// Create test user with hashed password
+	hashedPassword, _ := models.HashPassword("password123")
+	user := &models.User{
+		ID:       1,
+		Name:     "Test User",
+		Email:    "test@example.com",
+		Password: hashedPassword,
+		Role:     models.RoleReceptionist,
+	}
+	
+	// Setup expectations
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	
+	// Create service with mock
+	service := newTestAuthService(mockRepo, new(MockSessionRepository))
+	
+	// Test login with wrong password
+	req := models.LoginRequest{
+		Email:    "test@example.com",
+		Password: "wrong-password",
+	}
+	
+	res, err := service.Login(req, "127.0.0.1")
+	
+	// Assert results
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	assert.Equal(t, ErrInvalidCredentials, err)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+func TestLogin_UserNotFound(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockUserRepository)
+	
+	// Setup expectations
+	mockRepo.On("FindByEmail", "nonexistent@example.com").Return(nil, errors.New("user not found"))
+	
+	// Create service with mock
+	service := newTestAuthService(mockRepo, new(MockSessionRepository))
+	
+	// Test login with non-existent user
+	req := models.LoginRequest{
+		Email:    "nonexistent@example.com",
+		Password: "password123",
+	}
+	
+	res, err := service.Login(req, "127.0.0.1")
+	
+	// Assert results
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	assert.Equal(t, ErrInvalidCredentials, err)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+func TestValidateToken(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockUserRepository)
+	
+	mockSessionRepo := new(MockSessionRepository)
+	mockSessionRepo.On("FindByID", "session-1").Return(&models.Session{ID: "session-1", UserID: 1}, nil)
+	
+	// Create service with mock
+	service := newTestAuthService(mockRepo, mockSessionRepo)
+	
+	// Generate a token
+	token, err := service.GenerateToken(1, models.RoleReceptionist, "session-1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	
+	// Validate the token
+	claims, err := service.ValidateToken(token)
+	
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, claims)
+	assert.Equal(t, uint(1), claims.UserID)
+	assert.Equal(t, models.RoleReceptionist, claims.Role)
+}
+
+func TestValidateToken_RevokedSession(t *testing.T) {
+	revokedAt := time.Now()
+	mockSessionRepo := new(MockSessionRepository)
+	mockSessionRepo.On("FindByID", "session-1").Return(&models.Session{ID: "session-1", UserID: 1, RevokedAt: &revokedAt}, nil)
+
+	service := newTestAuthService(new(MockUserRepository), mockSessionRepo)
+
+	token, err := service.GenerateToken(1, models.RoleReceptionist, "session-1")
+	assert.NoError(t, err)
+
+	claims, err := service.ValidateToken(token)
+
+	assert.Nil(t, claims)
+	assert.Equal(t, ErrSessionRevoked, err)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+
+	user := &models.User{ID: 1, Role: models.RoleDoctor}
+	stored := &models.RefreshToken{ID: 7, SessionID: "session-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockSessionRepo.On("FindRefreshTokenByHash", hashToken("old-token")).Return(stored, nil)
+	mockSessionRepo.On("FindByID", "session-1").Return(&models.Session{ID: "session-1", UserID: 1}, nil)
+	mockSessionRepo.On("RotateRefreshToken", uint(7), mock.MatchedBy(func(next *models.RefreshToken) bool {
+		return next.SessionID == "session-1" && next.TokenHash != hashToken("old-token")
+	})).Return(true, nil)
+	mockRepo.On("FindByID", uint(1)).Return(user, nil)
+
+	service := newTestAuthService(mockRepo, mockSessionRepo)
+
+	res, err := service.Refresh("old-token")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+	assert.NotEmpty(t, res.Token)
+	assert.NotEqual(t, "old-token", res.RefreshToken)
+
+	// The new access token carries the permissions of the user's role
+	claims, err := service.ValidateToken(res.Token)
+	assert.NoError(t, err)
+	assert.True(t, claims.HasPermission(models.PermMedicalWrite))
+	assert.False(t, claims.HasPermission(models.PermUsersManage))
+
+	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestRefresh_ReuseRevokesSession(t *testing.T) {
+	mockSessionRepo := new(MockSessionRepository)
+
+	rotatedAt := time.Now().Add(-time.Minute)
+	stored := &models.RefreshToken{ID: 7, SessionID: "session-1", ExpiresAt: time.Now().Add(time.Hour), RotatedAt: &rotatedAt}
+
+	mockSessionRepo.On("FindRefreshTokenByHash", hashToken("old-token")).Return(stored, nil)
+	mockSessionRepo.On("FindByID", "session-1").Return(&models.Session{ID: "session-1", UserID: 1}, nil)
+	mockSessionRepo.On("Revoke", "session-1").Return(nil)
+
+	service := newTestAuthService(new(MockUserRepository), mockSessionRepo)
+
+	res, err := service.Refresh("old-token")
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrRefreshTokenReused, err)
+	mockSessionRepo.AssertExpectations(t)
+	mockSessionRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything)
+}
+
+func TestRefresh_ConcurrentRotationRevokesSession(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+
+	stored := &models.RefreshToken{ID: 7, SessionID: "session-1", ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockSessionRepo.On("FindRefreshTokenByHash", hashToken("old-token")).Return(stored, nil)
+	mockSessionRepo.On("FindByID", "session-1").Return(&models.Session{ID: "session-1", UserID: 1}, nil)
+	mockSessionRepo.On("RotateRefreshToken", uint(7), mock.AnythingOfType("*models.RefreshToken")).Return(false, nil)
+	mockSessionRepo.On("Revoke", "session-1").Return(nil)
+	mockRepo.On("FindByID", uint(1)).Return(&models.User{ID: 1, Role: models.RoleDoctor}, nil)
+
+	service := newTestAuthService(mockRepo, mockSessionRepo)
+
+	res, err := service.Refresh("old-token")
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrRefreshTokenReused, err)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestRefresh_ExpiredTokenIsNotUsedUp(t *testing.T) {
+	mockSessionRepo := new(MockSessionRepository)
+
+	stored := &models.RefreshToken{ID: 7, SessionID: "session-1", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	mockSessionRepo.On("FindRefreshTokenByHash", hashToken("old-token")).Return(stored, nil)
+	mockSessionRepo.On("FindByID", "session-1").Return(&models.Session{ID: "session-1", UserID: 1}, nil)
+
+	service := newTestAuthService(new(MockUserRepository), mockSessionRepo)
+
+	// Retrying an expired token keeps failing as expired rather than as reuse
+	for i := 0; i < 2; i++ {
+		res, err := service.Refresh("old-token")
+		assert.Nil(t, res)
+		assert.Equal(t, ErrRefreshTokenExpired, err)
+	}
+	mockSessionRepo.AssertNotCalled(t, "RotateRefreshToken", mock.Anything, mock.Anything)
+	mockSessionRepo.AssertNotCalled(t, "Revoke", mock.Anything)
+}
+
+func TestLogin_MFAEnabledReturnsChallenge(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+
+	hashedPassword, _ := models.HashPassword("password123")
+	user := &models.User{
+		ID:         1,
+		Email:      "doctor@example.com",
+		Password:   hashedPassword,
+		Role:       models.RoleDoctor,
+		MFAEnabled: true,
+	}
+	mockRepo.On("FindByEmail", "doctor@example.com").Return(user, nil)
+
+	// No session may be created before the second factor is verified
+	mockSessionRepo := new(MockSessionRepository)
+	service := newTestAuthService(mockRepo, mockSessionRepo)
+
+	res, err := service.Login(models.LoginRequest{Email: "doctor@example.com", Password: "password123"}, "127.0.0.1")
+
+	assert.NoError(t, err)
+	assert.True(t, res.MFARequired)
+	assert.NotEmpty(t, res.MFAToken)
+	assert.Empty(t, res.Token)
+	mockSessionRepo.AssertExpectations(t)
+
+	// The challenge token is not an access token
+	claims, err := service.ValidateToken(res.MFAToken)
+	assert.Nil(t, claims)
+	assert.Equal(t, ErrInvalidToken, err)
+}
+
+func TestLoginMFA_ValidCode(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+
+	secret, _ := generateTOTPSecret()
+	user := &models.User{ID: 1, Role: models.RoleDoctor, MFAEnabled: true, TOTPSecret: secret}
+
+	mockRepo.On("FindByID", uint(1)).Return(user, nil)
+	mockRepo.On("AdvanceTOTPStep", uint(1), mock.Anything).Return(true, nil)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*models.Session")).Return(nil)
+	mockSessionRepo.On("CreateRefreshToken", mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+
+	service := newTestAuthService(mockRepo, mockSessionRepo)
+
+	mfaToken, err := service.generateMFAChallenge(1)
+	assert.NoError(t, err)
+
+	key, _ := totpEncoding.DecodeString(secret)
+	code := totpCode(key, time.Now().Unix()/totpPeriod)
+
+	res, err := service.LoginMFA(models.MFALoginRequest{MFAToken: mfaToken, Code: code}, "127.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res.Token)
+	assert.NotEmpty(t, res.RefreshToken)
+	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestLogin_RoleRequiresMFAEnrollment(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockSessionRepo := new(MockSessionRepository)
+	mockMFARepo := new(MockMFARepository)
+
+	hashedPassword, _ := models.HashPassword("password123")
+	user := &models.User{ID: 1, Email: "doctor@example.com", Password: hashedPassword, Role: models.RoleDoctor}
+
+	mockRepo.On("FindByEmail", "doctor@example.com").Return(user, nil)
+	mockSessionRepo.On("Create", mock.AnythingOfType("*models.Session")).Return(nil)
+	mockSessionRepo.On("CreateRefreshToken", mock.AnythingOfType("*models.RefreshToken")).Return(nil)
+	mockMFARepo.On("RoleRequiresMFA", models.RoleDoctor).Return(true, nil)
+
+	service := newTestAuthServiceWithMFA(mockRepo, mockSessionRepo, mockMFARepo)
+
+	res, err := service.Login(models.LoginRequest{Email: "doctor@example.com", Password: "password123"}, "127.0.0.1")
+
+	assert.NoError(t, err)
+	assert.True(t, res.MFAEnrollmentRequired)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestLogin_LocksAccountAfterRepeatedFailures(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+
+	hashedPassword, _ := models.HashPassword("password123")
+	user := &models.User{ID: 1, Email: "test@example.com", Password: hashedPassword, Role: models.RoleReceptionist}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+
+	service := newTestAuthService(mockRepo, new(MockSessionRepository))
+	req := models.LoginRequest{Email: "test@example.com", Password: "wrong-password"}
+
+	for i := 0; i < 3; i++ {
+		_, err := service.Login(req, "127.0.0.1")
+		assert.Equal(t, ErrInvalidCredentials, err)
+	}
+
+	// Even the correct password is refused while the account is locked
+	req.Password = "password123"
+	res, err := service.Login(req, "127.0.0.1")
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrAccountLocked)
+
+	var retryErr *RetryAfterError
+	assert.ErrorAs(t, err, &retryErr)
+	assert.True(t, retryErr.RetryAfter > 0)
+}
+
+func TestLogin_DeactivatedUser(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+
+	hashedPassword, _ := models.HashPassword("password123")
+	deactivatedAt := time.Now()
+	user := &models.User{ID: 1, Email: "test@example.com", Password: hashedPassword, Role: models.RoleDoctor, DeactivatedAt: &deactivatedAt}
+	mockRepo.On("FindByEmail", "test@example.com").Return(user, nil)
+	mockSessionRepo := new(MockSessionRepository)
+
+	service := newTestAuthService(mockRepo, mockSessionRepo)
+
+	res, err := service.Login(models.LoginRequest{Email: "test@example.com", Password: "password123"}, "127.0.0.1")
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrAccountDeactivated, err)
+	mockSessionRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrAssignmentNotFound = errors.New("care team assignment not found")
+	ErrInvalidClinician   = errors.New("clinician not found, deactivated or not a doctor")
+	ErrInvalidDateRange   = errors.New("end date must be after start date")
+)
+
+// CareTeamRepository defines the care team data operations used by the services
+type CareTeamRepository interface {
+	Assign(assignment *models.CareTeamAssignment, event *models.AuditEvent) error
+	FindByID(id uint) (*models.CareTeamAssignment, error)
+	FindByPatient(patientID uint) ([]models.CareTeamAssignment, error)
+	IsMember(patientID, clinicianID uint, at time.Time) (bool, error)
+	End(id uint, at time.Time, event *models.AuditEvent) error
+	RecordOverride(override *models.AccessOverride) error
+}
+
+// CareTeamService handles assigning clinicians to patients
+type CareTeamService struct {
+	careTeamRepo   CareTeamRepository
+	patientService *PatientService
+	patientRepo    PatientRepository
+	userRepo       UserRepository
+	auditRepo      AuditRepository
+}
+
+// NewCareTeamService creates a new CareTeamService
+func NewCareTeamService(careTeamRepo CareTeamRepository, patientService *PatientService, patientRepo PatientRepository, userRepo UserRepository, auditRepo AuditRepository) *CareTeamService {
+	return &CareTeamService{
+		careTeamRepo:   careTeamRepo,
+		patientService: patientService,
+		patientRepo:    patientRepo,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+	}
+}
+
+// GetCareTeam gets the current and past assignments of a patient
+func (s *CareTeamService) GetCareTeam(accessor PatientAccessor, patientID uint) ([]models.CareTeamAssignment, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.careTeamRepo.FindByPatient(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "care team"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// Assign adds a doctor to a patient's care team. A new primary physician
+// ends the assignment of the previous one.
+func (s *CareTeamService) Assign(accessor PatientAccessor, patientID uint, req models.AssignClinicianRequest) (*models.CareTeamAssignment, error) {
+	if _, err := s.patientRepo.FindByID(patientID); err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	clinician, err := s.userRepo.FindByID(req.ClinicianID)
+	if err != nil || !clinician.IsActive() || clinician.Role != models.RoleDoctor {
+		return nil, ErrInvalidClinician
+	}
+
+	startDate := time.Now()
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+	if req.EndDate != nil && !req.EndDate.After(startDate) {
+		return nil, ErrInvalidDateRange
+	}
+
+	assignment := &models.CareTeamAssignment{
+		PatientID:   patientID,
+		ClinicianID: clinician.ID,
+		Role:        req.Role,
+		StartDate:   startDate,
+		EndDate:     req.EndDate,
+		AssignedBy:  accessor.UserID,
+	}
+	event := newAuditEvent(accessor, models.AuditActionCareTeamAssign, patientID, models.DiffRecords(nil, assignment))
+	if err := s.careTeamRepo.Assign(assignment, event); err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
+
+// Unassign ends a care team assignment now. The assignment is kept as history.
+func (s *CareTeamService) Unassign(accessor PatientAccessor, patientID, assignmentID uint) (*models.CareTeamAssignment, error) {
+	assignment, err := s.careTeamRepo.FindByID(assignmentID)
+	if err != nil || assignment.PatientID != patientID {
+		return nil, ErrAssignmentNotFound
+	}
+
+	now := time.Now()
+	if assignment.EndDate != nil && !assignment.EndDate.After(now) {
+		return nil, ErrAssignmentNotFound
+	}
+
+	before := *assignment
+	assignment.EndDate = &now
+	event := newAuditEvent(accessor, models.AuditActionCareTeamUnassign, patientID, models.DiffRecords(&before, assignment))
+	event.Details = fmt.Sprintf("assignment=%d", assignment.ID)
+	if err := s.careTeamRepo.End(assignment.ID, now, event); err != nil {
+		return nil, err
+	}
+
+	return assignment, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockCareTeamRepository is a mock implementation of CareTeamRepository
+type MockCareTeamRepository struct {
+	mock.Mock
+}
+
+func (m *MockCareTeamRepository) Assign(assignment *models.CareTeamAssignment, event *models.AuditEvent) error {
+	args := m.Called(assignment, event)
+	return args.Error(0)
+}
+
+func (m *MockCareTeamRepository) FindByID(id uint) (*models.CareTeamAssignment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CareTeamAssignment), args.Error(1)
+}
+
+func (m *MockCareTeamRepository) FindByPatient(patientID uint) ([]models.CareTeamAssignment, error) {
+	args := m.Called(patientID)
+	return args.Get(0).([]models.CareTeamAssignment), args.Error(1)
+}
+
+func (m *MockCareTeamRepository) IsMember(patientID, clinicianID uint, at time.Time) (bool, error) {
+	args := m.Called(patientID, clinicianID, at)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCareTeamRepository) End(id uint, at time.Time, event *models.AuditEvent) error {
+	args := m.Called(id, at, event)
+	return args.Error(0)
+}
+
+func (m *MockCareTeamRepository) RecordOverride(override *models.AccessOverride) error {
+	args := m.Called(override)
+	return args.Error(0)
+}
+
+// doctorAccessor is limited to their care teams but may override
+var doctorAccessor = PatientAccessor{UserID: 5, CanOverride: true}
+
+func TestGetPatient_DoctorNotOnCareTeam(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(false, nil)
+
+	service := NewPatientService(mockRepo, mockCareTeamRepo, newMockAuditRepository())
+
+	result, err := service.GetPatient(doctorAccessor, 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, ErrPatientAccessDenied, err)
+	mockCareTeamRepo.AssertNotCalled(t, "RecordOverride", mock.Anything)
+}
+
+func TestGetPatient_OverrideIsRecorded(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(false, nil)
+	mockCareTeamRepo.On("RecordOverride", mock.MatchedBy(func(o *models.AccessOverride) bool {
+		return o.UserID == 5 && o.PatientID == 1 && o.Action == "read" && o.Reason == "covering for Dr. Smith"
+	})).Return(nil)
+
+	service := NewPatientService(mockRepo, mockCareTeamRepo, newMockAuditRepository())
+
+	accessor := doctorAccessor
+	accessor.OverrideReason = "covering for Dr. Smith"
+	result, err := service.GetPatient(accessor, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	mockCareTeamRepo.AssertExpectations(t)
+}
+
+func TestGetAllPatients_DoctorSeesCareTeamOnly(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindAll", models.PatientFilter{CareTeamMemberID: 5}, 10, 0).Return([]models.Patient{{ID: 3}}, int64(1), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.GetAllPatients(doctorAccessor, models.PatientFilter{}, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.TotalItems)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreatePatient_DoctorBecomesPrimary(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Patient"), mock.MatchedBy(func(a *models.CareTeamAssignment) bool {
+		return a.ClinicianID == 5 && a.Role == models.CareTeamPrimary && a.AssignedBy == 5
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionCreate && e.Details == "primary=5"
+	})).Return(nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	_, err := service.CreatePatient(doctorAccessor, models.CreatePatientRequest{FirstName: "Walk", LastName: "In"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCareTeam_DoctorNotOnCareTeam(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(false, nil)
+	patientService := NewPatientService(mockPatientRepo, mockCareTeamRepo, newMockAuditRepository())
+
+	service := NewCareTeamService(mockCareTeamRepo, patientService, mockPatientRepo, new(MockUserRepository), newMockAuditRepository())
+
+	result, err := service.GetCareTeam(doctorAccessor, 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, ErrPatientAccessDenied, err)
+	mockCareTeamRepo.AssertNotCalled(t, "FindByPatient", mock.Anything)
+}
+
+func TestAssign_PrimaryEndsPreviousPrimary(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Role: models.RoleDoctor}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("Assign", mock.AnythingOfType("*models.CareTeamAssignment"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionCareTeamAssign && *e.PatientID == 1 && e.ActorID == 2
+	})).Return(nil)
+
+	service := NewCareTeamService(mockCareTeamRepo, nil, mockPatientRepo, mockUserRepo, newMockAuditRepository())
+
+	assignment, err := service.Assign(PatientAccessor{UserID: 2, AllPatients: true}, 1, models.AssignClinicianRequest{ClinicianID: 5, Role: models.CareTeamPrimary})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), assignment.ClinicianID)
+	assert.Equal(t, uint(2), assignment.AssignedBy)
+	mockCareTeamRepo.AssertExpectations(t)
+}
+
+func TestAssign_ReceptionistIsNotAClinician(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(3)).Return(&models.User{ID: 3, Role: models.RoleReceptionist}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+
+	service := NewCareTeamService(mockCareTeamRepo, nil, mockPatientRepo, mockUserRepo, newMockAuditRepository())
+
+	_, err := service.Assign(PatientAccessor{UserID: 2, AllPatients: true}, 1, models.AssignClinicianRequest{ClinicianID: 3, Role: models.CareTeamConsulting})
+
+	assert.Equal(t, ErrInvalidClinician, err)
+	mockCareTeamRepo.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything)
+}
+
+func TestAssign_InvalidDateRange(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Role: models.RoleDoctor}, nil)
+
+	service := NewCareTeamService(new(MockCareTeamRepository), nil, mockPatientRepo, mockUserRepo, newMockAuditRepository())
+
+	start := time.Now()
+	end := start.Add(-time.Hour)
+	_, err := service.Assign(PatientAccessor{UserID: 2, AllPatients: true}, 1, models.AssignClinicianRequest{
+		ClinicianID: 5,
+		Role:        models.CareTeamConsulting,
+		StartDate:   &start,
+		EndDate:     &end,
+	})
+
+	assert.Equal(t, ErrInvalidDateRange, err)
+}
+
+func TestUnassign_WrongPatient(t *testing.T) {
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("FindByID", uint(9)).Return(&models.CareTeamAssignment{ID: 9, PatientID: 2}, nil)
+
+	service := NewCareTeamService(mockCareTeamRepo, nil, new(MockPatientRepository), new(MockUserRepository), newMockAuditRepository())
+
+	_, err := service.Unassign(PatientAccessor{UserID: 2, AllPatients: true}, 1, 9)
+
+	assert.Equal(t, ErrAssignmentNotFound, err)
+	mockCareTeamRepo.AssertNotCalled(t, "End", mock.Anything, mock.Anything, mock.Anything)
+}
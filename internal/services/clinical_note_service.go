@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrClinicalNoteNotFound  = errors.New("clinical note not found")
+	ErrClinicalNoteSigned    = errors.New("clinical note is signed; add an addendum instead")
+	ErrClinicalNoteNotSigned = errors.New("clinical note is a draft; edit it instead")
+	ErrNotNoteAuthor         = errors.New("only the author can edit a draft note")
+	ErrEmptyClinicalNote     = errors.New("clinical note has no content")
+)
+
+// ClinicalNoteRepository defines the clinical note data operations used by the services
+type ClinicalNoteRepository interface {
+	Create(note *models.ClinicalNote, event *models.AuditEvent) error
+	FindByID(id uint) (*models.ClinicalNote, error)
+	FindByPatient(patientID uint, encounterID *uint) ([]models.ClinicalNote, error)
+	UpdateDraft(note *models.ClinicalNote, event *models.AuditEvent) (bool, error)
+	CreateAddendum(addendum *models.NoteAddendum, event *models.AuditEvent) error
+}
+
+// ClinicalNoteService handles the SOAP notes written during encounters
+type ClinicalNoteService struct {
+	noteRepo         ClinicalNoteRepository
+	patientService   *PatientService
+	encounterService *EncounterService
+	auditRepo        AuditRepository
+}
+
+// NewClinicalNoteService creates a new ClinicalNoteService
+func NewClinicalNoteService(noteRepo ClinicalNoteRepository, patientService *PatientService, encounterService *EncounterService, auditRepo AuditRepository) *ClinicalNoteService {
+	return &ClinicalNoteService{
+		noteRepo:         noteRepo,
+		patientService:   patientService,
+		encounterService: encounterService,
+		auditRepo:        auditRepo,
+	}
+}
+
+// GetNotes gets the notes of a patient, optionally only those of one
+// encounter
+func (s *ClinicalNoteService) GetNotes(accessor PatientAccessor, patientID uint, encounterID *uint) ([]models.ClinicalNote, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.FindByPatient(patientID, encounterID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "notes"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// GetNote gets a note of a patient with its addenda
+func (s *ClinicalNoteService) GetNote(accessor PatientAccessor, patientID, noteID uint) (*models.ClinicalNote, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	note, err := s.findNote(patientID, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = fmt.Sprintf("note=%d", note.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// GetLegacyNotes gets the free-text notes of a patient written before
+// clinical notes existed. They can no longer be changed.
+func (s *ClinicalNoteService) GetLegacyNotes(accessor PatientAccessor, patientID uint) (*models.LegacyNotes, error) {
+	patient, err := s.patientService.findAccessible(accessor, patientID, models.AuditActionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "legacy notes"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return &models.LegacyNotes{PatientID: patient.ID, Notes: patient.Notes}, nil
+}
+
+// CreateNote starts a draft note for an encounter of a patient
+func (s *ClinicalNoteService) CreateNote(accessor PatientAccessor, patientID uint, req models.CreateClinicalNoteRequest) (*models.ClinicalNote, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionNoteCreate); err != nil {
+		return nil, err
+	}
+	if err := s.encounterService.checkAttachable(patientID, &req.EncounterID); err != nil {
+		return nil, err
+	}
+
+	note := &models.ClinicalNote{
+		PatientID:   patientID,
+		EncounterID: req.EncounterID,
+		AuthorID:    accessor.UserID,
+		Status:      models.ClinicalNoteStatusDraft,
+	}
+	note.Apply(req.ClinicalNoteRequest)
+
+	event := newAuditEvent(accessor, models.AuditActionNoteCreate, patientID, models.DiffRecords(nil, note))
+	if err := s.noteRepo.Create(note, event); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// UpdateNote replaces the sections of a draft note. Only the author may
+// edit a draft.
+func (s *ClinicalNoteService) UpdateNote(accessor PatientAccessor, patientID, noteID uint, req models.ClinicalNoteRequest) (*models.ClinicalNote, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionNoteUpdate); err != nil {
+		return nil, err
+	}
+
+	note, err := s.findDraft(patientID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.AuthorID != accessor.UserID {
+		return nil, ErrNotNoteAuthor
+	}
+
+	before := *note
+	note.Apply(req)
+
+	event := newAuditEvent(accessor, models.AuditActionNoteUpdate, patientID, models.DiffRecords(&before, note))
+	event.Details = fmt.Sprintf("note=%d", note.ID)
+	if err := s.updateDraft(note, event); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// SignNote signs a draft note, locking it and recording who signed it and
+// when
+func (s *ClinicalNoteService) SignNote(accessor PatientAccessor, patientID, noteID uint) (*models.ClinicalNote, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionNoteSign); err != nil {
+		return nil, err
+	}
+
+	note, err := s.findDraft(patientID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.IsEmpty() {
+		return nil, ErrEmptyClinicalNote
+	}
+
+	before := *note
+	now := time.Now()
+	note.Status = models.ClinicalNoteStatusSigned
+	note.SignedBy = &accessor.UserID
+	note.SignedAt = &now
+
+	event := newAuditEvent(accessor, models.AuditActionNoteSign, patientID, models.DiffRecords(&before, note))
+	event.Details = fmt.Sprintf("note=%d", note.ID)
+	if err := s.updateDraft(note, event); err != nil {
+		return nil, err
+	}
+
+	return note, nil
+}
+
+// AddAddendum adds a correction or addition to a signed note
+func (s *ClinicalNoteService) AddAddendum(accessor PatientAccessor, patientID, noteID uint, req models.NoteAddendumRequest) (*models.ClinicalNote, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionNoteAddendum); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, ErrEmptyClinicalNote
+	}
+
+	note, err := s.findNote(patientID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.Status != models.ClinicalNoteStatusSigned {
+		return nil, ErrClinicalNoteNotSigned
+	}
+
+	addendum := &models.NoteAddendum{
+		NoteID:   note.ID,
+		AuthorID: accessor.UserID,
+		Body:     req.Body,
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionNoteAddendum, patientID, models.DiffRecords(nil, addendum))
+	event.Details = fmt.Sprintf("note=%d", note.ID)
+	if err := s.noteRepo.CreateAddendum(addendum, event); err != nil {
+		return nil, err
+	}
+
+	note.Addenda = append(note.Addenda, *addendum)
+	return note, nil
+}
+
+// updateDraft stores a draft note, failing if it was signed in the meantime
+func (s *ClinicalNoteService) updateDraft(note *models.ClinicalNote, event *models.AuditEvent) error {
+	updated, err := s.noteRepo.UpdateDraft(note, event)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrClinicalNoteSigned
+	}
+	return nil
+}
+
+// findDraft finds a draft note belonging to a patient
+func (s *ClinicalNoteService) findDraft(patientID, noteID uint) (*models.ClinicalNote, error) {
+	note, err := s.findNote(patientID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.Status != models.ClinicalNoteStatusDraft {
+		return nil, ErrClinicalNoteSigned
+	}
+	return note, nil
+}
+
+// findNote finds a note belonging to a patient
+func (s *ClinicalNoteService) findNote(patientID, noteID uint) (*models.ClinicalNote, error) {
+	note, err := s.noteRepo.FindByID(noteID)
+	if err != nil || note.PatientID != patientID {
+		return nil, ErrClinicalNoteNotFound
+	}
+	return note, nil
+}
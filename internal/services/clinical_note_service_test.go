@@ -0,0 +1,197 @@
+package services
+
+import (
+	"testing"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockClinicalNoteRepository is a mock implementation of ClinicalNoteRepository
+type MockClinicalNoteRepository struct {
+	mock.Mock
+}
+
+func (m *MockClinicalNoteRepository) Create(note *models.ClinicalNote, event *models.AuditEvent) error {
+	args := m.Called(note, event)
+	return args.Error(0)
+}
+
+func (m *MockClinicalNoteRepository) FindByID(id uint) (*models.ClinicalNote, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ClinicalNote), args.Error(1)
+}
+
+func (m *MockClinicalNoteRepository) FindByPatient(patientID uint, encounterID *uint) ([]models.ClinicalNote, error) {
+	args := m.Called(patientID, encounterID)
+	return args.Get(0).([]models.ClinicalNote), args.Error(1)
+}
+
+func (m *MockClinicalNoteRepository) UpdateDraft(note *models.ClinicalNote, event *models.AuditEvent) (bool, error) {
+	args := m.Called(note, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockClinicalNoteRepository) CreateAddendum(addendum *models.NoteAddendum, event *models.AuditEvent) error {
+	args := m.Called(addendum, event)
+	return args.Error(0)
+}
+
+// newTestClinicalNoteService creates a ClinicalNoteService for a patient on
+// the doctor's care team with encounter 3 in progress
+func newTestClinicalNoteService(noteRepo *MockClinicalNoteRepository) *ClinicalNoteService {
+	mockEncounterRepo := new(MockEncounterRepository)
+	mockEncounterRepo.On("FindByID", uint(3)).Return(&models.Encounter{
+		ID: 3, PatientID: 1, DoctorID: 5, Status: models.EncounterStatusInProgress,
+	}, nil)
+	encounterService := newTestEncounterService(mockEncounterRepo, new(MockUserRepository))
+	return NewClinicalNoteService(noteRepo, encounterService.patientService, encounterService, encounterService.auditRepo)
+}
+
+// draftNote returns a draft note of patient 1 written by the doctor
+func draftNote() *models.ClinicalNote {
+	return &models.ClinicalNote{
+		ID: 7, PatientID: 1, EncounterID: 3, AuthorID: 5, Status: models.ClinicalNoteStatusDraft,
+		Subjective: "Headache for three days", Assessment: "Tension headache",
+	}
+}
+
+func TestCreateNote(t *testing.T) {
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("Create", mock.AnythingOfType("*models.ClinicalNote"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionNoteCreate
+	})).Return(nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	note, err := service.CreateNote(doctorAccessor, 1, models.CreateClinicalNoteRequest{
+		EncounterID:         3,
+		ClinicalNoteRequest: models.ClinicalNoteRequest{Subjective: "Headache for three days"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClinicalNoteStatusDraft, note.Status)
+	assert.Equal(t, uint(5), note.AuthorID)
+	assert.Equal(t, uint(3), note.EncounterID)
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestCreateNote_UnknownEncounter(t *testing.T) {
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	service := newTestClinicalNoteService(mockNoteRepo)
+	service.encounterService.encounterRepo.(*MockEncounterRepository).On("FindByID", uint(4)).Return(&models.Encounter{
+		ID: 4, PatientID: 2, Status: models.EncounterStatusInProgress,
+	}, nil)
+
+	_, err := service.CreateNote(doctorAccessor, 1, models.CreateClinicalNoteRequest{EncounterID: 4})
+
+	assert.ErrorIs(t, err, ErrEncounterNotFound)
+	mockNoteRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSignNote(t *testing.T) {
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("FindByID", uint(7)).Return(draftNote(), nil)
+	mockNoteRepo.On("UpdateDraft", mock.AnythingOfType("*models.ClinicalNote"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionNoteSign && e.Changes["status"].After == models.ClinicalNoteStatusSigned
+	})).Return(true, nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	note, err := service.SignNote(doctorAccessor, 1, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ClinicalNoteStatusSigned, note.Status)
+	assert.Equal(t, uint(5), *note.SignedBy)
+	assert.NotNil(t, note.SignedAt)
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestSignNote_Empty(t *testing.T) {
+	empty := draftNote()
+	empty.Subjective, empty.Assessment = "", " "
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("FindByID", uint(7)).Return(empty, nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	_, err := service.SignNote(doctorAccessor, 1, 7)
+
+	assert.ErrorIs(t, err, ErrEmptyClinicalNote)
+}
+
+func TestUpdateNote_Signed(t *testing.T) {
+	signed := draftNote()
+	signed.Status = models.ClinicalNoteStatusSigned
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("FindByID", uint(7)).Return(signed, nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	_, err := service.UpdateNote(doctorAccessor, 1, 7, models.ClinicalNoteRequest{Plan: "Ibuprofen"})
+	assert.ErrorIs(t, err, ErrClinicalNoteSigned)
+
+	_, err = service.SignNote(doctorAccessor, 1, 7)
+	assert.ErrorIs(t, err, ErrClinicalNoteSigned)
+	mockNoteRepo.AssertNotCalled(t, "UpdateDraft", mock.Anything, mock.Anything)
+}
+
+func TestUpdateNote_SignedConcurrently(t *testing.T) {
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("FindByID", uint(7)).Return(draftNote(), nil)
+	mockNoteRepo.On("UpdateDraft", mock.Anything, mock.Anything).Return(false, nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	_, err := service.UpdateNote(doctorAccessor, 1, 7, models.ClinicalNoteRequest{Plan: "Ibuprofen"})
+
+	assert.ErrorIs(t, err, ErrClinicalNoteSigned)
+}
+
+func TestUpdateNote_NotAuthor(t *testing.T) {
+	note := draftNote()
+	note.AuthorID = 6
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("FindByID", uint(7)).Return(note, nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	_, err := service.UpdateNote(doctorAccessor, 1, 7, models.ClinicalNoteRequest{Plan: "Ibuprofen"})
+
+	assert.ErrorIs(t, err, ErrNotNoteAuthor)
+}
+
+func TestAddAddendum(t *testing.T) {
+	signed := draftNote()
+	signed.Status = models.ClinicalNoteStatusSigned
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("FindByID", uint(7)).Return(signed, nil)
+	mockNoteRepo.On("CreateAddendum", mock.AnythingOfType("*models.NoteAddendum"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionNoteAddendum && e.Details == "note=7"
+	})).Return(nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	note, err := service.AddAddendum(doctorAccessor, 1, 7, models.NoteAddendumRequest{Body: "Headache for five days, not three"})
+
+	assert.NoError(t, err)
+	assert.Len(t, note.Addenda, 1)
+	assert.Equal(t, "Tension headache", note.Assessment)
+	mockNoteRepo.AssertExpectations(t)
+}
+
+func TestAddAddendum_Draft(t *testing.T) {
+	mockNoteRepo := new(MockClinicalNoteRepository)
+	mockNoteRepo.On("FindByID", uint(7)).Return(draftNote(), nil)
+
+	service := newTestClinicalNoteService(mockNoteRepo)
+
+	_, err := service.AddAddendum(doctorAccessor, 1, 7, models.NoteAddendumRequest{Body: "Correction"})
+
+	assert.ErrorIs(t, err, ErrClinicalNoteNotSigned)
+}
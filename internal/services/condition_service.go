@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrConditionNotFound = errors.New("condition not found")
+	ErrUnknownICD10Code  = errors.New("unknown ICD-10 code")
+)
+
+// ConditionRepository defines the condition data operations used by the ConditionService
+type ConditionRepository interface {
+	Create(condition *models.Condition, event *models.AuditEvent) error
+	FindByID(id uint) (*models.Condition, error)
+	FindByPatient(patientID uint) ([]models.Condition, error)
+	Update(condition *models.Condition, event *models.AuditEvent) error
+	Delete(id uint, event *models.AuditEvent) error
+}
+
+// ConditionService handles the problem list of patients
+type ConditionService struct {
+	conditionRepo    ConditionRepository
+	patientService   *PatientService
+	encounterService *EncounterService
+	icd10Service     *ICD10Service
+	auditRepo        AuditRepository
+}
+
+// NewConditionService creates a new ConditionService
+func NewConditionService(conditionRepo ConditionRepository, patientService *PatientService, encounterService *EncounterService,
+	icd10Service *ICD10Service, auditRepo AuditRepository) *ConditionService {
+	return &ConditionService{
+		conditionRepo:    conditionRepo,
+		patientService:   patientService,
+		encounterService: encounterService,
+		icd10Service:     icd10Service,
+		auditRepo:        auditRepo,
+	}
+}
+
+// GetConditions gets the problem list of a patient
+func (s *ConditionService) GetConditions(accessor PatientAccessor, patientID uint) ([]models.Condition, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	conditions, err := s.conditionRepo.FindByPatient(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "conditions"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return conditions, nil
+}
+
+// GetCondition gets a condition of a patient
+func (s *ConditionService) GetCondition(accessor PatientAccessor, patientID, conditionID uint) (*models.Condition, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	condition, err := s.findCondition(patientID, conditionID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = fmt.Sprintf("condition=%d", condition.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return condition, nil
+}
+
+// CreateCondition diagnoses a patient with a condition. The accessor is
+// recorded as the diagnosing doctor.
+func (s *ConditionService) CreateCondition(accessor PatientAccessor, patientID uint, req models.ConditionRequest) (*models.Condition, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionConditionCreate); err != nil {
+		return nil, err
+	}
+
+	condition := &models.Condition{
+		PatientID:   patientID,
+		DiagnosedBy: accessor.UserID,
+	}
+	if err := s.apply(condition, req); err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionConditionCreate, patientID, models.DiffRecords(nil, condition))
+	if err := s.conditionRepo.Create(condition, event); err != nil {
+		return nil, err
+	}
+
+	return condition, nil
+}
+
+// UpdateCondition replaces the details of a condition of a patient
+func (s *ConditionService) UpdateCondition(accessor PatientAccessor, patientID, conditionID uint, req models.ConditionRequest) (*models.Condition, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionConditionUpdate); err != nil {
+		return nil, err
+	}
+
+	condition, err := s.findCondition(patientID, conditionID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *condition
+	if err := s.apply(condition, req); err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionConditionUpdate, patientID, models.DiffRecords(&before, condition))
+	event.Details = fmt.Sprintf("condition=%d", condition.ID)
+	if err := s.conditionRepo.Update(condition, event); err != nil {
+		return nil, err
+	}
+
+	return condition, nil
+}
+
+// DeleteCondition deletes a condition entered in error
+func (s *ConditionService) DeleteCondition(accessor PatientAccessor, patientID, conditionID uint) error {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionConditionDelete); err != nil {
+		return err
+	}
+
+	condition, err := s.findCondition(patientID, conditionID)
+	if err != nil {
+		return err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionConditionDelete, patientID, models.DiffRecords(condition, nil))
+	event.Details = fmt.Sprintf("condition=%d", condition.ID)
+	return s.conditionRepo.Delete(condition.ID, event)
+}
+
+// SearchICD10Codes finds ICD-10 codes by code prefix or description
+func (s *ConditionService) SearchICD10Codes(query string, limit int) []models.ICD10Code {
+	return s.icd10Service.Search(query, limit)
+}
+
+// apply validates a condition request and applies it to a condition
+func (s *ConditionService) apply(condition *models.Condition, req models.ConditionRequest) error {
+	code, ok := s.icd10Service.Lookup(req.Code)
+	if !ok {
+		return ErrUnknownICD10Code
+	}
+	if req.OnsetDate != nil && req.ResolutionDate != nil && req.ResolutionDate.Before(*req.OnsetDate) {
+		return ErrInvalidDateRange
+	}
+	if err := s.encounterService.checkAttachable(condition.PatientID, req.EncounterID); err != nil {
+		return err
+	}
+
+	condition.Apply(req, code)
+	return nil
+}
+
+// findCondition finds a condition belonging to a patient
+func (s *ConditionService) findCondition(patientID, conditionID uint) (*models.Condition, error) {
+	condition, err := s.conditionRepo.FindByID(conditionID)
+	if err != nil || condition.PatientID != patientID {
+		return nil, ErrConditionNotFound
+	}
+	return condition, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockConditionRepository is a mock implementation of ConditionRepository
+type MockConditionRepository struct {
+	mock.Mock
+}
+
+func (m *MockConditionRepository) Create(condition *models.Condition, event *models.AuditEvent) error {
+	args := m.Called(condition, event)
+	return args.Error(0)
+}
+
+func (m *MockConditionRepository) FindByID(id uint) (*models.Condition, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Condition), args.Error(1)
+}
+
+func (m *MockConditionRepository) FindByPatient(patientID uint) ([]models.Condition, error) {
+	args := m.Called(patientID)
+	return args.Get(0).([]models.Condition), args.Error(1)
+}
+
+func (m *MockConditionRepository) Update(condition *models.Condition, event *models.AuditEvent) error {
+	args := m.Called(condition, event)
+	return args.Error(0)
+}
+
+func (m *MockConditionRepository) Delete(id uint, event *models.AuditEvent) error {
+	args := m.Called(id, event)
+	return args.Error(0)
+}
+
+// testICD10Codes is a small ICD-10 code set
+var testICD10Codes = []models.ICD10Code{
+	{Code: "I10", Description: "Essential (primary) hypertension"},
+	{Code: "E119", Description: "Type 2 diabetes mellitus without complications"},
+	{Code: "E1165", Description: "Type 2 diabetes mellitus with hyperglycemia"},
+	{Code: "E109", Description: "Type 1 diabetes mellitus without complications"},
+	{Code: "O2441", Description: "Gestational diabetes mellitus in pregnancy"},
+}
+
+// newTestConditionService creates a ConditionService for a patient on the
+// doctor's care team
+func newTestConditionService(conditionRepo *MockConditionRepository) *ConditionService {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(true, nil)
+	auditRepo := newMockAuditRepository()
+
+	patientService := NewPatientService(mockPatientRepo, mockCareTeamRepo, auditRepo)
+	encounterService := NewEncounterService(new(MockEncounterRepository), patientService, new(MockUserRepository), auditRepo)
+	return NewConditionService(conditionRepo, patientService, encounterService, NewICD10Service(testICD10Codes), auditRepo)
+}
+
+func TestCreateCondition_Defaults(t *testing.T) {
+	mockConditionRepo := new(MockConditionRepository)
+	mockConditionRepo.On("Create", mock.AnythingOfType("*models.Condition"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionConditionCreate && e.Changes["code"].After == "E11.9"
+	})).Return(nil)
+
+	service := newTestConditionService(mockConditionRepo)
+
+	condition, err := service.CreateCondition(doctorAccessor, 1, models.ConditionRequest{Code: "e119"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "E11.9", condition.Code)
+	assert.Equal(t, "Type 2 diabetes mellitus without complications", condition.Description)
+	assert.Equal(t, models.ConditionStatusActive, condition.ClinicalStatus)
+	assert.Equal(t, uint(5), condition.DiagnosedBy)
+	mockConditionRepo.AssertExpectations(t)
+}
+
+func TestCreateCondition_UnknownCode(t *testing.T) {
+	mockConditionRepo := new(MockConditionRepository)
+
+	service := newTestConditionService(mockConditionRepo)
+
+	_, err := service.CreateCondition(doctorAccessor, 1, models.ConditionRequest{Code: "E11.999"})
+
+	assert.Equal(t, ErrUnknownICD10Code, err)
+	mockConditionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateCondition_ResolutionBeforeOnset(t *testing.T) {
+	mockConditionRepo := new(MockConditionRepository)
+	mockConditionRepo.On("FindByID", uint(4)).Return(&models.Condition{ID: 4, PatientID: 1, Code: "I10"}, nil)
+
+	service := newTestConditionService(mockConditionRepo)
+
+	onset := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	resolved := onset.AddDate(0, -1, 0)
+	_, err := service.UpdateCondition(doctorAccessor, 1, 4, models.ConditionRequest{
+		Code:           "I10",
+		ClinicalStatus: models.ConditionStatusResolved,
+		OnsetDate:      &onset,
+		ResolutionDate: &resolved,
+	})
+
+	assert.Equal(t, ErrInvalidDateRange, err)
+	mockConditionRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestGetAllPatients_ConditionFilterKeepsCareTeam(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindAll", models.PatientFilter{CareTeamMemberID: 5, ConditionCode: "E11"}, 10, 0).Return([]models.Patient{{ID: 3}}, int64(1), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.GetAllPatients(doctorAccessor, models.PatientFilter{ConditionCode: "E11"}, 1, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.TotalItems)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestICD10Search_CodePrefixThenDescription(t *testing.T) {
+	service := NewICD10Service(testICD10Codes)
+
+	results := service.Search("E11", 10)
+	assert.Equal(t, []string{"E11.65", "E11.9"}, icd10CodeList(results))
+
+	results = service.Search("diabetes without", 10)
+	assert.Equal(t, []string{"E10.9", "E11.9"}, icd10CodeList(results))
+
+	results = service.Search("diabetes", 2)
+	assert.Len(t, results, 2)
+}
+
+func TestICD10Lookup_IgnoresDotAndCase(t *testing.T) {
+	service := NewICD10Service(testICD10Codes)
+
+	code, ok := service.Lookup("o24.41")
+	assert.True(t, ok)
+	assert.Equal(t, "O24.41", code.Code)
+
+	_, ok = service.Lookup("Z99")
+	assert.False(t, ok)
+}
+
+func TestLoadICD10Codes_ShippedCodeFile(t *testing.T) {
+	codes, err := LoadICD10Codes(filepath.Join("..", "..", "data", "icd10cm_codes.txt"))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, codes)
+	service := NewICD10Service(codes)
+	_, ok := service.Lookup("E11.9")
+	assert.True(t, ok)
+}
+
+// icd10CodeList returns the codes of ICD-10 search results
+func icd10CodeList(codes []models.ICD10Code) []string {
+	list := make([]string, len(codes))
+	for i, code := range codes {
+		list[i] = code.Code
+	}
+	return list
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrInvalidEmergencyGrant = errors.New("emergency access grant is invalid or expired")
+	ErrEmergencyReason       = errors.New("a justification is required for emergency access")
+)
+
+// emergencyReadAction is the access override action recorded for every read
+// under an emergency access grant
+const emergencyReadAction = "emergency_read"
+
+// EmergencyAccessRepository defines the emergency access data operations used by the EmergencyAccessService
+type EmergencyAccessRepository interface {
+	Create(grant *models.EmergencyAccessGrant, event *models.AuditEvent) error
+	FindByID(id uint) (*models.EmergencyAccessGrant, error)
+	FindAll(filter models.EmergencyAccessFilter, limit, offset int) ([]models.EmergencyAccessGrant, int64, error)
+}
+
+// EmergencyAccessService handles break-the-glass access to patients outside
+// the user's care team
+type EmergencyAccessService struct {
+	emergencyRepo  EmergencyAccessRepository
+	patientRepo    PatientRepository
+	careTeamRepo   CareTeamRepository
+	medicationRepo MedicationRepository
+	auditRepo      AuditRepository
+	grantTTL       time.Duration
+}
+
+// NewEmergencyAccessService creates a new EmergencyAccessService
+func NewEmergencyAccessService(emergencyRepo EmergencyAccessRepository, patientRepo PatientRepository,
+	careTeamRepo CareTeamRepository, medicationRepo MedicationRepository, auditRepo AuditRepository, grantTTL time.Duration) *EmergencyAccessService {
+	return &EmergencyAccessService{
+		emergencyRepo:  emergencyRepo,
+		patientRepo:    patientRepo,
+		careTeamRepo:   careTeamRepo,
+		medicationRepo: medicationRepo,
+		auditRepo:      auditRepo,
+		grantTTL:       grantTTL,
+	}
+}
+
+// BreakGlass grants a user time-boxed emergency access to a patient. The
+// grant and its justification are logged permanently and recorded in the
+// audit trail.
+func (s *EmergencyAccessService) BreakGlass(accessor PatientAccessor, patientID uint, req models.BreakGlassRequest) (*models.EmergencyAccessGrant, error) {
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrEmergencyReason
+	}
+
+	if _, err := s.patientRepo.FindByID(patientID); err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	grant := &models.EmergencyAccessGrant{
+		UserID:    accessor.UserID,
+		PatientID: patientID,
+		Reason:    reason,
+		ExpiresAt: time.Now().Add(s.grantTTL),
+	}
+	event := newAuditEvent(accessor, models.AuditActionBreakGlass, patientID, models.DiffRecords(nil, grant))
+	if err := s.emergencyRepo.Create(grant, event); err != nil {
+		return nil, err
+	}
+
+	return grant, nil
+}
+
+// ActiveGrant gets a grant held by a user that has not expired yet
+func (s *EmergencyAccessService) ActiveGrant(userID, grantID uint) (*models.EmergencyAccessGrant, error) {
+	grant, err := s.emergencyRepo.FindByID(grantID)
+	if err != nil {
+		return nil, ErrInvalidEmergencyGrant
+	}
+	if grant.UserID != userID || !grant.IsActiveAt(time.Now()) {
+		return nil, ErrInvalidEmergencyGrant
+	}
+
+	return grant, nil
+}
+
+// GetEmergencySummary gets the allergies and active medications of the
+// patient covered by a grant. Every read is recorded.
+func (s *EmergencyAccessService) GetEmergencySummary(accessor PatientAccessor, grant *models.EmergencyAccessGrant, patientID uint) (*models.EmergencySummary, error) {
+	if grant == nil || grant.UserID != accessor.UserID || grant.PatientID != patientID || !grant.IsActiveAt(time.Now()) {
+		return nil, ErrInvalidEmergencyGrant
+	}
+
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	medications, err := s.medicationRepo.FindByPatient(patientID, models.MedicationStatusActive)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.careTeamRepo.RecordOverride(&models.AccessOverride{
+		UserID:    grant.UserID,
+		PatientID: patientID,
+		Action:    emergencyReadAction,
+		Reason:    fmt.Sprintf("emergency access grant %d: %s", grant.ID, grant.Reason),
+	}); err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionEmergencyRead, patientID, nil)
+	event.Details = fmt.Sprintf("grant=%d", grant.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return &models.EmergencySummary{
+		PatientID:      patient.ID,
+		FirstName:      patient.FirstName,
+		LastName:       patient.LastName,
+		DateOfBirth:    patient.DateOfBirth,
+		AllergyList:    patient.AllergyList,
+		Medications:    medications,
+		GrantExpiresAt: grant.ExpiresAt,
+	}, nil
+}
+
+// GetEmergencyAccessReport lists emergency access grants for compliance review
+func (s *EmergencyAccessService) GetEmergencyAccessReport(filter models.EmergencyAccessFilter, page, pageSize int) (*PaginationResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	grants, totalItems, err := s.emergencyRepo.FindAll(filter, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(totalItems) + pageSize - 1) / pageSize
+
+	return &PaginationResponse{
+		TotalItems: totalItems,
+		Items:      grants,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
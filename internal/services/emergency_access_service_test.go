@@ -0,0 +1,157 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEmergencyAccessRepository is a mock implementation of EmergencyAccessRepository
+type MockEmergencyAccessRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmergencyAccessRepository) Create(grant *models.EmergencyAccessGrant, event *models.AuditEvent) error {
+	args := m.Called(grant, event)
+	return args.Error(0)
+}
+
+func (m *MockEmergencyAccessRepository) FindByID(id uint) (*models.EmergencyAccessGrant, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EmergencyAccessGrant), args.Error(1)
+}
+
+func (m *MockEmergencyAccessRepository) FindAll(filter models.EmergencyAccessFilter, limit, offset int) ([]models.EmergencyAccessGrant, int64, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]models.EmergencyAccessGrant), args.Get(1).(int64), args.Error(2)
+}
+
+func TestBreakGlass_Success(t *testing.T) {
+	mockEmergencyRepo := new(MockEmergencyAccessRepository)
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockEmergencyRepo.On("Create", mock.AnythingOfType("*models.EmergencyAccessGrant"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionBreakGlass && *e.PatientID == 1 && e.ActorID == 5 &&
+			e.Changes["reason"].After == "unconscious patient in ED"
+	})).Return(nil)
+
+	service := NewEmergencyAccessService(mockEmergencyRepo, mockPatientRepo, new(MockCareTeamRepository), new(MockMedicationRepository), newMockAuditRepository(), time.Hour)
+
+	grant, err := service.BreakGlass(doctorAccessor, 1, models.BreakGlassRequest{Reason: "  unconscious patient in ED  "})
+
+	assert.NoError(t, err)
+	mockEmergencyRepo.AssertExpectations(t)
+	assert.Equal(t, uint(5), grant.UserID)
+	assert.Equal(t, "unconscious patient in ED", grant.Reason)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), grant.ExpiresAt, time.Minute)
+}
+
+func TestActiveGrant_Expired(t *testing.T) {
+	mockEmergencyRepo := new(MockEmergencyAccessRepository)
+	mockEmergencyRepo.On("FindByID", uint(3)).Return(&models.EmergencyAccessGrant{
+		ID: 3, UserID: 5, PatientID: 1, ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	service := NewEmergencyAccessService(mockEmergencyRepo, new(MockPatientRepository), new(MockCareTeamRepository), new(MockMedicationRepository), newMockAuditRepository(), time.Hour)
+
+	grant, err := service.ActiveGrant(5, 3)
+
+	assert.Nil(t, grant)
+	assert.Equal(t, ErrInvalidEmergencyGrant, err)
+}
+
+func TestActiveGrant_OtherUser(t *testing.T) {
+	mockEmergencyRepo := new(MockEmergencyAccessRepository)
+	mockEmergencyRepo.On("FindByID", uint(3)).Return(&models.EmergencyAccessGrant{
+		ID: 3, UserID: 5, PatientID: 1, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+
+	service := NewEmergencyAccessService(mockEmergencyRepo, new(MockPatientRepository), new(MockCareTeamRepository), new(MockMedicationRepository), newMockAuditRepository(), time.Hour)
+
+	grant, err := service.ActiveGrant(6, 3)
+
+	assert.Nil(t, grant)
+	assert.Equal(t, ErrInvalidEmergencyGrant, err)
+}
+
+func TestGetEmergencySummary_RecordsRead(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{
+		ID: 1, AllergyList: []models.Allergy{{Substance: "penicillin"}},
+	}, nil)
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByPatient", uint(1), models.MedicationStatusActive).Return([]models.Medication{{DrugName: "warfarin"}}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("RecordOverride", mock.MatchedBy(func(o *models.AccessOverride) bool {
+		return o.UserID == 5 && o.PatientID == 1 && o.Action == emergencyReadAction
+	})).Return(nil)
+
+	service := NewEmergencyAccessService(new(MockEmergencyAccessRepository), mockPatientRepo, mockCareTeamRepo, mockMedicationRepo, newMockAuditRepository(), time.Hour)
+	grant := &models.EmergencyAccessGrant{ID: 3, UserID: 5, PatientID: 1, Reason: "anaphylaxis", ExpiresAt: time.Now().Add(time.Hour)}
+
+	summary, err := service.GetEmergencySummary(doctorAccessor, grant, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "penicillin", summary.AllergyList[0].Substance)
+	assert.Equal(t, "warfarin", summary.Medications[0].DrugName)
+	mockCareTeamRepo.AssertExpectations(t)
+}
+
+func TestGetEmergencySummary_OtherPatient(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+
+	service := NewEmergencyAccessService(new(MockEmergencyAccessRepository), mockPatientRepo, mockCareTeamRepo, new(MockMedicationRepository), newMockAuditRepository(), time.Hour)
+	grant := &models.EmergencyAccessGrant{ID: 3, UserID: 5, PatientID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+
+	summary, err := service.GetEmergencySummary(doctorAccessor, grant, 2)
+
+	assert.Nil(t, summary)
+	assert.Equal(t, ErrInvalidEmergencyGrant, err)
+	mockPatientRepo.AssertNotCalled(t, "FindByID", mock.Anything)
+	mockCareTeamRepo.AssertNotCalled(t, "RecordOverride", mock.Anything)
+}
+
+func TestGetPatient_EmergencyGrantOpensPatient(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(false, nil)
+	mockCareTeamRepo.On("RecordOverride", mock.MatchedBy(func(o *models.AccessOverride) bool {
+		return o.UserID == 5 && o.PatientID == 1 && o.Action == "read" && o.Reason == "emergency access grant 3: anaphylaxis"
+	})).Return(nil)
+
+	service := NewPatientService(mockPatientRepo, mockCareTeamRepo, newMockAuditRepository())
+
+	accessor := doctorAccessor
+	accessor.EmergencyGrant = &models.EmergencyAccessGrant{ID: 3, UserID: 5, PatientID: 1, Reason: "anaphylaxis", ExpiresAt: time.Now().Add(time.Hour)}
+	result, err := service.GetPatient(accessor, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	mockCareTeamRepo.AssertExpectations(t)
+}
+
+func TestGetPatient_EmergencyGrantForOtherPatient(t *testing.T) {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(2)).Return(&models.Patient{ID: 2}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(2), uint(5), mock.Anything).Return(false, nil)
+
+	service := NewPatientService(mockPatientRepo, mockCareTeamRepo, newMockAuditRepository())
+
+	accessor := doctorAccessor
+	accessor.EmergencyGrant = &models.EmergencyAccessGrant{ID: 3, UserID: 5, PatientID: 1, Reason: "anaphylaxis", ExpiresAt: time.Now().Add(time.Hour)}
+	result, err := service.GetPatient(accessor, 2)
+
+	assert.Nil(t, result)
+	assert.Equal(t, ErrPatientAccessDenied, err)
+	mockCareTeamRepo.AssertNotCalled(t, "RecordOverride", mock.Anything)
+}
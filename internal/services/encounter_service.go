@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrEncounterNotFound          = errors.New("encounter not found")
+	ErrInvalidEncounterDoctor     = errors.New("doctor not found or deactivated")
+	ErrInvalidEncounterTransition = errors.New("encounter cannot move to that status")
+	ErrEncounterNotOpen           = errors.New("encounter does not accept records in its status")
+)
+
+// EncounterRepository defines the encounter data operations used by the services
+type EncounterRepository interface {
+	Create(encounter *models.Encounter, event *models.AuditEvent) error
+	FindByID(id uint) (*models.Encounter, error)
+	FindRecords(encounter *models.Encounter) error
+	FindByPatient(patientID uint) ([]models.Encounter, error)
+	UpdateStatus(encounter *models.Encounter, from models.EncounterStatus, event *models.AuditEvent) (bool, error)
+}
+
+// EncounterService handles the visits of patients
+type EncounterService struct {
+	encounterRepo  EncounterRepository
+	patientService *PatientService
+	userRepo       UserRepository
+	auditRepo      AuditRepository
+}
+
+// NewEncounterService creates a new EncounterService
+func NewEncounterService(encounterRepo EncounterRepository, patientService *PatientService, userRepo UserRepository, auditRepo AuditRepository) *EncounterService {
+	return &EncounterService{
+		encounterRepo:  encounterRepo,
+		patientService: patientService,
+		userRepo:       userRepo,
+		auditRepo:      auditRepo,
+	}
+}
+
+// GetEncounters gets the encounters of a patient
+func (s *EncounterService) GetEncounters(accessor PatientAccessor, patientID uint) ([]models.Encounter, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	encounters, err := s.encounterRepo.FindByPatient(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "encounters"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return encounters, nil
+}
+
+// GetEncounter gets an encounter of a patient with the vitals, diagnoses and
+// prescriptions attached to it
+func (s *EncounterService) GetEncounter(accessor PatientAccessor, patientID, encounterID uint) (*models.Encounter, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	encounter, err := s.findEncounter(patientID, encounterID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.encounterRepo.FindRecords(encounter); err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = fmt.Sprintf("encounter=%d", encounter.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+// CreateEncounter plans a visit of a patient to a doctor, or checks in a
+// walk-in straight away
+func (s *EncounterService) CreateEncounter(accessor PatientAccessor, patientID uint, req models.CreateEncounterRequest) (*models.Encounter, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionEncounterCreate); err != nil {
+		return nil, err
+	}
+
+	doctor, err := s.userRepo.FindByID(req.DoctorID)
+	if err != nil || doctor.Role != models.RoleDoctor || !doctor.IsActive() {
+		return nil, ErrInvalidEncounterDoctor
+	}
+
+	encounter := &models.Encounter{
+		PatientID:      patientID,
+		DoctorID:       doctor.ID,
+		Type:           req.Type,
+		Status:         models.EncounterStatusPlanned,
+		PlannedStart:   req.PlannedStart,
+		ChiefComplaint: req.ChiefComplaint,
+		Location:       req.Location,
+		CreatedBy:      accessor.UserID,
+	}
+	if req.Arrived {
+		now := time.Now()
+		encounter.Status = models.EncounterStatusArrived
+		encounter.StartedAt = &now
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionEncounterCreate, patientID, models.DiffRecords(nil, encounter))
+	if err := s.encounterRepo.Create(encounter, event); err != nil {
+		return nil, err
+	}
+
+	return encounter, nil
+}
+
+// CheckIn records that the patient of a planned encounter has arrived
+func (s *EncounterService) CheckIn(accessor PatientAccessor, patientID, encounterID uint) (*models.Encounter, error) {
+	return s.transition(accessor, patientID, encounterID, models.EncounterStatusArrived)
+}
+
+// StartEncounter records that the doctor has started seeing the patient
+func (s *EncounterService) StartEncounter(accessor PatientAccessor, patientID, encounterID uint) (*models.Encounter, error) {
+	return s.transition(accessor, patientID, encounterID, models.EncounterStatusInProgress)
+}
+
+// FinishEncounter closes an encounter in progress
+func (s *EncounterService) FinishEncounter(accessor PatientAccessor, patientID, encounterID uint) (*models.Encounter, error) {
+	return s.transition(accessor, patientID, encounterID, models.EncounterStatusFinished)
+}
+
+// CancelEncounter cancels an encounter that has not started
+func (s *EncounterService) CancelEncounter(accessor PatientAccessor, patientID, encounterID uint) (*models.Encounter, error) {
+	return s.transition(accessor, patientID, encounterID, models.EncounterStatusCancelled)
+}
+
+// transition moves an encounter to another status, recording when the
+// patient arrived and when the encounter finished
+func (s *EncounterService) transition(accessor PatientAccessor, patientID, encounterID uint, next models.EncounterStatus) (*models.Encounter, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionEncounterUpdate); err != nil {
+		return nil, err
+	}
+
+	encounter, err := s.findEncounter(patientID, encounterID)
+	if err != nil {
+		return nil, err
+	}
+	if !encounter.Status.CanTransitionTo(next) {
+		return nil, ErrInvalidEncounterTransition
+	}
+
+	before := *encounter
+	now := time.Now()
+	encounter.Status = next
+	switch next {
+	case models.EncounterStatusArrived:
+		encounter.StartedAt = &now
+	case models.EncounterStatusFinished:
+		encounter.EndedAt = &now
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionEncounterUpdate, patientID, models.DiffRecords(&before, encounter))
+	event.Details = fmt.Sprintf("encounter=%d", encounter.ID)
+	updated, err := s.encounterRepo.UpdateStatus(encounter, before.Status, event)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvalidEncounterTransition
+	}
+
+	return encounter, nil
+}
+
+// checkAttachable checks that records of a patient may be attached to an
+// encounter. A nil encounter ID attaches the records to no encounter.
+func (s *EncounterService) checkAttachable(patientID uint, encounterID *uint) error {
+	if encounterID == nil {
+		return nil
+	}
+
+	encounter, err := s.findEncounter(patientID, *encounterID)
+	if err != nil {
+		return err
+	}
+	if !encounter.Status.AcceptsRecords() {
+		return ErrEncounterNotOpen
+	}
+	return nil
+}
+
+// findEncounter finds an encounter belonging to a patient
+func (s *EncounterService) findEncounter(patientID, encounterID uint) (*models.Encounter, error) {
+	encounter, err := s.encounterRepo.FindByID(encounterID)
+	if err != nil || encounter.PatientID != patientID {
+		return nil, ErrEncounterNotFound
+	}
+	return encounter, nil
+}
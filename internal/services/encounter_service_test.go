@@ -0,0 +1,170 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockEncounterRepository is a mock implementation of EncounterRepository
+type MockEncounterRepository struct {
+	mock.Mock
+}
+
+func (m *MockEncounterRepository) Create(encounter *models.Encounter, event *models.AuditEvent) error {
+	args := m.Called(encounter, event)
+	return args.Error(0)
+}
+
+func (m *MockEncounterRepository) FindByID(id uint) (*models.Encounter, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) FindRecords(encounter *models.Encounter) error {
+	args := m.Called(encounter)
+	return args.Error(0)
+}
+
+func (m *MockEncounterRepository) FindByPatient(patientID uint) ([]models.Encounter, error) {
+	args := m.Called(patientID)
+	return args.Get(0).([]models.Encounter), args.Error(1)
+}
+
+func (m *MockEncounterRepository) UpdateStatus(encounter *models.Encounter, from models.EncounterStatus, event *models.AuditEvent) (bool, error) {
+	args := m.Called(encounter, from, event)
+	return args.Bool(0), args.Error(1)
+}
+
+// newTestEncounterService creates an EncounterService for patient 1, visible
+// to the receptionist and on the doctor's care team
+func newTestEncounterService(encounterRepo *MockEncounterRepository, userRepo *MockUserRepository) *EncounterService {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(true, nil)
+	auditRepo := newMockAuditRepository()
+
+	patientService := NewPatientService(mockPatientRepo, mockCareTeamRepo, auditRepo)
+	return NewEncounterService(encounterRepo, patientService, userRepo, auditRepo)
+}
+
+func TestCreateEncounter_WalkIn(t *testing.T) {
+	mockEncounterRepo := new(MockEncounterRepository)
+	mockEncounterRepo.On("Create", mock.AnythingOfType("*models.Encounter"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionEncounterCreate
+	})).Return(nil)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Role: models.RoleDoctor}, nil)
+
+	service := newTestEncounterService(mockEncounterRepo, mockUserRepo)
+
+	encounter, err := service.CreateEncounter(receptionistAccessor, 1, models.CreateEncounterRequest{
+		DoctorID:       5,
+		Type:           models.EncounterTypeEmergency,
+		ChiefComplaint: "Chest pain",
+		Arrived:        true,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.EncounterStatusArrived, encounter.Status)
+	assert.NotNil(t, encounter.StartedAt)
+	assert.Equal(t, uint(1), encounter.CreatedBy)
+	mockEncounterRepo.AssertExpectations(t)
+}
+
+func TestCreateEncounter_InvalidDoctor(t *testing.T) {
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(1)).Return(&models.User{ID: 1, Role: models.RoleReceptionist}, nil)
+	mockUserRepo.On("FindByID", uint(9)).Return(nil, errors.New("record not found"))
+
+	service := newTestEncounterService(new(MockEncounterRepository), mockUserRepo)
+
+	for _, doctorID := range []uint{1, 9} {
+		_, err := service.CreateEncounter(receptionistAccessor, 1, models.CreateEncounterRequest{
+			DoctorID: doctorID,
+			Type:     models.EncounterTypeOutpatient,
+		})
+		assert.ErrorIs(t, err, ErrInvalidEncounterDoctor)
+	}
+}
+
+func TestFinishEncounter(t *testing.T) {
+	mockEncounterRepo := new(MockEncounterRepository)
+	mockEncounterRepo.On("FindByID", uint(3)).Return(&models.Encounter{
+		ID: 3, PatientID: 1, DoctorID: 5, Status: models.EncounterStatusInProgress,
+	}, nil)
+	mockEncounterRepo.On("UpdateStatus", mock.AnythingOfType("*models.Encounter"), models.EncounterStatusInProgress, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionEncounterUpdate && e.Changes["status"].After == models.EncounterStatusFinished
+	})).Return(true, nil)
+
+	service := newTestEncounterService(mockEncounterRepo, new(MockUserRepository))
+
+	encounter, err := service.FinishEncounter(doctorAccessor, 1, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.EncounterStatusFinished, encounter.Status)
+	assert.NotNil(t, encounter.EndedAt)
+	mockEncounterRepo.AssertExpectations(t)
+}
+
+func TestEncounterTransition_Invalid(t *testing.T) {
+	mockEncounterRepo := new(MockEncounterRepository)
+	mockEncounterRepo.On("FindByID", uint(3)).Return(&models.Encounter{
+		ID: 3, PatientID: 1, DoctorID: 5, Status: models.EncounterStatusPlanned,
+	}, nil)
+
+	service := newTestEncounterService(mockEncounterRepo, new(MockUserRepository))
+
+	_, err := service.FinishEncounter(doctorAccessor, 1, 3)
+	assert.ErrorIs(t, err, ErrInvalidEncounterTransition)
+
+	_, err = service.StartEncounter(doctorAccessor, 1, 3)
+	assert.ErrorIs(t, err, ErrInvalidEncounterTransition)
+	mockEncounterRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEncounterTransition_LostRace(t *testing.T) {
+	mockEncounterRepo := new(MockEncounterRepository)
+	mockEncounterRepo.On("FindByID", uint(3)).Return(&models.Encounter{
+		ID: 3, PatientID: 1, DoctorID: 5, Status: models.EncounterStatusPlanned,
+	}, nil)
+	mockEncounterRepo.On("UpdateStatus", mock.Anything, models.EncounterStatusPlanned, mock.Anything).Return(false, nil)
+
+	service := newTestEncounterService(mockEncounterRepo, new(MockUserRepository))
+
+	_, err := service.CheckIn(receptionistAccessor, 1, 3)
+
+	assert.ErrorIs(t, err, ErrInvalidEncounterTransition)
+}
+
+func TestRecordVitals_EncounterNotAttachable(t *testing.T) {
+	mockEncounterRepo := new(MockEncounterRepository)
+	mockEncounterRepo.On("FindByID", uint(3)).Return(&models.Encounter{
+		ID: 3, PatientID: 1, Status: models.EncounterStatusCancelled,
+	}, nil)
+	mockEncounterRepo.On("FindByID", uint(4)).Return(&models.Encounter{
+		ID: 4, PatientID: 2, Status: models.EncounterStatusInProgress,
+	}, nil)
+	mockVitalRepo := new(MockVitalRepository)
+
+	service := newTestVitalService(mockVitalRepo)
+	service.encounterService = newTestEncounterService(mockEncounterRepo, new(MockUserRepository))
+
+	cancelled, other := uint(3), uint(4)
+	observations := []models.VitalInput{{Type: models.VitalPulse, Value: 72}}
+
+	_, err := service.RecordVitals(doctorAccessor, 1, models.RecordVitalsRequest{EncounterID: &cancelled, Observations: observations})
+	assert.ErrorIs(t, err, ErrEncounterNotOpen)
+
+	_, err = service.RecordVitals(doctorAccessor, 1, models.RecordVitalsRequest{EncounterID: &other, Observations: observations})
+	assert.ErrorIs(t, err, ErrEncounterNotFound)
+	mockVitalRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"healthcare-app/internal/models"
+)
+
+// ICD10Service looks up and searches the ICD-10 code set
+type ICD10Service struct {
+	codes  []models.ICD10Code
+	byCode map[string]int
+}
+
+// NewICD10Service creates a new ICD10Service
+func NewICD10Service(codes []models.ICD10Code) *ICD10Service {
+	s := &ICD10Service{
+		codes:  make([]models.ICD10Code, 0, len(codes)),
+		byCode: make(map[string]int, len(codes)),
+	}
+	for _, code := range codes {
+		code.Code = models.NormalizeICD10Code(code.Code)
+		if _, ok := s.byCode[code.Code]; ok {
+			continue
+		}
+		s.byCode[code.Code] = len(s.codes)
+		s.codes = append(s.codes, code)
+	}
+	sort.Slice(s.codes, func(i, j int) bool {
+		return s.codes[i].Code < s.codes[j].Code
+	})
+	for i, code := range s.codes {
+		s.byCode[code.Code] = i
+	}
+	return s
+}
+
+// LoadICD10Codes reads the ICD-10 code set from a CSV file with code and
+// description columns, or from a text file with one code per line followed
+// by its description, as in the CMS ICD-10-CM code files
+func LoadICD10Codes(path string) ([]models.ICD10Code, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening ICD-10 code file: %w", err)
+	}
+	defer file.Close()
+
+	var codes []models.ICD10Code
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		codes, err = readICD10CSV(file)
+	} else {
+		codes, err = readICD10Text(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading ICD-10 code file %s: %w", path, err)
+	}
+
+	return codes, nil
+}
+
+// readICD10CSV reads ICD-10 codes from CSV, skipping a header row
+func readICD10CSV(r io.Reader) ([]models.ICD10Code, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var codes []models.ICD10Code
+	for i, record := range records {
+		if len(record) < 2 {
+			return nil, fmt.Errorf("line %d: expected code and description", i+1)
+		}
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "code") {
+			continue
+		}
+		codes = append(codes, models.ICD10Code{
+			Code:        strings.TrimSpace(record[0]),
+			Description: strings.TrimSpace(record[1]),
+		})
+	}
+	return codes, nil
+}
+
+// readICD10Text reads ICD-10 codes from lines of a code, whitespace and a
+// description
+func readICD10Text(r io.Reader) ([]models.ICD10Code, error) {
+	var codes []models.ICD10Code
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		i := strings.IndexAny(text, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected code and description", line)
+		}
+		codes = append(codes, models.ICD10Code{
+			Code:        text[:i],
+			Description: strings.TrimSpace(text[i:]),
+		})
+	}
+	return codes, scanner.Err()
+}
+
+// Lookup finds an ICD-10 code, with or without its dot
+func (s *ICD10Service) Lookup(code string) (models.ICD10Code, bool) {
+	i, ok := s.byCode[models.NormalizeICD10Code(code)]
+	if !ok {
+		return models.ICD10Code{}, false
+	}
+	return s.codes[i], true
+}
+
+// Search finds up to limit codes starting with the query, followed by codes
+// whose description contains every word of the query
+func (s *ICD10Service) Search(query string, limit int) []models.ICD10Code {
+	results := []models.ICD10Code{}
+	query = strings.TrimSpace(query)
+	if query == "" || limit < 1 {
+		return results
+	}
+
+	prefix := models.NormalizeICD10Code(query)
+	start := sort.Search(len(s.codes), func(i int) bool {
+		return s.codes[i].Code >= prefix
+	})
+	for i := start; i < len(s.codes) && len(results) < limit && strings.HasPrefix(s.codes[i].Code, prefix); i++ {
+		results = append(results, s.codes[i])
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	for _, code := range s.codes {
+		if len(results) >= limit {
+			break
+		}
+		if strings.HasPrefix(code.Code, prefix) {
+			continue
+		}
+		description := strings.ToLower(code.Description)
+		matches := true
+		for _, word := range words {
+			if !strings.Contains(description, word) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			results = append(results, code)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrInteractionOverrideRequired = errors.New("a high-severity interaction requires an override reason")
+)
+
+// InteractionOverrideError reports a prescription blocked by high-severity
+// interactions, carrying every warning found
+type InteractionOverrideError struct {
+	Warnings []models.InteractionWarning
+}
+
+// Error implements the error interface
+func (e *InteractionOverrideError) Error() string {
+	return ErrInteractionOverrideRequired.Error()
+}
+
+// Unwrap returns the underlying sentinel error
+func (e *InteractionOverrideError) Unwrap() error {
+	return ErrInteractionOverrideRequired
+}
+
+// InteractionService checks drugs for interactions with other drugs and with
+// allergies against a local dataset
+type InteractionService struct {
+	rules []models.InteractionRule
+}
+
+// NewInteractionService creates a new InteractionService
+func NewInteractionService(rules []models.InteractionRule) *InteractionService {
+	return &InteractionService{rules: rules}
+}
+
+// LoadInteractionRules reads an interaction dataset from a JSON file holding
+// an array of rules, or from a CSV file with the columns type, drug,
+// interactant, severity and description
+func LoadInteractionRules(path string) ([]models.InteractionRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening interaction dataset: %w", err)
+	}
+	defer file.Close()
+
+	var rules []models.InteractionRule
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		rules, err = readInteractionCSV(file)
+	} else {
+		err = json.NewDecoder(file).Decode(&rules)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading interaction dataset %s: %w", path, err)
+	}
+
+	for i, rule := range rules {
+		if (rule.Type != models.InteractionTypeDrug && rule.Type != models.InteractionTypeAllergy) ||
+			strings.TrimSpace(rule.Drug) == "" || strings.TrimSpace(rule.Interactant) == "" ||
+			!rule.Severity.IsValid() {
+			return nil, fmt.Errorf("invalid interaction rule %d in %s", i+1, path)
+		}
+	}
+
+	return rules, nil
+}
+
+// readInteractionCSV reads interaction rules from CSV with a header row
+func readInteractionCSV(r io.Reader) ([]models.InteractionRule, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"type", "drug", "interactant", "severity"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+	column := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	rules := make([]models.InteractionRule, 0, len(records)-1)
+	for _, record := range records[1:] {
+		rules = append(rules, models.InteractionRule{
+			Type:        models.InteractionType(column(record, "type")),
+			Drug:        column(record, "drug"),
+			Interactant: column(record, "interactant"),
+			Severity:    models.InteractionSeverity(column(record, "severity")),
+			Description: column(record, "description"),
+		})
+	}
+	return rules, nil
+}
+
+// Check checks a drug for interactions with other drugs and with allergy
+// substances, most severe first. A documented allergy to the drug itself is
+// always a high-severity warning.
+func (s *InteractionService) Check(drugName string, drugs, allergies []string) models.InteractionCheckResult {
+	var warnings []models.InteractionWarning
+	warn := func(interactionType models.InteractionType, interactant string, severity models.InteractionSeverity, description string) {
+		for i, w := range warnings {
+			if w.Type == interactionType && w.Interactant == interactant {
+				if severityRank(severity) > severityRank(w.Severity) {
+					warnings[i].Severity = severity
+					warnings[i].Description = description
+				}
+				return
+			}
+		}
+		warnings = append(warnings, models.InteractionWarning{
+			Type:        interactionType,
+			Drug:        drugName,
+			Interactant: interactant,
+			Severity:    severity,
+			Description: description,
+		})
+	}
+
+	for _, allergy := range allergies {
+		if matchesTerm(drugName, allergy) || matchesTerm(allergy, drugName) {
+			warn(models.InteractionTypeAllergy, allergy, models.InteractionSeverityHigh, "Documented allergy to "+allergy)
+		}
+	}
+
+	for _, rule := range s.rules {
+		switch rule.Type {
+		case models.InteractionTypeDrug:
+			for _, drug := range drugs {
+				if (matchesTerm(drugName, rule.Drug) && matchesTerm(drug, rule.Interactant)) ||
+					(matchesTerm(drugName, rule.Interactant) && matchesTerm(drug, rule.Drug)) {
+					warn(models.InteractionTypeDrug, drug, rule.Severity, rule.Description)
+				}
+			}
+		case models.InteractionTypeAllergy:
+			if !matchesTerm(drugName, rule.Drug) {
+				continue
+			}
+			for _, allergy := range allergies {
+				if matchesTerm(allergy, rule.Interactant) {
+					warn(models.InteractionTypeAllergy, allergy, rule.Severity, rule.Description)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return severityRank(warnings[i].Severity) > severityRank(warnings[j].Severity)
+	})
+
+	result := models.InteractionCheckResult{Warnings: warnings}
+	if result.Warnings == nil {
+		result.Warnings = []models.InteractionWarning{}
+	}
+	for _, w := range warnings {
+		if w.Severity == models.InteractionSeverityHigh {
+			result.OverrideRequired = true
+		}
+	}
+	return result
+}
+
+// matchesTerm checks if a drug name or allergy substance contains a term,
+// ignoring case
+func matchesTerm(name, term string) bool {
+	term = strings.ToLower(strings.TrimSpace(term))
+	return term != "" && strings.Contains(strings.ToLower(name), term)
+}
+
+// severityRank orders severities from least to most serious
+func severityRank(severity models.InteractionSeverity) int {
+	switch severity {
+	case models.InteractionSeverityHigh:
+		return 3
+	case models.InteractionSeverityModerate:
+		return 2
+	case models.InteractionSeverityLow:
+		return 1
+	default:
+		return 0
+	}
+}
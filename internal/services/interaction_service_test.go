@@ -0,0 +1,85 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testInteractionRules is a small interaction dataset
+var testInteractionRules = []models.InteractionRule{
+	{Type: models.InteractionTypeDrug, Drug: "warfarin", Interactant: "aspirin", Severity: models.InteractionSeverityHigh, Description: "Bleeding"},
+	{Type: models.InteractionTypeDrug, Drug: "lisinopril", Interactant: "spironolactone", Severity: models.InteractionSeverityModerate, Description: "Hyperkalemia"},
+	{Type: models.InteractionTypeAllergy, Drug: "amoxicillin", Interactant: "penicillin", Severity: models.InteractionSeverityHigh, Description: "Penicillin"},
+	{Type: models.InteractionTypeAllergy, Drug: "cephalexin", Interactant: "penicillin", Severity: models.InteractionSeverityModerate, Description: "Cross-sensitivity"},
+}
+
+func TestInteractionCheck_DrugInteractionBothDirections(t *testing.T) {
+	service := NewInteractionService(testInteractionRules)
+
+	result := service.Check("Aspirin 75mg", []string{"Warfarin sodium"}, nil)
+	assert.True(t, result.OverrideRequired)
+	assert.Len(t, result.Warnings, 1)
+	assert.Equal(t, "Warfarin sodium", result.Warnings[0].Interactant)
+
+	result = service.Check("warfarin", []string{"ASPIRIN"}, nil)
+	assert.True(t, result.OverrideRequired)
+}
+
+func TestInteractionCheck_AllergiesMostSevereFirst(t *testing.T) {
+	service := NewInteractionService(testInteractionRules)
+
+	result := service.Check("Cephalexin", []string{"Lisinopril", "Spironolactone"}, []string{"Cephalexin", "Penicillin"})
+
+	assert.True(t, result.OverrideRequired)
+	assert.Len(t, result.Warnings, 2)
+	assert.Equal(t, models.InteractionSeverityHigh, result.Warnings[0].Severity)
+	assert.Equal(t, "Cephalexin", result.Warnings[0].Interactant)
+	assert.Equal(t, models.InteractionSeverityModerate, result.Warnings[1].Severity)
+	assert.Equal(t, "Penicillin", result.Warnings[1].Interactant)
+}
+
+func TestInteractionCheck_NoWarnings(t *testing.T) {
+	service := NewInteractionService(testInteractionRules)
+
+	result := service.Check("Lisinopril", []string{"Metformin"}, []string{"Latex"})
+
+	assert.False(t, result.OverrideRequired)
+	assert.Empty(t, result.Warnings)
+}
+
+func TestLoadInteractionRules_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interactions.csv")
+	data := "type,drug,interactant,severity,description\n" +
+		"drug,warfarin,aspirin,high,\"Bleeding, monitor INR\"\n" +
+		"allergy,amoxicillin,penicillin,high,\n"
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	rules, err := LoadInteractionRules(path)
+
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "Bleeding, monitor INR", rules[0].Description)
+	assert.Equal(t, models.InteractionTypeAllergy, rules[1].Type)
+}
+
+func TestLoadInteractionRules_InvalidSeverity(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "interactions.json")
+	data := `[{"type": "drug", "drug": "warfarin", "interactant": "aspirin", "severity": "severe"}]`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0600))
+
+	_, err := LoadInteractionRules(path)
+
+	assert.Error(t, err)
+}
+
+func TestLoadInteractionRules_ShippedDataset(t *testing.T) {
+	rules, err := LoadInteractionRules(filepath.Join("..", "..", "data", "interactions.json"))
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rules)
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Predefined errors
+var (
+	ErrUnknownKey           = errors.New("unknown signing key")
+	ErrNoSigningKey         = errors.New("no signing key available")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+)
+
+// Supported signing algorithms
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// signingKey is a key pair (or a bare public key) identified by a kid
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	public    crypto.PublicKey
+	createdAt time.Time
+	retiredAt *time.Time
+}
+
+// KeyManager holds the keys used to sign and verify JWTs.
+//
+// The newest private key signs new tokens. Older keys are retired but stay
+// available for verification for the retention period, which must be at
+// least the lifetime of the tokens they signed. Public-only keys are used for
+// verification alone and never expire.
+type KeyManager struct {
+	mu        sync.RWMutex
+	dir       string
+	algorithm string
+	retention time.Duration
+	keys      []*signingKey
+}
+
+// NewKeyManager creates a KeyManager and loads the PEM keys found in dir.
+// If dir is empty the keys are kept in memory only. A signing key is
+// generated when none is found.
+func NewKeyManager(dir, algorithm string, retention time.Duration) (*KeyManager, error) {
+	if _, err := signingMethodFor(algorithm); err != nil {
+		return nil, err
+	}
+
+	m := &KeyManager{
+		dir:       dir,
+		algorithm: algorithm,
+		retention: retention,
+	}
+
+	if dir != "" {
+		if err := m.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := m.currentSigningKey(); err != nil {
+		if err := m.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// Rotate generates a new signing key, retires the current one and prunes
+// retired keys whose retention period has passed
+func (m *KeyManager) Rotate() error {
+	key, err := generateSigningKey(m.algorithm)
+	if err != nil {
+		return err
+	}
+
+	if m.dir != "" {
+		if err := writePrivateKey(filepath.Join(m.dir, key.id+".pem"), key.private); err != nil {
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for _, k := range m.keys {
+		if k.private != nil && k.retiredAt == nil {
+			k.retiredAt = &now
+		}
+	}
+	m.keys = append(m.keys, key)
+	m.prune(now)
+
+	return nil
+}
+
+// StartRotation rotates the signing key every interval until stop is closed.
+// The first rotation happens immediately if the current key is already older
+// than interval.
+func (m *KeyManager) StartRotation(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	if key, err := m.currentSigningKey(); err == nil && time.Since(key.createdAt) >= interval {
+		if err := m.Rotate(); err != nil {
+			log.Printf("Failed to rotate signing key: %v", err)
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.Rotate(); err != nil {
+					log.Printf("Failed to rotate signing key: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Sign signs claims with the current signing key and sets the kid header
+func (m *KeyManager) Sign(claims jwt.Claims) (string, error) {
+	key, err := m.currentSigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+
+	return token.SignedString(key.private)
+}
+
+// Keyfunc resolves the verification key for a parsed token from its kid header
+func (m *KeyManager) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok || kid == "" {
+		return nil, ErrUnknownKey
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	for _, k := range m.keys {
+		if k.id != kid || m.expired(k, now) {
+			continue
+		}
+		if token.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return k.public, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// ValidMethods returns the algorithms accepted when verifying tokens
+func (m *KeyManager) ValidMethods() []string {
+	return []string{AlgorithmRS256, AlgorithmEdDSA}
+}
+
+// JWKS returns the public verification keys as a JSON Web Key Set
+func (m *KeyManager) JWKS() models.JSONWebKeySet {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := models.JSONWebKeySet{Keys: []models.JSONWebKey{}}
+	now := time.Now()
+	for _, k := range m.keys {
+		if m.expired(k, now) {
+			continue
+		}
+
+		jwk := models.JSONWebKey{
+			KeyID:     k.id,
+			Use:       "sig",
+			Algorithm: k.method.Alg(),
+		}
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// currentSigningKey returns the newest private key that has not been retired
+func (m *KeyManager) currentSigningKey() (*signingKey, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for i := len(m.keys) - 1; i >= 0; i-- {
+		if k := m.keys[i]; k.private != nil && k.retiredAt == nil {
+			return k, nil
+		}
+	}
+	return nil, ErrNoSigningKey
+}
+
+// expired reports whether a retired key is past its retention period
+func (m *KeyManager) expired(k *signingKey, now time.Time) bool {
+	return k.retiredAt != nil && now.After(k.retiredAt.Add(m.retention))
+}
+
+// prune drops expired keys and removes their files. The caller must hold m.mu.
+func (m *KeyManager) prune(now time.Time) {
+	kept := m.keys[:0]
+	for _, k := range m.keys {
+		if !m.expired(k, now) {
+			kept = append(kept, k)
+			continue
+		}
+		if m.dir != "" {
+			path := filepath.Join(m.dir, k.id+".pem")
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Printf("Failed to remove expired signing key %s: %v", path, err)
+			}
+		}
+	}
+	m.keys = kept
+}
+
+// load reads every *.pem file in the key directory. The kid of a key is its
+// file name without the extension, and a private key is considered retired
+// from the moment the next newer private key was created.
+func (m *KeyManager) load() error {
+	if err := os.MkdirAll(m.dir, 0700); err != nil {
+		return fmt.Errorf("error creating key directory: %w", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(m.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	var keys []*signingKey
+	for _, path := range paths {
+		key, err := readKeyFile(path)
+		if err != nil {
+			return fmt.Errorf("error loading key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].createdAt.Equal(keys[j].createdAt) {
+			return keys[i].id < keys[j].id
+		}
+		return keys[i].createdAt.Before(keys[j].createdAt)
+	})
+
+	var previous *signingKey
+	for _, k := range keys {
+		if k.private == nil {
+			continue
+		}
+		if previous != nil {
+			retiredAt := k.createdAt
+			previous.retiredAt = &retiredAt
+		}
+		previous = k
+	}
+
+	m.keys = keys
+	m.prune(time.Now())
+
+	return nil
+}
+
+// readKeyFile parses a PKCS#8 private key or PKIX public key PEM file
+func readKeyFile(path string) (*signingKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key := &signingKey{
+		id:        strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		createdAt: info.ModTime(),
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		key.private = signer
+		key.public = signer.Public()
+	case "PUBLIC KEY":
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key.public = parsed
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+
+	switch key.public.(type) {
+	case *rsa.PublicKey:
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	return key, nil
+}
+
+// writePrivateKey stores a private key as a PKCS#8 PEM file
+func writePrivateKey(path string, key crypto.Signer) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return os.WriteFile(path, data, 0600)
+}
+
+// generateSigningKey creates a new key pair for the given algorithm
+func generateSigningKey(algorithm string) (*signingKey, error) {
+	method, err := signingMethodFor(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	var private crypto.Signer
+	switch algorithm {
+	case AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	suffix, err := generateRandomToken(4)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &signingKey{
+		id:        now.UTC().Format("20060102T150405Z") + "-" + suffix,
+		method:    method,
+		private:   private,
+		public:    private.Public(),
+		createdAt: now,
+	}, nil
+}
+
+// signingMethodFor maps an algorithm name to its JWT signing method
+func signingMethodFor(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func parseWithKeyManager(m *KeyManager, tokenString string) error {
+	_, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, m.Keyfunc,
+		jwt.WithValidMethods(m.ValidMethods()))
+	return err
+}
+
+func testClaims() jwt.RegisteredClaims {
+	return jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+}
+
+func TestKeyManager_RotatedKeyStillVerifies(t *testing.T) {
+	m, err := NewKeyManager("", AlgorithmEdDSA, time.Hour)
+	assert.NoError(t, err)
+
+	token, err := m.Sign(testClaims())
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Rotate())
+
+	// Token signed by the retired key keeps validating
+	assert.NoError(t, parseWithKeyManager(m, token))
+	assert.Len(t, m.JWKS().Keys, 2)
+}
+
+func TestKeyManager_ExpiredKeyRejected(t *testing.T) {
+	m, err := NewKeyManager("", AlgorithmEdDSA, 0)
+	assert.NoError(t, err)
+
+	token, err := m.Sign(testClaims())
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.Rotate())
+	time.Sleep(time.Millisecond)
+
+	assert.ErrorIs(t, parseWithKeyManager(m, token), ErrUnknownKey)
+}
+
+func TestKeyManager_LoadsKeysFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := NewKeyManager(dir, AlgorithmRS256, time.Hour)
+	assert.NoError(t, err)
+
+	token, err := first.Sign(testClaims())
+	assert.NoError(t, err)
+
+	// A second instance sharing the directory verifies the same tokens
+	second, err := NewKeyManager(dir, AlgorithmRS256, time.Hour)
+	assert.NoError(t, err)
+
+	assert.NoError(t, parseWithKeyManager(second, token))
+
+	jwks := second.JWKS()
+	assert.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "RSA", jwks.Keys[0].KeyType)
+	assert.Equal(t, "RS256", jwks.Keys[0].Algorithm)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrAccountLocked   = errors.New("account is temporarily locked")
+	ErrTooManyAttempts = errors.New("too many failed login attempts")
+	ErrClientIPBlocked = errors.New("too many failed login attempts from this address")
+)
+
+// RetryAfterError reports a throttled login and when it may be retried
+type RetryAfterError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v, retry in %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+// Unwrap returns the underlying sentinel error
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// LoginAttemptStore defines the storage for login failure counters. Get
+// returns nil when a key has no recorded failures.
+type LoginAttemptStore interface {
+	Get(key string) (*models.LoginAttempt, error)
+	RecordFailure(key string, now time.Time, window time.Duration) (*models.LoginAttempt, error)
+	SetLockedUntil(key string, until *time.Time) error
+	Reset(key string) error
+}
+
+// LockoutPolicy configures login throttling
+type LockoutPolicy struct {
+	// AccountThreshold is the number of failures that locks an account
+	AccountThreshold int
+	// IPThreshold is the number of failures that blocks a client IP
+	IPThreshold int
+	// LockoutDuration is how long a lock lasts
+	LockoutDuration time.Duration
+	// FailureWindow is how long a failure is remembered
+	FailureWindow time.Duration
+	// BaseDelay is the wait imposed after the first failure; it doubles with
+	// every further failure up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// LoginThrottle tracks failed logins per account and per client IP, imposes
+// progressive delays and temporarily locks accounts and addresses
+type LoginThrottle struct {
+	store  LoginAttemptStore
+	policy LockoutPolicy
+}
+
+// NewLoginThrottle creates a new LoginThrottle
+func NewLoginThrottle(store LoginAttemptStore, policy LockoutPolicy) *LoginThrottle {
+	return &LoginThrottle{
+		store:  store,
+		policy: policy,
+	}
+}
+
+// Check returns a *RetryAfterError if a login for email from ip must be
+// refused at this time
+func (t *LoginThrottle) Check(email, ip string) error {
+	now := time.Now()
+
+	if err := t.check(accountKey(email), now, ErrAccountLocked); err != nil {
+		return err
+	}
+	if ip != "" {
+		if err := t.check(ipKey(ip), now, ErrClientIPBlocked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed login and applies locks once thresholds are reached
+func (t *LoginThrottle) RecordFailure(email, ip string) error {
+	now := time.Now()
+
+	if err := t.recordFailure(accountKey(email), t.policy.AccountThreshold, now); err != nil {
+		return err
+	}
+	if ip != "" {
+		if err := t.recordFailure(ipKey(ip), t.policy.IPThreshold, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordSuccess clears the failure counter of an account after a successful login
+func (t *LoginThrottle) RecordSuccess(email string) error {
+	return t.store.Reset(accountKey(email))
+}
+
+// Unlock clears the lock and failure counter of an account
+func (t *LoginThrottle) Unlock(email string) error {
+	return t.store.Reset(accountKey(email))
+}
+
+// Status returns the recorded failures of an account, or nil if there are none
+func (t *LoginThrottle) Status(email string) (*models.LoginAttempt, error) {
+	return t.store.Get(accountKey(email))
+}
+
+// check refuses a key that is locked or still inside its progressive delay
+func (t *LoginThrottle) check(key string, now time.Time, lockedErr error) error {
+	attempt, err := t.store.Get(key)
+	if err != nil {
+		return err
+	}
+	if attempt == nil {
+		return nil
+	}
+
+	if attempt.IsLocked(now) {
+		return &RetryAfterError{Err: lockedErr, RetryAfter: attempt.LockedUntil.Sub(now)}
+	}
+
+	if attempt.LastFailureAt.Before(now.Add(-t.policy.FailureWindow)) {
+		return nil
+	}
+
+	retryAt := attempt.LastFailureAt.Add(t.delay(attempt.Failures))
+	if now.Before(retryAt) {
+		return &RetryAfterError{Err: ErrTooManyAttempts, RetryAfter: retryAt.Sub(now)}
+	}
+
+	return nil
+}
+
+// recordFailure increments a key's counter and locks it at the threshold
+func (t *LoginThrottle) recordFailure(key string, threshold int, now time.Time) error {
+	attempt, err := t.store.RecordFailure(key, now, t.policy.FailureWindow)
+	if err != nil {
+		return err
+	}
+
+	if threshold > 0 && attempt.Failures >= threshold && !attempt.IsLocked(now) {
+		until := now.Add(t.policy.LockoutDuration)
+		return t.store.SetLockedUntil(key, &until)
+	}
+	return nil
+}
+
+// delay returns the wait imposed after the given number of failures
+func (t *LoginThrottle) delay(failures int) time.Duration {
+	if failures < 1 || t.policy.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := t.policy.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= t.policy.MaxDelay {
+			return t.policy.MaxDelay
+		}
+	}
+	return delay
+}
+
+// accountKey returns the attempt key of an account
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// ipKey returns the attempt key of a client IP
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
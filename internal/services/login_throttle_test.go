@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/repositories"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginThrottle_ProgressiveDelay(t *testing.T) {
+	throttle := NewLoginThrottle(repositories.NewMemoryLoginAttemptStore(), LockoutPolicy{
+		AccountThreshold: 10,
+		IPThreshold:      10,
+		LockoutDuration:  time.Minute,
+		FailureWindow:    time.Minute,
+		BaseDelay:        time.Second,
+		MaxDelay:         4 * time.Second,
+	})
+
+	assert.NoError(t, throttle.Check("user@example.com", "10.0.0.1"))
+	assert.NoError(t, throttle.RecordFailure("user@example.com", "10.0.0.1"))
+
+	err := throttle.Check("user@example.com", "10.0.0.1")
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+
+	assert.Equal(t, time.Second, throttle.delay(1))
+	assert.Equal(t, 2*time.Second, throttle.delay(2))
+	assert.Equal(t, 4*time.Second, throttle.delay(5))
+}
+
+func TestLoginThrottle_LocksClientIP(t *testing.T) {
+	throttle := NewLoginThrottle(repositories.NewMemoryLoginAttemptStore(), LockoutPolicy{
+		AccountThreshold: 10,
+		IPThreshold:      2,
+		LockoutDuration:  time.Minute,
+		FailureWindow:    time.Minute,
+	})
+
+	assert.NoError(t, throttle.RecordFailure("a@example.com", "10.0.0.1"))
+	assert.NoError(t, throttle.RecordFailure("b@example.com", "10.0.0.1"))
+
+	// A third account from the same address is refused
+	assert.ErrorIs(t, throttle.Check("c@example.com", "10.0.0.1"), ErrClientIPBlocked)
+	assert.NoError(t, throttle.Check("c@example.com", "10.0.0.2"))
+}
+
+func TestLoginThrottle_UnlockClearsAccount(t *testing.T) {
+	throttle := NewLoginThrottle(repositories.NewMemoryLoginAttemptStore(), LockoutPolicy{
+		AccountThreshold: 1,
+		IPThreshold:      10,
+		LockoutDuration:  time.Minute,
+		FailureWindow:    time.Minute,
+	})
+
+	assert.NoError(t, throttle.RecordFailure("User@Example.com", ""))
+	assert.ErrorIs(t, throttle.Check("user@example.com", ""), ErrAccountLocked)
+
+	status, err := throttle.Status("user@example.com")
+	assert.NoError(t, err)
+	assert.True(t, status.IsLocked(time.Now()))
+
+	assert.NoError(t, throttle.Unlock("user@example.com"))
+	assert.NoError(t, throttle.Check("user@example.com", ""))
+}
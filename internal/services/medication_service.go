@@ -0,0 +1,334 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrMedicationNotFound  = errors.New("medication not found")
+	ErrMedicationNotActive = errors.New("medication is no longer active")
+	ErrStopReasonRequired  = errors.New("a reason is required to stop a medication")
+	ErrInvalidStopDate     = errors.New("stop date is before the start date")
+	ErrNotAPrescription    = errors.New("medication was not prescribed here")
+)
+
+// MedicationRepository defines the medication data operations used by the MedicationService
+type MedicationRepository interface {
+	Create(medication *models.Medication, event *models.AuditEvent) error
+	FindByID(id uint) (*models.Medication, error)
+	FindByPatient(patientID uint, status models.MedicationStatus) ([]models.Medication, error)
+	FindAll(filter models.MedicationFilter, limit, offset int) ([]models.PatientMedication, int64, error)
+	Update(medication *models.Medication, event *models.AuditEvent) error
+}
+
+// MedicationService handles the medication statements and prescriptions of patients
+type MedicationService struct {
+	medicationRepo     MedicationRepository
+	allergyRepo        AllergyRepository
+	patientService     *PatientService
+	encounterService   *EncounterService
+	userRepo           UserRepository
+	interactionService *InteractionService
+	auditRepo          AuditRepository
+}
+
+// NewMedicationService creates a new MedicationService
+func NewMedicationService(medicationRepo MedicationRepository, allergyRepo AllergyRepository, patientService *PatientService,
+	encounterService *EncounterService, userRepo UserRepository, interactionService *InteractionService, auditRepo AuditRepository) *MedicationService {
+	return &MedicationService{
+		medicationRepo:     medicationRepo,
+		allergyRepo:        allergyRepo,
+		patientService:     patientService,
+		encounterService:   encounterService,
+		userRepo:           userRepo,
+		interactionService: interactionService,
+		auditRepo:          auditRepo,
+	}
+}
+
+// GetMedications gets the medications of a patient, optionally only those
+// with a status
+func (s *MedicationService) GetMedications(accessor PatientAccessor, patientID uint, status models.MedicationStatus) ([]models.Medication, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	medications, err := s.medicationRepo.FindByPatient(patientID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "medications"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return medications, nil
+}
+
+// GetMedication gets a medication of a patient
+func (s *MedicationService) GetMedication(accessor PatientAccessor, patientID, medicationID uint) (*models.Medication, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	medication, err := s.findMedication(patientID, medicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = fmt.Sprintf("medication=%d", medication.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return medication, nil
+}
+
+// GetActiveMedications lists the active medications across the patients the
+// accessor may see, optionally only those of a drug
+func (s *MedicationService) GetActiveMedications(accessor PatientAccessor, drugName string, page, pageSize int) (*PaginationResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	filter := models.MedicationFilter{
+		DrugName:         strings.TrimSpace(drugName),
+		Status:           models.MedicationStatusActive,
+		CareTeamMemberID: s.patientService.filterFor(accessor).CareTeamMemberID,
+	}
+
+	offset := (page - 1) * pageSize
+	medications, totalItems, err := s.medicationRepo.FindAll(filter, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(medications))
+	for i, medication := range medications {
+		ids[i] = fmt.Sprint(medication.PatientID)
+	}
+	event := newAuditEvent(accessor, models.AuditActionSearch, 0, nil)
+	event.Details = fmt.Sprintf("medications drug=%q page=%d pageSize=%d patients=[%s]", filter.DrugName, page, pageSize, strings.Join(ids, ","))
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(totalItems) + pageSize - 1) / pageSize
+
+	return &PaginationResponse{
+		TotalItems: totalItems,
+		Items:      medications,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// CreateMedication records a medication statement or prescribes a
+// medication. Prescriptions are attributed to the accessor and checked for
+// interactions with the patient's active medications and allergies; a
+// high-severity interaction requires an override reason.
+func (s *MedicationService) CreateMedication(accessor PatientAccessor, patientID uint, req models.CreateMedicationRequest) (*models.Medication, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionMedicationCreate); err != nil {
+		return nil, err
+	}
+	if err := s.encounterService.checkAttachable(patientID, req.EncounterID); err != nil {
+		return nil, err
+	}
+
+	medication := &models.Medication{
+		PatientID:      patientID,
+		EncounterID:    req.EncounterID,
+		Kind:           req.Kind,
+		DrugName:       strings.TrimSpace(req.DrugName),
+		DrugCode:       req.DrugCode,
+		Dose:           req.Dose,
+		Route:          req.Route,
+		Frequency:      req.Frequency,
+		Duration:       req.Duration,
+		Instructions:   req.Instructions,
+		PrescriberName: req.PrescriberName,
+		StartDate:      time.Now(),
+		Status:         models.MedicationStatusActive,
+		RecordedBy:     accessor.UserID,
+	}
+	if req.StartDate != nil {
+		medication.StartDate = *req.StartDate
+	}
+	if medication.Kind == models.MedicationPrescription {
+		prescriber, err := s.userRepo.FindByID(accessor.UserID)
+		if err != nil {
+			return nil, ErrUserNotFound
+		}
+		medication.PrescriberID = &prescriber.ID
+		medication.PrescriberName = prescriber.Name
+
+		drugs, allergies, err := s.interactants(patientID)
+		if err != nil {
+			return nil, err
+		}
+		result := s.interactionService.Check(medication.DrugName, drugs, allergies)
+		if result.OverrideRequired {
+			reason := strings.TrimSpace(req.OverrideReason)
+			if reason == "" {
+				return nil, &InteractionOverrideError{Warnings: result.Warnings}
+			}
+			medication.InteractionOverrideReason = reason
+		}
+		medication.InteractionWarnings = result.Warnings
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionMedicationCreate, patientID, models.DiffRecords(nil, medication))
+	if err := s.medicationRepo.Create(medication, event); err != nil {
+		return nil, err
+	}
+
+	return medication, nil
+}
+
+// UpdateMedication changes the details of an active medication, or stops or
+// completes it. Stopping a medication requires a reason.
+func (s *MedicationService) UpdateMedication(accessor PatientAccessor, patientID, medicationID uint, req models.UpdateMedicationRequest) (*models.Medication, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionMedicationUpdate); err != nil {
+		return nil, err
+	}
+
+	medication, err := s.findMedication(patientID, medicationID)
+	if err != nil {
+		return nil, err
+	}
+	if !medication.IsActive() {
+		return nil, ErrMedicationNotActive
+	}
+
+	before := *medication
+	medication.ApplyUpdates(req)
+
+	if req.Status != "" && req.Status != models.MedicationStatusActive {
+		reason := strings.TrimSpace(req.StopReason)
+		if req.Status == models.MedicationStatusStopped && reason == "" {
+			return nil, ErrStopReasonRequired
+		}
+		stopDate := time.Now()
+		if req.StopDate != nil {
+			stopDate = *req.StopDate
+		}
+		if stopDate.Before(medication.StartDate) {
+			return nil, ErrInvalidStopDate
+		}
+		medication.Status = req.Status
+		medication.StopDate = &stopDate
+		medication.StopReason = reason
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionMedicationUpdate, patientID, models.DiffRecords(&before, medication))
+	event.Details = fmt.Sprintf("medication=%d", medication.ID)
+	if err := s.medicationRepo.Update(medication, event); err != nil {
+		return nil, err
+	}
+
+	return medication, nil
+}
+
+// CheckInteractions checks a drug for interactions with the given drugs and
+// allergies and, when a patient is given, with the patient's active
+// medications and allergies
+func (s *MedicationService) CheckInteractions(accessor PatientAccessor, req models.InteractionCheckRequest) (*models.InteractionCheckResult, error) {
+	drugs := req.Drugs
+	allergies := req.Allergies
+
+	if req.PatientID != 0 {
+		if err := s.patientService.CheckAccess(accessor, req.PatientID, models.AuditActionRead); err != nil {
+			return nil, err
+		}
+
+		patientDrugs, patientAllergies, err := s.interactants(req.PatientID)
+		if err != nil {
+			return nil, err
+		}
+		drugs = append(append([]string{}, drugs...), patientDrugs...)
+		allergies = append(append([]string{}, allergies...), patientAllergies...)
+
+		event := newAuditEvent(accessor, models.AuditActionRead, req.PatientID, nil)
+		event.Details = fmt.Sprintf("interactions drug=%q", req.DrugName)
+		if err := s.auditRepo.Append(event); err != nil {
+			return nil, err
+		}
+	}
+
+	result := s.interactionService.Check(strings.TrimSpace(req.DrugName), drugs, allergies)
+	return &result, nil
+}
+
+// interactants gets the drug names of a patient's active medications and the
+// substances of their active allergies
+func (s *MedicationService) interactants(patientID uint) (drugs, allergies []string, err error) {
+	medications, err := s.medicationRepo.FindByPatient(patientID, models.MedicationStatusActive)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, medication := range medications {
+		drugs = append(drugs, medication.DrugName)
+	}
+
+	patientAllergies, err := s.allergyRepo.FindByPatient(patientID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, allergy := range patientAllergies {
+		if allergy.Status == models.AllergyStatusActive {
+			allergies = append(allergies, allergy.Substance)
+		}
+	}
+
+	return drugs, allergies, nil
+}
+
+// GetPrescription gets what is printed on the prescription of a medication
+func (s *MedicationService) GetPrescription(accessor PatientAccessor, patientID, medicationID uint) (*models.PrescriptionDocument, error) {
+	patient, err := s.patientService.findAccessible(accessor, patientID, models.AuditActionRead)
+	if err != nil {
+		return nil, err
+	}
+
+	medication, err := s.findMedication(patientID, medicationID)
+	if err != nil {
+		return nil, err
+	}
+	if medication.Kind != models.MedicationPrescription {
+		return nil, ErrNotAPrescription
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = fmt.Sprintf("prescription=%d", medication.ID)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return &models.PrescriptionDocument{
+		Medication: *medication,
+		Patient:    *patient,
+		PrintedAt:  time.Now(),
+	}, nil
+}
+
+// findMedication finds a medication belonging to a patient
+func (s *MedicationService) findMedication(patientID, medicationID uint) (*models.Medication, error) {
+	medication, err := s.medicationRepo.FindByID(medicationID)
+	if err != nil || medication.PatientID != patientID {
+		return nil, ErrMedicationNotFound
+	}
+	return medication, nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMedicationRepository is a mock implementation of MedicationRepository
+type MockMedicationRepository struct {
+	mock.Mock
+}
+
+func (m *MockMedicationRepository) Create(medication *models.Medication, event *models.AuditEvent) error {
+	args := m.Called(medication, event)
+	return args.Error(0)
+}
+
+func (m *MockMedicationRepository) FindByID(id uint) (*models.Medication, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Medication), args.Error(1)
+}
+
+func (m *MockMedicationRepository) FindByPatient(patientID uint, status models.MedicationStatus) ([]models.Medication, error) {
+	args := m.Called(patientID, status)
+	return args.Get(0).([]models.Medication), args.Error(1)
+}
+
+func (m *MockMedicationRepository) FindAll(filter models.MedicationFilter, limit, offset int) ([]models.PatientMedication, int64, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]models.PatientMedication), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockMedicationRepository) Update(medication *models.Medication, event *models.AuditEvent) error {
+	args := m.Called(medication, event)
+	return args.Error(0)
+}
+
+// newTestMedicationService creates a MedicationService for a patient on the
+// doctor's care team who is allergic to penicillin
+func newTestMedicationService(medicationRepo *MockMedicationRepository, userRepo *MockUserRepository) *MedicationService {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1, FirstName: "Jane", LastName: "Doe"}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(true, nil)
+	auditRepo := newMockAuditRepository()
+
+	mockAllergyRepo := new(MockAllergyRepository)
+	mockAllergyRepo.On("FindByPatient", uint(1)).Return([]models.Allergy{
+		{ID: 2, PatientID: 1, Substance: "Penicillin", Status: models.AllergyStatusActive},
+	}, nil)
+	interactionService := NewInteractionService(testInteractionRules)
+
+	patientService := NewPatientService(mockPatientRepo, mockCareTeamRepo, auditRepo)
+	encounterService := NewEncounterService(new(MockEncounterRepository), patientService, userRepo, auditRepo)
+	return NewMedicationService(medicationRepo, mockAllergyRepo, patientService, encounterService, userRepo, interactionService, auditRepo)
+}
+
+// activeMedication returns an active prescription of patient 1
+func activeMedication() *models.Medication {
+	return &models.Medication{
+		ID:         7,
+		PatientID:  1,
+		Kind:       models.MedicationPrescription,
+		DrugName:   "Warfarin",
+		Dose:       "5 mg",
+		Route:      "oral",
+		Frequency:  "once daily",
+		StartDate:  time.Now().Add(-30 * 24 * time.Hour),
+		Status:     models.MedicationStatusActive,
+		RecordedBy: 5,
+	}
+}
+
+func TestCreateMedication_PrescriptionAttributedToPrescriber(t *testing.T) {
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByPatient", uint(1), models.MedicationStatusActive).Return([]models.Medication{}, nil)
+	mockMedicationRepo.On("Create", mock.AnythingOfType("*models.Medication"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionMedicationCreate && e.Changes["drug_name"].After == "Warfarin"
+	})).Return(nil)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Name: "Dr. Smith", Role: models.RoleDoctor}, nil)
+
+	service := newTestMedicationService(mockMedicationRepo, mockUserRepo)
+
+	medication, err := service.CreateMedication(doctorAccessor, 1, models.CreateMedicationRequest{
+		Kind:           models.MedicationPrescription,
+		DrugName:       " Warfarin ",
+		Dose:           "5 mg",
+		Route:          "oral",
+		Frequency:      "once daily",
+		PrescriberName: "Someone else",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Warfarin", medication.DrugName)
+	assert.Equal(t, models.MedicationStatusActive, medication.Status)
+	assert.Equal(t, uint(5), *medication.PrescriberID)
+	assert.Equal(t, "Dr. Smith", medication.PrescriberName)
+	assert.False(t, medication.StartDate.IsZero())
+	mockMedicationRepo.AssertExpectations(t)
+}
+
+func TestCreateMedication_HighSeverityInteractionRequiresOverride(t *testing.T) {
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByPatient", uint(1), models.MedicationStatusActive).Return([]models.Medication{*activeMedication()}, nil)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Name: "Dr. Smith", Role: models.RoleDoctor}, nil)
+
+	service := newTestMedicationService(mockMedicationRepo, mockUserRepo)
+
+	_, err := service.CreateMedication(doctorAccessor, 1, models.CreateMedicationRequest{
+		Kind:      models.MedicationPrescription,
+		DrugName:  "Aspirin",
+		Dose:      "75 mg",
+		Route:     "oral",
+		Frequency: "once daily",
+	})
+
+	var overrideErr *InteractionOverrideError
+	assert.ErrorAs(t, err, &overrideErr)
+	assert.ErrorIs(t, err, ErrInteractionOverrideRequired)
+	assert.Len(t, overrideErr.Warnings, 1)
+	assert.Equal(t, "Warfarin", overrideErr.Warnings[0].Interactant)
+	mockMedicationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateMedication_InteractionOverridden(t *testing.T) {
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByPatient", uint(1), models.MedicationStatusActive).Return([]models.Medication{}, nil)
+	mockMedicationRepo.On("Create", mock.MatchedBy(func(m *models.Medication) bool {
+		return len(m.InteractionWarnings) == 1
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		_, warningsRecorded := e.Changes["interaction_warnings"]
+		return e.Changes["interaction_override_reason"].After == "Tolerated amoxicillin in 2020" && warningsRecorded
+	})).Return(nil)
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Name: "Dr. Smith", Role: models.RoleDoctor}, nil)
+
+	service := newTestMedicationService(mockMedicationRepo, mockUserRepo)
+
+	medication, err := service.CreateMedication(doctorAccessor, 1, models.CreateMedicationRequest{
+		Kind:           models.MedicationPrescription,
+		DrugName:       "Amoxicillin",
+		Dose:           "500 mg",
+		Route:          "oral",
+		Frequency:      "three times daily",
+		OverrideReason: "Tolerated amoxicillin in 2020",
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, medication.InteractionWarnings, 1)
+	assert.Equal(t, models.InteractionTypeAllergy, medication.InteractionWarnings[0].Type)
+	mockMedicationRepo.AssertExpectations(t)
+}
+
+func TestUpdateMedication_StopRequiresReason(t *testing.T) {
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByID", uint(7)).Return(activeMedication(), nil)
+
+	service := newTestMedicationService(mockMedicationRepo, new(MockUserRepository))
+
+	_, err := service.UpdateMedication(doctorAccessor, 1, 7, models.UpdateMedicationRequest{
+		Status: models.MedicationStatusStopped,
+	})
+
+	assert.Equal(t, ErrStopReasonRequired, err)
+	mockMedicationRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdateMedication_Stop(t *testing.T) {
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByID", uint(7)).Return(activeMedication(), nil)
+	mockMedicationRepo.On("Update", mock.AnythingOfType("*models.Medication"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Changes["status"].Before == models.MedicationStatusActive &&
+			e.Changes["status"].After == models.MedicationStatusStopped
+	})).Return(nil)
+
+	service := newTestMedicationService(mockMedicationRepo, new(MockUserRepository))
+
+	medication, err := service.UpdateMedication(doctorAccessor, 1, 7, models.UpdateMedicationRequest{
+		Status:     models.MedicationStatusStopped,
+		StopReason: "Bleeding risk",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.MedicationStatusStopped, medication.Status)
+	assert.Equal(t, "Bleeding risk", medication.StopReason)
+	assert.NotNil(t, medication.StopDate)
+	mockMedicationRepo.AssertExpectations(t)
+}
+
+func TestUpdateMedication_NotActive(t *testing.T) {
+	stopped := activeMedication()
+	stopped.Status = models.MedicationStatusCompleted
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByID", uint(7)).Return(stopped, nil)
+
+	service := newTestMedicationService(mockMedicationRepo, new(MockUserRepository))
+
+	_, err := service.UpdateMedication(doctorAccessor, 1, 7, models.UpdateMedicationRequest{Dose: "10 mg"})
+
+	assert.Equal(t, ErrMedicationNotActive, err)
+}
+
+func TestGetActiveMedications_LimitedToCareTeam(t *testing.T) {
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindAll", models.MedicationFilter{
+		DrugName:         "warfarin",
+		Status:           models.MedicationStatusActive,
+		CareTeamMemberID: 5,
+	}, 10, 0).Return([]models.PatientMedication{{Medication: *activeMedication()}}, int64(1), nil)
+
+	service := newTestMedicationService(mockMedicationRepo, new(MockUserRepository))
+
+	response, err := service.GetActiveMedications(doctorAccessor, "warfarin", 1, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), response.TotalItems)
+	mockMedicationRepo.AssertExpectations(t)
+}
+
+func TestGetPrescription_Statement(t *testing.T) {
+	statement := activeMedication()
+	statement.Kind = models.MedicationStatement
+	mockMedicationRepo := new(MockMedicationRepository)
+	mockMedicationRepo.On("FindByID", uint(7)).Return(statement, nil)
+
+	service := newTestMedicationService(mockMedicationRepo, new(MockUserRepository))
+
+	_, err := service.GetPrescription(doctorAccessor, 1, 7)
+
+	assert.Equal(t, ErrNotAPrescription, err)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrMFAAlreadyEnabled = errors.New("MFA is already enabled")
+	ErrMFANotEnrolled    = errors.New("MFA enrollment has not been started")
+	ErrInvalidMFACode    = errors.New("invalid MFA code")
+)
+
+// recoveryCodeCount is the number of recovery codes issued per enrollment
+const recoveryCodeCount = 10
+
+// MFARepository defines the MFA data operations used by the MFAService
+type MFARepository interface {
+	ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error
+	UseRecoveryCode(userID uint, codeHash string) (bool, error)
+	RoleRequiresMFA(role models.UserRole) (bool, error)
+	FindAllRolePolicies() ([]models.RolePolicy, error)
+	SaveRolePolicy(policy *models.RolePolicy) error
+}
+
+// MFAService handles TOTP enrollment, verification and role MFA policies
+type MFAService struct {
+	userRepo UserRepository
+	mfaRepo  MFARepository
+	issuer   string
+}
+
+// NewMFAService creates a new MFAService
+func NewMFAService(userRepo UserRepository, mfaRepo MFARepository, issuer string) *MFAService {
+	return &MFAService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		issuer:   issuer,
+	}
+}
+
+// Enroll generates a new TOTP secret for a user. The secret only becomes
+// active once confirmed with a valid code.
+func (s *MFAService) Enroll(userID uint) (*models.MFAEnrollmentResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateColumns(user.ID, map[string]interface{}{
+		"totp_secret":    secret,
+		"totp_last_step": 0,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.MFAEnrollmentResponse{
+		Secret:          secret,
+		ProvisioningURI: totpProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment activates MFA after checking a code from the
+// authenticator app and returns a fresh set of recovery codes
+func (s *MFAService) ConfirmEnrollment(userID uint, code string) (*models.RecoveryCodesResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	step, ok := validateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, err := s.regenerateRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateColumns(user.ID, map[string]interface{}{
+		"mfa_enabled":    true,
+		"totp_last_step": step,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &models.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Verify checks a TOTP code or, if code is empty, a recovery code
+func (s *MFAService) Verify(user *models.User, code, recoveryCode string) error {
+	if !user.MFAEnabled {
+		return ErrMFANotEnrolled
+	}
+
+	if code == "" {
+		used, err := s.mfaRepo.UseRecoveryCode(user.ID, hashToken(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	step, ok := validateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	// The step is only advanced if no concurrent login used this code first
+	advanced, err := s.userRepo.AdvanceTOTPStep(user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !advanced {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// RoleRequiresMFA checks whether users of a role must use MFA
+func (s *MFAService) RoleRequiresMFA(role models.UserRole) (bool, error) {
+	return s.mfaRepo.RoleRequiresMFA(role)
+}
+
+// GetRolePolicies gets all role policies
+func (s *MFAService) GetRolePolicies() ([]models.RolePolicy, error) {
+	return s.mfaRepo.FindAllRolePolicies()
+}
+
+// UpdateRolePolicy sets whether users of a role must use MFA
+func (s *MFAService) UpdateRolePolicy(role models.UserRole, req models.UpdateRolePolicyRequest) (*models.RolePolicy, error) {
+	policy := &models.RolePolicy{
+		Role:       role,
+		RequireMFA: req.RequireMFA,
+	}
+
+	if err := s.mfaRepo.SaveRolePolicy(policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// regenerateRecoveryCodes replaces a user's recovery codes and returns them
+// in plain text. Only their hashes are stored.
+func (s *MFAService) regenerateRecoveryCodes(userID uint) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	stored := make([]models.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := generateTOTPSecret()
+		if err != nil {
+			return nil, err
+		}
+		code := raw[:5] + "-" + raw[5:10]
+		codes = append(codes, code)
+		stored = append(stored, models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: hashToken(normalizeRecoveryCode(code)),
+		})
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, stored); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// normalizeRecoveryCode makes recovery codes case and dash insensitive
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockMFARepository is a mock implementation of MFARepository
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error {
+	args := m.Called(userID, codes)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	args := m.Called(userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) RoleRequiresMFA(role models.UserRole) (bool, error) {
+	args := m.Called(role)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) FindAllRolePolicies() ([]models.RolePolicy, error) {
+	args := m.Called()
+	return args.Get(0).([]models.RolePolicy), args.Error(1)
+}
+
+func (m *MockMFARepository) SaveRolePolicy(policy *models.RolePolicy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func TestTOTPCode_RFC6238Vector(t *testing.T) {
+	// RFC 6238 appendix B, SHA1, T = 59s, truncated to 6 digits
+	assert.Equal(t, "287082", totpCode([]byte("12345678901234567890"), 59/totpPeriod))
+}
+
+func TestValidateTOTP_RejectsReplayedStep(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	key, _ := totpEncoding.DecodeString(secret)
+	step := now.Unix() / totpPeriod
+	code := totpCode(key, step)
+
+	matched, ok := validateTOTP(secret, code, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, step, matched)
+
+	_, ok = validateTOTP(secret, code, now, matched)
+	assert.False(t, ok)
+}
+
+func TestEnroll_ReturnsProvisioningURI(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.User{ID: 1, Email: "doctor@example.com"}, nil)
+	mockRepo.On("UpdateColumns", uint(1), mock.Anything).Return(nil)
+
+	service := NewMFAService(mockRepo, new(MockMFARepository), "Clinic")
+
+	res, err := service.Enroll(1)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, res.Secret)
+	assert.Contains(t, res.ProvisioningURI, "otpauth://totp/Clinic:doctor@example.com?")
+	assert.Contains(t, res.ProvisioningURI, "secret="+res.Secret)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestConfirmEnrollment_InvalidCode(t *testing.T) {
+	secret, _ := generateTOTPSecret()
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.User{ID: 1, TOTPSecret: secret}, nil)
+
+	service := NewMFAService(mockRepo, new(MockMFARepository), "Clinic")
+
+	res, err := service.ConfirmEnrollment(1, "000000")
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrInvalidMFACode, err)
+}
+
+func TestVerify_ReplayedCode(t *testing.T) {
+	secret, _ := generateTOTPSecret()
+	key, _ := totpEncoding.DecodeString(secret)
+	step := time.Now().Unix() / totpPeriod
+	mockRepo := new(MockUserRepository)
+	// Another login accepted the same code after this user was loaded
+	mockRepo.On("AdvanceTOTPStep", uint(1), step).Return(false, nil)
+
+	service := NewMFAService(mockRepo, new(MockMFARepository), "Clinic")
+
+	err := service.Verify(&models.User{ID: 1, MFAEnabled: true, TOTPSecret: secret}, totpCode(key, step), "")
+
+	assert.Equal(t, ErrInvalidMFACode, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestVerify_RecoveryCode(t *testing.T) {
+	mockMFARepo := new(MockMFARepository)
+	mockMFARepo.On("UseRecoveryCode", uint(1), hashToken("ABCDE12345")).Return(true, nil)
+
+	service := NewMFAService(new(MockUserRepository), mockMFARepo, "Clinic")
+
+	err := service.Verify(&models.User{ID: 1, MFAEnabled: true}, "", "abcde-12345")
+
+	assert.NoError(t, err)
+	mockMFARepo.AssertExpectations(t)
+}
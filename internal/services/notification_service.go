@@ -0,0 +1,498 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// deliveryBatchSize caps the notifications delivered in one run
+	deliveryBatchSize = 100
+	// retryBaseDelay is the wait before retrying a failed delivery; each
+	// further failure doubles it, up to retryMaxDelay
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = time.Hour
+	// deliveryLease is how long notifications claimed by one delivery run
+	// are held before another run may claim them again
+	deliveryLease = 15 * time.Minute
+)
+
+// NotificationRepository defines the notification data operations used by
+// the services
+type NotificationRepository interface {
+	Create(notification *models.Notification, event *models.AuditEvent) (bool, error)
+	FindEventKeys(keys []string) ([]string, error)
+	ClaimDue(at time.Time, lease time.Duration, limit int) ([]models.Notification, error)
+	UpdateDelivery(notification *models.Notification) error
+	FindAll(filter models.NotificationFilter) ([]models.Notification, error)
+	FindPreference(patientID uint) (*models.ContactPreference, error)
+	SavePreference(preference *models.ContactPreference, event *models.AuditEvent) error
+}
+
+// NotificationService sends messages to patients. Messages are rendered
+// from templates for the channel the patient prefers and put in a
+// persistent outbox, which is delivered in the background with retries. It
+// also queues reminders of booked appointments.
+type NotificationService struct {
+	notificationRepo NotificationRepository
+	appointmentRepo  AppointmentRepository
+	patientService   *PatientService
+	patientRepo      PatientRepository
+	userRepo         UserRepository
+	notifiers        map[models.NotificationChannel]Notifier
+	auditRepo        AuditRepository
+	location         *time.Location
+	reminders        []time.Duration
+	maxAttempts      int
+}
+
+// NewNotificationService creates a new NotificationService. Appointments
+// are reminded each of the reminder lead times before they start, and
+// deliveries are given up after maxAttempts failures.
+func NewNotificationService(notificationRepo NotificationRepository, appointmentRepo AppointmentRepository, patientService *PatientService, patientRepo PatientRepository, userRepo UserRepository, emailNotifier, smsNotifier Notifier, auditRepo AuditRepository, location *time.Location, reminders []time.Duration, maxAttempts int) *NotificationService {
+	// Reminder windows are worked out from the longest lead time down
+	sorted := append([]time.Duration(nil), reminders...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] > sorted[j] })
+
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		appointmentRepo:  appointmentRepo,
+		patientService:   patientService,
+		patientRepo:      patientRepo,
+		userRepo:         userRepo,
+		notifiers: map[models.NotificationChannel]Notifier{
+			models.NotificationChannelEmail: emailNotifier,
+			models.NotificationChannelSMS:   smsNotifier,
+		},
+		auditRepo:   auditRepo,
+		location:    location,
+		reminders:   sorted,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Queue puts a notification to a patient in the outbox, rendered from the
+// templates of its kind with data. The data is kept with it, so it can be
+// rendered again if the patient has to be reached on another channel. A
+// notification the patient opted out of, or cannot be reached for, is stored
+// as suppressed rather than sent. Each event key is queued once; queueing it
+// again does nothing.
+func (s *NotificationService) Queue(accessor PatientAccessor, patient *models.Patient, kind models.NotificationKind, eventKey string, data appointmentMessage) error {
+	return s.queue(accessor, patient, kind, eventKey, data, nil)
+}
+
+// queue puts a notification in the outbox like Queue. A notification about
+// an appointment records its start, so that it is not sent once the
+// appointment is cancelled or moved.
+func (s *NotificationService) queue(accessor PatientAccessor, patient *models.Patient, kind models.NotificationKind, eventKey string, data appointmentMessage, appointment *models.Appointment) error {
+	if _, ok := notificationTemplates[kind]; !ok {
+		return fmt.Errorf("no templates for %s notifications", kind)
+	}
+	preference, err := s.findPreference(patient.ID)
+	if err != nil {
+		return err
+	}
+
+	notification := &models.Notification{
+		PatientID:     patient.ID,
+		Kind:          kind,
+		EventKey:      eventKey,
+		Status:        models.NotificationStatusPending,
+		NextAttemptAt: time.Now(),
+	}
+	if appointment != nil {
+		start := appointment.StartTime
+		notification.AppointmentID = &appointment.ID
+		notification.AppointmentStart = &start
+	}
+	channel, recipient := contactFor(patient, preference)
+	switch {
+	case kind == models.NotificationKindAppointmentReminder && preference.RemindersOptOut:
+		notification.Status = models.NotificationStatusSuppressed
+		notification.LastError = "patient opted out of reminders"
+	case channel == "":
+		notification.Status = models.NotificationStatusSuppressed
+		notification.LastError = "no contact details the patient has not opted out of"
+	default:
+		messageData, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		message := string(messageData)
+		notification.MessageData = &message
+		if err := renderNotification(notification, channel, data); err != nil {
+			return err
+		}
+		notification.Recipient = recipient
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionNotificationCreate, patient.ID, nil)
+	event.Details = fmt.Sprintf("notification=%s channel=%s status=%s", eventKey, notification.Channel, notification.Status)
+	_, err = s.notificationRepo.Create(notification, event)
+	return err
+}
+
+// QueueReminders queues the reminders of booked appointments due at a
+// time. An appointment gets the reminder of the shortest lead time it
+// starts within, so one booked at short notice is reminded once rather than
+// once per lead time.
+func (s *NotificationService) QueueReminders(now time.Time) error {
+	for i, lead := range s.reminders {
+		from := now
+		if i+1 < len(s.reminders) {
+			from = now.Add(s.reminders[i+1])
+		}
+		to := now.Add(lead)
+
+		appointments, err := s.appointmentRepo.FindAll(models.AppointmentFilter{
+			Status: models.AppointmentStatusBooked,
+			From:   &from,
+			To:     &to,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.queueReminders(appointments, lead); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue claims the pending notifications that are due and delivers
+// them. Each is checked against the patient's preferences again first and
+// is suppressed when it can no longer be sent. A failed delivery is retried
+// later, waiting longer after each failure, until the notification runs out
+// of attempts and is marked failed.
+func (s *NotificationService) DeliverDue() error {
+	notifications, err := s.notificationRepo.ClaimDue(time.Now(), deliveryLease, deliveryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range notifications {
+		notification := &notifications[i]
+		reason, err := s.recheck(notification)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			notification.Status = models.NotificationStatusSuppressed
+			notification.LastError = reason
+		} else {
+			s.deliver(notification)
+		}
+		if err := s.notificationRepo.UpdateDelivery(notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartDelivery queues due reminders and delivers due notifications every
+// interval until stop is closed
+func (s *NotificationService) StartDelivery(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.QueueReminders(time.Now()); err != nil {
+					log.Printf("Failed to queue appointment reminders: %v", err)
+				}
+				if err := s.DeliverDue(); err != nil {
+					log.Printf("Failed to deliver notifications: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// GetNotifications lists notifications newest first and records which
+// patients were listed
+func (s *NotificationService) GetNotifications(accessor PatientAccessor, filter models.NotificationFilter) ([]models.Notification, error) {
+	notifications, err := s.notificationRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(notifications))
+	for i, notification := range notifications {
+		ids[i] = fmt.Sprint(notification.PatientID)
+	}
+	event := newAuditEvent(accessor, models.AuditActionSearch, filter.PatientID, nil)
+	event.Details = fmt.Sprintf("notifications patients=[%s]", strings.Join(ids, ","))
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GetContactPreference gets how a patient wants to be contacted
+func (s *NotificationService) GetContactPreference(accessor PatientAccessor, patientID uint) (*models.ContactPreference, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	preference, err := s.findPreference(patientID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "contact preferences"
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return preference, nil
+}
+
+// UpdateContactPreference replaces how a patient wants to be contacted,
+// including the channels and messages they opted out of
+func (s *NotificationService) UpdateContactPreference(accessor PatientAccessor, patientID uint, req models.UpdateContactPreferenceRequest) (*models.ContactPreference, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionContactUpdate); err != nil {
+		return nil, err
+	}
+
+	preference, err := s.findPreference(patientID)
+	if err != nil {
+		return nil, err
+	}
+	before := *preference
+	preference.PreferredChannel = req.PreferredChannel
+	preference.EmailOptOut = req.EmailOptOut
+	preference.SMSOptOut = req.SMSOptOut
+	preference.RemindersOptOut = req.RemindersOptOut
+	preference.UpdatedBy = accessor.UserID
+
+	event := newAuditEvent(accessor, models.AuditActionContactUpdate, patientID, models.DiffRecords(&before, preference))
+	if err := s.notificationRepo.SavePreference(preference, event); err != nil {
+		return nil, err
+	}
+
+	return preference, nil
+}
+
+// queueReminders queues the reminders of a lead time for the appointments
+// that have not had them yet
+func (s *NotificationService) queueReminders(appointments []models.PatientAppointment, lead time.Duration) error {
+	if len(appointments) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(appointments))
+	for i := range appointments {
+		keys[i] = reminderKey(&appointments[i].Appointment, lead)
+	}
+	queued, err := s.notificationRepo.FindEventKeys(keys)
+	if err != nil {
+		return err
+	}
+	done := make(map[string]bool, len(queued))
+	for _, key := range queued {
+		done[key] = true
+	}
+
+	for i := range appointments {
+		appointment := &appointments[i].Appointment
+		if done[keys[i]] {
+			continue
+		}
+		patient, err := s.patientRepo.FindByID(appointment.PatientID)
+		if err != nil {
+			continue
+		}
+
+		data := newAppointmentMessage(patient, appointment.Type, appointment.StartTime, s.location)
+		if doctor, err := s.userRepo.FindByID(appointment.DoctorID); err == nil {
+			data.DoctorName = doctor.Name
+		}
+		if err := s.queue(systemAccessor, patient, models.NotificationKindAppointmentReminder, keys[i], data, appointment); err != nil {
+			log.Printf("Failed to queue reminder of appointment %d: %v", appointment.ID, err)
+		}
+	}
+	return nil
+}
+
+// recheck checks that a queued notification can still be sent: the patient
+// has not opted out of it since it was queued, and the appointment a
+// reminder is about is still booked at the same time. The channel and
+// recipient are chosen again from the current preferences, and the message
+// is rendered again when the channel changed. It gives why the notification
+// must be suppressed instead, or "" when it can be sent.
+func (s *NotificationService) recheck(notification *models.Notification) (string, error) {
+	patient, err := s.patientRepo.FindByID(notification.PatientID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "patient not found", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	preference, err := s.findPreference(patient.ID)
+	if err != nil {
+		return "", err
+	}
+
+	if notification.Kind == models.NotificationKindAppointmentReminder {
+		if preference.RemindersOptOut {
+			return "patient opted out of reminders", nil
+		}
+		if notification.AppointmentID != nil {
+			appointment, err := s.appointmentRepo.FindByID(*notification.AppointmentID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", err
+			}
+			if err != nil || appointment.Status != models.AppointmentStatusBooked ||
+				notification.AppointmentStart == nil || !appointment.StartTime.Equal(*notification.AppointmentStart) {
+				return "appointment was cancelled or rescheduled", nil
+			}
+		}
+	}
+
+	channel, recipient := contactFor(patient, preference)
+	if channel != notification.Channel && notification.MessageData == nil {
+		// Without its message data the notification can only be sent on the
+		// channel it was rendered for
+		channel, recipient = notification.Channel, addressFor(patient, preference, notification.Channel)
+	}
+	if recipient == "" {
+		return "no contact details the patient has not opted out of", nil
+	}
+
+	if channel != notification.Channel {
+		var data appointmentMessage
+		if err := json.Unmarshal([]byte(*notification.MessageData), &data); err != nil {
+			return "", err
+		}
+		if err := renderNotification(notification, channel, data); err != nil {
+			return "", err
+		}
+	}
+	notification.Recipient = recipient
+	return "", nil
+}
+
+// deliver makes one delivery attempt of a notification and records the
+// outcome on it
+func (s *NotificationService) deliver(notification *models.Notification) {
+	notification.Attempts++
+
+	err := errors.New("no notifier for channel " + string(notification.Channel))
+	if notifier, ok := s.notifiers[notification.Channel]; ok && notifier != nil {
+		err = notifier.Notify(notification.Recipient, notification.Subject, notification.Body)
+	}
+
+	now := time.Now()
+	if err == nil {
+		notification.Status = models.NotificationStatusSent
+		notification.SentAt = &now
+		notification.LastError = ""
+		return
+	}
+
+	notification.LastError = err.Error()
+	if notification.Attempts >= s.maxAttempts {
+		notification.Status = models.NotificationStatusFailed
+		return
+	}
+	notification.NextAttemptAt = now.Add(retryDelay(notification.Attempts))
+}
+
+// renderNotification fills in the subject and body of a notification from
+// the templates of its kind for the channel it is sent on
+func renderNotification(notification *models.Notification, channel models.NotificationChannel, data appointmentMessage) error {
+	tmpl, ok := notificationTemplates[notification.Kind]
+	if !ok {
+		return fmt.Errorf("no templates for %s notifications", notification.Kind)
+	}
+	subject, body, err := tmpl.render(channel, data)
+	if err != nil {
+		return err
+	}
+
+	notification.Channel = channel
+	notification.Subject = subject
+	notification.Body = body
+	return nil
+}
+
+// findPreference finds the contact preferences of a patient, or the
+// defaults when none were set
+func (s *NotificationService) findPreference(patientID uint) (*models.ContactPreference, error) {
+	preference, err := s.notificationRepo.FindPreference(patientID)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		preference = &models.ContactPreference{PatientID: patientID}
+	}
+	return preference, nil
+}
+
+// contactFor chooses the channel and address to reach a patient at: the
+// preferred channel when the patient has an address for it and has not
+// opted out of it, otherwise the other channel on the same terms. The
+// channel is empty when neither can be used.
+func contactFor(patient *models.Patient, preference *models.ContactPreference) (models.NotificationChannel, string) {
+	channels := []models.NotificationChannel{models.NotificationChannelEmail, models.NotificationChannelSMS}
+	if preference.PreferredChannel == models.NotificationChannelSMS {
+		channels = []models.NotificationChannel{models.NotificationChannelSMS, models.NotificationChannelEmail}
+	}
+
+	for _, channel := range channels {
+		if address := addressFor(patient, preference, channel); address != "" {
+			return channel, address
+		}
+	}
+	return "", ""
+}
+
+// addressFor gives the address to reach a patient at on a channel, or ""
+// when the patient has none or opted out of the channel
+func addressFor(patient *models.Patient, preference *models.ContactPreference, channel models.NotificationChannel) string {
+	switch {
+	case channel == models.NotificationChannelEmail && !preference.EmailOptOut:
+		return patient.Email
+	case channel == models.NotificationChannelSMS && !preference.SMSOptOut:
+		return patient.ContactNumber
+	}
+	return ""
+}
+
+// reminderKey is the event key of the reminder of an appointment at a lead
+// time. The start time is part of it, so a rescheduled appointment is
+// reminded again.
+func reminderKey(appointment *models.Appointment, lead time.Duration) string {
+	return fmt.Sprintf("appointment_reminder:%d:%d:%s", appointment.ID, appointment.StartTime.Unix(), lead)
+}
+
+// retryDelay is the wait before retrying a delivery that failed attempts
+// times
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
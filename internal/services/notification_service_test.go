@@ -0,0 +1,368 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationRepository is a mock implementation of
+// NotificationRepository
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *models.Notification, event *models.AuditEvent) (bool, error) {
+	args := m.Called(notification, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) FindEventKeys(keys []string) ([]string, error) {
+	args := m.Called(keys)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ClaimDue(at time.Time, lease time.Duration, limit int) ([]models.Notification, error) {
+	args := m.Called(at, lease, limit)
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateDelivery(notification *models.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) FindAll(filter models.NotificationFilter) ([]models.Notification, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) FindPreference(patientID uint) (*models.ContactPreference, error) {
+	args := m.Called(patientID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ContactPreference), args.Error(1)
+}
+
+func (m *MockNotificationRepository) SavePreference(preference *models.ContactPreference, event *models.AuditEvent) error {
+	args := m.Called(preference, event)
+	return args.Error(0)
+}
+
+// newMockNotificationRepository creates a MockNotificationRepository
+// queueing any notification to patients without contact preferences
+func newMockNotificationRepository() *MockNotificationRepository {
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("FindPreference", mock.Anything).Return(nil, nil).Maybe()
+	mockNotificationRepo.On("Create", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	return mockNotificationRepo
+}
+
+// failingNotifier fails every message it is asked to send
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(to, subject, body string) error {
+	return errors.New("connection refused")
+}
+
+// newTestNotificationService creates a NotificationService for patient 1,
+// who has an email address and a contact number, and patient 2, who only has
+// a contact number, that reminds appointments 24 and 2 hours ahead and gives
+// up after three failed deliveries
+func newTestNotificationService(notificationRepo *MockNotificationRepository, appointmentRepo *MockAppointmentRepository, email, sms Notifier) *NotificationService {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{
+		ID: 1, FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", ContactNumber: "555-0100",
+	}, nil).Maybe()
+	mockPatientRepo.On("FindByID", uint(2)).Return(&models.Patient{
+		ID: 2, FirstName: "John", LastName: "Roe", ContactNumber: "555-0199",
+	}, nil).Maybe()
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Name: "Dr. Smith"}, nil).Maybe()
+	patientService := NewPatientService(mockPatientRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	return NewNotificationService(notificationRepo, appointmentRepo, patientService, mockPatientRepo, mockUserRepo,
+		email, sms, newMockAuditRepository(), time.UTC, []time.Duration{2 * time.Hour, 24 * time.Hour}, 3)
+}
+
+// testPatient returns patient 1 as newTestNotificationService finds them
+func testPatient() *models.Patient {
+	return &models.Patient{ID: 1, FirstName: "Jane", LastName: "Doe", Email: "jane@example.com", ContactNumber: "555-0100"}
+}
+
+func TestQueue_PreferredChannel(t *testing.T) {
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("FindPreference", uint(1)).Return(&models.ContactPreference{
+		PatientID: 1, PreferredChannel: models.NotificationChannelSMS,
+	}, nil)
+	mockNotificationRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Channel == models.NotificationChannelSMS && n.Recipient == "555-0100" && n.Subject == "" &&
+			n.Status == models.NotificationStatusPending && strings.Contains(n.Body, "Monday 2 March 2099 at 09:20") &&
+			strings.Contains(*n.MessageData, `"Time":"09:20"`)
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionNotificationCreate && *e.PatientID == 1
+	})).Return(true, nil)
+
+	service := newTestNotificationService(mockNotificationRepo, new(MockAppointmentRepository), &recordingNotifier{}, &recordingNotifier{})
+
+	data := newAppointmentMessage(testPatient(), models.AppointmentTypeConsultation, testSlot("09:20"), time.UTC)
+	err := service.Queue(receptionistAccessor, testPatient(), models.NotificationKindAppointmentReminder, "reminder", data)
+
+	assert.NoError(t, err)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestQueue_OptedOutOfPreferredChannel(t *testing.T) {
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("FindPreference", uint(1)).Return(&models.ContactPreference{
+		PatientID: 1, PreferredChannel: models.NotificationChannelEmail, EmailOptOut: true,
+	}, nil)
+	mockNotificationRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Channel == models.NotificationChannelSMS && n.Recipient == "555-0100"
+	}), mock.Anything).Return(true, nil)
+
+	service := newTestNotificationService(mockNotificationRepo, new(MockAppointmentRepository), &recordingNotifier{}, &recordingNotifier{})
+
+	data := newAppointmentMessage(testPatient(), models.AppointmentTypeConsultation, testSlot("09:20"), time.UTC)
+	err := service.Queue(receptionistAccessor, testPatient(), models.NotificationKindSlotOffer, "offer", data)
+
+	assert.NoError(t, err)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestQueue_RemindersOptOut(t *testing.T) {
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("FindPreference", uint(1)).Return(&models.ContactPreference{PatientID: 1, RemindersOptOut: true}, nil)
+	mockNotificationRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Kind == models.NotificationKindAppointmentReminder && n.Status == models.NotificationStatusSuppressed && n.Body == ""
+	}), mock.Anything).Return(true, nil).Once()
+	// Slot offers were asked for by joining the waitlist, so they still go out
+	mockNotificationRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Kind == models.NotificationKindSlotOffer && n.Status == models.NotificationStatusPending &&
+			n.Channel == models.NotificationChannelEmail && strings.Contains(n.Body, "quote offer 4")
+	}), mock.Anything).Return(true, nil).Once()
+
+	service := newTestNotificationService(mockNotificationRepo, new(MockAppointmentRepository), &recordingNotifier{}, &recordingNotifier{})
+
+	data := newAppointmentMessage(testPatient(), models.AppointmentTypeConsultation, testSlot("09:20"), time.UTC)
+	data.OfferID = 4
+	assert.NoError(t, service.Queue(systemAccessor, testPatient(), models.NotificationKindAppointmentReminder, "reminder", data))
+	assert.NoError(t, service.Queue(systemAccessor, testPatient(), models.NotificationKindSlotOffer, "offer", data))
+
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestQueueReminders(t *testing.T) {
+	now := testSlot("09:00").Add(-20 * time.Hour)
+	day := &models.Appointment{ID: 1, PatientID: 1, DoctorID: 5, Type: models.AppointmentTypeConsultation,
+		Status: models.AppointmentStatusBooked, StartTime: testSlot("09:00"), EndTime: testSlot("09:20")}
+	soon := &models.Appointment{ID: 2, PatientID: 1, DoctorID: 5, Type: models.AppointmentTypeFollowUp,
+		Status: models.AppointmentStatusBooked, StartTime: now.Add(time.Hour), EndTime: now.Add(80 * time.Minute)}
+
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindAll", mock.MatchedBy(func(f models.AppointmentFilter) bool {
+		return f.From.Equal(now.Add(2*time.Hour)) && f.To.Equal(now.Add(24*time.Hour))
+	})).Return([]models.PatientAppointment{{Appointment: *day}}, nil)
+	mockAppointmentRepo.On("FindAll", mock.MatchedBy(func(f models.AppointmentFilter) bool {
+		return f.From.Equal(now) && f.To.Equal(now.Add(2*time.Hour))
+	})).Return([]models.PatientAppointment{{Appointment: *soon}}, nil)
+
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("FindEventKeys", []string{reminderKey(day, 24*time.Hour)}).Return([]string{}, nil)
+	// The two-hour reminder of the other appointment was queued already
+	mockNotificationRepo.On("FindEventKeys", []string{reminderKey(soon, 2*time.Hour)}).Return([]string{reminderKey(soon, 2*time.Hour)}, nil)
+	mockNotificationRepo.On("FindPreference", uint(1)).Return(nil, nil)
+	mockNotificationRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.EventKey == reminderKey(day, 24*time.Hour) && *n.AppointmentID == day.ID && n.AppointmentStart.Equal(day.StartTime) &&
+			n.Subject == "Appointment reminder: Monday 2 March 2099 at 09:00" &&
+			strings.Contains(n.Body, "Dear Jane Doe") && strings.Contains(n.Body, "with Dr. Smith")
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionNotificationCreate && e.ActorID == 0
+	})).Return(true, nil).Once()
+
+	service := newTestNotificationService(mockNotificationRepo, mockAppointmentRepo, &recordingNotifier{}, &recordingNotifier{})
+
+	err := service.QueueReminders(now)
+
+	assert.NoError(t, err)
+	mockAppointmentRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue(t *testing.T) {
+	mockNotificationRepo := newMockNotificationRepository()
+	mockNotificationRepo.On("ClaimDue", mock.Anything, deliveryLease, deliveryBatchSize).Return([]models.Notification{
+		{ID: 1, PatientID: 1, Kind: models.NotificationKindSlotOffer, Channel: models.NotificationChannelEmail,
+			Recipient: "jane@example.com", Subject: "Hi", Body: "Hello", Status: models.NotificationStatusPending},
+		{ID: 2, PatientID: 2, Kind: models.NotificationKindSlotOffer, Channel: models.NotificationChannelSMS,
+			Recipient: "555-0199", Body: "Hello", Status: models.NotificationStatusPending},
+		{ID: 3, PatientID: 2, Kind: models.NotificationKindSlotOffer, Channel: models.NotificationChannelSMS,
+			Recipient: "555-0199", Body: "Hello", Status: models.NotificationStatusPending, Attempts: 2},
+	}, nil)
+	mockNotificationRepo.On("UpdateDelivery", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.ID == 1 && n.Status == models.NotificationStatusSent && n.SentAt != nil && n.Attempts == 1
+	})).Return(nil)
+	mockNotificationRepo.On("UpdateDelivery", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.ID == 2 && n.Status == models.NotificationStatusPending && n.Attempts == 1 &&
+			n.LastError == "connection refused" && n.NextAttemptAt.After(time.Now().Add(59*time.Second))
+	})).Return(nil)
+	mockNotificationRepo.On("UpdateDelivery", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.ID == 3 && n.Status == models.NotificationStatusFailed && n.Attempts == 3
+	})).Return(nil)
+	email := &recordingNotifier{}
+
+	service := newTestNotificationService(mockNotificationRepo, new(MockAppointmentRepository), email, failingNotifier{})
+
+	err := service.DeliverDue()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jane@example.com"}, email.to)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue_RechecksPreferences(t *testing.T) {
+	data, _ := json.Marshal(appointmentMessage{PatientName: "Jane Doe", Type: models.AppointmentTypeConsultation,
+		Date: "Monday 2 March 2099", Time: "09:00", HeldUntil: "10:00", OfferID: 4})
+	messageData := string(data)
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("ClaimDue", mock.Anything, deliveryLease, deliveryBatchSize).Return([]models.Notification{
+		{ID: 1, PatientID: 1, Kind: models.NotificationKindSlotOffer, Channel: models.NotificationChannelEmail,
+			Recipient: "jane@example.com", Subject: "Appointment slot available", Body: "Dear Jane Doe", MessageData: &messageData,
+			Status: models.NotificationStatusPending},
+		{ID: 2, PatientID: 1, Kind: models.NotificationKindAppointmentReminder, Channel: models.NotificationChannelSMS,
+			Recipient: "555-0100", Body: "Hello", Status: models.NotificationStatusPending},
+	}, nil)
+	// The patient opted out of email and of reminders after these were queued
+	mockNotificationRepo.On("FindPreference", uint(1)).Return(&models.ContactPreference{
+		PatientID: 1, EmailOptOut: true, RemindersOptOut: true,
+	}, nil)
+	mockNotificationRepo.On("UpdateDelivery", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.ID == 1 && n.Status == models.NotificationStatusSent && n.Channel == models.NotificationChannelSMS &&
+			n.Recipient == "555-0100" && n.Subject == "" && strings.Contains(n.Body, "quoting offer 4")
+	})).Return(nil)
+	mockNotificationRepo.On("UpdateDelivery", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.ID == 2 && n.Status == models.NotificationStatusSuppressed && n.LastError == "patient opted out of reminders"
+	})).Return(nil)
+	email, sms := &recordingNotifier{}, &recordingNotifier{}
+
+	service := newTestNotificationService(mockNotificationRepo, new(MockAppointmentRepository), email, sms)
+
+	err := service.DeliverDue()
+
+	assert.NoError(t, err)
+	assert.Empty(t, email.to)
+	assert.Equal(t, []string{"555-0100"}, sms.to)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue_KeepsNotificationWhenPatientLookupFails(t *testing.T) {
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("ClaimDue", mock.Anything, deliveryLease, deliveryBatchSize).Return([]models.Notification{
+		{ID: 1, PatientID: 1, Kind: models.NotificationKindSlotOffer, Channel: models.NotificationChannelEmail,
+			Recipient: "jane@example.com", Body: "Hello", Status: models.NotificationStatusPending},
+	}, nil)
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(nil, errors.New("connection reset"))
+	email := &recordingNotifier{}
+
+	service := newTestNotificationService(mockNotificationRepo, new(MockAppointmentRepository), email, &recordingNotifier{})
+	service.patientRepo = mockPatientRepo
+
+	err := service.DeliverDue()
+
+	// The notification stays pending and is claimed again after the lease
+	assert.Error(t, err)
+	assert.Empty(t, email.to)
+	mockNotificationRepo.AssertNotCalled(t, "UpdateDelivery", mock.Anything)
+}
+
+func TestDeliverDue_SuppressesRescheduledReminder(t *testing.T) {
+	moved, kept := uint(1), uint(2)
+	start := testSlot("09:00")
+	mockNotificationRepo := newMockNotificationRepository()
+	mockNotificationRepo.On("ClaimDue", mock.Anything, deliveryLease, deliveryBatchSize).Return([]models.Notification{
+		{ID: 1, PatientID: 1, Kind: models.NotificationKindAppointmentReminder, AppointmentID: &moved, AppointmentStart: &start,
+			Channel: models.NotificationChannelEmail, Recipient: "jane@example.com", Body: "Hello", Status: models.NotificationStatusPending},
+		{ID: 2, PatientID: 1, Kind: models.NotificationKindAppointmentReminder, AppointmentID: &kept, AppointmentStart: &start,
+			Channel: models.NotificationChannelEmail, Recipient: "jane@example.com", Body: "Hello", Status: models.NotificationStatusPending},
+	}, nil)
+	mockNotificationRepo.On("UpdateDelivery", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.ID == 1 && n.Status == models.NotificationStatusSuppressed && n.LastError == "appointment was cancelled or rescheduled"
+	})).Return(nil)
+	mockNotificationRepo.On("UpdateDelivery", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.ID == 2 && n.Status == models.NotificationStatusSent
+	})).Return(nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindByID", moved).Return(&models.Appointment{ID: moved, PatientID: 1,
+		Status: models.AppointmentStatusBooked, StartTime: testSlot("10:00"), EndTime: testSlot("10:20")}, nil)
+	mockAppointmentRepo.On("FindByID", kept).Return(&models.Appointment{ID: kept, PatientID: 1,
+		Status: models.AppointmentStatusBooked, StartTime: start, EndTime: testSlot("09:20")}, nil)
+	email := &recordingNotifier{}
+
+	service := newTestNotificationService(mockNotificationRepo, mockAppointmentRepo, email, &recordingNotifier{})
+
+	err := service.DeliverDue()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jane@example.com"}, email.to)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, retryDelay(1))
+	assert.Equal(t, 4*time.Minute, retryDelay(3))
+	assert.Equal(t, time.Hour, retryDelay(20))
+}
+
+func TestUpdateContactPreference(t *testing.T) {
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("FindPreference", uint(1)).Return(nil, nil)
+	mockNotificationRepo.On("SavePreference", mock.MatchedBy(func(p *models.ContactPreference) bool {
+		return p.PatientID == 1 && p.PreferredChannel == models.NotificationChannelSMS && p.RemindersOptOut && p.UpdatedBy == 1
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		_, changed := e.Changes["reminders_opt_out"]
+		return e.Action == models.AuditActionContactUpdate && *e.PatientID == 1 && changed
+	})).Return(nil)
+
+	service := newTestNotificationService(mockNotificationRepo, new(MockAppointmentRepository), &recordingNotifier{}, &recordingNotifier{})
+
+	preference, err := service.UpdateContactPreference(receptionistAccessor, 1, models.UpdateContactPreferenceRequest{
+		PreferredChannel: models.NotificationChannelSMS,
+		RemindersOptOut:  true,
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, preference.RemindersOptOut)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestHTTPSMSNotifier(t *testing.T) {
+	var received smsGatewayRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if received.To == "555-0199" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := NewHTTPSMSNotifier(server.URL, "secret", "Clinic")
+
+	assert.NoError(t, notifier.Notify("555-0100", "ignored", "Hello"))
+	assert.Equal(t, "Bearer secret", authorization)
+	assert.Equal(t, smsGatewayRequest{From: "Clinic", To: "555-0100", Message: "Hello"}, received)
+	assert.Error(t, notifier.Notify("555-0199", "", "Hello"))
+}
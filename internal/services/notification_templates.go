@@ -0,0 +1,98 @@
+package services
+
+import (
+	"strings"
+	"text/template"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// messageTemplate renders one kind of notification. Emails get the subject
+// and body; text messages get the shorter SMS text.
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+	sms     *template.Template
+}
+
+// appointmentMessage is the data the notification templates are filled in
+// with. Dates and times are in the clinic time zone.
+type appointmentMessage struct {
+	PatientName string
+	DoctorName  string
+	Type        models.AppointmentType
+	Date        string
+	Time        string
+	// HeldUntil is when a slot offer expires
+	HeldUntil string
+	OfferID   uint
+}
+
+// messageDateLayout is the layout of dates in notifications
+const messageDateLayout = "Monday 2 January 2006"
+
+// newAppointmentMessage fills in the message data of an appointment of a
+// patient starting at a time
+func newAppointmentMessage(patient *models.Patient, appointmentType models.AppointmentType, start time.Time, location *time.Location) appointmentMessage {
+	local := start.In(location)
+	return appointmentMessage{
+		PatientName: strings.TrimSpace(patient.FirstName + " " + patient.LastName),
+		Type:        appointmentType,
+		Date:        local.Format(messageDateLayout),
+		Time:        local.Format(models.ClockLayout),
+	}
+}
+
+// notificationTemplates holds the templates of each kind of notification
+var notificationTemplates = map[models.NotificationKind]messageTemplate{
+	models.NotificationKindAppointmentReminder: newMessageTemplate("appointment_reminder",
+		"Appointment reminder: {{.Date}} at {{.Time}}",
+		`Dear {{.PatientName}},
+
+This is a reminder of your {{.Type}} appointment{{if .DoctorName}} with {{.DoctorName}}{{end}} on {{.Date}} at {{.Time}}.
+
+If you cannot attend, please contact the clinic so the slot can be offered to another patient.`,
+		`Reminder: {{.Type}} appointment{{if .DoctorName}} with {{.DoctorName}}{{end}} on {{.Date}} at {{.Time}}. Please contact the clinic if you cannot attend.`),
+	models.NotificationKindSlotOffer: newMessageTemplate("slot_offer",
+		"Appointment slot available",
+		`Dear {{.PatientName}},
+
+An earlier {{.Type}} appointment is available on {{.Date}} at {{.Time}}.
+
+It is held for you until {{.HeldUntil}}. Contact the clinic and quote offer {{.OfferID}} to accept it.`,
+		`An earlier {{.Type}} appointment is available on {{.Date}} at {{.Time}}, held until {{.HeldUntil}}. Contact the clinic quoting offer {{.OfferID}} to accept.`),
+}
+
+// newMessageTemplate parses the templates of a kind of notification
+func newMessageTemplate(name, subject, body, sms string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New(name + ".subject").Parse(subject)),
+		body:    template.Must(template.New(name + ".body").Parse(body)),
+		sms:     template.Must(template.New(name + ".sms").Parse(sms)),
+	}
+}
+
+// render fills in the subject and body of a notification for a channel
+func (t messageTemplate) render(channel models.NotificationChannel, data interface{}) (string, string, error) {
+	if channel == models.NotificationChannelSMS {
+		body, err := executeTemplate(t.sms, data)
+		return "", body, err
+	}
+
+	subject, err := executeTemplate(t.subject, data)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := executeTemplate(t.body, data)
+	return subject, body, err
+}
+
+// executeTemplate fills in a template
+func executeTemplate(t *template.Template, data interface{}) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
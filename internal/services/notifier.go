@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Notifier delivers messages to users out of band, such as by email or text
+// message
+type Notifier interface {
+	Notify(to, subject, body string) error
+}
+
+// LogNotifier writes messages to a file, or to the application log when no
+// file is configured. It is meant for local development and testing.
+type LogNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewLogNotifier creates a new LogNotifier
+func NewLogNotifier(path string) *LogNotifier {
+	return &LogNotifier{path: path}
+}
+
+// Notify records the message
+func (n *LogNotifier) Notify(to, subject, body string) error {
+	entry := fmt.Sprintf("[%s] To: %s\nSubject: %s\n\n%s\n\n",
+		time.Now().Format(time.RFC3339), to, subject, body)
+
+	if n.path == "" {
+		log.Print(entry)
+		return nil
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(entry)
+	return err
+}
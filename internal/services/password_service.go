@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrPasswordUnchanged      = errors.New("new password must differ from the current password")
+	ErrInvalidResetToken      = errors.New("invalid or expired reset token")
+)
+
+// PasswordResetRepository defines the reset token data operations used by the PasswordService
+type PasswordResetRepository interface {
+	Create(token *models.PasswordResetToken) error
+	FindByHash(hash string) (*models.PasswordResetToken, error)
+	MarkUsed(id uint) (bool, error)
+	InvalidateAllForUser(userID uint) error
+}
+
+// PasswordService handles password changes and resets
+type PasswordService struct {
+	userRepo    UserRepository
+	resetRepo   PasswordResetRepository
+	sessionRepo SessionRepository
+	throttle    *LoginThrottle
+	notifier    Notifier
+	resetTTL    time.Duration
+	resetURL    string
+}
+
+// NewPasswordService creates a new PasswordService. resetURL may contain a
+// %s placeholder which is replaced by the reset token in notifications.
+func NewPasswordService(userRepo UserRepository, resetRepo PasswordResetRepository, sessionRepo SessionRepository,
+	throttle *LoginThrottle, notifier Notifier, resetTTL time.Duration, resetURL string) *PasswordService {
+	return &PasswordService{
+		userRepo:    userRepo,
+		resetRepo:   resetRepo,
+		sessionRepo: sessionRepo,
+		throttle:    throttle,
+		notifier:    notifier,
+		resetTTL:    resetTTL,
+		resetURL:    resetURL,
+	}
+}
+
+// ChangePassword changes a user's own password after checking the current
+// one. Every other session of the user is revoked.
+func (s *PasswordService) ChangePassword(userID uint, sessionID string, req models.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if !models.CheckPasswordHash(req.CurrentPassword, user.Password) {
+		return ErrInvalidCurrentPassword
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return ErrPasswordUnchanged
+	}
+
+	if err := s.setPassword(user.ID, req.NewPassword); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeAllForUserExcept(user.ID, sessionID)
+}
+
+// ForcePasswordChange requires a user to change their password on next login
+func (s *PasswordService) ForcePasswordChange(userID uint) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	return s.userRepo.UpdateColumns(userID, map[string]interface{}{"must_change_password": true})
+}
+
+// RequestReset sends a single-use reset token to the user with the given
+// email. Unknown emails and deactivated accounts are ignored so the response
+// does not reveal which accounts exist.
+func (s *PasswordService) RequestReset(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || !user.IsActive() {
+		return nil
+	}
+
+	token, err := generateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	// Only the most recent token stays valid
+	if err := s.resetRepo.InvalidateAllForUser(user.ID); err != nil {
+		return err
+	}
+
+	if err := s.resetRepo.Create(&models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(s.resetTTL),
+	}); err != nil {
+		return err
+	}
+
+	if err := s.notifier.Notify(user.Email, "Password reset", s.resetMessage(token)); err != nil {
+		log.Printf("Failed to send password reset for user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token. The token is
+// consumed, every session of the user is revoked and any lockout is cleared.
+func (s *PasswordService) ResetPassword(req models.ResetPasswordRequest) error {
+	stored, err := s.resetRepo.FindByHash(hashToken(req.Token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	used, err := s.resetRepo.MarkUsed(stored.ID)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.FindByID(stored.UserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.setPassword(user.ID, req.NewPassword); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(user.ID); err != nil {
+		return err
+	}
+
+	return s.throttle.Unlock(user.Email)
+}
+
+// setPassword stores a new password hash and clears the forced change flag
+func (s *PasswordService) setPassword(userID uint, password string) error {
+	hash, err := models.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.UpdateColumns(userID, map[string]interface{}{
+		"password":             hash,
+		"must_change_password": false,
+	})
+}
+
+// resetMessage builds the body of a password reset notification
+func (s *PasswordService) resetMessage(token string) string {
+	var b strings.Builder
+	b.WriteString("A password reset was requested for your account.\n\n")
+	if strings.Contains(s.resetURL, "%s") {
+		fmt.Fprintf(&b, "Reset your password: %s\n", strings.Replace(s.resetURL, "%s", token, 1))
+	} else {
+		fmt.Fprintf(&b, "Your reset token: %s\n", token)
+	}
+	fmt.Fprintf(&b, "\nThe token expires in %s and can be used once. "+
+		"If you did not request a reset, ignore this message.\n", s.resetTTL)
+	return b.String()
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPasswordResetRepository is a mock implementation of PasswordResetRepository
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(token *models.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) FindByHash(hash string) (*models.PasswordResetToken, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) MarkUsed(id uint) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) InvalidateAllForUser(userID uint) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// recordingNotifier records the notifications it is asked to send
+type recordingNotifier struct {
+	to   []string
+	body []string
+}
+
+func (n *recordingNotifier) Notify(to, subject, body string) error {
+	n.to = append(n.to, to)
+	n.body = append(n.body, body)
+	return nil
+}
+
+func TestChangePassword_WrongCurrentPassword(t *testing.T) {
+	hashedPassword, _ := models.HashPassword("password123")
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.User{ID: 1, Password: hashedPassword}, nil)
+
+	service := NewPasswordService(mockRepo, new(MockPasswordResetRepository), new(MockSessionRepository),
+		newTestLoginThrottle(), &recordingNotifier{}, time.Hour, "")
+
+	err := service.ChangePassword(1, "session", models.ChangePasswordRequest{
+		CurrentPassword: "wrongpassword",
+		NewPassword:     "newpassword123",
+	})
+
+	assert.Equal(t, ErrInvalidCurrentPassword, err)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_RevokesOtherSessions(t *testing.T) {
+	hashedPassword, _ := models.HashPassword("password123")
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.User{ID: 1, Password: hashedPassword, MustChangePassword: true}, nil)
+	mockRepo.On("UpdateColumns", uint(1), mock.MatchedBy(func(columns map[string]interface{}) bool {
+		hash, _ := columns["password"].(string)
+		return models.CheckPasswordHash("newpassword123", hash) && columns["must_change_password"] == false
+	})).Return(nil)
+	mockSessionRepo := new(MockSessionRepository)
+	mockSessionRepo.On("RevokeAllForUserExcept", uint(1), "session").Return(nil)
+
+	service := NewPasswordService(mockRepo, new(MockPasswordResetRepository), mockSessionRepo,
+		newTestLoginThrottle(), &recordingNotifier{}, time.Hour, "")
+
+	err := service.ChangePassword(1, "session", models.ChangePasswordRequest{
+		CurrentPassword: "password123",
+		NewPassword:     "newpassword123",
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestRequestReset_UnknownEmailIsIgnored(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByEmail", "nobody@example.com").Return(nil, errors.New("not found"))
+	notifier := &recordingNotifier{}
+
+	service := NewPasswordService(mockRepo, new(MockPasswordResetRepository), new(MockSessionRepository),
+		newTestLoginThrottle(), notifier, time.Hour, "")
+
+	err := service.RequestReset("nobody@example.com")
+
+	assert.NoError(t, err)
+	assert.Empty(t, notifier.to)
+}
+
+func TestRequestReset_SendsToken(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByEmail", "test@example.com").Return(&models.User{ID: 1, Email: "test@example.com"}, nil)
+	mockResetRepo := new(MockPasswordResetRepository)
+	mockResetRepo.On("InvalidateAllForUser", uint(1)).Return(nil)
+	mockResetRepo.On("Create", mock.AnythingOfType("*models.PasswordResetToken")).Return(nil)
+	notifier := &recordingNotifier{}
+
+	service := NewPasswordService(mockRepo, mockResetRepo, new(MockSessionRepository),
+		newTestLoginThrottle(), notifier, time.Hour, "https://app.example.com/reset?token=%s")
+
+	err := service.RequestReset("test@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test@example.com"}, notifier.to)
+	assert.Contains(t, notifier.body[0], "https://app.example.com/reset?token=")
+	mockResetRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_ExpiredToken(t *testing.T) {
+	mockResetRepo := new(MockPasswordResetRepository)
+	mockResetRepo.On("FindByHash", hashToken("token")).Return(&models.PasswordResetToken{
+		ID:        1,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	service := NewPasswordService(new(MockUserRepository), mockResetRepo, new(MockSessionRepository),
+		newTestLoginThrottle(), &recordingNotifier{}, time.Hour, "")
+
+	err := service.ResetPassword(models.ResetPasswordRequest{Token: "token", NewPassword: "newpassword123"})
+
+	assert.Equal(t, ErrInvalidResetToken, err)
+	mockResetRepo.AssertNotCalled(t, "MarkUsed", mock.Anything)
+}
+
+func TestResetPassword_TokenIsSingleUse(t *testing.T) {
+	mockResetRepo := new(MockPasswordResetRepository)
+	mockResetRepo.On("FindByHash", hashToken("token")).Return(&models.PasswordResetToken{
+		ID:        1,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	// A concurrent reset consumed the token first
+	mockResetRepo.On("MarkUsed", uint(1)).Return(false, nil)
+	mockRepo := new(MockUserRepository)
+
+	service := NewPasswordService(mockRepo, mockResetRepo, new(MockSessionRepository),
+		newTestLoginThrottle(), &recordingNotifier{}, time.Hour, "")
+
+	err := service.ResetPassword(models.ResetPasswordRequest{Token: "token", NewPassword: "newpassword123"})
+
+	assert.Equal(t, ErrInvalidResetToken, err)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything)
+}
@@ -1,165 +1,633 @@
-package services
-
-import (
-	"errors"
-
-	"healthcare-app/internal/models"
-	"healthcare-app/internal/repositories"
-)
-
-// Predefined errors
-var (
-	ErrPatientNotFound = errors.New("patient not found")
-)
-
-// PaginationResponse represents a paginated response
-type PaginationResponse struct {
-	TotalItems int64       `json:"totalItems"`
-	Items      interface{} `json:"items"`
-	Page       int         `json:"page"`
-	PageSize   int         `json:"pageSize"`
-	TotalPages int         `json:"totalPages"`
-}
-
-// PatientService handles patient business logic
-type PatientService struct {
-	patientRepo *repositories.PatientRepository
-}
-
-// NewPatientService creates a new PatientService
-func NewPatientService(patientRepo *repositories.PatientRepository) *PatientService {
-	return &PatientService{
-		patientRepo: patientRepo,
-	}
-}
-
-// CreatePatient creates a new patient
-func (s *PatientService) CreatePatient(req models.CreatePatientRequest, registeredByID uint) (*models.Patient, error) {
-	patient := &models.Patient{
-		FirstName:         req.FirstName,
-		LastName:          req.LastName,
-		DateOfBirth:       req.DateOfBirth,
-		Gender:            req.Gender,
-		ContactNumber:     req.ContactNumber,
-		Email:             req.Email,
-		Address:           req.Address,
-		EmergencyName:     req.EmergencyName,
-		EmergencyNumber:   req.EmergencyNumber,
-		BloodGroup:        req.BloodGroup,
-		Allergies:         req.Allergies,
-		MedicalHistory:    req.MedicalHistory,
-		CurrentMedication: req.CurrentMedication,
-		Notes:             req.Notes,
-		RegisteredBy:      registeredByID,
-	}
-
-	if err := s.patientRepo.Create(patient); err != nil {
-		return nil, err
-	}
-
-	return patient, nil
-}
-
-// GetPatient gets a patient by ID
-func (s *PatientService) GetPatient(id uint) (*models.Patient, error) {
-	patient, err := s.patientRepo.FindByID(id)
-	if err != nil {
-		return nil, ErrPatientNotFound
-	}
-
-	return patient, nil
-}
-
-// GetAllPatients gets all patients with pagination
-func (s *PatientService) GetAllPatients(page, pageSize int) (*PaginationResponse, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 {
-		pageSize = 10
-	}
-
-	offset := (page - 1) * pageSize
-	patients, totalItems, err := s.patientRepo.FindAll(pageSize, offset)
-	if err != nil {
-		return nil, err
-	}
-
-	totalPages := (int(totalItems) + pageSize - 1) / pageSize
-	
-	return &PaginationResponse{
-		TotalItems: totalItems,
-		Items:      patients,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}, nil
-}
-
-// UpdatePatient updates a patient
-func (s *PatientService) UpdatePatient(id uint, req models.UpdatePatientRequest) (*models.Patient, error) {
-	patient, err := s.patientRepo.FindByID(id)
-	if err != nil {
-		return nil, ErrPatientNotFound
-	}
-
-	patient.ApplyUpdates(req)
-
-	if err := s.patientRepo.Update(patient); err != nil {
-		return nil, err
-	}
-
-	return patient, nil
-}
-
-// UpdatePatientMedicalInfo updates a patient's medical information
-func (s *PatientService) UpdatePatientMedicalInfo(id uint, req models.UpdatePatientMedicalRequest) (*models.Patient, error) {
-	patient, err := s.patientRepo.FindByID(id)
-	if err != nil {
-		return nil, ErrPatientNotFound
-	}
-
-	patient.ApplyMedicalUpdates(req)
-
-	if err := s.patientRepo.Update(patient); err != nil {
-		return nil, err
-	}
-
-	return patient, nil
-}
-
-// DeletePatient deletes a patient
-func (s *PatientService) DeletePatient(id uint) error {
-	_, err := s.patientRepo.FindByID(id)
-	if err != nil {
-		return ErrPatientNotFound
-	}
-
-	return s.patientRepo.Delete(id)
-}
-
-// SearchPatients searches for patients
-func (s *PatientService) SearchPatients(searchTerm string, page, pageSize int) (*PaginationResponse, error) {
-	if page < 1 {
-		page = 1
-	}
-	if pageSize < 1 {
-		pageSize = 10
-	}
-
-	offset := (page - 1) * pageSize
-	patients, totalItems, err := s.patientRepo.SearchPatients(searchTerm, pageSize, offset)
-	if err != nil {
-		return nil, err
-	}
-
-	totalPages := (int(totalItems) + pageSize - 1) / pageSize
-	
-	return &PaginationResponse{
-		TotalItems: totalItems,
-		Items:      patients,
-		Page:       page,
-		PageSize:   pageSize,
-		TotalPages: totalPages,
-	}, nil
-} 
\ No newline at end of file
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrPatientNotFound        = errors.New("patient not found")
+	ErrPatientAccessDenied    = errors.New("patient is not on your care team")
+	ErrRevisionNotFound       = errors.New("patient revision not found")
+	ErrMedicalFieldsForbidden = errors.New("not allowed to change medical information")
+	ErrPatientModified        = errors.New("patient was modified since it was read")
+	ErrInvalidPatch           = errors.New("invalid patch")
+	ErrFieldNotPermitted      = errors.New("not allowed to change field")
+)
+
+// PatientConflictError reports an update based on an outdated version of a
+// patient, carrying the current version
+type PatientConflictError struct {
+	Current *models.Patient
+}
+
+// Error implements the error interface
+func (e *PatientConflictError) Error() string {
+	return ErrPatientModified.Error()
+}
+
+// Unwrap returns the underlying sentinel error
+func (e *PatientConflictError) Unwrap() error {
+	return ErrPatientModified
+}
+
+// PatientAccessor describes who is accessing patient records, how far their
+// access reaches and where the request came from
+type PatientAccessor struct {
+	UserID uint
+	Role   models.UserRole
+	// AllPatients grants access to every patient regardless of care teams
+	AllPatients bool
+	// CanOverride allows access outside the care team when OverrideReason is
+	// given; every such access is recorded
+	CanOverride    bool
+	OverrideReason string
+	// CanEditDemographics and CanEditMedical allow changes to the
+	// demographic and medical fields of a record
+	CanEditDemographics bool
+	CanEditMedical      bool
+	// EmergencyGrant is a break-the-glass grant presented with the request;
+	// it opens the patient it covers to its holder until it expires
+	EmergencyGrant *models.EmergencyAccessGrant
+	// RequestID and ClientIP identify the request in the audit trail
+	RequestID string
+	ClientIP  string
+}
+
+// PaginationResponse represents a paginated response
+type PaginationResponse struct {
+	TotalItems int64       `json:"totalItems"`
+	Items      interface{} `json:"items"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"pageSize"`
+	TotalPages int         `json:"totalPages"`
+}
+
+// PatientRepository defines the patient data operations used by the services
+type PatientRepository interface {
+	Create(patient *models.Patient, primary *models.CareTeamAssignment, event *models.AuditEvent) error
+	FindByID(id uint) (*models.Patient, error)
+	FindAll(filter models.PatientFilter, limit, offset int) ([]models.Patient, int64, error)
+	Update(patient *models.Patient, event *models.AuditEvent) (bool, error)
+	Delete(id uint, event *models.AuditEvent) error
+	SearchPatients(searchTerm string, filter models.PatientFilter, limit, offset int) ([]models.Patient, int64, error)
+	FindRevisions(patientID uint, limit, offset int) ([]models.PatientRevision, int64, error)
+	FindRevision(patientID uint, revision int) (*models.PatientRevision, error)
+	FindRevisionAsOf(patientID uint, at time.Time) (*models.PatientRevision, error)
+}
+
+// PatientService handles patient business logic. Every read and write of a
+// patient record is recorded in the audit trail.
+type PatientService struct {
+	patientRepo  PatientRepository
+	careTeamRepo CareTeamRepository
+	auditRepo    AuditRepository
+}
+
+// NewPatientService creates a new PatientService
+func NewPatientService(patientRepo PatientRepository, careTeamRepo CareTeamRepository, auditRepo AuditRepository) *PatientService {
+	return &PatientService{
+		patientRepo:  patientRepo,
+		careTeamRepo: careTeamRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+// CreatePatient creates a new patient. Medical fields are only taken from the
+// request when the accessor may edit them. A clinician limited to their care
+// teams becomes the primary physician of the patient they register.
+func (s *PatientService) CreatePatient(accessor PatientAccessor, req models.CreatePatientRequest) (*models.Patient, error) {
+	patient := &models.Patient{
+		FirstName:       req.FirstName,
+		LastName:        req.LastName,
+		DateOfBirth:     req.DateOfBirth,
+		Gender:          req.Gender,
+		ContactNumber:   req.ContactNumber,
+		Email:           req.Email,
+		Address:         req.Address,
+		EmergencyName:   req.EmergencyName,
+		EmergencyNumber: req.EmergencyNumber,
+		RegisteredBy:    accessor.UserID,
+		Version:         1,
+	}
+	if accessor.CanEditMedical {
+		patient.ApplyMedicalUpdates(req.MedicalUpdates())
+	}
+
+	var primary *models.CareTeamAssignment
+	if !accessor.AllPatients {
+		primary = &models.CareTeamAssignment{
+			ClinicianID: accessor.UserID,
+			Role:        models.CareTeamPrimary,
+			StartDate:   time.Now(),
+			AssignedBy:  accessor.UserID,
+		}
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionCreate, 0, models.DiffPatients(nil, patient))
+	if primary != nil {
+		event.Details = fmt.Sprintf("primary=%d", primary.ClinicianID)
+	}
+	if err := s.patientRepo.Create(patient, primary, event); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// GetPatient gets a patient by ID
+func (s *PatientService) GetPatient(accessor PatientAccessor, id uint) (*models.Patient, error) {
+	patient, err := s.patientRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, patient.ID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditRepo.Append(newAuditEvent(accessor, models.AuditActionRead, patient.ID, nil)); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// CheckAccess checks that a patient exists and that the accessor may act on
+// their records
+func (s *PatientService) CheckAccess(accessor PatientAccessor, patientID uint, action models.AuditAction) error {
+	_, err := s.findAccessible(accessor, patientID, action)
+	return err
+}
+
+// findAccessible finds a patient the accessor may act on
+func (s *PatientService) findAccessible(accessor PatientAccessor, patientID uint, action models.AuditAction) (*models.Patient, error) {
+	patient, err := s.patientRepo.FindByID(patientID)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, patientID, action); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// GetPatientAsOf reconstructs a patient record as it was at a point in time
+func (s *PatientService) GetPatientAsOf(accessor PatientAccessor, id uint, asOf time.Time) (*models.Patient, error) {
+	if _, err := s.patientRepo.FindByID(id); err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, id, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	revision, err := s.patientRepo.FindRevisionAsOf(id, asOf)
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+	patient, err := revision.Patient()
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, id, nil)
+	event.Details = fmt.Sprintf("asOf=%s revision=%d", asOf.UTC().Format(time.RFC3339), revision.Revision)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// GetPatientHistory gets the revisions of a patient with their authors and
+// changes, most recent first
+func (s *PatientService) GetPatientHistory(accessor PatientAccessor, id uint, page, pageSize int) (*PaginationResponse, error) {
+	if _, err := s.patientRepo.FindByID(id); err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, id, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	revisions, totalItems, err := s.patientRepo.FindRevisions(id, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+	// Changes to the legacy notes predate clinical notes and are only
+	// served with them
+	for i := range revisions {
+		delete(revisions[i].Changes, "notes")
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, id, nil)
+	event.Details = fmt.Sprintf("history page=%d pageSize=%d", page, pageSize)
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(totalItems) + pageSize - 1) / pageSize
+
+	return &PaginationResponse{
+		TotalItems: totalItems,
+		Items:      revisions,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// RevertPatient restores the fields of a patient that is still at the
+// expected version to an earlier revision. The revert is stored as a new
+// revision, so no history is lost.
+func (s *PatientService) RevertPatient(accessor PatientAccessor, id uint, expectedVersion, revisionNumber int) (*models.Patient, error) {
+	patient, err := s.patientRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, id, models.AuditActionRevert); err != nil {
+		return nil, err
+	}
+
+	if patient.Version != expectedVersion {
+		return nil, &PatientConflictError{Current: patient}
+	}
+
+	revision, err := s.patientRepo.FindRevision(id, revisionNumber)
+	if err != nil {
+		return nil, ErrRevisionNotFound
+	}
+	snapshot, err := revision.Patient()
+	if err != nil {
+		return nil, err
+	}
+
+	before := *patient
+	patient.RestoreFrom(snapshot)
+
+	changes := models.DiffPatients(&before, patient)
+	if changes.TouchesMedicalFields() && !accessor.CanEditMedical {
+		return nil, ErrMedicalFieldsForbidden
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRevert, id, changes)
+	event.Details = fmt.Sprintf("revision=%d", revisionNumber)
+	if err := s.update(patient, event); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// GetAllPatients gets all patients visible to the accessor matching a filter
+// with pagination
+func (s *PatientService) GetAllPatients(accessor PatientAccessor, filter models.PatientFilter, page, pageSize int) (*PaginationResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	filter.CareTeamMemberID = s.filterFor(accessor).CareTeamMemberID
+	patients, totalItems, err := s.patientRepo.FindAll(filter, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionList, 0, nil)
+	event.Details = fmt.Sprintf("page=%d pageSize=%d patients=%s", page, pageSize, patientIDs(patients))
+	if filter.ConditionCode != "" {
+		event.Details = fmt.Sprintf("condition=%s %s", filter.ConditionCode, event.Details)
+	}
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(totalItems) + pageSize - 1) / pageSize
+	
+	return &PaginationResponse{
+		TotalItems: totalItems,
+		Items:      patients,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// UpdatePatient updates a patient that is still at the expected version.
+// Medical fields are left unchanged unless the accessor may edit them, and
+// like the other fields only those given are applied.
+func (s *PatientService) UpdatePatient(accessor PatientAccessor, id uint, expectedVersion int, req models.UpdatePatientRequest) (*models.Patient, error) {
+	patient, err := s.patientRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, patient.ID, models.AuditActionUpdate); err != nil {
+		return nil, err
+	}
+
+	if patient.Version != expectedVersion {
+		return nil, &PatientConflictError{Current: patient}
+	}
+
+	before := *patient
+	patient.ApplyUpdates(req)
+	if accessor.CanEditMedical {
+		patient.MergeMedicalUpdates(req.MedicalUpdates())
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionUpdate, patient.ID, models.DiffPatients(&before, patient))
+	if err := s.update(patient, event); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// UpdatePatientMedicalInfo updates the medical information of a patient that
+// is still at the expected version
+func (s *PatientService) UpdatePatientMedicalInfo(accessor PatientAccessor, id uint, expectedVersion int, req models.UpdatePatientMedicalRequest) (*models.Patient, error) {
+	patient, err := s.patientRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, patient.ID, models.AuditActionUpdateMedical); err != nil {
+		return nil, err
+	}
+
+	if patient.Version != expectedVersion {
+		return nil, &PatientConflictError{Current: patient}
+	}
+
+	before := *patient
+	patient.ApplyMedicalUpdates(req)
+
+	event := newAuditEvent(accessor, models.AuditActionUpdateMedical, patient.ID, models.DiffPatients(&before, patient))
+	if err := s.update(patient, event); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// PatchPatient applies a JSON merge patch (RFC 7396) to a patient that is
+// still at the expected version: omitted fields are left untouched and null
+// clears a field. Each field may only be changed with its permission, and
+// medicalOnly limits the patch to medical fields. An empty patch is invalid.
+func (s *PatientService) PatchPatient(accessor PatientAccessor, id uint, expectedVersion int, patch map[string]json.RawMessage, medicalOnly bool) (*models.Patient, error) {
+	patient, err := s.patientRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPatientNotFound
+	}
+
+	action := models.AuditActionUpdate
+	if medicalOnly {
+		action = models.AuditActionUpdateMedical
+	}
+	if err := s.authorize(accessor, patient.ID, action); err != nil {
+		return nil, err
+	}
+
+	if patient.Version != expectedVersion {
+		return nil, &PatientConflictError{Current: patient}
+	}
+
+	if len(patch) == 0 {
+		return nil, fmt.Errorf("%w: the patch is empty", ErrInvalidPatch)
+	}
+
+	before := *patient
+	if err := applyPatientPatch(accessor, patient, patch, medicalOnly); err != nil {
+		return nil, err
+	}
+
+	// A patch that changes nothing still returns the record, so it is
+	// recorded as a read
+	changes := models.DiffPatients(&before, patient)
+	if len(changes) == 0 {
+		event := newAuditEvent(accessor, models.AuditActionRead, patient.ID, nil)
+		event.Details = "patch without changes"
+		if err := s.auditRepo.Append(event); err != nil {
+			return nil, err
+		}
+		return patient, nil
+	}
+
+	event := newAuditEvent(accessor, action, patient.ID, changes)
+	if err := s.update(patient, event); err != nil {
+		return nil, err
+	}
+
+	return patient, nil
+}
+
+// DeletePatient deletes a patient
+func (s *PatientService) DeletePatient(accessor PatientAccessor, id uint) error {
+	patient, err := s.patientRepo.FindByID(id)
+	if err != nil {
+		return ErrPatientNotFound
+	}
+
+	if err := s.authorize(accessor, id, models.AuditActionDelete); err != nil {
+		return err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionDelete, id, models.DiffPatients(patient, nil))
+	return s.patientRepo.Delete(id, event)
+}
+
+// SearchPatients searches for patients visible to the accessor
+func (s *PatientService) SearchPatients(accessor PatientAccessor, searchTerm string, page, pageSize int) (*PaginationResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	patients, totalItems, err := s.patientRepo.SearchPatients(searchTerm, s.filterFor(accessor), pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionSearch, 0, nil)
+	event.Details = fmt.Sprintf("q=%q page=%d pageSize=%d patients=%s", searchTerm, page, pageSize, patientIDs(patients))
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	totalPages := (int(totalItems) + pageSize - 1) / pageSize
+	
+	return &PaginationResponse{
+		TotalItems: totalItems,
+		Items:      patients,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// applyPatientPatch checks a merge patch against the field permissions of the
+// accessor and applies it to a patient
+func applyPatientPatch(accessor PatientAccessor, patient *models.Patient, patch map[string]json.RawMessage, medicalOnly bool) error {
+	// Apply fields in a stable order so errors are reproducible
+	names := make([]string, 0, len(patch))
+	for name := range patch {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		permission, ok := models.PatientFieldPermissions[name]
+		if !ok {
+			return fmt.Errorf("%w: field %q cannot be changed", ErrInvalidPatch, name)
+		}
+		if (medicalOnly && permission != models.PermMedicalWrite) || !accessor.canChange(permission) {
+			return fmt.Errorf("%w: %s", ErrFieldNotPermitted, name)
+		}
+
+		value := patch[name]
+		if models.IsNull(value) && models.RequiredPatientFields[name] {
+			return fmt.Errorf("%w: field %q cannot be cleared", ErrInvalidPatch, name)
+		}
+		if err := patient.SetField(name, value); err != nil {
+			return fmt.Errorf("%w: invalid value for field %q", ErrInvalidPatch, name)
+		}
+	}
+
+	return validatePatient(patient)
+}
+
+// validatePatient checks the values a patch may leave invalid
+func validatePatient(patient *models.Patient) error {
+	if patient.FirstName == "" || patient.LastName == "" || patient.DateOfBirth.IsZero() ||
+		patient.ContactNumber == "" || patient.Address == "" {
+		return fmt.Errorf("%w: required fields cannot be empty", ErrInvalidPatch)
+	}
+	switch patient.Gender {
+	case "male", "female", "other":
+	default:
+		return fmt.Errorf("%w: gender must be male, female or other", ErrInvalidPatch)
+	}
+	if patient.Email != "" {
+		if _, err := mail.ParseAddress(patient.Email); err != nil {
+			return fmt.Errorf("%w: invalid email", ErrInvalidPatch)
+		}
+	}
+	return nil
+}
+
+// canChange checks if the accessor holds the permission needed to change a field
+func (a PatientAccessor) canChange(permission models.Permission) bool {
+	switch permission {
+	case models.PermPatientsWrite:
+		return a.CanEditDemographics
+	case models.PermMedicalWrite:
+		return a.CanEditMedical
+	default:
+		return false
+	}
+}
+
+// update stores a changed patient, reporting a conflict with the current
+// version when another write got there first
+func (s *PatientService) update(patient *models.Patient, event *models.AuditEvent) error {
+	updated, err := s.patientRepo.Update(patient, event)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		current, err := s.patientRepo.FindByID(patient.ID)
+		if err != nil {
+			return ErrPatientNotFound
+		}
+		return &PatientConflictError{Current: current}
+	}
+	return nil
+}
+
+// authorize checks that the accessor may act on a patient. Access outside
+// the care team requires an explicit override, which is recorded; denied
+// attempts are recorded in the audit trail.
+func (s *PatientService) authorize(accessor PatientAccessor, patientID uint, action models.AuditAction) error {
+	if accessor.AllPatients {
+		return nil
+	}
+
+	member, err := s.careTeamRepo.IsMember(patientID, accessor.UserID, time.Now())
+	if err != nil {
+		return err
+	}
+	if member {
+		return nil
+	}
+
+	// Reads and writes under an emergency grant are recorded like overrides
+	if grant := accessor.EmergencyGrant; grant != nil && grant.UserID == accessor.UserID &&
+		grant.PatientID == patientID && grant.IsActiveAt(time.Now()) {
+		return s.careTeamRepo.RecordOverride(&models.AccessOverride{
+			UserID:    accessor.UserID,
+			PatientID: patientID,
+			Action:    string(action),
+			Reason:    fmt.Sprintf("emergency access grant %d: %s", grant.ID, grant.Reason),
+		})
+	}
+
+	if !accessor.CanOverride || accessor.OverrideReason == "" {
+		event := newAuditEvent(accessor, models.AuditActionAccessDenied, patientID, nil)
+		event.Details = string(action)
+		if err := s.auditRepo.Append(event); err != nil {
+			return err
+		}
+		return ErrPatientAccessDenied
+	}
+
+	return s.careTeamRepo.RecordOverride(&models.AccessOverride{
+		UserID:    accessor.UserID,
+		PatientID: patientID,
+		Action:    string(action),
+		Reason:    accessor.OverrideReason,
+	})
+}
+
+// filterFor limits listings to the accessor's care teams when required
+func (s *PatientService) filterFor(accessor PatientAccessor) models.PatientFilter {
+	if accessor.AllPatients {
+		return models.PatientFilter{}
+	}
+	return models.PatientFilter{CareTeamMemberID: accessor.UserID}
+}
+
+// patientIDs formats the IDs of listed patients for the audit trail
+func patientIDs(patients []models.Patient) string {
+	ids := make([]string, len(patients))
+	for i, patient := range patients {
+		ids[i] = fmt.Sprint(patient.ID)
+	}
+	return "[" + strings.Join(ids, ",") + "]"
+}
\ No newline at end of file
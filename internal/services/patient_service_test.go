@@ -1,267 +1,631 @@
-package services
-
-import (
-	"errors"
-	"testing"
-	"time"
-
-	"healthcare-app/internal/models"
-
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
-)
-
-// MockPatientRepository is a mock implementation of PatientRepository
-type MockPatientRepository struct {
-	mock.Mock
-}
-
-func (m *MockPatientRepository) Create(patient *models.Patient) error {
-	args := m.Called(patient)
-	return args.Error(0)
-}
-
-func (m *MockPatientRepository) FindByID(id uint) (*models.Patient, error) {
-	args := m.Called(id)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*models.Patient), args.Error(1)
-}
-
-func (m *MockPatientRepository) FindAll(limit, offset int) ([]models.Patient, int64, error) {
-	args := m.Called(limit, offset)
-	return args.Get(0).([]models.Patient), args.Get(1).(int64), args.Error(2)
-}
-
-func (m *MockPatientRepository) Update(patient *models.Patient) error {
-	args := m.Called(patient)
-	return args.Error(0)
-}
-
-func (m *MockPatientRepository) Delete(id uint) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
-
-func (m *MockPatientRepository) SearchPatients(searchTerm string, limit, offset int) ([]models.Patient, int64, error) {
-	args := m.Called(searchTerm, limit, offset)
-	return args.Get(0).([]models.Patient), args.Get(1).(int64), args.Error(2)
-}
-
-func TestCreatePatient(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockPatientRepository)
-	
-	// Create test data
-	dob, _ := time.Parse("2006-01-02", "1990-01-01")
-	createReq := models.CreatePatientRequest{
-		FirstName:     "John",
-		LastName:      "Doe",
-		DateOfBirth:   dob,
-		Gender:        "male",
-		ContactNumber: "1234567890",
-		Email:         "john.doe@example.com",
-		Address:       "123 Main St",
-	}
-	
-	// Setup expectations
-	mockRepo.On("Create", mock.AnythingOfType("*models.Patient")).Return(nil)
-	
-	// Create service with mock
-	service := NewPatientService(mockRepo)
-	
-	// Test create patient
-	patient, err := service.CreatePatient(createReq, 1)
-	
-	// Assert results
-	assert.NoError(t, err)
-	assert.NotNil(t, patient)
-	assert.Equal(t, "John", patient.FirstName)
-	assert.Equal(t, "Doe", patient.LastName)
-	assert.Equal(t, dob, patient.DateOfBirth)
-	assert.Equal(t, "male", patient.Gender)
-	assert.Equal(t, "1234567890", patient.ContactNumber)
-	assert.Equal(t, "john.doe@example.com", patient.Email)
-	assert.Equal(t, "123 Main St", patient.Address)
-	assert.Equal(t, uint(1), patient.RegisteredBy)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestGetPatient_Success(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockPatientRepository)
-	
-	// Create test data
-	dob, _ := time.Parse("2006-01-02", "1990-01-01")
-	patient := &models.Patient{
-		ID:            1,
-		FirstName:     "John",
-		LastName:      "Doe",
-		DateOfBirth:   dob,
-		Gender:        "male",
-		ContactNumber: "1234567890",
-		Email:         "john.doe@example.com",
-		Address:       "123 Main St",
-		RegisteredBy:  1,
-	}
-	
-	// Setup expectations
-	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
-	
-	// Create service with mock
-	service := NewPatientService(mockRepo)
-	
-	// Test get patient
-	result, err := service.GetPatient(1)
-	
-	// Assert results
-	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, patient, result)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestGetPatient_NotFound(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockPatientRepository)
-	
-	// Setup expectations
-	mockRepo.On("FindByID", uint(999)).Return(nil, errors.New("patient not found"))
-	
-	// Create service with mock
-	service := NewPatientService(mockRepo)
-	
-	// Test get patient
-	result, err := service.GetPatient(999)
-	
-	// Assert results
-	assert.Error(t, err)
-	assert.Nil(t, result)
-	assert.Equal(t, ErrPatientNotFound, err)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestGetAllPatients(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockPatientRepository)
-	
-	// Create test data
-	dob, _ := time.Parse("2006-01-02", "1990-01-01")
-	patients := []models.Patient{
-		{
-			ID:            1,
-			FirstName:     "John",
-			LastName:      "Doe",
-			DateOfBirth:   dob,
-			Gender:        "male",
-			ContactNumber: "1234567890",
-			Email:         "john.doe@example.com",
-			Address:       "123 Main St",
-			RegisteredBy:  1,
-		},
-		{
-			ID:            2,
-			FirstName:     "Jane",
-			LastName:      "Smith",
-			DateOfBirth:   dob,
-			Gender:        "female",
-			ContactNumber: "0987654321",
-			Email:         "jane.smith@example.com",
-			Address:       "456 Oak St",
-			RegisteredBy:  1,
-		},
-	}
-	
-	// Setup expectations
-	mockRepo.On("FindAll", 10, 0).Return(patients, int64(2), nil)
-	
-	// Create service with mock
-	service := NewPatientService(mockRepo)
-	
-	// Test get all patients
-	result, err := service.GetAllPatients(1, 10)
-	
-	// Assert results
-	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, int64(2), result.TotalItems)
-	assert.Equal(t, 1, result.Page)
-	assert.Equal(t, 10, result.PageSize)
-	assert.Equal(t, 1, result.TotalPages)
-	assert.Equal(t, patients, result.Items)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestUpdatePatient(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockPatientRepository)
-	
-	// Create test data
-	dob, _ := time.Parse("2006-01-02", "1990-01-01")
-	patient := &models.Patient{
-		ID:            1,
-		FirstName:     "John",
-		LastName:      "Doe",
-		DateOfBirth:   dob,
-		Gender:        "male",
-		ContactNumber: "1234567890",
-		Email:         "john.doe@example.com",
-		Address:       "123 Main St",
-		RegisteredBy:  1,
-	}
-	
-	updateReq := models.UpdatePatientRequest{
-		FirstName: "Johnny",
-		Email:     "johnny.doe@example.com",
-	}
-	
-	// Setup expectations
-	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
-	mockRepo.On("Update", patient).Return(nil)
-	
-	// Create service with mock
-	service := NewPatientService(mockRepo)
-	
-	// Test update patient
-	result, err := service.UpdatePatient(1, updateReq)
-	
-	// Assert results
-	assert.NoError(t, err)
-	assert.NotNil(t, result)
-	assert.Equal(t, "Johnny", result.FirstName) // Updated field
-	assert.Equal(t, "Doe", result.LastName)     // Unchanged field
-	assert.Equal(t, "johnny.doe@example.com", result.Email) // Updated field
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-}
-
-func TestDeletePatient(t *testing.T) {
-	// Create mock repository
-	mockRepo := new(MockPatientRepository)
-	
-	// Setup expectations
-	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
-	mockRepo.On("Delete", uint(1)).Return(nil)
-	
-	// Create service with mock
-	service := NewPatientService(mockRepo)
-	
-	// Test delete patient
-	err := service.DeletePatient(1)
-	
-	// Assert results
-	assert.NoError(t, err)
-	
-	// Verify expectations
-	mockRepo.AssertExpectations(t)
-} 
\ No newline at end of file
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPatientRepository is a mock implementation of PatientRepository
+type MockPatientRepository struct {
+	mock.Mock
+}
+
+func (m *MockPatientRepository) Create(patient *models.Patient, primary *models.CareTeamAssignment, event *models.AuditEvent) error {
+	args := m.Called(patient, primary, event)
+	return args.Error(0)
+}
+
+func (m *MockPatientRepository) FindByID(id uint) (*models.Patient, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Patient), args.Error(1)
+}
+
+func (m *MockPatientRepository) FindAll(filter models.PatientFilter, limit, offset int) ([]models.Patient, int64, error) {
+	args := m.Called(filter, limit, offset)
+	return args.Get(0).([]models.Patient), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPatientRepository) Update(patient *models.Patient, event *models.AuditEvent) (bool, error) {
+	args := m.Called(patient, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPatientRepository) Delete(id uint, event *models.AuditEvent) error {
+	args := m.Called(id, event)
+	return args.Error(0)
+}
+
+func (m *MockPatientRepository) SearchPatients(searchTerm string, filter models.PatientFilter, limit, offset int) ([]models.Patient, int64, error) {
+	args := m.Called(searchTerm, filter, limit, offset)
+	return args.Get(0).([]models.Patient), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPatientRepository) FindRevisions(patientID uint, limit, offset int) ([]models.PatientRevision, int64, error) {
+	args := m.Called(patientID, limit, offset)
+	return args.Get(0).([]models.PatientRevision), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPatientRepository) FindRevision(patientID uint, revision int) (*models.PatientRevision, error) {
+	args := m.Called(patientID, revision)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PatientRevision), args.Error(1)
+}
+
+func (m *MockPatientRepository) FindRevisionAsOf(patientID uint, at time.Time) (*models.PatientRevision, error) {
+	args := m.Called(patientID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PatientRevision), args.Error(1)
+}
+
+// receptionistAccessor can access every patient
+var receptionistAccessor = PatientAccessor{UserID: 1, AllPatients: true}
+
+func TestCreatePatient(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockPatientRepository)
+	
+	// Create test data
+	dob, _ := time.Parse("2006-01-02", "1990-01-01")
+	createReq := models.CreatePatientRequest{
+		FirstName:     "John",
+		LastName:      "Doe",
+		DateOfBirth:   dob,
+		Gender:        "male",
+		ContactNumber: "1234567890",
+		Email:         "john.doe@example.com",
+		Address:       "123 Main St",
+	}
+	
+	// Setup expectations
+	mockRepo.On("Create", mock.AnythingOfType("*models.Patient"), (*models.CareTeamAssignment)(nil), mock.AnythingOfType("*models.AuditEvent")).Return(nil)
+	
+	// Create service with mock
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	
+	// Test create patient
+	patient, err := service.CreatePatient(receptionistAccessor, createReq)
+	
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, patient)
+	assert.Equal(t, "John", patient.FirstName)
+	assert.Equal(t, "Doe", patient.LastName)
+	assert.Equal(t, dob, patient.DateOfBirth)
+	assert.Equal(t, "male", patient.Gender)
+	assert.Equal(t, "1234567890", patient.ContactNumber)
+	assert.Equal(t, "john.doe@example.com", patient.Email)
+	assert.Equal(t, "123 Main St", patient.Address)
+	assert.Equal(t, uint(1), patient.RegisteredBy)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetPatient_Success(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockPatientRepository)
+	
+	// Create test data
+	dob, _ := time.Parse("2006-01-02", "1990-01-01")
+	patient := &models.Patient{
+		ID:            1,
+		FirstName:     "John",
+		LastName:      "Doe",
+		DateOfBirth:   dob,
+		Gender:        "male",
+		ContactNumber: "1234567890",
+		Email:         "john.doe@example.com",
+		Address:       "123 Main St",
+		RegisteredBy:  1,
+	}
+	
+	// Setup expectations
+	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
+	
+	// Create service with mock
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	
+	// Test get patient
+	result, err := service.GetPatient(receptionistAccessor, 1)
+	
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, patient, result)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetPatient_NotFound(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockPatientRepository)
+	
+	// Setup expectations
+	mockRepo.On("FindByID", uint(999)).Return(nil, errors.New("patient not found"))
+	
+	// Create service with mock
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	
+	// Test get patient
+	result, err := service.GetPatient(receptionistAccessor, 999)
+	
+	// Assert results
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, ErrPatientNotFound, err)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetAllPatients(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockPatientRepository)
+	
+	// Create test data
+	dob, _ := time.Parse("2006-01-02", "1990-01-01")
+	patients := []models.Patient{
+		{
+			ID:            1,
+			FirstName:     "John",
+			LastName:      "Doe",
+			DateOfBirth:   dob,
+			Gender:        "male",
+			ContactNumber: "1234567890",
+			Email:         "john.doe@example.com",
+			Address:       "123 Main St",
+			RegisteredBy:  1,
+		},
+		{
+			ID:            2,
+			FirstName:     "Jane",
+			LastName:      "Smith",
+			DateOfBirth:   dob,
+			Gender:        "female",
+			ContactNumber: "0987654321",
+			Email:         "jane.smith@example.com",
+			Address:       "456 Oak St",
+			RegisteredBy:  1,
+		},
+	}
+	
+	// Setup expectations
+	mockRepo.On("FindAll", models.PatientFilter{}, 10, 0).Return(patients, int64(2), nil)
+	
+	// Create service with mock
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	
+	// Test get all patients
+	result, err := service.GetAllPatients(receptionistAccessor, models.PatientFilter{}, 1, 10)
+	
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, int64(2), result.TotalItems)
+	assert.Equal(t, 1, result.Page)
+	assert.Equal(t, 10, result.PageSize)
+	assert.Equal(t, 1, result.TotalPages)
+	assert.Equal(t, patients, result.Items)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdatePatient(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockPatientRepository)
+	
+	// Create test data
+	dob, _ := time.Parse("2006-01-02", "1990-01-01")
+	patient := &models.Patient{
+		ID:            1,
+		FirstName:     "John",
+		LastName:      "Doe",
+		DateOfBirth:   dob,
+		Gender:        "male",
+		ContactNumber: "1234567890",
+		Email:         "john.doe@example.com",
+		Address:       "123 Main St",
+		RegisteredBy:  1,
+		Version:       1,
+	}
+	
+	updateReq := models.UpdatePatientRequest{
+		FirstName: "Johnny",
+		Email:     "johnny.doe@example.com",
+	}
+	
+	// Setup expectations
+	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
+	mockRepo.On("Update", patient, mock.AnythingOfType("*models.AuditEvent")).Return(true, nil)
+	
+	// Create service with mock
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	
+	// Test update patient
+	result, err := service.UpdatePatient(receptionistAccessor, 1, 1, updateReq)
+	
+	// Assert results
+	assert.NoError(t, err)
+	assert.NotNil(t, result)
+	assert.Equal(t, "Johnny", result.FirstName) // Updated field
+	assert.Equal(t, "Doe", result.LastName)     // Unchanged field
+	assert.Equal(t, "johnny.doe@example.com", result.Email) // Updated field
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+func TestDeletePatient(t *testing.T) {
+	// Create mock repository
+	mockRepo := new(MockPatientRepository)
+	
+	// Setup expectations
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockRepo.On("Delete", uint(1), mock.AnythingOfType("*models.AuditEvent")).Return(nil)
+	
+	// Create service with mock
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	
+	// Test delete patient
+	err := service.DeletePatient(receptionistAccessor, 1)
+	
+	// Assert results
+	assert.NoError(t, err)
+	
+	// Verify expectations
+	mockRepo.AssertExpectations(t)
+}
+
+// patientRevision builds a revision holding a snapshot of a patient
+func patientRevision(t *testing.T, number int, patient *models.Patient) *models.PatientRevision {
+	revision, err := models.NewPatientRevision(patient, &models.AuditEvent{Action: models.AuditActionUpdate, ActorID: 2})
+	assert.NoError(t, err)
+	revision.Revision = number
+	return revision
+}
+
+func TestGetPatientAsOf(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	asOf := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1, BloodGroup: "A+"}, nil)
+	mockRepo.On("FindRevisionAsOf", uint(1), asOf).Return(patientRevision(t, 2, &models.Patient{ID: 1, BloodGroup: "O+"}), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.GetPatientAsOf(receptionistAccessor, 1, asOf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "O+", result.BloodGroup)
+}
+
+func TestGetPatientAsOf_BeforeCreation(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	asOf := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockRepo.On("FindRevisionAsOf", uint(1), asOf).Return(nil, errors.New("record not found"))
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.GetPatientAsOf(receptionistAccessor, 1, asOf)
+
+	assert.Nil(t, result)
+	assert.Equal(t, ErrRevisionNotFound, err)
+}
+
+func TestGetPatientHistory_HidesLegacyNotes(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockRepo.On("FindRevisions", uint(1), 10, 0).Return([]models.PatientRevision{{
+		PatientID: 1,
+		Revision:  2,
+		Changes: models.AuditChanges{
+			"notes":       {Before: "", After: "anxious about results"},
+			"blood_group": {Before: "", After: "O+"},
+		},
+	}}, int64(1), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.GetPatientHistory(receptionistAccessor, 1, 1, 10)
+
+	assert.NoError(t, err)
+	changes := result.Items.([]models.PatientRevision)[0].Changes
+	assert.NotContains(t, changes, "notes")
+	assert.Contains(t, changes, "blood_group")
+}
+
+func TestRevertPatient_RestoresRevision(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	current := &models.Patient{ID: 1, FirstName: "Jon", LastName: "Doe", BloodGroup: "O+", Version: 3}
+	mockRepo.On("FindByID", uint(1)).Return(current, nil)
+	mockRepo.On("FindRevision", uint(1), 1).Return(patientRevision(t, 1, &models.Patient{ID: 1, FirstName: "John", LastName: "Doe", BloodGroup: "O+"}), nil)
+	mockRepo.On("Update", current, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		_, nameChanged := e.Changes["first_name"]
+		return e.Action == models.AuditActionRevert && nameChanged && len(e.Changes) == 1
+	})).Return(true, nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.RevertPatient(receptionistAccessor, 1, 3, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "John", result.FirstName)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRevertPatient_MedicalFieldsRequirePermission(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1, BloodGroup: "A+", Version: 3}, nil)
+	mockRepo.On("FindRevision", uint(1), 1).Return(patientRevision(t, 1, &models.Patient{ID: 1, BloodGroup: "O+"}), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.RevertPatient(receptionistAccessor, 1, 3, 1)
+
+	assert.Nil(t, result)
+	assert.Equal(t, ErrMedicalFieldsForbidden, err)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdatePatient_StaleVersion(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	current := &models.Patient{ID: 1, FirstName: "John", Version: 4}
+	mockRepo.On("FindByID", uint(1)).Return(current, nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.UpdatePatient(receptionistAccessor, 1, 3, models.UpdatePatientRequest{FirstName: "Johnny"})
+
+	assert.Nil(t, result)
+	var conflictErr *PatientConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, current, conflictErr.Current)
+	assert.ErrorIs(t, err, ErrPatientModified)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdatePatient_LeavesMedicalFieldsWithoutPermission(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	patient := &models.Patient{ID: 1, FirstName: "John", EmergencyName: "Jane", BloodGroup: "O+", MedicalHistory: "asthma", Version: 1}
+	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
+	mockRepo.On("Update", patient, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return !e.Changes.TouchesMedicalFields()
+	})).Return(true, nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.UpdatePatient(receptionistAccessor, 1, 1, models.UpdatePatientRequest{FirstName: "Johnny", MedicalHistory: "none"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Johnny", result.FirstName)
+	assert.Equal(t, "Jane", result.EmergencyName)
+	assert.Equal(t, "O+", result.BloodGroup)
+	assert.Equal(t, "asthma", result.MedicalHistory)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdatePatient_DoctorLeavesOmittedMedicalFields(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	patient := &models.Patient{ID: 1, FirstName: "John", Address: "123 Main St", BloodGroup: "O+", MedicalHistory: "asthma", Version: 1}
+	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
+	mockRepo.On("Update", patient, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return !e.Changes.TouchesMedicalFields()
+	})).Return(true, nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := doctorAccessor
+	accessor.AllPatients = true
+	accessor.CanEditDemographics = true
+	accessor.CanEditMedical = true
+	result, err := service.UpdatePatient(accessor, 1, 1, models.UpdatePatientRequest{FirstName: "John", Address: "456 Oak St"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "456 Oak St", result.Address)
+	assert.Equal(t, "O+", result.BloodGroup)
+	assert.Equal(t, "asthma", result.MedicalHistory)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreatePatient_IgnoresMedicalFieldsWithoutPermission(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("Create", mock.AnythingOfType("*models.Patient"), (*models.CareTeamAssignment)(nil), mock.AnythingOfType("*models.AuditEvent")).Return(nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	patient, err := service.CreatePatient(receptionistAccessor, models.CreatePatientRequest{FirstName: "Walk", LastName: "In", BloodGroup: "A+", MedicalHistory: "asthma"})
+
+	assert.NoError(t, err)
+	assert.Empty(t, patient.BloodGroup)
+	assert.Empty(t, patient.MedicalHistory)
+}
+
+func TestUpdatePatientMedicalInfo_LostRace(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	loaded := &models.Patient{ID: 1, BloodGroup: "O+", Version: 2}
+	current := &models.Patient{ID: 1, BloodGroup: "B+", Version: 3}
+	mockRepo.On("FindByID", uint(1)).Return(loaded, nil).Once()
+	mockRepo.On("FindByID", uint(1)).Return(current, nil).Once()
+	mockRepo.On("Update", loaded, mock.AnythingOfType("*models.AuditEvent")).Return(false, nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	result, err := service.UpdatePatientMedicalInfo(receptionistAccessor, 1, 2, models.UpdatePatientMedicalRequest{BloodGroup: "A+"})
+
+	assert.Nil(t, result)
+	var conflictErr *PatientConflictError
+	assert.ErrorAs(t, err, &conflictErr)
+	assert.Equal(t, "B+", conflictErr.Current.BloodGroup)
+}
+
+// patchablePatient is a valid patient record for patch tests
+func patchablePatient() *models.Patient {
+	return &models.Patient{
+		ID:             1,
+		FirstName:      "John",
+		LastName:       "Doe",
+		DateOfBirth:    time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Gender:         "male",
+		ContactNumber:  "1234567890",
+		Address:        "123 Main St",
+		EmergencyName:  "Jane Doe",
+		MedicalHistory: "asthma",
+		Notes:          "follow up in 6 months",
+		Version:        1,
+	}
+}
+
+func TestPatchPatient_OnlyTouchesPatchedFields(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	patient := patchablePatient()
+	mockRepo.On("FindByID", uint(1)).Return(patient, nil)
+	mockRepo.On("Update", patient, mock.AnythingOfType("*models.AuditEvent")).Return(true, nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := receptionistAccessor
+	accessor.CanEditDemographics = true
+	result, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{
+		"address":        json.RawMessage(`"456 Oak St"`),
+		"emergency_name": json.RawMessage(`null`),
+	}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "456 Oak St", result.Address)
+	assert.Equal(t, "", result.EmergencyName)
+	assert.Equal(t, "asthma", result.MedicalHistory)
+	assert.Equal(t, "follow up in 6 months", result.Notes)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestPatchPatient_RejectsMedicalFieldWithoutPermission(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(patchablePatient(), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := receptionistAccessor
+	accessor.CanEditDemographics = true
+	_, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{
+		"address":         json.RawMessage(`"456 Oak St"`),
+		"medical_history": json.RawMessage(`null`),
+	}, false)
+
+	assert.ErrorIs(t, err, ErrFieldNotPermitted)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPatchPatient_MedicalEndpointRejectsDemographics(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(patchablePatient(), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := receptionistAccessor
+	accessor.CanEditDemographics = true
+	accessor.CanEditMedical = true
+	_, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{
+		"address": json.RawMessage(`"456 Oak St"`),
+	}, true)
+
+	assert.ErrorIs(t, err, ErrFieldNotPermitted)
+}
+
+func TestPatchPatient_RejectsClearingRequiredField(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(patchablePatient(), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := receptionistAccessor
+	accessor.CanEditDemographics = true
+	_, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{
+		"last_name": json.RawMessage(`null`),
+	}, false)
+
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestPatchPatient_RejectsUnknownField(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(patchablePatient(), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := receptionistAccessor
+	accessor.CanEditDemographics = true
+	_, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{
+		"registered_by": json.RawMessage(`7`),
+	}, false)
+
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestPatchPatient_RejectsLegacyFields(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(patchablePatient(), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := doctorAccessor
+	accessor.AllPatients = true
+	accessor.CanEditMedical = true
+	_, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{
+		"allergies": json.RawMessage(`"penicillin"`),
+		"notes":     json.RawMessage(`"follow up"`),
+	}, true)
+
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPatchPatient_RejectsEmptyPatch(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(patchablePatient(), nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), newMockAuditRepository())
+
+	accessor := receptionistAccessor
+	accessor.CanEditDemographics = true
+	_, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{}, false)
+
+	assert.ErrorIs(t, err, ErrInvalidPatch)
+}
+
+func TestPatchPatient_NoChangesIsRecordedAsRead(t *testing.T) {
+	mockRepo := new(MockPatientRepository)
+	mockRepo.On("FindByID", uint(1)).Return(patchablePatient(), nil)
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("Append", mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionRead && *e.PatientID == 1 && e.Details == "patch without changes"
+	})).Return(nil)
+
+	service := NewPatientService(mockRepo, new(MockCareTeamRepository), mockAuditRepo)
+
+	accessor := receptionistAccessor
+	accessor.CanEditDemographics = true
+	result, err := service.PatchPatient(accessor, 1, 1, map[string]json.RawMessage{
+		"address": json.RawMessage(`"123 Main St"`),
+	}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), result.ID)
+	mockAuditRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
\ No newline at end of file
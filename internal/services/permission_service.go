@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrInvalidRole       = errors.New("invalid role")
+	ErrAdminLockout      = errors.New("the admin role must keep the users:manage permission")
+)
+
+// PermissionRepository defines the role permission data operations used by the PermissionService
+type PermissionRepository interface {
+	FindByRole(role models.UserRole) ([]models.Permission, error)
+	FindAll() ([]models.RolePermission, error)
+	ReplaceForRole(role models.UserRole, permissions []models.Permission) error
+	Count() (int64, error)
+}
+
+// PermissionService handles the mapping of roles to permission sets
+type PermissionService struct {
+	permissionRepo PermissionRepository
+}
+
+// NewPermissionService creates a new PermissionService
+func NewPermissionService(permissionRepo PermissionRepository) *PermissionService {
+	return &PermissionService{
+		permissionRepo: permissionRepo,
+	}
+}
+
+// SeedDefaults stores the default role permissions when none are stored yet
+func (s *PermissionService) SeedDefaults() error {
+	count, err := s.permissionRepo.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	for role, permissions := range models.DefaultRolePermissions {
+		if err := s.permissionRepo.ReplaceForRole(role, permissions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PermissionsForRole gets the permissions granted to a role
+func (s *PermissionService) PermissionsForRole(role models.UserRole) ([]models.Permission, error) {
+	return s.permissionRepo.FindByRole(role)
+}
+
+// GetRolePermissions gets the permission set of every role
+func (s *PermissionService) GetRolePermissions() ([]models.RolePermissionsResponse, error) {
+	rolePermissions, err := s.permissionRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[models.UserRole][]models.Permission)
+	for _, rp := range rolePermissions {
+		byRole[rp.Role] = append(byRole[rp.Role], rp.Permission)
+	}
+
+	responses := make([]models.RolePermissionsResponse, 0, len(byRole))
+	for role, permissions := range byRole {
+		responses = append(responses, models.RolePermissionsResponse{Role: role, Permissions: permissions})
+	}
+	sort.Slice(responses, func(i, j int) bool { return responses[i].Role < responses[j].Role })
+
+	return responses, nil
+}
+
+// UpdateRolePermissions replaces the permission set of a role. Users of the
+// role receive the new set with their next access token.
+func (s *PermissionService) UpdateRolePermissions(role models.UserRole, req models.UpdateRolePermissionsRequest) (*models.RolePermissionsResponse, error) {
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+
+	seen := make(map[models.Permission]bool)
+	permissions := make([]models.Permission, 0, len(req.Permissions))
+	for _, permission := range req.Permissions {
+		if !permission.IsValid() {
+			return nil, ErrUnknownPermission
+		}
+		if seen[permission] {
+			continue
+		}
+		seen[permission] = true
+		permissions = append(permissions, permission)
+	}
+
+	// Removing user management from admins would leave nobody able to restore it
+	if role == models.RoleAdmin && !seen[models.PermUsersManage] {
+		return nil, ErrAdminLockout
+	}
+
+	if err := s.permissionRepo.ReplaceForRole(role, permissions); err != nil {
+		return nil, err
+	}
+
+	return &models.RolePermissionsResponse{Role: role, Permissions: permissions}, nil
+}
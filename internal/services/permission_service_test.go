@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPermissionRepository is a mock implementation of PermissionRepository
+type MockPermissionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPermissionRepository) FindByRole(role models.UserRole) ([]models.Permission, error) {
+	args := m.Called(role)
+	return args.Get(0).([]models.Permission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) FindAll() ([]models.RolePermission, error) {
+	args := m.Called()
+	return args.Get(0).([]models.RolePermission), args.Error(1)
+}
+
+func (m *MockPermissionRepository) ReplaceForRole(role models.UserRole, permissions []models.Permission) error {
+	args := m.Called(role, permissions)
+	return args.Error(0)
+}
+
+func (m *MockPermissionRepository) Count() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// newTestPermissionService creates a PermissionService granting the default
+// permission sets
+func newTestPermissionService() *PermissionService {
+	mockRepo := new(MockPermissionRepository)
+	for role, permissions := range models.DefaultRolePermissions {
+		mockRepo.On("FindByRole", role).Return(permissions, nil)
+	}
+	return NewPermissionService(mockRepo)
+}
+
+func TestSeedDefaults_SkipsWhenPermissionsExist(t *testing.T) {
+	mockRepo := new(MockPermissionRepository)
+	mockRepo.On("Count").Return(int64(3), nil)
+
+	service := NewPermissionService(mockRepo)
+
+	err := service.SeedDefaults()
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "ReplaceForRole", mock.Anything, mock.Anything)
+}
+
+func TestUpdateRolePermissions_UnknownPermission(t *testing.T) {
+	mockRepo := new(MockPermissionRepository)
+
+	service := NewPermissionService(mockRepo)
+
+	res, err := service.UpdateRolePermissions(models.RoleDoctor, models.UpdateRolePermissionsRequest{
+		Permissions: []models.Permission{models.PermPatientsRead, "patients:everything"},
+	})
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrUnknownPermission, err)
+	mockRepo.AssertNotCalled(t, "ReplaceForRole", mock.Anything, mock.Anything)
+}
+
+func TestUpdateRolePermissions_AdminKeepsUserManagement(t *testing.T) {
+	service := NewPermissionService(new(MockPermissionRepository))
+
+	res, err := service.UpdateRolePermissions(models.RoleAdmin, models.UpdateRolePermissionsRequest{
+		Permissions: []models.Permission{models.PermPatientsRead},
+	})
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrAdminLockout, err)
+}
+
+func TestUpdateRolePermissions_RemovesDuplicates(t *testing.T) {
+	mockRepo := new(MockPermissionRepository)
+	mockRepo.On("ReplaceForRole", models.RoleReceptionist,
+		[]models.Permission{models.PermPatientsRead, models.PermPatientsWrite}).Return(nil)
+
+	service := NewPermissionService(mockRepo)
+
+	res, err := service.UpdateRolePermissions(models.RoleReceptionist, models.UpdateRolePermissionsRequest{
+		Permissions: []models.Permission{models.PermPatientsRead, models.PermPatientsWrite, models.PermPatientsRead},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Permission{models.PermPatientsRead, models.PermPatientsWrite}, res.Permissions)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"sync"
+)
+
+// QueueBroker tells open queue streams in this process that a doctor's
+// queue changed. Streams read the queue again on each signal, so signals
+// that arrive while one is pending are merged.
+type QueueBroker struct {
+	mu          sync.Mutex
+	subscribers map[uint]map[chan struct{}]struct{}
+}
+
+// NewQueueBroker creates a new QueueBroker
+func NewQueueBroker() *QueueBroker {
+	return &QueueBroker{
+		subscribers: make(map[uint]map[chan struct{}]struct{}),
+	}
+}
+
+// Subscribe returns a channel signalled when the queue of a doctor changes,
+// or any queue when doctorID is 0, and a function that closes the
+// subscription
+func (b *QueueBroker) Subscribe(doctorID uint) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	b.mu.Lock()
+	if b.subscribers[doctorID] == nil {
+		b.subscribers[doctorID] = make(map[chan struct{}]struct{})
+	}
+	b.subscribers[doctorID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers[doctorID], ch)
+		if len(b.subscribers[doctorID]) == 0 {
+			delete(b.subscribers, doctorID)
+		}
+	}
+}
+
+// Publish signals the subscribers of a doctor's queue and of every queue
+func (b *QueueBroker) Publish(doctorID uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, id := range []uint{doctorID, 0} {
+		for ch := range b.subscribers[id] {
+			select {
+			case ch <- struct{}{}:
+			default:
+				// A signal is already pending
+			}
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrQueueEntryNotFound      = errors.New("queue entry not found")
+	ErrAlreadyQueued           = errors.New("patient is already in a queue today")
+	ErrInvalidQueueTransition  = errors.New("invalid queue status transition")
+	ErrAppointmentNotToday     = errors.New("appointment is not today")
+	ErrAppointmentOtherPatient = errors.New("appointment belongs to another patient")
+)
+
+// QueueRepository defines the check-in queue data operations used by the services
+type QueueRepository interface {
+	Create(entry *models.QueueEntry, event *models.AuditEvent) (bool, error)
+	FindByID(id uint) (*models.QueueEntry, error)
+	FindByDate(doctorID uint, date string) ([]models.PatientQueueEntry, error)
+	UpdateStatus(entry *models.QueueEntry, from models.QueueStatus, event *models.AuditEvent) (bool, error)
+	UpdateDoctor(entry *models.QueueEntry, event *models.AuditEvent) (bool, error)
+}
+
+// QueueService handles the front-desk queues of patients checked in for the
+// day. Queues are stored, so they survive a restart; open streams are told
+// about changes through the broker.
+type QueueService struct {
+	queueRepo       QueueRepository
+	appointmentRepo AppointmentRepository
+	scheduleService *ScheduleService
+	patientService  *PatientService
+	broker          *QueueBroker
+	auditRepo       AuditRepository
+}
+
+// NewQueueService creates a new QueueService
+func NewQueueService(queueRepo QueueRepository, appointmentRepo AppointmentRepository, scheduleService *ScheduleService, patientService *PatientService, broker *QueueBroker, auditRepo AuditRepository) *QueueService {
+	return &QueueService{
+		queueRepo:       queueRepo,
+		appointmentRepo: appointmentRepo,
+		scheduleService: scheduleService,
+		patientService:  patientService,
+		broker:          broker,
+		auditRepo:       auditRepo,
+	}
+}
+
+// CheckIn adds a patient to the end of a doctor's queue for today,
+// optionally for one of today's appointments
+func (s *QueueService) CheckIn(accessor PatientAccessor, req models.CheckInRequest) (*models.QueueEntry, error) {
+	if err := s.patientService.CheckAccess(accessor, req.PatientID, models.AuditActionQueueCreate); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := s.scheduleService.dayOf(now)
+	doctorID := req.DoctorID
+	if req.AppointmentID != nil {
+		appointment, err := s.appointmentRepo.FindByID(*req.AppointmentID)
+		if err != nil {
+			return nil, ErrAppointmentNotFound
+		}
+		if appointment.PatientID != req.PatientID {
+			return nil, ErrAppointmentOtherPatient
+		}
+		if appointment.Status != models.AppointmentStatusBooked {
+			return nil, ErrAppointmentCancelled
+		}
+		if !s.scheduleService.dayOf(appointment.StartTime).Equal(today) {
+			return nil, ErrAppointmentNotToday
+		}
+		if doctorID == 0 {
+			doctorID = appointment.DoctorID
+		}
+	}
+	if err := s.scheduleService.findDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.QueueEntry{
+		PatientID:     req.PatientID,
+		DoctorID:      doctorID,
+		AppointmentID: req.AppointmentID,
+		Date:          today,
+		Status:        models.QueueStatusWaiting,
+		CheckedInAt:   now,
+		CheckedInBy:   accessor.UserID,
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionQueueCreate, req.PatientID, models.DiffRecords(nil, entry))
+	created, err := s.queueRepo.Create(entry, event)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyQueued
+	}
+
+	s.broker.Publish(entry.DoctorID)
+	return entry, nil
+}
+
+// GetQueue gets today's queue of a doctor, or of every doctor when doctorID
+// is 0, and records which patients were listed
+func (s *QueueService) GetQueue(accessor PatientAccessor, doctorID uint) (*models.DoctorQueue, error) {
+	queue, err := s.RefreshQueue(doctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(queue.Entries))
+	for i, entry := range queue.Entries {
+		ids[i] = fmt.Sprint(entry.PatientID)
+	}
+	event := newAuditEvent(accessor, models.AuditActionSearch, 0, nil)
+	event.Details = fmt.Sprintf("queue doctor=%d patients=[%s]", doctorID, strings.Join(ids, ","))
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return queue, nil
+}
+
+// RefreshQueue gets today's queue of a doctor again for a stream whose
+// opening was recorded by GetQueue
+func (s *QueueService) RefreshQueue(doctorID uint) (*models.DoctorQueue, error) {
+	now := time.Now()
+	date := s.scheduleService.dayOf(now).Format(models.DateLayout)
+	entries, err := s.queueRepo.FindByDate(doctorID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &models.DoctorQueue{DoctorID: doctorID, Date: date, Entries: entries}
+	called, totalWait := 0, 0
+	for i := range queue.Entries {
+		entry := &queue.Entries[i]
+		entry.WaitMinutes = minutesBetween(entry.CheckedInAt, entry.CalledAt, now)
+		if entry.StartedAt != nil {
+			entry.ConsultationMinutes = minutesBetween(*entry.StartedAt, entry.DoneAt, now)
+		}
+		if entry.Status == models.QueueStatusWaiting {
+			queue.Waiting++
+		} else {
+			called++
+			totalWait += entry.WaitMinutes
+		}
+	}
+	if called > 0 {
+		queue.AverageWaitMinutes = totalWait / called
+	}
+	if queue.Entries == nil {
+		queue.Entries = []models.PatientQueueEntry{}
+	}
+
+	return queue, nil
+}
+
+// Subscribe returns a channel signalled when the queue of a doctor changes,
+// or any queue when doctorID is 0, and a function that closes the
+// subscription
+func (s *QueueService) Subscribe(doctorID uint) (<-chan struct{}, func()) {
+	return s.broker.Subscribe(doctorID)
+}
+
+// Call calls a waiting patient in to the doctor
+func (s *QueueService) Call(accessor PatientAccessor, id uint) (*models.QueueEntry, error) {
+	return s.transition(accessor, id, models.QueueStatusCalled)
+}
+
+// StartConsultation records that the doctor has started seeing a called
+// patient
+func (s *QueueService) StartConsultation(accessor PatientAccessor, id uint) (*models.QueueEntry, error) {
+	return s.transition(accessor, id, models.QueueStatusInConsultation)
+}
+
+// Complete records that a consultation has ended
+func (s *QueueService) Complete(accessor PatientAccessor, id uint) (*models.QueueEntry, error) {
+	return s.transition(accessor, id, models.QueueStatusDone)
+}
+
+// Assign moves a waiting patient to the end of another doctor's queue
+func (s *QueueService) Assign(accessor PatientAccessor, id uint, req models.AssignQueueRequest) (*models.QueueEntry, error) {
+	entry, err := s.findEntry(accessor, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != models.QueueStatusWaiting {
+		return nil, ErrInvalidQueueTransition
+	}
+	if err := s.scheduleService.findDoctor(req.DoctorID); err != nil {
+		return nil, err
+	}
+
+	before := *entry
+	entry.DoctorID = req.DoctorID
+
+	event := newAuditEvent(accessor, models.AuditActionQueueUpdate, entry.PatientID, models.DiffRecords(&before, entry))
+	event.Details = fmt.Sprintf("queue=%d", entry.ID)
+	updated, err := s.queueRepo.UpdateDoctor(entry, event)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvalidQueueTransition
+	}
+
+	s.broker.Publish(before.DoctorID)
+	s.broker.Publish(entry.DoctorID)
+	return entry, nil
+}
+
+// transition moves a queue entry to the next status, recording when it did
+func (s *QueueService) transition(accessor PatientAccessor, id uint, next models.QueueStatus) (*models.QueueEntry, error) {
+	entry, err := s.findEntry(accessor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.Status.CanTransitionTo(next) {
+		return nil, ErrInvalidQueueTransition
+	}
+
+	before := *entry
+	now := time.Now()
+	entry.Status = next
+	switch next {
+	case models.QueueStatusCalled:
+		entry.CalledAt = &now
+	case models.QueueStatusInConsultation:
+		entry.StartedAt = &now
+	case models.QueueStatusDone:
+		entry.DoneAt = &now
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionQueueUpdate, entry.PatientID, models.DiffRecords(&before, entry))
+	event.Details = fmt.Sprintf("queue=%d", entry.ID)
+	updated, err := s.queueRepo.UpdateStatus(entry, before.Status, event)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrInvalidQueueTransition
+	}
+
+	s.broker.Publish(entry.DoctorID)
+	return entry, nil
+}
+
+// findEntry finds a queue entry of a patient the accessor may reach
+func (s *QueueService) findEntry(accessor PatientAccessor, id uint) (*models.QueueEntry, error) {
+	entry, err := s.queueRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrQueueEntryNotFound
+	}
+	if err := s.patientService.CheckAccess(accessor, entry.PatientID, models.AuditActionQueueUpdate); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// minutesBetween returns the whole minutes from a start to an end, or to
+// now while there is no end
+func minutesBetween(start time.Time, end *time.Time, now time.Time) int {
+	if end != nil {
+		now = *end
+	}
+	return int(now.Sub(start).Minutes())
+}
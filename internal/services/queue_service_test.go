@@ -0,0 +1,187 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockQueueRepository is a mock implementation of QueueRepository
+type MockQueueRepository struct {
+	mock.Mock
+}
+
+func (m *MockQueueRepository) Create(entry *models.QueueEntry, event *models.AuditEvent) (bool, error) {
+	args := m.Called(entry, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQueueRepository) FindByID(id uint) (*models.QueueEntry, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QueueEntry), args.Error(1)
+}
+
+func (m *MockQueueRepository) FindByDate(doctorID uint, date string) ([]models.PatientQueueEntry, error) {
+	args := m.Called(doctorID, date)
+	return args.Get(0).([]models.PatientQueueEntry), args.Error(1)
+}
+
+func (m *MockQueueRepository) UpdateStatus(entry *models.QueueEntry, from models.QueueStatus, event *models.AuditEvent) (bool, error) {
+	args := m.Called(entry, from, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockQueueRepository) UpdateDoctor(entry *models.QueueEntry, event *models.AuditEvent) (bool, error) {
+	args := m.Called(entry, event)
+	return args.Bool(0), args.Error(1)
+}
+
+// newTestQueueService creates a QueueService in UTC for patient 1 and
+// doctor 5
+func newTestQueueService(queueRepo *MockQueueRepository, appointmentRepo *MockAppointmentRepository) *QueueService {
+	scheduleService := newTestScheduleService(new(MockScheduleRepository), appointmentRepo)
+
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	auditRepo := newMockAuditRepository()
+
+	patientService := NewPatientService(mockPatientRepo, new(MockCareTeamRepository), auditRepo)
+	return NewQueueService(queueRepo, appointmentRepo, scheduleService, patientService, NewQueueBroker(), auditRepo)
+}
+
+func TestCheckIn(t *testing.T) {
+	mockQueueRepo := new(MockQueueRepository)
+	mockQueueRepo.On("Create", mock.AnythingOfType("*models.QueueEntry"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionQueueCreate && *e.PatientID == 1
+	})).Return(true, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindByID", uint(7)).Return(&models.Appointment{
+		ID: 7, PatientID: 1, DoctorID: 5, Status: models.AppointmentStatusBooked, StartTime: time.Now(),
+	}, nil)
+
+	service := newTestQueueService(mockQueueRepo, mockAppointmentRepo)
+	changes, unsubscribe := service.Subscribe(5)
+	defer unsubscribe()
+
+	// The doctor comes from the appointment
+	appointmentID := uint(7)
+	entry, err := service.CheckIn(receptionistAccessor, models.CheckInRequest{PatientID: 1, AppointmentID: &appointmentID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5), entry.DoctorID)
+	assert.Equal(t, models.QueueStatusWaiting, entry.Status)
+	assert.Len(t, changes, 1)
+	mockQueueRepo.AssertExpectations(t)
+}
+
+func TestCheckIn_AlreadyQueued(t *testing.T) {
+	mockQueueRepo := new(MockQueueRepository)
+	mockQueueRepo.On("Create", mock.Anything, mock.Anything).Return(false, nil)
+
+	service := newTestQueueService(mockQueueRepo, new(MockAppointmentRepository))
+
+	_, err := service.CheckIn(receptionistAccessor, models.CheckInRequest{PatientID: 1, DoctorID: 5})
+
+	assert.ErrorIs(t, err, ErrAlreadyQueued)
+}
+
+func TestCheckIn_InvalidAppointment(t *testing.T) {
+	mockQueueRepo := new(MockQueueRepository)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindByID", uint(7)).Return(&models.Appointment{
+		ID: 7, PatientID: 2, DoctorID: 5, Status: models.AppointmentStatusBooked, StartTime: time.Now(),
+	}, nil)
+	mockAppointmentRepo.On("FindByID", uint(8)).Return(&models.Appointment{
+		ID: 8, PatientID: 1, DoctorID: 5, Status: models.AppointmentStatusBooked, StartTime: testSlot("09:00"),
+	}, nil)
+
+	service := newTestQueueService(mockQueueRepo, mockAppointmentRepo)
+
+	appointmentID := uint(7)
+	_, err := service.CheckIn(receptionistAccessor, models.CheckInRequest{PatientID: 1, AppointmentID: &appointmentID})
+	assert.ErrorIs(t, err, ErrAppointmentOtherPatient)
+
+	appointmentID = 8
+	_, err = service.CheckIn(receptionistAccessor, models.CheckInRequest{PatientID: 1, AppointmentID: &appointmentID})
+	assert.ErrorIs(t, err, ErrAppointmentNotToday)
+
+	mockQueueRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCallPatient(t *testing.T) {
+	mockQueueRepo := new(MockQueueRepository)
+	mockQueueRepo.On("FindByID", uint(3)).Return(&models.QueueEntry{
+		ID: 3, PatientID: 1, DoctorID: 5, Status: models.QueueStatusWaiting, CheckedInAt: time.Now(),
+	}, nil)
+	mockQueueRepo.On("UpdateStatus", mock.AnythingOfType("*models.QueueEntry"), models.QueueStatusWaiting, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionQueueUpdate && e.Changes["status"].After == models.QueueStatusCalled
+	})).Return(true, nil)
+
+	service := newTestQueueService(mockQueueRepo, new(MockAppointmentRepository))
+
+	entry, err := service.Call(receptionistAccessor, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.QueueStatusCalled, entry.Status)
+	assert.NotNil(t, entry.CalledAt)
+	mockQueueRepo.AssertExpectations(t)
+}
+
+func TestStartConsultation_NotCalled(t *testing.T) {
+	mockQueueRepo := new(MockQueueRepository)
+	mockQueueRepo.On("FindByID", uint(3)).Return(&models.QueueEntry{
+		ID: 3, PatientID: 1, DoctorID: 5, Status: models.QueueStatusWaiting,
+	}, nil)
+
+	service := newTestQueueService(mockQueueRepo, new(MockAppointmentRepository))
+
+	_, err := service.StartConsultation(receptionistAccessor, 3)
+
+	assert.ErrorIs(t, err, ErrInvalidQueueTransition)
+	mockQueueRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAssignDoctor_NotWaiting(t *testing.T) {
+	mockQueueRepo := new(MockQueueRepository)
+	mockQueueRepo.On("FindByID", uint(3)).Return(&models.QueueEntry{
+		ID: 3, PatientID: 1, DoctorID: 5, Status: models.QueueStatusInConsultation,
+	}, nil)
+
+	service := newTestQueueService(mockQueueRepo, new(MockAppointmentRepository))
+
+	_, err := service.Assign(receptionistAccessor, 3, models.AssignQueueRequest{DoctorID: 5})
+
+	assert.ErrorIs(t, err, ErrInvalidQueueTransition)
+	mockQueueRepo.AssertNotCalled(t, "UpdateDoctor", mock.Anything, mock.Anything)
+}
+
+func TestGetQueue_WaitTimes(t *testing.T) {
+	now := time.Now()
+	checkedIn := now.Add(-30 * time.Minute)
+	called := now.Add(-20 * time.Minute)
+	mockQueueRepo := new(MockQueueRepository)
+	mockQueueRepo.On("FindByDate", uint(5), now.UTC().Format(models.DateLayout)).Return([]models.PatientQueueEntry{
+		{QueueEntry: models.QueueEntry{ID: 3, PatientID: 1, DoctorID: 5, Status: models.QueueStatusInConsultation,
+			CheckedInAt: checkedIn, CalledAt: &called, StartedAt: &called}},
+		{QueueEntry: models.QueueEntry{ID: 4, PatientID: 1, DoctorID: 5, Status: models.QueueStatusWaiting,
+			CheckedInAt: now.Add(-5 * time.Minute)}},
+	}, nil)
+
+	service := newTestQueueService(mockQueueRepo, new(MockAppointmentRepository))
+
+	queue, err := service.GetQueue(receptionistAccessor, 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, queue.Entries[0].WaitMinutes)
+	assert.Equal(t, 20, queue.Entries[0].ConsultationMinutes)
+	assert.Equal(t, 5, queue.Entries[1].WaitMinutes)
+	assert.Equal(t, 1, queue.Waiting)
+	assert.Equal(t, 10, queue.AverageWaitMinutes)
+}
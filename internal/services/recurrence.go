@@ -0,0 +1,166 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSeriesOccurrences bounds the occurrences a recurrence rule may generate
+const maxSeriesOccurrences = 104
+
+// rruleWeekdays maps RRULE weekday codes to weekdays
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// recurrence is the subset of an RFC 5545 RRULE used to book series: FREQ
+// (DAILY or WEEKLY), INTERVAL, BYDAY for weekly rules, and COUNT or UNTIL
+type recurrence struct {
+	weekly   bool
+	interval int
+	byDay    []time.Weekday
+	count    int
+	// until is the last calendar date, zero when count is set
+	until time.Time
+}
+
+// parseRecurrence parses a recurrence rule such as
+// FREQ=WEEKLY;BYDAY=MO,TH;COUNT=16, with or without the RRULE: prefix
+func parseRecurrence(rule string) (*recurrence, error) {
+	rec := &recurrence{interval: 1}
+	freq := ""
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a NAME=VALUE part", ErrInvalidRecurrence, part)
+		}
+		value = strings.ToUpper(strings.TrimSpace(value))
+		switch strings.ToUpper(strings.TrimSpace(name)) {
+		case "FREQ":
+			freq = value
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive number", ErrInvalidRecurrence)
+			}
+			rec.interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(value, ",") {
+				weekday, ok := rruleWeekdays[code]
+				if !ok {
+					return nil, fmt.Errorf("%w: %q is not a weekday", ErrInvalidRecurrence, code)
+				}
+				rec.byDay = append(rec.byDay, weekday)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive number", ErrInvalidRecurrence)
+			}
+			rec.count = n
+		case "UNTIL":
+			// Only the date of a DATE-TIME value is used
+			if len(value) < 8 {
+				return nil, fmt.Errorf("%w: UNTIL must be a date such as 20240131", ErrInvalidRecurrence)
+			}
+			until, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("%w: UNTIL must be a date such as 20240131", ErrInvalidRecurrence)
+			}
+			rec.until = until
+		default:
+			return nil, fmt.Errorf("%w: %s is not supported", ErrInvalidRecurrence, name)
+		}
+	}
+
+	switch freq {
+	case "WEEKLY":
+		rec.weekly = true
+	case "DAILY":
+		if len(rec.byDay) > 0 {
+			return nil, fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRecurrence)
+		}
+	default:
+		return nil, fmt.Errorf("%w: FREQ must be DAILY or WEEKLY", ErrInvalidRecurrence)
+	}
+	if (rec.count == 0) == rec.until.IsZero() {
+		return nil, fmt.Errorf("%w: exactly one of COUNT and UNTIL is required", ErrInvalidRecurrence)
+	}
+	if rec.count > maxSeriesOccurrences {
+		return nil, fmt.Errorf("%w: a series has at most %d occurrences", ErrInvalidRecurrence, maxSeriesOccurrences)
+	}
+	return rec, nil
+}
+
+// dates lists the calendar dates of the occurrences from a first date on.
+// Weeks start on Monday, and the first date is only an occurrence when it
+// matches the rule.
+func (r *recurrence) dates(first time.Time) ([]time.Time, error) {
+	var dates []time.Time
+	add := func(date time.Time) bool {
+		if !r.until.IsZero() && date.After(r.until) {
+			return false
+		}
+		dates = append(dates, date)
+		return r.count == 0 || len(dates) < r.count
+	}
+
+	if !r.weekly {
+		for date := first; add(date) && len(dates) <= maxSeriesOccurrences; {
+			date = date.AddDate(0, 0, r.interval)
+		}
+	} else {
+		offsets := r.weekdayOffsets(first)
+		weekStart := first.AddDate(0, 0, -mondayOffset(first.Weekday()))
+	weeks:
+		for ; len(dates) <= maxSeriesOccurrences; weekStart = weekStart.AddDate(0, 0, 7*r.interval) {
+			for _, offset := range offsets {
+				date := weekStart.AddDate(0, 0, offset)
+				if date.Before(first) {
+					continue
+				}
+				if !add(date) {
+					break weeks
+				}
+			}
+		}
+	}
+
+	if len(dates) > maxSeriesOccurrences {
+		return nil, fmt.Errorf("%w: a series has at most %d occurrences", ErrInvalidRecurrence, maxSeriesOccurrences)
+	}
+	return dates, nil
+}
+
+// weekdayOffsets lists the days after Monday a weekly rule falls on, in
+// order; without BYDAY it falls on the weekday of the first date
+func (r *recurrence) weekdayOffsets(first time.Time) []int {
+	days := r.byDay
+	if len(days) == 0 {
+		days = []time.Weekday{first.Weekday()}
+	}
+	seen := map[int]bool{}
+	var offsets []int
+	for _, day := range days {
+		if offset := mondayOffset(day); !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Ints(offsets)
+	return offsets
+}
+
+// mondayOffset returns the number of days a weekday falls after Monday
+func mondayOffset(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
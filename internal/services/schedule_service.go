@@ -0,0 +1,436 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrDoctorNotFound            = errors.New("doctor not found or deactivated")
+	ErrInvalidWorkingHours       = errors.New("invalid working hours")
+	ErrInvalidDate               = errors.New("invalid date")
+	ErrScheduleExceptionExists   = errors.New("doctor already has an exception on that date")
+	ErrScheduleExceptionNotFound = errors.New("schedule exception not found")
+	ErrLeaveNotFound             = errors.New("leave not found")
+)
+
+// Bounds of the length of appointment slots
+const (
+	minSlotMinutes = 5
+	maxSlotMinutes = 480
+)
+
+// ScheduleRepository defines the doctor schedule data operations used by the services
+type ScheduleRepository interface {
+	FindWorkingHours(doctorID uint) ([]models.WorkingHours, error)
+	ReplaceWorkingHours(doctorID uint, hours []models.WorkingHours) error
+	FindException(doctorID uint, date string) (*models.ScheduleException, error)
+	FindExceptionByID(id uint) (*models.ScheduleException, error)
+	FindExceptionsFrom(doctorID uint, from string) ([]models.ScheduleException, error)
+	CreateException(exception *models.ScheduleException) error
+	DeleteException(id uint) error
+	IsOnLeave(doctorID uint, date string) (bool, error)
+	FindLeaveByID(id uint) (*models.DoctorLeave, error)
+	FindLeaveFrom(doctorID uint, from string) ([]models.DoctorLeave, error)
+	CreateLeave(leave *models.DoctorLeave) error
+	DeleteLeave(id uint) error
+}
+
+// ScheduleService handles the working hours of doctors and the slots they
+// can be booked in. Times of day are in the clinic's time zone.
+type ScheduleService struct {
+	scheduleRepo    ScheduleRepository
+	appointmentRepo AppointmentRepository
+	waitlistRepo    WaitlistRepository
+	userRepo        UserRepository
+	location        *time.Location
+}
+
+// NewScheduleService creates a new ScheduleService
+func NewScheduleService(scheduleRepo ScheduleRepository, appointmentRepo AppointmentRepository, waitlistRepo WaitlistRepository, userRepo UserRepository, location *time.Location) *ScheduleService {
+	return &ScheduleService{
+		scheduleRepo:    scheduleRepo,
+		appointmentRepo: appointmentRepo,
+		waitlistRepo:    waitlistRepo,
+		userRepo:        userRepo,
+		location:        location,
+	}
+}
+
+// GetSchedule gets the weekly hours of a doctor with the exceptions and
+// leave from today on
+func (s *ScheduleService) GetSchedule(doctorID uint) (*models.DoctorSchedule, error) {
+	if err := s.findDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	today := s.dayOf(time.Now()).Format(models.DateLayout)
+	hours, err := s.scheduleRepo.FindWorkingHours(doctorID)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := s.scheduleRepo.FindExceptionsFrom(doctorID, today)
+	if err != nil {
+		return nil, err
+	}
+	leave, err := s.scheduleRepo.FindLeaveFrom(doctorID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DoctorSchedule{
+		DoctorID:   doctorID,
+		Hours:      hours,
+		Exceptions: exceptions,
+		Leave:      leave,
+	}, nil
+}
+
+// SetWorkingHours replaces the weekly hours of a doctor. Blocks on the same
+// weekday must not overlap.
+func (s *ScheduleService) SetWorkingHours(doctorID uint, req models.SetWorkingHoursRequest) ([]models.WorkingHours, error) {
+	if err := s.findDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	hours := make([]models.WorkingHours, 0, len(req.Hours))
+	for _, input := range req.Hours {
+		if err := validateWorkingHours(input.StartTime, input.EndTime, input.SlotMinutes); err != nil {
+			return nil, err
+		}
+		hours = append(hours, models.WorkingHours{
+			DoctorID:    doctorID,
+			Weekday:     input.Weekday,
+			StartTime:   input.StartTime,
+			EndTime:     input.EndTime,
+			SlotMinutes: input.SlotMinutes,
+		})
+	}
+
+	// Times of day in ClockLayout sort in time order
+	sort.Slice(hours, func(i, j int) bool {
+		if hours[i].Weekday != hours[j].Weekday {
+			return hours[i].Weekday < hours[j].Weekday
+		}
+		return hours[i].StartTime < hours[j].StartTime
+	})
+	for i := 1; i < len(hours); i++ {
+		if hours[i].Weekday == hours[i-1].Weekday && hours[i].StartTime < hours[i-1].EndTime {
+			return nil, fmt.Errorf("%w: blocks on %s overlap", ErrInvalidWorkingHours, hours[i].Weekday)
+		}
+	}
+
+	if err := s.scheduleRepo.ReplaceWorkingHours(doctorID, hours); err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
+
+// AddException changes the hours of a doctor on one date, or marks the day
+// off when no hours are given
+func (s *ScheduleService) AddException(doctorID uint, req models.ScheduleExceptionRequest) (*models.ScheduleException, error) {
+	if err := s.findDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	date, err := s.parseDate(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if req.StartTime != "" || req.EndTime != "" {
+		if err := validateWorkingHours(req.StartTime, req.EndTime, req.SlotMinutes); err != nil {
+			return nil, err
+		}
+	}
+
+	existing, err := s.scheduleRepo.FindException(doctorID, req.Date)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrScheduleExceptionExists
+	}
+
+	exception := &models.ScheduleException{
+		DoctorID:    doctorID,
+		Date:        date,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		SlotMinutes: req.SlotMinutes,
+		Reason:      req.Reason,
+	}
+	if exception.IsClosed() {
+		exception.SlotMinutes = 0
+	}
+	if err := s.scheduleRepo.CreateException(exception); err != nil {
+		return nil, err
+	}
+
+	return exception, nil
+}
+
+// DeleteException restores the weekly hours of a doctor on the date of an
+// exception
+func (s *ScheduleService) DeleteException(doctorID, exceptionID uint) error {
+	exception, err := s.scheduleRepo.FindExceptionByID(exceptionID)
+	if err != nil || exception.DoctorID != doctorID {
+		return ErrScheduleExceptionNotFound
+	}
+	return s.scheduleRepo.DeleteException(exceptionID)
+}
+
+// AddLeave records whole days a doctor is away. Appointments already booked
+// in the period are kept and have to be rescheduled.
+func (s *ScheduleService) AddLeave(doctorID uint, req models.DoctorLeaveRequest, createdBy uint) (*models.DoctorLeave, error) {
+	if err := s.findDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	start, err := s.parseDate(req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	end, err := s.parseDate(req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, ErrInvalidDateRange
+	}
+
+	leave := &models.DoctorLeave{
+		DoctorID:  doctorID,
+		StartDate: start,
+		EndDate:   end,
+		Reason:    req.Reason,
+		CreatedBy: createdBy,
+	}
+	if err := s.scheduleRepo.CreateLeave(leave); err != nil {
+		return nil, err
+	}
+
+	return leave, nil
+}
+
+// DeleteLeave deletes a leave of a doctor
+func (s *ScheduleService) DeleteLeave(doctorID, leaveID uint) error {
+	leave, err := s.scheduleRepo.FindLeaveByID(leaveID)
+	if err != nil || leave.DoctorID != doctorID {
+		return ErrLeaveNotFound
+	}
+	return s.scheduleRepo.DeleteLeave(leaveID)
+}
+
+// GetAvailability gets the free slots of a doctor on a date that have not
+// started yet
+func (s *ScheduleService) GetAvailability(doctorID uint, date string) (*models.DoctorAvailability, error) {
+	if err := s.findDoctor(doctorID); err != nil {
+		return nil, err
+	}
+
+	day, err := s.parseDate(date)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := s.freeSlots(doctorID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DoctorAvailability{
+		DoctorID: doctorID,
+		Date:     date,
+		Slots:    slots,
+	}, nil
+}
+
+// freeSlot finds the free slot of a doctor starting at a time. The
+// appointments being moved, if any, do not count as taking their slots.
+func (s *ScheduleService) freeSlot(doctorID uint, start time.Time, movingIDs ...uint) (*models.Slot, error) {
+	slots, err := s.freeSlots(doctorID, s.dayOf(start), movingIDs...)
+	if err != nil {
+		return nil, err
+	}
+	for _, slot := range slots {
+		if slot.Start.Equal(start) {
+			return &slot, nil
+		}
+	}
+	return nil, ErrSlotUnavailable
+}
+
+// freeSlots lists the slots of a doctor on a day that have not started and
+// do not overlap a booking other than the ones being moved, or a slot held
+// for a waitlisted patient
+func (s *ScheduleService) freeSlots(doctorID uint, day time.Time, movingIDs ...uint) ([]models.Slot, error) {
+	slots, err := s.slotsOn(doctorID, day)
+	if err != nil || len(slots) == 0 {
+		return []models.Slot{}, err
+	}
+
+	booked, err := s.appointmentRepo.FindBooked(doctorID, slots[0].Start, slots[len(slots)-1].End)
+	if err != nil {
+		return nil, err
+	}
+	held, err := s.waitlistRepo.FindHeld(doctorID, slots[0].Start, slots[len(slots)-1].End)
+	if err != nil {
+		return nil, err
+	}
+
+	moving := make(map[uint]bool, len(movingIDs))
+	for _, id := range movingIDs {
+		moving[id] = true
+	}
+
+	now := time.Now()
+	free := make([]models.Slot, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Start.Before(now) {
+			continue
+		}
+		taken := false
+		for _, appointment := range booked {
+			if !moving[appointment.ID] && appointment.StartTime.Before(slot.End) && appointment.EndTime.After(slot.Start) {
+				taken = true
+				break
+			}
+		}
+		for _, offer := range held {
+			if offer.StartTime.Before(slot.End) && offer.EndTime.After(slot.Start) {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			free = append(free, slot)
+		}
+	}
+	return free, nil
+}
+
+// slotsOn lists every slot of a doctor's hours on a day, taking exceptions
+// and leave into account
+func (s *ScheduleService) slotsOn(doctorID uint, day time.Time) ([]models.Slot, error) {
+	date := day.Format(models.DateLayout)
+
+	onLeave, err := s.isOnLeave(doctorID, day)
+	if err != nil || onLeave {
+		return nil, err
+	}
+
+	var blocks []models.WorkingHours
+	exception, err := s.scheduleRepo.FindException(doctorID, date)
+	if err != nil {
+		return nil, err
+	}
+	if exception != nil {
+		if exception.IsClosed() {
+			return nil, nil
+		}
+		blocks = []models.WorkingHours{{StartTime: exception.StartTime, EndTime: exception.EndTime, SlotMinutes: exception.SlotMinutes}}
+	} else {
+		hours, err := s.scheduleRepo.FindWorkingHours(doctorID)
+		if err != nil {
+			return nil, err
+		}
+		for _, block := range hours {
+			if block.Weekday == day.Weekday() {
+				blocks = append(blocks, block)
+			}
+		}
+	}
+
+	var slots []models.Slot
+	for _, block := range blocks {
+		start, err := s.clockOn(day, block.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := s.clockOn(day, block.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		length := time.Duration(block.SlotMinutes) * time.Minute
+		for t := start; !t.Add(length).After(end); t = t.Add(length) {
+			slots = append(slots, models.Slot{Start: t, End: t.Add(length)})
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	return slots, nil
+}
+
+// isOnLeave checks if a doctor is on leave on a calendar date
+func (s *ScheduleService) isOnLeave(doctorID uint, day time.Time) (bool, error) {
+	return s.scheduleRepo.IsOnLeave(doctorID, day.Format(models.DateLayout))
+}
+
+// findDoctor checks that a user is an active doctor
+func (s *ScheduleService) findDoctor(doctorID uint) error {
+	doctor, err := s.userRepo.FindByID(doctorID)
+	if err != nil || doctor.Role != models.RoleDoctor || !doctor.IsActive() {
+		return ErrDoctorNotFound
+	}
+	return nil
+}
+
+// parseDate parses a calendar date. Calendar dates are held as midnight UTC
+// so they are stored as the same date whatever the clinic's time zone.
+func (s *ScheduleService) parseDate(date string) (time.Time, error) {
+	day, err := time.Parse(models.DateLayout, strings.TrimSpace(date))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDate, date)
+	}
+	return day, nil
+}
+
+// dayOf returns the calendar date of a time in the clinic's time zone
+func (s *ScheduleService) dayOf(t time.Time) time.Time {
+	local := t.In(s.location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// dayBounds returns the start of a date in the clinic's time zone and the
+// start of the next day
+func (s *ScheduleService) dayBounds(date string) (time.Time, time.Time, error) {
+	day, err := s.parseDate(date)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, s.location)
+	return start, start.AddDate(0, 0, 1), nil
+}
+
+// clockOn returns a time of day on a calendar date in the clinic's time zone
+func (s *ScheduleService) clockOn(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse(models.ClockLayout, clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %q is not a time of day", ErrInvalidWorkingHours, clock)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, s.location), nil
+}
+
+// validateWorkingHours checks that a block of working time holds at least
+// one slot
+func validateWorkingHours(startTime, endTime string, slotMinutes int) error {
+	start, err := time.Parse(models.ClockLayout, startTime)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a time of day", ErrInvalidWorkingHours, startTime)
+	}
+	end, err := time.Parse(models.ClockLayout, endTime)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not a time of day", ErrInvalidWorkingHours, endTime)
+	}
+	if slotMinutes < minSlotMinutes || slotMinutes > maxSlotMinutes {
+		return fmt.Errorf("%w: slots must last %d to %d minutes", ErrInvalidWorkingHours, minSlotMinutes, maxSlotMinutes)
+	}
+	if end.Sub(start) < time.Duration(slotMinutes)*time.Minute {
+		return fmt.Errorf("%w: %s-%s does not hold a %d minute slot", ErrInvalidWorkingHours, startTime, endTime, slotMinutes)
+	}
+	return nil
+}
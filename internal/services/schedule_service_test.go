@@ -0,0 +1,257 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockScheduleRepository is a mock implementation of ScheduleRepository
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepository) FindWorkingHours(doctorID uint) ([]models.WorkingHours, error) {
+	args := m.Called(doctorID)
+	return args.Get(0).([]models.WorkingHours), args.Error(1)
+}
+
+func (m *MockScheduleRepository) ReplaceWorkingHours(doctorID uint, hours []models.WorkingHours) error {
+	args := m.Called(doctorID, hours)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) FindException(doctorID uint, date string) (*models.ScheduleException, error) {
+	args := m.Called(doctorID, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScheduleException), args.Error(1)
+}
+
+func (m *MockScheduleRepository) FindExceptionByID(id uint) (*models.ScheduleException, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScheduleException), args.Error(1)
+}
+
+func (m *MockScheduleRepository) FindExceptionsFrom(doctorID uint, from string) ([]models.ScheduleException, error) {
+	args := m.Called(doctorID, from)
+	return args.Get(0).([]models.ScheduleException), args.Error(1)
+}
+
+func (m *MockScheduleRepository) CreateException(exception *models.ScheduleException) error {
+	args := m.Called(exception)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) DeleteException(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) IsOnLeave(doctorID uint, date string) (bool, error) {
+	args := m.Called(doctorID, date)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockScheduleRepository) FindLeaveByID(id uint) (*models.DoctorLeave, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DoctorLeave), args.Error(1)
+}
+
+func (m *MockScheduleRepository) FindLeaveFrom(doctorID uint, from string) ([]models.DoctorLeave, error) {
+	args := m.Called(doctorID, from)
+	return args.Get(0).([]models.DoctorLeave), args.Error(1)
+}
+
+func (m *MockScheduleRepository) CreateLeave(leave *models.DoctorLeave) error {
+	args := m.Called(leave)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) DeleteLeave(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+// testScheduleDate is a day far enough ahead that none of its slots have
+// started
+const testScheduleDate = "2099-03-02"
+
+// newTestScheduleService creates a ScheduleService in UTC for doctor 5, who
+// works 09:00-10:00 in 20 minute slots on the weekday of testScheduleDate,
+// with no slots held for the waitlist
+func newTestScheduleService(scheduleRepo *MockScheduleRepository, appointmentRepo *MockAppointmentRepository) *ScheduleService {
+	waitlistRepo := new(MockWaitlistRepository)
+	waitlistRepo.On("FindHeld", uint(5), mock.Anything, mock.Anything).Return([]models.SlotOffer{}, nil).Maybe()
+	return newTestScheduleServiceWithWaitlist(scheduleRepo, appointmentRepo, waitlistRepo)
+}
+
+// newTestScheduleServiceWithWaitlist creates the ScheduleService of
+// newTestScheduleService with the slots held in waitlistRepo
+func newTestScheduleServiceWithWaitlist(scheduleRepo *MockScheduleRepository, appointmentRepo *MockAppointmentRepository, waitlistRepo *MockWaitlistRepository) *ScheduleService {
+	day, _ := time.Parse(models.DateLayout, testScheduleDate)
+	scheduleRepo.On("FindWorkingHours", uint(5)).Return([]models.WorkingHours{
+		{DoctorID: 5, Weekday: day.Weekday(), StartTime: "09:00", EndTime: "10:00", SlotMinutes: 20},
+	}, nil).Maybe()
+	mockUserRepo := new(MockUserRepository)
+	mockUserRepo.On("FindByID", uint(5)).Return(&models.User{ID: 5, Role: models.RoleDoctor}, nil)
+	mockUserRepo.On("FindByID", uint(1)).Return(&models.User{ID: 1, Role: models.RoleReceptionist}, nil)
+
+	return NewScheduleService(scheduleRepo, appointmentRepo, waitlistRepo, mockUserRepo, time.UTC)
+}
+
+// testSlot returns the time of day on testScheduleDate in UTC
+func testSlot(clock string) time.Time {
+	t, _ := time.Parse(models.DateLayout+" "+models.ClockLayout, testScheduleDate+" "+clock)
+	return t
+}
+
+func TestGetAvailability_WeeklyHours(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), testScheduleDate).Return(false, nil)
+	mockScheduleRepo.On("FindException", uint(5), testScheduleDate).Return(nil, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), testSlot("09:00"), testSlot("10:00")).Return([]models.Appointment{
+		{ID: 7, DoctorID: 5, Status: models.AppointmentStatusBooked, StartTime: testSlot("09:20"), EndTime: testSlot("09:40")},
+	}, nil)
+
+	service := newTestScheduleService(mockScheduleRepo, mockAppointmentRepo)
+
+	availability, err := service.GetAvailability(5, testScheduleDate)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Slot{
+		{Start: testSlot("09:00"), End: testSlot("09:20")},
+		{Start: testSlot("09:40"), End: testSlot("10:00")},
+	}, availability.Slots)
+}
+
+func TestGetAvailability_HeldForWaitlist(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), testScheduleDate).Return(false, nil)
+	mockScheduleRepo.On("FindException", uint(5), testScheduleDate).Return(nil, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), testSlot("09:00"), testSlot("10:00")).Return([]models.Appointment{}, nil)
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindHeld", uint(5), testSlot("09:00"), testSlot("10:00")).Return([]models.SlotOffer{
+		{ID: 3, DoctorID: 5, Status: models.SlotOfferStatusPending, StartTime: testSlot("09:20"), EndTime: testSlot("09:40")},
+	}, nil)
+
+	service := newTestScheduleServiceWithWaitlist(mockScheduleRepo, mockAppointmentRepo, mockWaitlistRepo)
+
+	availability, err := service.GetAvailability(5, testScheduleDate)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Slot{
+		{Start: testSlot("09:00"), End: testSlot("09:20")},
+		{Start: testSlot("09:40"), End: testSlot("10:00")},
+	}, availability.Slots)
+}
+
+func TestGetAvailability_Exception(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), testScheduleDate).Return(false, nil)
+	mockScheduleRepo.On("FindException", uint(5), testScheduleDate).Return(&models.ScheduleException{
+		DoctorID: 5, StartTime: "14:00", EndTime: "15:00", SlotMinutes: 30,
+	}, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{}, nil)
+
+	service := newTestScheduleService(mockScheduleRepo, mockAppointmentRepo)
+
+	availability, err := service.GetAvailability(5, testScheduleDate)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Slot{
+		{Start: testSlot("14:00"), End: testSlot("14:30")},
+		{Start: testSlot("14:30"), End: testSlot("15:00")},
+	}, availability.Slots)
+	mockScheduleRepo.AssertNotCalled(t, "FindWorkingHours", mock.Anything)
+}
+
+func TestGetAvailability_DayOff(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), testScheduleDate).Return(false, nil)
+	mockScheduleRepo.On("FindException", uint(5), testScheduleDate).Return(&models.ScheduleException{DoctorID: 5}, nil)
+	mockAppointmentRepo := new(MockAppointmentRepository)
+
+	service := newTestScheduleService(mockScheduleRepo, mockAppointmentRepo)
+
+	availability, err := service.GetAvailability(5, testScheduleDate)
+
+	assert.NoError(t, err)
+	assert.Empty(t, availability.Slots)
+	mockAppointmentRepo.AssertNotCalled(t, "FindBooked", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetAvailability_OnLeave(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), testScheduleDate).Return(true, nil)
+
+	service := newTestScheduleService(mockScheduleRepo, new(MockAppointmentRepository))
+
+	availability, err := service.GetAvailability(5, testScheduleDate)
+
+	assert.NoError(t, err)
+	assert.Empty(t, availability.Slots)
+	mockScheduleRepo.AssertNotCalled(t, "FindException", mock.Anything, mock.Anything)
+}
+
+func TestGetAvailability_InvalidRequest(t *testing.T) {
+	service := newTestScheduleService(new(MockScheduleRepository), new(MockAppointmentRepository))
+
+	_, err := service.GetAvailability(5, "02/03/2099")
+	assert.ErrorIs(t, err, ErrInvalidDate)
+
+	_, err = service.GetAvailability(1, testScheduleDate)
+	assert.ErrorIs(t, err, ErrDoctorNotFound)
+}
+
+func TestSetWorkingHours_Overlap(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+
+	service := newTestScheduleService(mockScheduleRepo, new(MockAppointmentRepository))
+
+	_, err := service.SetWorkingHours(5, models.SetWorkingHoursRequest{Hours: []models.WorkingHoursInput{
+		{Weekday: time.Monday, StartTime: "13:00", EndTime: "17:00", SlotMinutes: 15},
+		{Weekday: time.Monday, StartTime: "09:00", EndTime: "13:30", SlotMinutes: 15},
+	}})
+
+	assert.ErrorIs(t, err, ErrInvalidWorkingHours)
+	mockScheduleRepo.AssertNotCalled(t, "ReplaceWorkingHours", mock.Anything, mock.Anything)
+}
+
+func TestSetWorkingHours_InvalidBlock(t *testing.T) {
+	service := newTestScheduleService(new(MockScheduleRepository), new(MockAppointmentRepository))
+
+	for _, input := range []models.WorkingHoursInput{
+		{Weekday: time.Monday, StartTime: "9am", EndTime: "17:00", SlotMinutes: 15},
+		{Weekday: time.Monday, StartTime: "09:00", EndTime: "09:10", SlotMinutes: 15},
+		{Weekday: time.Monday, StartTime: "09:00", EndTime: "17:00", SlotMinutes: 0},
+	} {
+		_, err := service.SetWorkingHours(5, models.SetWorkingHoursRequest{Hours: []models.WorkingHoursInput{input}})
+		assert.ErrorIs(t, err, ErrInvalidWorkingHours)
+	}
+}
+
+func TestAddLeave_InvalidRange(t *testing.T) {
+	mockScheduleRepo := new(MockScheduleRepository)
+
+	service := newTestScheduleService(mockScheduleRepo, new(MockAppointmentRepository))
+
+	_, err := service.AddLeave(5, models.DoctorLeaveRequest{StartDate: "2099-03-10", EndDate: "2099-03-02"}, 1)
+
+	assert.ErrorIs(t, err, ErrInvalidDateRange)
+	mockScheduleRepo.AssertNotCalled(t, "CreateLeave", mock.Anything)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// smsGatewayTimeout bounds each request to the SMS gateway
+const smsGatewayTimeout = 10 * time.Second
+
+// HTTPSMSNotifier sends text messages through an HTTP SMS gateway. Each
+// message is POSTed as JSON with the sender, recipient and text; any 2xx
+// response means the gateway accepted it.
+type HTTPSMSNotifier struct {
+	url    string
+	token  string
+	sender string
+	client *http.Client
+}
+
+// smsGatewayRequest is the body posted to the SMS gateway
+type smsGatewayRequest struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// NewHTTPSMSNotifier creates a new HTTPSMSNotifier. The token, when given,
+// is sent as a bearer token.
+func NewHTTPSMSNotifier(url, token, sender string) *HTTPSMSNotifier {
+	return &HTTPSMSNotifier{
+		url:    url,
+		token:  token,
+		sender: sender,
+		client: &http.Client{Timeout: smsGatewayTimeout},
+	}
+}
+
+// Notify sends the body as a text message; text messages have no subject
+func (n *HTTPSMSNotifier) Notify(to, subject, body string) error {
+	payload, err := json.Marshal(smsGatewayRequest{From: n.sender, To: to, Message: body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("SMS gateway responded %s", resp.Status)
+	}
+	return nil
+}
@@ -1,120 +1,267 @@
-package services
-
-import (
-	"errors"
-
-	"healthcare-app/internal/models"
-	"healthcare-app/internal/repositories"
-)
-
-// Predefined errors
-var (
-	ErrEmailExists = errors.New("email already exists")
-	ErrUserNotFound = errors.New("user not found")
-)
-
-// UserService handles user business logic
-type UserService struct {
-	userRepo *repositories.UserRepository
-}
-
-// NewUserService creates a new UserService
-func NewUserService(userRepo *repositories.UserRepository) *UserService {
-	return &UserService{
-		userRepo: userRepo,
-	}
-}
-
-// CreateUser creates a new user
-func (s *UserService) CreateUser(req models.CreateUserRequest) (*models.UserResponse, error) {
-	// Check if email exists
-	exists, err := s.userRepo.EmailExists(req.Email)
-	if err != nil {
-		return nil, err
-	}
-	if exists {
-		return nil, ErrEmailExists
-	}
-
-	// Create user
-	user := &models.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: req.Password,
-		Role:     req.Role,
-	}
-
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, err
-	}
-
-	return &user.ToUserResponse(), nil
-}
-
-// GetUser gets a user by ID
-func (s *UserService) GetUser(id uint) (*models.UserResponse, error) {
-	user, err := s.userRepo.FindByID(id)
-	if err != nil {
-		return nil, ErrUserNotFound
-	}
-
-	response := user.ToUserResponse()
-	return &response, nil
-}
-
-// GetAllUsers gets all users
-func (s *UserService) GetAllUsers() ([]models.UserResponse, error) {
-	users, err := s.userRepo.FindAll()
-	if err != nil {
-		return nil, err
-	}
-
-	var responses []models.UserResponse
-	for _, user := range users {
-		responses = append(responses, user.ToUserResponse())
-	}
-
-	return responses, nil
-}
-
-// UpdateUser updates a user
-func (s *UserService) UpdateUser(id uint, req models.CreateUserRequest) (*models.UserResponse, error) {
-	user, err := s.userRepo.FindByID(id)
-	if err != nil {
-		return nil, ErrUserNotFound
-	}
-
-	// Check if email exists for a different user
-	if req.Email != user.Email {
-		exists, err := s.userRepo.EmailExists(req.Email)
-		if err != nil {
-			return nil, err
-		}
-		if exists {
-			return nil, ErrEmailExists
-		}
-	}
-
-	// Update user
-	user.Name = req.Name
-	user.Email = req.Email
-	user.Password = req.Password
-	user.Role = req.Role
-
-	if err := s.userRepo.Update(user); err != nil {
-		return nil, err
-	}
-
-	response := user.ToUserResponse()
-	return &response, nil
-}
-
-// DeleteUser deletes a user
-func (s *UserService) DeleteUser(id uint) error {
-	_, err := s.userRepo.FindByID(id)
-	if err != nil {
-		return ErrUserNotFound
-	}
-
-	return s.userRepo.Delete(id)
-} 
\ No newline at end of file
+package services
+
+import (
+	"errors"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrEmailExists         = errors.New("email already exists")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrCannotModifySelf    = errors.New("you cannot deactivate or delete your own account")
+	ErrUserAlreadyInactive = errors.New("user is already deactivated")
+	ErrUserAlreadyActive   = errors.New("user is already active")
+)
+
+// UserRepository defines the user data operations used by the services
+type UserRepository interface {
+	Create(user *models.User) error
+	FindByID(id uint) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
+	FindAll(filter models.UserFilter, limit, offset int) ([]models.User, int64, error)
+	Update(user *models.User) error
+	UpdateColumns(id uint, columns map[string]interface{}) error
+	AdvanceTOTPStep(id uint, step int64) (bool, error)
+	Delete(id uint) error
+	EmailExists(email string) (bool, error)
+}
+
+// UserService handles user business logic
+type UserService struct {
+	userRepo    UserRepository
+	sessionRepo SessionRepository
+	throttle    *LoginThrottle
+}
+
+// NewUserService creates a new UserService
+func NewUserService(userRepo UserRepository, sessionRepo SessionRepository, throttle *LoginThrottle) *UserService {
+	return &UserService{
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		throttle:    throttle,
+	}
+}
+
+// CreateUser creates a new user
+func (s *UserService) CreateUser(req models.CreateUserRequest) (*models.UserResponse, error) {
+	// Check if email exists
+	exists, err := s.userRepo.EmailExists(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrEmailExists
+	}
+
+	// Create user
+	user := &models.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: req.Password,
+		Role:     req.Role,
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	response := user.ToUserResponse()
+	return &response, nil
+}
+
+// GetUser gets a user by ID
+func (s *UserService) GetUser(id uint) (*models.UserResponse, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	return s.toUserResponse(user)
+}
+
+// GetAllUsers gets users matching a filter with pagination
+func (s *UserService) GetAllUsers(filter models.UserFilter, page, pageSize int) (*PaginationResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	users, totalItems, err := s.userRepo.FindAll(filter, pageSize, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]models.UserResponse, 0, len(users))
+	for i := range users {
+		response, err := s.toUserResponse(&users[i])
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, *response)
+	}
+
+	totalPages := (int(totalItems) + pageSize - 1) / pageSize
+
+	return &PaginationResponse{
+		TotalItems: totalItems,
+		Items:      responses,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// UpdateUser updates a user. The stored password hash is only replaced when
+// a new password is supplied. Changing the role or password signs the user
+// out everywhere so the change takes effect immediately.
+func (s *UserService) UpdateUser(id uint, req models.UpdateUserRequest) (*models.UserResponse, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	// Check if email exists for a different user
+	if req.Email != user.Email {
+		exists, err := s.userRepo.EmailExists(req.Email)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrEmailExists
+		}
+	}
+
+	// Update user
+	columns := map[string]interface{}{
+		"name":  req.Name,
+		"email": req.Email,
+		"role":  req.Role,
+	}
+	if req.Password != "" {
+		hash, err := models.HashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		columns["password"] = hash
+	}
+
+	if err := s.userRepo.UpdateColumns(user.ID, columns); err != nil {
+		return nil, err
+	}
+
+	if req.Password != "" || req.Role != user.Role {
+		if err := s.sessionRepo.RevokeAllForUser(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Name = req.Name
+	user.Email = req.Email
+	user.Role = req.Role
+
+	return s.toUserResponse(user)
+}
+
+// DeleteUser deletes a user and revokes their sessions
+func (s *UserService) DeleteUser(actorID, id uint) error {
+	if actorID == id {
+		return ErrCannotModifySelf
+	}
+
+	_, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.userRepo.Delete(id); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.RevokeAllForUser(id)
+}
+
+// DeactivateUser prevents a user from logging in and revokes their sessions
+// while keeping the account and its history
+func (s *UserService) DeactivateUser(actorID, id uint) (*models.UserResponse, error) {
+	if actorID == id {
+		return nil, ErrCannotModifySelf
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if !user.IsActive() {
+		return nil, ErrUserAlreadyInactive
+	}
+
+	now := time.Now()
+	if err := s.userRepo.UpdateColumns(user.ID, map[string]interface{}{"deactivated_at": now}); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.RevokeAllForUser(user.ID); err != nil {
+		return nil, err
+	}
+
+	user.DeactivatedAt = &now
+	return s.toUserResponse(user)
+}
+
+// ActivateUser allows a deactivated user to log in again
+func (s *UserService) ActivateUser(id uint) (*models.UserResponse, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.IsActive() {
+		return nil, ErrUserAlreadyActive
+	}
+
+	if err := s.userRepo.UpdateColumns(user.ID, map[string]interface{}{"deactivated_at": nil}); err != nil {
+		return nil, err
+	}
+
+	user.DeactivatedAt = nil
+	return s.toUserResponse(user)
+}
+
+// UnlockUser clears a user's login lockout and failure counter
+func (s *UserService) UnlockUser(id uint) (*models.UserResponse, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	if err := s.throttle.Unlock(user.Email); err != nil {
+		return nil, err
+	}
+
+	response := user.ToUserResponse()
+	return &response, nil
+}
+
+// toUserResponse converts a User to a UserResponse including its lock state
+func (s *UserService) toUserResponse(user *models.User) (*models.UserResponse, error) {
+	response := user.ToUserResponse()
+
+	attempt, err := s.throttle.Status(user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if attempt != nil {
+		response.FailedLoginAttempts = attempt.Failures
+		if attempt.IsLocked(time.Now()) {
+			response.Locked = true
+			response.LockedUntil = attempt.LockedUntil
+		}
+	}
+
+	return &response, nil
+}
\ No newline at end of file
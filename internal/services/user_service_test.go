@@ -0,0 +1,126 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdateUser_WithoutPasswordKeepsHash(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(2)).Return(&models.User{
+		ID:       2,
+		Name:     "Old Name",
+		Email:    "doctor@example.com",
+		Password: "stored-hash",
+		Role:     models.RoleDoctor,
+	}, nil)
+	mockRepo.On("UpdateColumns", uint(2), map[string]interface{}{
+		"name":  "New Name",
+		"email": "doctor@example.com",
+		"role":  models.RoleDoctor,
+	}).Return(nil)
+	mockSessionRepo := new(MockSessionRepository)
+
+	service := NewUserService(mockRepo, mockSessionRepo, newTestLoginThrottle())
+
+	res, err := service.UpdateUser(2, models.UpdateUserRequest{
+		Name:  "New Name",
+		Email: "doctor@example.com",
+		Role:  models.RoleDoctor,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "New Name", res.Name)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockSessionRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
+}
+
+func TestUpdateUser_RoleChangeRevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(2)).Return(&models.User{ID: 2, Email: "user@example.com", Role: models.RoleReceptionist}, nil)
+	mockRepo.On("UpdateColumns", uint(2), mock.Anything).Return(nil)
+	mockSessionRepo := new(MockSessionRepository)
+	mockSessionRepo.On("RevokeAllForUser", uint(2)).Return(nil)
+
+	service := NewUserService(mockRepo, mockSessionRepo, newTestLoginThrottle())
+
+	res, err := service.UpdateUser(2, models.UpdateUserRequest{
+		Name:  "User",
+		Email: "user@example.com",
+		Role:  models.RoleAdmin,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, res.Role)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestDeactivateUser_RevokesSessions(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(2)).Return(&models.User{ID: 2, Email: "user@example.com"}, nil)
+	mockRepo.On("UpdateColumns", uint(2), mock.Anything).Return(nil)
+	mockSessionRepo := new(MockSessionRepository)
+	mockSessionRepo.On("RevokeAllForUser", uint(2)).Return(nil)
+
+	service := NewUserService(mockRepo, mockSessionRepo, newTestLoginThrottle())
+
+	res, err := service.DeactivateUser(1, 2)
+
+	assert.NoError(t, err)
+	assert.False(t, res.Active)
+	assert.NotNil(t, res.DeactivatedAt)
+	mockRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestDeactivateUser_Self(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+
+	service := NewUserService(mockRepo, new(MockSessionRepository), newTestLoginThrottle())
+
+	res, err := service.DeactivateUser(1, 1)
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrCannotModifySelf, err)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything)
+}
+
+func TestActivateUser_AlreadyActive(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindByID", uint(2)).Return(&models.User{ID: 2}, nil)
+
+	service := NewUserService(mockRepo, new(MockSessionRepository), newTestLoginThrottle())
+
+	res, err := service.ActivateUser(2)
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrUserAlreadyActive, err)
+}
+
+func TestGetAllUsers_FilterAndPagination(t *testing.T) {
+	deactivatedAt := time.Now()
+	users := []models.User{
+		{ID: 3, Email: "a@example.com", Role: models.RoleDoctor, DeactivatedAt: &deactivatedAt},
+		{ID: 4, Email: "b@example.com", Role: models.RoleDoctor, DeactivatedAt: &deactivatedAt},
+	}
+	filter := models.UserFilter{Role: models.RoleDoctor, Status: models.UserStatusDeactivated}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("FindAll", filter, 2, 2).Return(users, int64(5), nil)
+
+	service := NewUserService(mockRepo, new(MockSessionRepository), newTestLoginThrottle())
+
+	res, err := service.GetAllUsers(filter, 2, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), res.TotalItems)
+	assert.Equal(t, 3, res.TotalPages)
+	assert.Len(t, res.Items, 2)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrImplausibleVital = errors.New("implausible vital sign")
+	ErrInvalidVitalUnit = errors.New("invalid unit for vital sign")
+)
+
+// plausibleVitalRanges bound the values each vital sign can take, in stored
+// units; anything outside is a recording error
+var plausibleVitalRanges = map[models.VitalType][2]float64{
+	models.VitalBloodPressure: {40, 300},
+	models.VitalPulse:         {20, 300},
+	models.VitalTemperature:   {25, 45},
+	models.VitalSpO2:          {50, 100},
+	models.VitalHeight:        {20, 272},
+	models.VitalWeight:        {0.5, 650},
+	models.VitalBMI:           {5, 150},
+}
+
+// plausibleDiastolicRange bounds diastolic blood pressure in mmHg
+var plausibleDiastolicRange = [2]float64{20, 200}
+
+// vitalClockSkew is how far in the future vital signs may be recorded
+const vitalClockSkew = 5 * time.Minute
+
+// VitalRepository defines the vital sign data operations used by the VitalService
+type VitalRepository interface {
+	CreateBatch(vitals []models.Vital, event *models.AuditEvent) error
+	FindByPatient(patientID uint, filter models.VitalFilter) ([]models.Vital, error)
+}
+
+// VitalService handles the vital sign observations of patients
+type VitalService struct {
+	vitalRepo        VitalRepository
+	patientService   *PatientService
+	encounterService *EncounterService
+	auditRepo        AuditRepository
+}
+
+// NewVitalService creates a new VitalService
+func NewVitalService(vitalRepo VitalRepository, patientService *PatientService, encounterService *EncounterService, auditRepo AuditRepository) *VitalService {
+	return &VitalService{
+		vitalRepo:        vitalRepo,
+		patientService:   patientService,
+		encounterService: encounterService,
+		auditRepo:        auditRepo,
+	}
+}
+
+// RecordVitals records a set of vital signs taken together, optionally during
+// an encounter, converting them to the stored units. BMI is calculated when
+// weight and height are recorded together without it.
+func (s *VitalService) RecordVitals(accessor PatientAccessor, patientID uint, req models.RecordVitalsRequest) ([]models.Vital, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionVitalsRecord); err != nil {
+		return nil, err
+	}
+
+	recordedAt := time.Now()
+	if req.RecordedAt != nil {
+		recordedAt = *req.RecordedAt
+	}
+	if recordedAt.After(time.Now().Add(vitalClockSkew)) {
+		return nil, fmt.Errorf("%w: recorded_at is in the future", ErrImplausibleVital)
+	}
+	if err := s.encounterService.checkAttachable(patientID, req.EncounterID); err != nil {
+		return nil, err
+	}
+
+	vitals := make([]models.Vital, 0, len(req.Observations)+1)
+	recorded := make(map[models.VitalType]float64)
+	for _, input := range req.Observations {
+		vital, err := newVital(patientID, accessor.UserID, recordedAt, input)
+		if err != nil {
+			return nil, err
+		}
+		vital.EncounterID = req.EncounterID
+		vitals = append(vitals, vital)
+		recorded[vital.Type] = vital.Value
+	}
+
+	weight, hasWeight := recorded[models.VitalWeight]
+	height, hasHeight := recorded[models.VitalHeight]
+	if _, hasBMI := recorded[models.VitalBMI]; !hasBMI && hasWeight && hasHeight {
+		vitals = append(vitals, models.Vital{
+			PatientID:   patientID,
+			EncounterID: req.EncounterID,
+			Type:        models.VitalBMI,
+			Value:       models.CalculateBMI(weight, height),
+			Unit:        models.VitalUnits[models.VitalBMI],
+			RecordedAt:  recordedAt,
+			RecordedBy:  accessor.UserID,
+		})
+	}
+
+	types := make([]string, len(vitals))
+	for i := range vitals {
+		types[i] = string(vitals[i].Type)
+	}
+	event := newAuditEvent(accessor, models.AuditActionVitalsRecord, patientID, nil)
+	event.Details = fmt.Sprintf("vitals=%s recorded_at=%s", strings.Join(types, ","), recordedAt.UTC().Format(time.RFC3339))
+	if err := s.vitalRepo.CreateBatch(vitals, event); err != nil {
+		return nil, err
+	}
+
+	for i := range vitals {
+		vitals[i].Interpret()
+	}
+	return vitals, nil
+}
+
+// GetVitals gets the vital signs of a patient matching a filter, oldest
+// first, flagged against reference ranges and optionally in imperial units
+func (s *VitalService) GetVitals(accessor PatientAccessor, patientID uint, filter models.VitalFilter, imperial bool) ([]models.Vital, error) {
+	if err := s.patientService.CheckAccess(accessor, patientID, models.AuditActionRead); err != nil {
+		return nil, err
+	}
+
+	vitals, err := s.vitalRepo.FindByPatient(patientID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionRead, patientID, nil)
+	event.Details = "vitals"
+	if filter.Type != "" {
+		event.Details += " type=" + string(filter.Type)
+	}
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	for i := range vitals {
+		vitals[i].Interpret()
+		if imperial {
+			vitals[i] = vitals[i].InImperialUnits()
+		}
+	}
+	return vitals, nil
+}
+
+// newVital validates an observation and converts it to the stored unit
+func newVital(patientID, recordedBy uint, recordedAt time.Time, input models.VitalInput) (models.Vital, error) {
+	value, ok := models.ToVitalUnit(input.Type, input.Value, input.Unit)
+	if !ok {
+		return models.Vital{}, fmt.Errorf("%w: %s cannot be given in %q", ErrInvalidVitalUnit, input.Type, input.Unit)
+	}
+
+	bounds := plausibleVitalRanges[input.Type]
+	if value < bounds[0] || value > bounds[1] {
+		return models.Vital{}, fmt.Errorf("%w: %s of %g %s is outside %g-%g", ErrImplausibleVital,
+			input.Type, value, models.VitalUnits[input.Type], bounds[0], bounds[1])
+	}
+
+	vital := models.Vital{
+		PatientID:  patientID,
+		Type:       input.Type,
+		Value:      value,
+		Unit:       models.VitalUnits[input.Type],
+		RecordedAt: recordedAt,
+		RecordedBy: recordedBy,
+	}
+
+	if input.Type == models.VitalBloodPressure {
+		if input.Diastolic == nil {
+			return models.Vital{}, fmt.Errorf("%w: bp requires a diastolic value", ErrImplausibleVital)
+		}
+		diastolic := *input.Diastolic
+		if diastolic < plausibleDiastolicRange[0] || diastolic > plausibleDiastolicRange[1] || diastolic >= value {
+			return models.Vital{}, fmt.Errorf("%w: bp of %g/%g mmHg", ErrImplausibleVital, value, diastolic)
+		}
+		vital.Diastolic = &diastolic
+	}
+
+	return vital, nil
+}
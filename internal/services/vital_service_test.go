@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockVitalRepository is a mock implementation of VitalRepository
+type MockVitalRepository struct {
+	mock.Mock
+}
+
+func (m *MockVitalRepository) CreateBatch(vitals []models.Vital, event *models.AuditEvent) error {
+	args := m.Called(vitals, event)
+	return args.Error(0)
+}
+
+func (m *MockVitalRepository) FindByPatient(patientID uint, filter models.VitalFilter) ([]models.Vital, error) {
+	args := m.Called(patientID, filter)
+	return args.Get(0).([]models.Vital), args.Error(1)
+}
+
+// newTestVitalService creates a VitalService for a patient on the doctor's
+// care team
+func newTestVitalService(vitalRepo *MockVitalRepository) *VitalService {
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1}, nil)
+	mockCareTeamRepo := new(MockCareTeamRepository)
+	mockCareTeamRepo.On("IsMember", uint(1), uint(5), mock.Anything).Return(true, nil)
+	auditRepo := newMockAuditRepository()
+
+	patientService := NewPatientService(mockPatientRepo, mockCareTeamRepo, auditRepo)
+	encounterService := NewEncounterService(new(MockEncounterRepository), patientService, new(MockUserRepository), auditRepo)
+	return NewVitalService(vitalRepo, patientService, encounterService, auditRepo)
+}
+
+func TestRecordVitals_ConvertsUnitsAndCalculatesBMI(t *testing.T) {
+	mockVitalRepo := new(MockVitalRepository)
+	mockVitalRepo.On("CreateBatch", mock.Anything, mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionVitalsRecord
+	})).Return(nil)
+
+	service := newTestVitalService(mockVitalRepo)
+
+	vitals, err := service.RecordVitals(doctorAccessor, 1, models.RecordVitalsRequest{
+		Observations: []models.VitalInput{
+			{Type: models.VitalTemperature, Value: 101.3, Unit: "°F"},
+			{Type: models.VitalWeight, Value: 220.5, Unit: "lb"},
+			{Type: models.VitalHeight, Value: 180},
+		},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, vitals, 4)
+	assert.Equal(t, 38.5, vitals[0].Value)
+	assert.Equal(t, "C", vitals[0].Unit)
+	assert.True(t, vitals[0].Abnormal)
+	assert.Equal(t, models.VitalInterpretationHigh, vitals[0].Interpretation)
+	assert.Equal(t, 100.0, vitals[1].Value)
+	assert.Equal(t, models.VitalBMI, vitals[3].Type)
+	assert.Equal(t, 30.9, vitals[3].Value)
+	assert.True(t, vitals[3].Abnormal)
+	mockVitalRepo.AssertExpectations(t)
+}
+
+func TestRecordVitals_Implausible(t *testing.T) {
+	diastolic := 130.0
+	future := time.Now().Add(time.Hour)
+	tests := []models.RecordVitalsRequest{
+		{Observations: []models.VitalInput{{Type: models.VitalSpO2, Value: 120}}},
+		{Observations: []models.VitalInput{{Type: models.VitalBloodPressure, Value: 120}}},
+		{Observations: []models.VitalInput{{Type: models.VitalBloodPressure, Value: 120, Diastolic: &diastolic}}},
+		{Observations: []models.VitalInput{{Type: models.VitalPulse, Value: 72}}, RecordedAt: &future},
+	}
+
+	for _, req := range tests {
+		mockVitalRepo := new(MockVitalRepository)
+		service := newTestVitalService(mockVitalRepo)
+
+		_, err := service.RecordVitals(doctorAccessor, 1, req)
+
+		assert.True(t, errors.Is(err, ErrImplausibleVital), "got %v", err)
+		mockVitalRepo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	}
+}
+
+func TestRecordVitals_InvalidUnit(t *testing.T) {
+	service := newTestVitalService(new(MockVitalRepository))
+
+	_, err := service.RecordVitals(doctorAccessor, 1, models.RecordVitalsRequest{
+		Observations: []models.VitalInput{{Type: models.VitalPulse, Value: 72, Unit: "lb"}},
+	})
+
+	assert.True(t, errors.Is(err, ErrInvalidVitalUnit))
+}
+
+func TestGetVitals_FlagsAndConverts(t *testing.T) {
+	diastolic := 95.0
+	mockVitalRepo := new(MockVitalRepository)
+	filter := models.VitalFilter{}
+	mockVitalRepo.On("FindByPatient", uint(1), filter).Return([]models.Vital{
+		{ID: 1, Type: models.VitalBloodPressure, Value: 150, Diastolic: &diastolic, Unit: "mmHg"},
+		{ID: 2, Type: models.VitalWeight, Value: 50, Unit: "kg"},
+		{ID: 3, Type: models.VitalSpO2, Value: 97, Unit: "%"},
+	}, nil)
+
+	service := newTestVitalService(mockVitalRepo)
+
+	vitals, err := service.GetVitals(doctorAccessor, 1, filter, true)
+
+	assert.NoError(t, err)
+	assert.True(t, vitals[0].Abnormal)
+	assert.Equal(t, 110.2, vitals[1].Value)
+	assert.Equal(t, "lb", vitals[1].Unit)
+	assert.False(t, vitals[2].Abnormal)
+	assert.Equal(t, models.VitalInterpretationNormal, vitals[2].Interpretation)
+}
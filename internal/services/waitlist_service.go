@@ -0,0 +1,421 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"healthcare-app/internal/models"
+)
+
+// Predefined errors
+var (
+	ErrWaitlistEntryNotFound      = errors.New("waitlist entry not found")
+	ErrWaitlistEntryNotWaiting    = errors.New("waitlist entry is not waiting")
+	ErrInvalidWaitlistPreferences = errors.New("invalid waitlist days or times")
+	ErrSlotOfferNotFound          = errors.New("slot offer not found")
+	ErrSlotOfferClosed            = errors.New("slot offer is no longer open")
+)
+
+// systemAccessor is the actor of changes the application makes on its own,
+// such as offering a slot again once a hold has expired
+var systemAccessor = PatientAccessor{AllPatients: true}
+
+// WaitlistRepository defines the waitlist data operations used by the services
+type WaitlistRepository interface {
+	Create(entry *models.WaitlistEntry, event *models.AuditEvent) error
+	FindByID(id uint) (*models.WaitlistEntry, error)
+	FindAll(filter models.WaitlistFilter) ([]models.PatientWaitlistEntry, error)
+	FindWaiting(doctorID uint, appointmentType models.AppointmentType, start time.Time) ([]models.WaitlistEntry, error)
+	Remove(entry *models.WaitlistEntry, event *models.AuditEvent) (bool, error)
+	FindHeld(doctorID uint, from, to time.Time) ([]models.SlotOffer, error)
+	CreateOffer(offer *models.SlotOffer, event *models.AuditEvent) (bool, error)
+	FindOfferByID(id uint) (*models.SlotOffer, error)
+	FindOffers(filter models.SlotOfferFilter) ([]models.SlotOffer, error)
+	FindExpiredOffers(before time.Time) ([]models.SlotOffer, error)
+	AcceptOffer(offer *models.SlotOffer, appointment *models.Appointment, event *models.AuditEvent) (bool, error)
+	CloseOffer(offer *models.SlotOffer, event *models.AuditEvent) (bool, error)
+}
+
+// WaitlistService keeps patients waiting for an earlier appointment and
+// offers them slots freed by cancellations. An offered slot is held for the
+// patient until the offer expires, then offered to the next patient.
+type WaitlistService struct {
+	waitlistRepo    WaitlistRepository
+	scheduleService *ScheduleService
+	patientService  *PatientService
+	patientRepo     PatientRepository
+	notifications   *NotificationService
+	auditRepo       AuditRepository
+	offerTTL        time.Duration
+}
+
+// NewWaitlistService creates a new WaitlistService
+func NewWaitlistService(waitlistRepo WaitlistRepository, scheduleService *ScheduleService, patientService *PatientService, patientRepo PatientRepository, notifications *NotificationService, auditRepo AuditRepository, offerTTL time.Duration) *WaitlistService {
+	return &WaitlistService{
+		waitlistRepo:    waitlistRepo,
+		scheduleService: scheduleService,
+		patientService:  patientService,
+		patientRepo:     patientRepo,
+		notifications:   notifications,
+		auditRepo:       auditRepo,
+		offerTTL:        offerTTL,
+	}
+}
+
+// AddEntry puts a patient on a doctor's waitlist for a type of appointment
+func (s *WaitlistService) AddEntry(accessor PatientAccessor, req models.CreateWaitlistEntryRequest) (*models.WaitlistEntry, error) {
+	if err := s.patientService.CheckAccess(accessor, req.PatientID, models.AuditActionWaitlistCreate); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleService.findDoctor(req.DoctorID); err != nil {
+		return nil, err
+	}
+	if err := validateWaitlistPreferences(req); err != nil {
+		return nil, err
+	}
+
+	entry := &models.WaitlistEntry{
+		PatientID: req.PatientID,
+		DoctorID:  req.DoctorID,
+		Type:      req.Type,
+		Days:      models.Weekdays(req.Days),
+		FromTime:  req.FromTime,
+		ToTime:    req.ToTime,
+		Status:    models.WaitlistStatusWaiting,
+		Reason:    req.Reason,
+		CreatedBy: accessor.UserID,
+	}
+	if entry.Days == nil {
+		entry.Days = models.Weekdays{}
+	}
+
+	event := newAuditEvent(accessor, models.AuditActionWaitlistCreate, req.PatientID, models.DiffRecords(nil, entry))
+	if err := s.waitlistRepo.Create(entry, event); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetEntries lists waitlist entries oldest first and records which patients
+// were listed
+func (s *WaitlistService) GetEntries(accessor PatientAccessor, filter models.WaitlistFilter) ([]models.PatientWaitlistEntry, error) {
+	entries, err := s.waitlistRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = fmt.Sprint(entry.PatientID)
+	}
+	event := newAuditEvent(accessor, models.AuditActionSearch, 0, nil)
+	event.Details = fmt.Sprintf("waitlist doctor=%d patients=[%s]", filter.DoctorID, strings.Join(ids, ","))
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// RemoveEntry takes a waiting patient off the waitlist
+func (s *WaitlistService) RemoveEntry(accessor PatientAccessor, id uint) (*models.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	if err := s.patientService.CheckAccess(accessor, entry.PatientID, models.AuditActionWaitlistUpdate); err != nil {
+		return nil, err
+	}
+	if entry.Status != models.WaitlistStatusWaiting {
+		return nil, ErrWaitlistEntryNotWaiting
+	}
+
+	before := *entry
+	entry.Status = models.WaitlistStatusRemoved
+
+	event := newAuditEvent(accessor, models.AuditActionWaitlistUpdate, entry.PatientID, models.DiffRecords(&before, entry))
+	event.Details = fmt.Sprintf("waitlist=%d", entry.ID)
+	removed, err := s.waitlistRepo.Remove(entry, event)
+	if err != nil {
+		return nil, err
+	}
+	if !removed {
+		return nil, ErrWaitlistEntryNotWaiting
+	}
+
+	return entry, nil
+}
+
+// GetOffers lists slot offers newest first and records which patients were
+// listed
+func (s *WaitlistService) GetOffers(accessor PatientAccessor, filter models.SlotOfferFilter) ([]models.SlotOffer, error) {
+	offers, err := s.waitlistRepo.FindOffers(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(offers))
+	for i, offer := range offers {
+		ids[i] = fmt.Sprint(offer.PatientID)
+	}
+	event := newAuditEvent(accessor, models.AuditActionSearch, 0, nil)
+	event.Details = fmt.Sprintf("slot offers doctor=%d patients=[%s]", filter.DoctorID, strings.Join(ids, ","))
+	if err := s.auditRepo.Append(event); err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// AcceptOffer books the slot of an open offer for its patient. The booking,
+// the offer and the waitlist entry are stored together, so either all of
+// them change or none.
+func (s *WaitlistService) AcceptOffer(accessor PatientAccessor, id uint) (*models.Appointment, error) {
+	offer, err := s.findOffer(accessor, id, models.AuditActionAppointmentCreate)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if offer.Status != models.SlotOfferStatusPending || !offer.ExpiresAt.After(now) {
+		return nil, ErrSlotOfferClosed
+	}
+	onLeave, err := s.scheduleService.isOnLeave(offer.DoctorID, s.scheduleService.dayOf(offer.StartTime))
+	if err != nil {
+		return nil, err
+	}
+	if onLeave {
+		return nil, ErrDoctorOnLeave
+	}
+	entry, err := s.waitlistRepo.FindByID(offer.WaitlistEntryID)
+	if err != nil {
+		return nil, ErrWaitlistEntryNotFound
+	}
+
+	appointment := &models.Appointment{
+		PatientID: offer.PatientID,
+		DoctorID:  offer.DoctorID,
+		Type:      offer.Type,
+		Status:    models.AppointmentStatusBooked,
+		StartTime: offer.StartTime,
+		EndTime:   offer.EndTime,
+		Reason:    entry.Reason,
+		BookedBy:  accessor.UserID,
+	}
+	offer.Status = models.SlotOfferStatusAccepted
+	offer.RespondedAt = &now
+
+	event := newAuditEvent(accessor, models.AuditActionAppointmentCreate, offer.PatientID, models.DiffRecords(nil, appointment))
+	event.Details = fmt.Sprintf("offer=%d waitlist=%d", offer.ID, offer.WaitlistEntryID)
+	accepted, err := s.waitlistRepo.AcceptOffer(offer, appointment, event)
+	if err != nil {
+		return nil, err
+	}
+	if !accepted {
+		return nil, ErrSlotOfferClosed
+	}
+
+	return appointment, nil
+}
+
+// DeclineOffer closes an open offer, puts its patient back on the waitlist
+// and offers the slot to the next patient
+func (s *WaitlistService) DeclineOffer(accessor PatientAccessor, id uint) (*models.SlotOffer, error) {
+	offer, err := s.findOffer(accessor, id, models.AuditActionWaitlistUpdate)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Status != models.SlotOfferStatusPending {
+		return nil, ErrSlotOfferClosed
+	}
+
+	if err := s.closeOffer(accessor, offer, models.SlotOfferStatusDeclined); err != nil {
+		return nil, err
+	}
+	s.offerSlot(accessor, offer.DoctorID, offer.Type, offer.StartTime, offer.CancelledAppointmentID)
+	return offer, nil
+}
+
+// OfferFreedSlot offers the slot of a cancelled appointment to the first
+// waitlisted patient it suits. Failures are logged rather than returned, so
+// they do not undo the cancellation.
+func (s *WaitlistService) OfferFreedSlot(accessor PatientAccessor, appointment *models.Appointment) {
+	s.offerSlot(accessor, appointment.DoctorID, appointment.Type, appointment.StartTime, appointment.ID)
+}
+
+// ExpireOffers closes the offers whose hold has ended and offers their slots
+// to the next patients
+func (s *WaitlistService) ExpireOffers() error {
+	offers, err := s.waitlistRepo.FindExpiredOffers(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range offers {
+		offer := &offers[i]
+		if err := s.closeOffer(systemAccessor, offer, models.SlotOfferStatusExpired); err != nil {
+			if errors.Is(err, ErrSlotOfferClosed) {
+				continue
+			}
+			return err
+		}
+		s.offerSlot(systemAccessor, offer.DoctorID, offer.Type, offer.StartTime, offer.CancelledAppointmentID)
+	}
+	return nil
+}
+
+// StartExpiry expires offers every interval until stop is closed
+func (s *WaitlistService) StartExpiry(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := s.ExpireOffers(); err != nil {
+					log.Printf("Failed to expire slot offers: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// offerSlot offers a free slot of a doctor to the first waiting patient it
+// suits who has not been offered it yet, and queues a message telling the
+// patient. The offer holds the slot until it expires, or until the slot
+// starts if that is sooner.
+func (s *WaitlistService) offerSlot(accessor PatientAccessor, doctorID uint, appointmentType models.AppointmentType, start time.Time, cancelledID uint) {
+	if !start.After(time.Now()) {
+		return
+	}
+	entries, err := s.waitlistRepo.FindWaiting(doctorID, appointmentType, start)
+	if err != nil {
+		log.Printf("Failed to find waitlisted patients for doctor %d: %v", doctorID, err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	slot, err := s.scheduleService.freeSlot(doctorID, start)
+	if err != nil {
+		if !errors.Is(err, ErrSlotUnavailable) {
+			log.Printf("Failed to check slot of doctor %d at %s: %v", doctorID, start.Format(time.RFC3339), err)
+		}
+		return
+	}
+	local := slot.Start.In(s.scheduleService.location)
+	startClock := local.Format(models.ClockLayout)
+	endClock := slot.End.In(s.scheduleService.location).Format(models.ClockLayout)
+
+	for i := range entries {
+		entry := &entries[i]
+		if !entry.Accepts(local.Weekday(), startClock, endClock) {
+			continue
+		}
+		patient, err := s.patientRepo.FindByID(entry.PatientID)
+		if err != nil {
+			continue
+		}
+
+		expiresAt := time.Now().Add(s.offerTTL)
+		if slot.Start.Before(expiresAt) {
+			expiresAt = slot.Start
+		}
+		offer := &models.SlotOffer{
+			WaitlistEntryID:        entry.ID,
+			PatientID:              entry.PatientID,
+			DoctorID:               doctorID,
+			Type:                   appointmentType,
+			StartTime:              slot.Start,
+			EndTime:                slot.End,
+			Status:                 models.SlotOfferStatusPending,
+			ExpiresAt:              expiresAt,
+			CancelledAppointmentID: cancelledID,
+		}
+
+		event := newAuditEvent(accessor, models.AuditActionWaitlistUpdate, entry.PatientID, models.DiffRecords(nil, offer))
+		event.Details = fmt.Sprintf("waitlist=%d appointment=%d", entry.ID, cancelledID)
+		created, err := s.waitlistRepo.CreateOffer(offer, event)
+		if err != nil {
+			log.Printf("Failed to offer slot to waitlist entry %d: %v", entry.ID, err)
+			return
+		}
+		if !created {
+			continue
+		}
+
+		data := newAppointmentMessage(patient, offer.Type, offer.StartTime, s.scheduleService.location)
+		expires := offer.ExpiresAt.In(s.scheduleService.location)
+		data.HeldUntil = expires.Format(models.ClockLayout) + " on " + expires.Format("Monday 2 January")
+		data.OfferID = offer.ID
+		key := fmt.Sprintf("slot_offer:%d", offer.ID)
+		if err := s.notifications.Queue(accessor, patient, models.NotificationKindSlotOffer, key, data); err != nil {
+			log.Printf("Failed to notify patient %d of slot offer %d: %v", patient.ID, offer.ID, err)
+		}
+		return
+	}
+}
+
+// closeOffer stores an open offer as declined or expired and puts its
+// patient back on the waitlist
+func (s *WaitlistService) closeOffer(accessor PatientAccessor, offer *models.SlotOffer, status models.SlotOfferStatus) error {
+	before := *offer
+	now := time.Now()
+	offer.Status = status
+	offer.RespondedAt = &now
+
+	event := newAuditEvent(accessor, models.AuditActionWaitlistUpdate, offer.PatientID, models.DiffRecords(&before, offer))
+	event.Details = fmt.Sprintf("offer=%d waitlist=%d", offer.ID, offer.WaitlistEntryID)
+	closed, err := s.waitlistRepo.CloseOffer(offer, event)
+	if err != nil {
+		return err
+	}
+	if !closed {
+		return ErrSlotOfferClosed
+	}
+	return nil
+}
+
+// findOffer finds a slot offer of a patient the accessor may reach
+func (s *WaitlistService) findOffer(accessor PatientAccessor, id uint, action models.AuditAction) (*models.SlotOffer, error) {
+	offer, err := s.waitlistRepo.FindOfferByID(id)
+	if err != nil {
+		return nil, ErrSlotOfferNotFound
+	}
+	if err := s.patientService.CheckAccess(accessor, offer.PatientID, action); err != nil {
+		return nil, err
+	}
+	return offer, nil
+}
+
+// validateWaitlistPreferences checks the days and times of day of a
+// waitlist request
+func validateWaitlistPreferences(req models.CreateWaitlistEntryRequest) error {
+	for _, day := range req.Days {
+		if day < time.Sunday || day > time.Saturday {
+			return ErrInvalidWaitlistPreferences
+		}
+	}
+	for _, clock := range []string{req.FromTime, req.ToTime} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse(models.ClockLayout, clock); err != nil || len(clock) != len(models.ClockLayout) {
+			return ErrInvalidWaitlistPreferences
+		}
+	}
+	if req.FromTime != "" && req.ToTime != "" && req.FromTime >= req.ToTime {
+		return ErrInvalidWaitlistPreferences
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"healthcare-app/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWaitlistRepository is a mock implementation of WaitlistRepository
+type MockWaitlistRepository struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistRepository) Create(entry *models.WaitlistEntry, event *models.AuditEvent) error {
+	args := m.Called(entry, event)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistRepository) FindByID(id uint) (*models.WaitlistEntry, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) FindAll(filter models.WaitlistFilter) ([]models.PatientWaitlistEntry, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.PatientWaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) FindWaiting(doctorID uint, appointmentType models.AppointmentType, start time.Time) ([]models.WaitlistEntry, error) {
+	args := m.Called(doctorID, appointmentType, start)
+	return args.Get(0).([]models.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) Remove(entry *models.WaitlistEntry, event *models.AuditEvent) (bool, error) {
+	args := m.Called(entry, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) FindHeld(doctorID uint, from, to time.Time) ([]models.SlotOffer, error) {
+	args := m.Called(doctorID, from, to)
+	return args.Get(0).([]models.SlotOffer), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) CreateOffer(offer *models.SlotOffer, event *models.AuditEvent) (bool, error) {
+	args := m.Called(offer, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) FindOfferByID(id uint) (*models.SlotOffer, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SlotOffer), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) FindOffers(filter models.SlotOfferFilter) ([]models.SlotOffer, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.SlotOffer), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) FindExpiredOffers(before time.Time) ([]models.SlotOffer, error) {
+	args := m.Called(before)
+	return args.Get(0).([]models.SlotOffer), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) AcceptOffer(offer *models.SlotOffer, appointment *models.Appointment, event *models.AuditEvent) (bool, error) {
+	args := m.Called(offer, appointment, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) CloseOffer(offer *models.SlotOffer, event *models.AuditEvent) (bool, error) {
+	args := m.Called(offer, event)
+	return args.Bool(0), args.Error(1)
+}
+
+// newTestWaitlistService creates a WaitlistService that holds offers for an
+// hour and queues its messages with notificationRepo
+func newTestWaitlistService(waitlistRepo *MockWaitlistRepository, scheduleService *ScheduleService, patientService *PatientService, patientRepo PatientRepository, notificationRepo *MockNotificationRepository) *WaitlistService {
+	notifications := NewNotificationService(notificationRepo, new(MockAppointmentRepository), patientService, patientRepo,
+		new(MockUserRepository), &recordingNotifier{}, &recordingNotifier{}, newMockAuditRepository(), time.UTC, nil, 3)
+	return NewWaitlistService(waitlistRepo, scheduleService, patientService, patientRepo, notifications, newMockAuditRepository(), time.Hour)
+}
+
+// newTestWaitlist creates a WaitlistService for patient 1 and doctor 5,
+// whose 09:00-10:00 slots on testScheduleDate are all free
+func newTestWaitlist(waitlistRepo *MockWaitlistRepository, notificationRepo *MockNotificationRepository) *WaitlistService {
+	waitlistRepo.On("FindHeld", uint(5), mock.Anything, mock.Anything).Return([]models.SlotOffer{}, nil).Maybe()
+	mockScheduleRepo := new(MockScheduleRepository)
+	mockScheduleRepo.On("IsOnLeave", uint(5), testScheduleDate).Return(false, nil).Maybe()
+	mockScheduleRepo.On("FindException", uint(5), testScheduleDate).Return(nil, nil).Maybe()
+	mockAppointmentRepo := new(MockAppointmentRepository)
+	mockAppointmentRepo.On("FindBooked", uint(5), mock.Anything, mock.Anything).Return([]models.Appointment{}, nil).Maybe()
+	scheduleService := newTestScheduleServiceWithWaitlist(mockScheduleRepo, mockAppointmentRepo, waitlistRepo)
+
+	mockPatientRepo := new(MockPatientRepository)
+	mockPatientRepo.On("FindByID", uint(1)).Return(&models.Patient{ID: 1, Email: "patient@example.com", ContactNumber: "555-0100"}, nil)
+	patientService := NewPatientService(mockPatientRepo, new(MockCareTeamRepository), newMockAuditRepository())
+	return newTestWaitlistService(waitlistRepo, scheduleService, patientService, mockPatientRepo, notificationRepo)
+}
+
+// testOffer returns a pending offer to patient 1 of the 09:20 slot on
+// testScheduleDate from waitlist entry 2
+func testOffer() *models.SlotOffer {
+	return &models.SlotOffer{
+		ID: 4, WaitlistEntryID: 2, PatientID: 1, DoctorID: 5, Type: models.AppointmentTypeConsultation,
+		StartTime: testSlot("09:20"), EndTime: testSlot("09:40"), Status: models.SlotOfferStatusPending,
+		ExpiresAt: time.Now().Add(time.Hour), CancelledAppointmentID: 7,
+	}
+}
+
+func TestAddWaitlistEntry_InvalidPreferences(t *testing.T) {
+	service := newTestWaitlist(new(MockWaitlistRepository), newMockNotificationRepository())
+
+	for _, req := range []models.CreateWaitlistEntryRequest{
+		{Days: []time.Weekday{7}},
+		{FromTime: "9:00"},
+		{FromTime: "12:00", ToTime: "09:00"},
+	} {
+		req.PatientID, req.DoctorID, req.Type = 1, 5, models.AppointmentTypeConsultation
+		_, err := service.AddEntry(receptionistAccessor, req)
+		assert.ErrorIs(t, err, ErrInvalidWaitlistPreferences)
+	}
+}
+
+func TestOfferFreedSlot(t *testing.T) {
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindWaiting", uint(5), models.AppointmentTypeConsultation, testSlot("09:20")).Return([]models.WaitlistEntry{
+		// testScheduleDate is a Monday
+		{ID: 1, PatientID: 2, DoctorID: 5, Days: models.Weekdays{time.Tuesday}, Status: models.WaitlistStatusWaiting},
+		{ID: 2, PatientID: 1, DoctorID: 5, Days: models.Weekdays{time.Monday}, FromTime: "09:00", ToTime: "12:00", Status: models.WaitlistStatusWaiting},
+	}, nil)
+	mockWaitlistRepo.On("CreateOffer", mock.MatchedBy(func(o *models.SlotOffer) bool {
+		return o.WaitlistEntryID == 2 && o.Status == models.SlotOfferStatusPending && o.EndTime.Equal(testSlot("09:40"))
+	}), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionWaitlistUpdate && *e.PatientID == 1
+	})).Return(true, nil)
+	mockNotificationRepo := new(MockNotificationRepository)
+	mockNotificationRepo.On("FindPreference", uint(1)).Return(nil, nil)
+	mockNotificationRepo.On("Create", mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Kind == models.NotificationKindSlotOffer && n.Recipient == "patient@example.com" &&
+			strings.Contains(n.Body, "Monday 2 March 2099 at 09:20")
+	}), mock.Anything).Return(true, nil)
+
+	service := newTestWaitlist(mockWaitlistRepo, mockNotificationRepo)
+
+	service.OfferFreedSlot(receptionistAccessor, &models.Appointment{
+		ID: 7, PatientID: 3, DoctorID: 5, Type: models.AppointmentTypeConsultation,
+		StartTime: testSlot("09:20"), EndTime: testSlot("09:40"),
+	})
+
+	mockWaitlistRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+}
+
+func TestAcceptOffer(t *testing.T) {
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindOfferByID", uint(4)).Return(testOffer(), nil)
+	mockWaitlistRepo.On("FindByID", uint(2)).Return(&models.WaitlistEntry{ID: 2, PatientID: 1, Reason: "Earlier review"}, nil)
+	mockWaitlistRepo.On("AcceptOffer", mock.MatchedBy(func(o *models.SlotOffer) bool {
+		return o.Status == models.SlotOfferStatusAccepted && o.RespondedAt != nil
+	}), mock.AnythingOfType("*models.Appointment"), mock.MatchedBy(func(e *models.AuditEvent) bool {
+		return e.Action == models.AuditActionAppointmentCreate && e.Details == "offer=4 waitlist=2"
+	})).Return(true, nil)
+
+	service := newTestWaitlist(mockWaitlistRepo, newMockNotificationRepository())
+
+	appointment, err := service.AcceptOffer(receptionistAccessor, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.AppointmentStatusBooked, appointment.Status)
+	assert.Equal(t, testSlot("09:20"), appointment.StartTime)
+	assert.Equal(t, "Earlier review", appointment.Reason)
+	mockWaitlistRepo.AssertExpectations(t)
+}
+
+func TestAcceptOffer_Expired(t *testing.T) {
+	offer := testOffer()
+	offer.ExpiresAt = time.Now().Add(-time.Minute)
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindOfferByID", uint(4)).Return(offer, nil)
+
+	service := newTestWaitlist(mockWaitlistRepo, newMockNotificationRepository())
+
+	_, err := service.AcceptOffer(receptionistAccessor, 4)
+
+	assert.ErrorIs(t, err, ErrSlotOfferClosed)
+	mockWaitlistRepo.AssertNotCalled(t, "AcceptOffer", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeclineOffer_OffersNextPatient(t *testing.T) {
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindOfferByID", uint(4)).Return(testOffer(), nil)
+	mockWaitlistRepo.On("CloseOffer", mock.MatchedBy(func(o *models.SlotOffer) bool {
+		return o.ID == 4 && o.Status == models.SlotOfferStatusDeclined
+	}), mock.Anything).Return(true, nil)
+	// Entry 2 has been offered this slot, so it is not listed again
+	mockWaitlistRepo.On("FindWaiting", uint(5), models.AppointmentTypeConsultation, testSlot("09:20")).Return([]models.WaitlistEntry{
+		{ID: 3, PatientID: 1, DoctorID: 5, Status: models.WaitlistStatusWaiting},
+	}, nil)
+	mockWaitlistRepo.On("CreateOffer", mock.MatchedBy(func(o *models.SlotOffer) bool {
+		return o.WaitlistEntryID == 3 && o.CancelledAppointmentID == 7
+	}), mock.Anything).Return(true, nil)
+
+	service := newTestWaitlist(mockWaitlistRepo, newMockNotificationRepository())
+
+	offer, err := service.DeclineOffer(receptionistAccessor, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SlotOfferStatusDeclined, offer.Status)
+	mockWaitlistRepo.AssertExpectations(t)
+}
+
+func TestExpireOffers(t *testing.T) {
+	offer := testOffer()
+	offer.ExpiresAt = time.Now().Add(-time.Minute)
+	mockWaitlistRepo := new(MockWaitlistRepository)
+	mockWaitlistRepo.On("FindExpiredOffers", mock.Anything).Return([]models.SlotOffer{*offer}, nil)
+	mockWaitlistRepo.On("CloseOffer", mock.MatchedBy(func(o *models.SlotOffer) bool {
+		return o.ID == 4 && o.Status == models.SlotOfferStatusExpired
+	}), mock.Anything).Return(true, nil)
+	mockWaitlistRepo.On("FindWaiting", uint(5), models.AppointmentTypeConsultation, testSlot("09:20")).Return([]models.WaitlistEntry{}, nil)
+
+	service := newTestWaitlist(mockWaitlistRepo, newMockNotificationRepository())
+
+	err := service.ExpireOffers()
+
+	assert.NoError(t, err)
+	mockWaitlistRepo.AssertExpectations(t)
+}
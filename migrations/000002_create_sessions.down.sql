@@ -0,0 +1,7 @@
+-- Drop refresh tokens table and its indexes
+DROP INDEX IF EXISTS idx_refresh_tokens_session_id;
+DROP TABLE IF EXISTS refresh_tokens;
+
+-- Drop sessions table and its indexes
+DROP INDEX IF EXISTS idx_sessions_user_id;
+DROP TABLE IF EXISTS sessions;
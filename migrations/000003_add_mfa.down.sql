@@ -0,0 +1,11 @@
+-- Drop role policies table
+DROP TABLE IF EXISTS role_policies;
+
+-- Drop recovery codes table and its indexes
+DROP INDEX IF EXISTS idx_recovery_codes_user_id;
+DROP TABLE IF EXISTS recovery_codes;
+
+-- Drop TOTP columns from users
+ALTER TABLE users DROP COLUMN IF EXISTS totp_last_step;
+ALTER TABLE users DROP COLUMN IF EXISTS totp_secret;
+ALTER TABLE users DROP COLUMN IF EXISTS mfa_enabled;
@@ -0,0 +1,2 @@
+-- Drop login attempts table
+DROP TABLE IF EXISTS login_attempts;
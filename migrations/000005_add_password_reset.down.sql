@@ -0,0 +1,6 @@
+-- Drop password reset tokens table and its indexes
+DROP INDEX IF EXISTS idx_password_reset_tokens_user_id;
+DROP TABLE IF EXISTS password_reset_tokens;
+
+-- Drop forced password change flag from users
+ALTER TABLE users DROP COLUMN IF EXISTS must_change_password;
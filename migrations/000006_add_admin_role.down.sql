@@ -0,0 +1,8 @@
+-- Remove admin users before restoring the original role constraint
+DELETE FROM users WHERE role = 'admin';
+
+DROP INDEX IF EXISTS idx_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS deactivated_at;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('receptionist', 'doctor'));
@@ -0,0 +1,17 @@
+-- Allow the admin role
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('admin', 'receptionist', 'doctor'));
+
+-- Add deactivation timestamp to users
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_users_role ON users(role);
+
+-- Insert a default admin user with the same example password as the seeded users
+INSERT INTO users (name, email, password, role) 
+VALUES (
+    'Administrator', 
+    'admin@example.com', 
+    '$2a$14$ajq8Q7fbtFRQvXpdCq7Jcuy.Rx1h/L4J60Otx.gyNLbAYctGMJ9tK', 
+    'admin'
+) ON CONFLICT DO NOTHING;
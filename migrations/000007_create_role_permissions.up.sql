@@ -0,0 +1,18 @@
+-- Create role permissions table
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(50) NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role, permission)
+);
+
+-- Insert the default permission sets
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'users:manage'),
+    ('receptionist', 'patients:read'),
+    ('receptionist', 'patients:write'),
+    ('receptionist', 'patients:delete'),
+    ('doctor', 'patients:read'),
+    ('doctor', 'patients:write'),
+    ('doctor', 'medical:write')
+ON CONFLICT DO NOTHING;
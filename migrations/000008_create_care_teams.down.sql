@@ -0,0 +1,12 @@
+-- Revoke the care team permissions
+DELETE FROM role_permissions WHERE permission IN ('patients:all', 'patients:override', 'care_team:manage');
+
+-- Drop access overrides table and its indexes
+DROP INDEX IF EXISTS idx_access_overrides_patient_id;
+DROP INDEX IF EXISTS idx_access_overrides_user_id;
+DROP TABLE IF EXISTS access_overrides;
+
+-- Drop care team assignments table and its indexes
+DROP INDEX IF EXISTS idx_care_team_assignments_clinician_id;
+DROP INDEX IF EXISTS idx_care_team_assignments_patient_id;
+DROP TABLE IF EXISTS care_team_assignments;
@@ -0,0 +1,35 @@
+-- Create care team assignments table
+CREATE TABLE IF NOT EXISTS care_team_assignments (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id) ON DELETE CASCADE,
+    clinician_id INTEGER NOT NULL REFERENCES users(id),
+    role VARCHAR(20) NOT NULL CHECK (role IN ('primary', 'consulting')),
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE,
+    assigned_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_care_team_assignments_patient_id ON care_team_assignments(patient_id);
+CREATE INDEX idx_care_team_assignments_clinician_id ON care_team_assignments(clinician_id);
+
+-- Create access overrides table
+CREATE TABLE IF NOT EXISTS access_overrides (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    action VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_access_overrides_user_id ON access_overrides(user_id);
+CREATE INDEX idx_access_overrides_patient_id ON access_overrides(patient_id);
+
+-- Grant the care team permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('receptionist', 'patients:all'),
+    ('receptionist', 'care_team:manage'),
+    ('doctor', 'patients:override')
+ON CONFLICT DO NOTHING;
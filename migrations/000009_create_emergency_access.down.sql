@@ -0,0 +1,10 @@
+-- Revoke the break-the-glass and compliance permissions
+DELETE FROM role_permissions WHERE permission IN ('patients:emergency', 'audit:read');
+
+-- Drop emergency access grants table, its trigger and its indexes
+DROP TRIGGER IF EXISTS emergency_access_grants_append_only ON emergency_access_grants;
+DROP FUNCTION IF EXISTS prevent_emergency_access_change();
+DROP INDEX IF EXISTS idx_emergency_access_grants_created_at;
+DROP INDEX IF EXISTS idx_emergency_access_grants_patient_id;
+DROP INDEX IF EXISTS idx_emergency_access_grants_user_id;
+DROP TABLE IF EXISTS emergency_access_grants;
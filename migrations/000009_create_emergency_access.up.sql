@@ -0,0 +1,30 @@
+-- Create emergency access grants table
+CREATE TABLE IF NOT EXISTS emergency_access_grants (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    reason TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_emergency_access_grants_user_id ON emergency_access_grants(user_id);
+CREATE INDEX idx_emergency_access_grants_patient_id ON emergency_access_grants(patient_id);
+CREATE INDEX idx_emergency_access_grants_created_at ON emergency_access_grants(created_at);
+
+-- Make the emergency access log append-only
+CREATE OR REPLACE FUNCTION prevent_emergency_access_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'emergency_access_grants is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER emergency_access_grants_append_only
+    BEFORE UPDATE OR DELETE ON emergency_access_grants
+    FOR EACH ROW EXECUTE FUNCTION prevent_emergency_access_change();
+
+-- Grant the break-the-glass and compliance permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('doctor', 'patients:emergency'),
+    ('admin', 'audit:read')
+ON CONFLICT DO NOTHING;
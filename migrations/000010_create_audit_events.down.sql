@@ -0,0 +1,9 @@
+-- Drop audit events table, its trigger and its indexes
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+DROP FUNCTION IF EXISTS prevent_audit_event_change();
+DROP INDEX IF EXISTS idx_audit_events_created_at;
+DROP INDEX IF EXISTS idx_audit_events_action;
+DROP INDEX IF EXISTS idx_audit_events_patient_id;
+DROP INDEX IF EXISTS idx_audit_events_actor_id;
+DROP TABLE IF EXISTS audit_events;
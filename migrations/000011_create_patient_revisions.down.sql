@@ -0,0 +1,4 @@
+-- Drop patient revisions table and its indexes
+DROP INDEX IF EXISTS idx_patient_revisions_created_at;
+DROP INDEX IF EXISTS idx_patient_revisions_patient_revision;
+DROP TABLE IF EXISTS patient_revisions;
@@ -0,0 +1,22 @@
+-- Create patient revisions table
+CREATE TABLE IF NOT EXISTS patient_revisions (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    revision INTEGER NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    changed_by INTEGER NOT NULL,
+    changed_by_role VARCHAR(50),
+    changes JSONB,
+    snapshot JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_patient_revisions_patient_revision ON patient_revisions(patient_id, revision);
+CREATE INDEX idx_patient_revisions_created_at ON patient_revisions(created_at);
+
+-- Record the current state of existing patients as their first revision
+INSERT INTO patient_revisions (patient_id, revision, action, changed_by, snapshot, created_at)
+SELECT id, 1, 'snapshot', registered_by, to_jsonb(patients) - 'deleted_at', updated_at
+FROM patients
+WHERE deleted_at IS NULL
+ON CONFLICT DO NOTHING;
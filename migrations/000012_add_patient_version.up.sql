@@ -0,0 +1,2 @@
+-- Add version column for optimistic concurrency control on patients
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
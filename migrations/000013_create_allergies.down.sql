@@ -0,0 +1,5 @@
+-- Drop allergies table and its indexes
+DROP INDEX IF EXISTS idx_allergies_deleted_at;
+DROP INDEX IF EXISTS idx_allergies_status;
+DROP INDEX IF EXISTS idx_allergies_patient_id;
+DROP TABLE IF EXISTS allergies;
@@ -0,0 +1,28 @@
+-- Create allergies table
+CREATE TABLE IF NOT EXISTS allergies (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    substance TEXT NOT NULL,
+    substance_code VARCHAR(100),
+    substance_system VARCHAR(255),
+    category VARCHAR(20) CHECK (category IN ('', 'food', 'medication', 'environment', 'biologic')),
+    criticality VARCHAR(20) NOT NULL CHECK (criticality IN ('low', 'high', 'unable-to-assess')),
+    reactions JSONB NOT NULL DEFAULT '[]',
+    onset TIMESTAMP WITH TIME ZONE,
+    note TEXT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'inactive', 'resolved')),
+    recorded_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_allergies_patient_id ON allergies(patient_id);
+CREATE INDEX idx_allergies_status ON allergies(status);
+CREATE INDEX idx_allergies_deleted_at ON allergies(deleted_at);
+
+-- Preserve the free-text allergies as unstructured entries
+INSERT INTO allergies (patient_id, substance, criticality, status, note, recorded_by, created_at, updated_at)
+SELECT id, allergies, 'unable-to-assess', 'active', 'Migrated from the free-text allergies field', registered_by, updated_at, updated_at
+FROM patients
+WHERE deleted_at IS NULL AND TRIM(COALESCE(allergies, '')) <> '';
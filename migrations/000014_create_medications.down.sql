@@ -0,0 +1,6 @@
+-- Drop medications table and its indexes
+DROP INDEX IF EXISTS idx_medications_deleted_at;
+DROP INDEX IF EXISTS idx_medications_status;
+DROP INDEX IF EXISTS idx_medications_drug_name;
+DROP INDEX IF EXISTS idx_medications_patient_id;
+DROP TABLE IF EXISTS medications;
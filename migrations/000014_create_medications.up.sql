@@ -0,0 +1,34 @@
+-- Create medications table
+CREATE TABLE IF NOT EXISTS medications (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('statement', 'prescription')),
+    drug_name TEXT NOT NULL,
+    drug_code VARCHAR(100),
+    dose VARCHAR(255),
+    route VARCHAR(100),
+    frequency VARCHAR(255),
+    duration VARCHAR(255),
+    instructions TEXT,
+    prescriber_id INTEGER REFERENCES users(id),
+    prescriber_name VARCHAR(255),
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    stop_date TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'stopped', 'completed')),
+    stop_reason TEXT,
+    recorded_by INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_medications_patient_id ON medications(patient_id);
+CREATE INDEX idx_medications_drug_name ON medications(drug_name);
+CREATE INDEX idx_medications_status ON medications(status);
+CREATE INDEX idx_medications_deleted_at ON medications(deleted_at);
+
+-- Preserve the free-text current medication as unstructured statements
+INSERT INTO medications (patient_id, kind, drug_name, start_date, status, instructions, recorded_by, created_at, updated_at)
+SELECT id, 'statement', current_medication, updated_at, 'active', 'Migrated from the free-text current medication field', registered_by, updated_at, updated_at
+FROM patients
+WHERE deleted_at IS NULL AND TRIM(COALESCE(current_medication, '')) <> '';
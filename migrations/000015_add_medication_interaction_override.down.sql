@@ -0,0 +1,2 @@
+-- Drop the interaction override reason of prescriptions
+ALTER TABLE medications DROP COLUMN IF EXISTS interaction_override_reason;
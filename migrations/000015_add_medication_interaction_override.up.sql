@@ -0,0 +1,2 @@
+-- Record why a prescription was issued despite a high-severity interaction
+ALTER TABLE medications ADD COLUMN IF NOT EXISTS interaction_override_reason TEXT;
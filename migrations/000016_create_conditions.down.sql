@@ -0,0 +1,6 @@
+-- Drop conditions table and its indexes
+DROP INDEX IF EXISTS idx_conditions_deleted_at;
+DROP INDEX IF EXISTS idx_conditions_clinical_status;
+DROP INDEX IF EXISTS idx_conditions_code;
+DROP INDEX IF EXISTS idx_conditions_patient_id;
+DROP TABLE IF EXISTS conditions;
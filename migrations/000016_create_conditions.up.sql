@@ -0,0 +1,20 @@
+-- Create conditions table
+CREATE TABLE IF NOT EXISTS conditions (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    code VARCHAR(10) NOT NULL,
+    description TEXT NOT NULL,
+    clinical_status VARCHAR(20) NOT NULL CHECK (clinical_status IN ('active', 'recurrence', 'relapse', 'inactive', 'remission', 'resolved')),
+    onset_date TIMESTAMP WITH TIME ZONE,
+    resolution_date TIMESTAMP WITH TIME ZONE,
+    note TEXT,
+    diagnosed_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_conditions_patient_id ON conditions(patient_id);
+CREATE INDEX idx_conditions_code ON conditions(code varchar_pattern_ops);
+CREATE INDEX idx_conditions_clinical_status ON conditions(clinical_status);
+CREATE INDEX idx_conditions_deleted_at ON conditions(deleted_at);
@@ -0,0 +1,3 @@
+-- Drop vitals table and its indexes
+DROP INDEX IF EXISTS idx_vitals_patient_type_time;
+DROP TABLE IF EXISTS vitals;
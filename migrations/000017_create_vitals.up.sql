@@ -0,0 +1,14 @@
+-- Create vitals table
+CREATE TABLE IF NOT EXISTS vitals (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('bp', 'pulse', 'temperature', 'spo2', 'height', 'weight', 'bmi')),
+    value DOUBLE PRECISION NOT NULL,
+    diastolic DOUBLE PRECISION,
+    unit VARCHAR(20) NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    recorded_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_vitals_patient_type_time ON vitals(patient_id, type, recorded_at);
@@ -0,0 +1,17 @@
+-- Revoke the encounter permissions
+DELETE FROM role_permissions WHERE permission IN ('encounters:manage');
+
+-- Detach clinical records from encounters
+DROP INDEX IF EXISTS idx_medications_encounter_id;
+DROP INDEX IF EXISTS idx_conditions_encounter_id;
+DROP INDEX IF EXISTS idx_vitals_encounter_id;
+ALTER TABLE medications DROP COLUMN IF EXISTS encounter_id;
+ALTER TABLE conditions DROP COLUMN IF EXISTS encounter_id;
+ALTER TABLE vitals DROP COLUMN IF EXISTS encounter_id;
+
+-- Drop encounters table and its indexes
+DROP INDEX IF EXISTS idx_encounters_deleted_at;
+DROP INDEX IF EXISTS idx_encounters_status;
+DROP INDEX IF EXISTS idx_encounters_doctor_id;
+DROP INDEX IF EXISTS idx_encounters_patient_id;
+DROP TABLE IF EXISTS encounters;
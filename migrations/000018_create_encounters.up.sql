@@ -0,0 +1,36 @@
+-- Create encounters table
+CREATE TABLE IF NOT EXISTS encounters (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('outpatient', 'emergency', 'follow-up')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('planned', 'arrived', 'in-progress', 'finished', 'cancelled')),
+    planned_start TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    chief_complaint TEXT,
+    location VARCHAR(255),
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_encounters_patient_id ON encounters(patient_id);
+CREATE INDEX idx_encounters_doctor_id ON encounters(doctor_id);
+CREATE INDEX idx_encounters_status ON encounters(status);
+CREATE INDEX idx_encounters_deleted_at ON encounters(deleted_at);
+
+-- Attach clinical records to the encounter they were recorded in
+ALTER TABLE vitals ADD COLUMN IF NOT EXISTS encounter_id INTEGER REFERENCES encounters(id);
+ALTER TABLE conditions ADD COLUMN IF NOT EXISTS encounter_id INTEGER REFERENCES encounters(id);
+ALTER TABLE medications ADD COLUMN IF NOT EXISTS encounter_id INTEGER REFERENCES encounters(id);
+
+CREATE INDEX idx_vitals_encounter_id ON vitals(encounter_id);
+CREATE INDEX idx_conditions_encounter_id ON conditions(encounter_id);
+CREATE INDEX idx_medications_encounter_id ON medications(encounter_id);
+
+-- Grant the encounter permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('receptionist', 'encounters:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,16 @@
+-- Revoke the clinical note permissions
+DELETE FROM role_permissions WHERE permission IN ('notes:read');
+
+-- Drop the signed note lock
+DROP TRIGGER IF EXISTS note_addenda_append_only ON note_addenda;
+DROP TRIGGER IF EXISTS clinical_notes_lock_signed ON clinical_notes;
+DROP FUNCTION IF EXISTS prevent_signed_note_change();
+
+-- Drop note addenda table and its indexes
+DROP INDEX IF EXISTS idx_note_addenda_note_id;
+DROP TABLE IF EXISTS note_addenda;
+
+-- Drop clinical notes table and its indexes
+DROP INDEX IF EXISTS idx_clinical_notes_encounter_id;
+DROP INDEX IF EXISTS idx_clinical_notes_patient_id;
+DROP TABLE IF EXISTS clinical_notes;
@@ -0,0 +1,57 @@
+-- Create clinical notes table
+CREATE TABLE IF NOT EXISTS clinical_notes (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    encounter_id INTEGER NOT NULL REFERENCES encounters(id),
+    author_id INTEGER NOT NULL REFERENCES users(id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'signed')),
+    subjective TEXT,
+    objective TEXT,
+    assessment TEXT,
+    plan TEXT,
+    signed_by INTEGER REFERENCES users(id),
+    signed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (status = 'draft' OR (signed_by IS NOT NULL AND signed_at IS NOT NULL))
+);
+
+CREATE INDEX idx_clinical_notes_patient_id ON clinical_notes(patient_id);
+CREATE INDEX idx_clinical_notes_encounter_id ON clinical_notes(encounter_id);
+
+-- Create note addenda table
+CREATE TABLE IF NOT EXISTS note_addenda (
+    id SERIAL PRIMARY KEY,
+    note_id INTEGER NOT NULL REFERENCES clinical_notes(id),
+    author_id INTEGER NOT NULL REFERENCES users(id),
+    body TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_note_addenda_note_id ON note_addenda(note_id);
+
+-- Lock signed notes and their addenda
+CREATE OR REPLACE FUNCTION prevent_signed_note_change() RETURNS trigger AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'note_addenda' OR OLD.status = 'signed' THEN
+        RAISE EXCEPTION 'signed clinical notes and addenda cannot be changed';
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER clinical_notes_lock_signed
+    BEFORE UPDATE OR DELETE ON clinical_notes
+    FOR EACH ROW EXECUTE FUNCTION prevent_signed_note_change();
+
+CREATE TRIGGER note_addenda_append_only
+    BEFORE UPDATE OR DELETE ON note_addenda
+    FOR EACH ROW EXECUTE FUNCTION prevent_signed_note_change();
+
+-- Grant the clinical note permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('doctor', 'notes:read')
+ON CONFLICT DO NOTHING;
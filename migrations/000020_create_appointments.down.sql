@@ -0,0 +1,21 @@
+-- Revoke the appointment permissions
+DELETE FROM role_permissions WHERE permission IN ('appointments:manage');
+
+-- Drop appointments table and its indexes
+ALTER TABLE IF EXISTS appointments DROP CONSTRAINT IF EXISTS appointments_no_double_booking;
+DROP INDEX IF EXISTS idx_appointments_status;
+DROP INDEX IF EXISTS idx_appointments_doctor_start;
+DROP INDEX IF EXISTS idx_appointments_patient_id;
+DROP TABLE IF EXISTS appointments;
+
+-- Drop doctor_leave table and its indexes
+DROP INDEX IF EXISTS idx_doctor_leave_doctor_id;
+DROP TABLE IF EXISTS doctor_leave;
+
+-- Drop schedule_exceptions table and its indexes
+DROP INDEX IF EXISTS idx_schedule_exceptions_doctor_date;
+DROP TABLE IF EXISTS schedule_exceptions;
+
+-- Drop working_hours table and its indexes
+DROP INDEX IF EXISTS idx_working_hours_doctor_id;
+DROP TABLE IF EXISTS working_hours;
@@ -0,0 +1,76 @@
+-- Create working_hours table
+CREATE TABLE IF NOT EXISTS working_hours (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    slot_minutes INTEGER NOT NULL CHECK (slot_minutes > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_time > start_time)
+);
+
+CREATE INDEX idx_working_hours_doctor_id ON working_hours(doctor_id);
+
+-- Create schedule_exceptions table; an exception without hours closes the day
+CREATE TABLE IF NOT EXISTS schedule_exceptions (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    date DATE NOT NULL,
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    slot_minutes INTEGER,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_schedule_exceptions_doctor_date ON schedule_exceptions(doctor_id, date);
+
+-- Create doctor_leave table; end_date is the last day of the leave
+CREATE TABLE IF NOT EXISTS doctor_leave (
+    id SERIAL PRIMARY KEY,
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_doctor_leave_doctor_id ON doctor_leave(doctor_id);
+
+-- Create appointments table
+CREATE TABLE IF NOT EXISTS appointments (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('consultation', 'follow-up', 'procedure')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('booked', 'cancelled')),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT,
+    reschedule_reason TEXT,
+    cancellation_reason TEXT,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    booked_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_time > start_time)
+);
+
+CREATE INDEX idx_appointments_patient_id ON appointments(patient_id);
+CREATE INDEX idx_appointments_doctor_start ON appointments(doctor_id, start_time);
+CREATE INDEX idx_appointments_status ON appointments(status);
+
+-- Reject overlapping bookings of the same doctor, however they are written
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE appointments ADD CONSTRAINT appointments_no_double_booking
+    EXCLUDE USING gist (doctor_id WITH =, tstzrange(start_time, end_time) WITH &&)
+    WHERE (status = 'booked');
+
+-- Grant the appointment permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('receptionist', 'appointments:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,8 @@
+-- Detach appointments from series
+DROP INDEX IF EXISTS idx_appointments_series_id;
+ALTER TABLE appointments DROP COLUMN IF EXISTS series_id;
+
+-- Drop appointment_series table and its indexes
+DROP INDEX IF EXISTS idx_appointment_series_doctor_id;
+DROP INDEX IF EXISTS idx_appointment_series_patient_id;
+DROP TABLE IF EXISTS appointment_series;
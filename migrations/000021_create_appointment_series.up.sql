@@ -0,0 +1,24 @@
+-- Create appointment_series table
+CREATE TABLE IF NOT EXISTS appointment_series (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('consultation', 'follow-up', 'procedure')),
+    rule TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('booked', 'cancelled')),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT,
+    cancellation_reason TEXT,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_appointment_series_patient_id ON appointment_series(patient_id);
+CREATE INDEX idx_appointment_series_doctor_id ON appointment_series(doctor_id);
+
+-- Attach appointments to the series they were booked in
+ALTER TABLE appointments ADD COLUMN IF NOT EXISTS series_id INTEGER REFERENCES appointment_series(id);
+
+CREATE INDEX idx_appointments_series_id ON appointments(series_id);
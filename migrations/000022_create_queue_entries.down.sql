@@ -0,0 +1,9 @@
+-- Revoke the queue permissions
+DELETE FROM role_permissions WHERE permission IN ('queue:read', 'queue:manage');
+
+-- Drop queue_entries table and its indexes
+DROP INDEX IF EXISTS idx_queue_entries_open_patient;
+DROP INDEX IF EXISTS idx_queue_entries_appointment_id;
+DROP INDEX IF EXISTS idx_queue_entries_patient_id;
+DROP INDEX IF EXISTS idx_queue_entries_doctor_date;
+DROP TABLE IF EXISTS queue_entries;
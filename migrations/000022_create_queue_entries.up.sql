@@ -0,0 +1,30 @@
+-- Create queue_entries table
+CREATE TABLE IF NOT EXISTS queue_entries (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    appointment_id INTEGER REFERENCES appointments(id),
+    date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('waiting', 'called', 'in-consultation', 'done')),
+    checked_in_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    called_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    done_at TIMESTAMP WITH TIME ZONE,
+    checked_in_by INTEGER NOT NULL REFERENCES users(id),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_queue_entries_doctor_date ON queue_entries(doctor_id, date);
+CREATE INDEX idx_queue_entries_patient_id ON queue_entries(patient_id);
+CREATE INDEX idx_queue_entries_appointment_id ON queue_entries(appointment_id);
+
+-- A patient can be in only one queue at a time on a day
+CREATE UNIQUE INDEX idx_queue_entries_open_patient ON queue_entries(patient_id, date) WHERE status <> 'done';
+
+-- Grant the queue permissions
+INSERT INTO role_permissions (role, permission) VALUES
+    ('receptionist', 'queue:read'),
+    ('receptionist', 'queue:manage'),
+    ('doctor', 'queue:read'),
+    ('doctor', 'queue:manage')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,13 @@
+-- Drop slot_offers table and its indexes
+DROP INDEX IF EXISTS idx_slot_offers_pending_slot;
+DROP INDEX IF EXISTS idx_slot_offers_status;
+DROP INDEX IF EXISTS idx_slot_offers_doctor_start;
+DROP INDEX IF EXISTS idx_slot_offers_patient_id;
+DROP INDEX IF EXISTS idx_slot_offers_waitlist_entry_id;
+DROP TABLE IF EXISTS slot_offers;
+
+-- Drop waitlist_entries table and its indexes
+DROP INDEX IF EXISTS idx_waitlist_entries_status;
+DROP INDEX IF EXISTS idx_waitlist_entries_doctor_type;
+DROP INDEX IF EXISTS idx_waitlist_entries_patient_id;
+DROP TABLE IF EXISTS waitlist_entries;
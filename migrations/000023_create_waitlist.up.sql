@@ -0,0 +1,45 @@
+-- Create waitlist_entries table
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('consultation', 'follow-up', 'procedure')),
+    days JSONB NOT NULL DEFAULT '[]',
+    from_time VARCHAR(5),
+    to_time VARCHAR(5),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('waiting', 'offered', 'booked', 'removed')),
+    reason TEXT,
+    created_by INTEGER NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_waitlist_entries_patient_id ON waitlist_entries(patient_id);
+CREATE INDEX idx_waitlist_entries_doctor_type ON waitlist_entries(doctor_id, type);
+CREATE INDEX idx_waitlist_entries_status ON waitlist_entries(status);
+
+-- Create slot_offers table
+CREATE TABLE IF NOT EXISTS slot_offers (
+    id SERIAL PRIMARY KEY,
+    waitlist_entry_id INTEGER NOT NULL REFERENCES waitlist_entries(id),
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    doctor_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('consultation', 'follow-up', 'procedure')),
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'accepted', 'declined', 'expired')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    cancelled_appointment_id INTEGER NOT NULL REFERENCES appointments(id),
+    appointment_id INTEGER REFERENCES appointments(id),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_slot_offers_waitlist_entry_id ON slot_offers(waitlist_entry_id);
+CREATE INDEX idx_slot_offers_patient_id ON slot_offers(patient_id);
+CREATE INDEX idx_slot_offers_doctor_start ON slot_offers(doctor_id, start_time);
+CREATE INDEX idx_slot_offers_status ON slot_offers(status);
+
+-- A slot is held for one patient at a time
+CREATE UNIQUE INDEX idx_slot_offers_pending_slot ON slot_offers(doctor_id, start_time) WHERE status = 'pending';
@@ -0,0 +1,8 @@
+-- Drop contact_preferences table
+DROP TABLE IF EXISTS contact_preferences;
+
+-- Drop notifications table and its indexes
+DROP INDEX IF EXISTS idx_notifications_event_key;
+DROP INDEX IF EXISTS idx_notifications_status_next_attempt;
+DROP INDEX IF EXISTS idx_notifications_patient_id;
+DROP TABLE IF EXISTS notifications;
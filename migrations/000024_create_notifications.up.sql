@@ -0,0 +1,35 @@
+-- Create notifications table
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    patient_id INTEGER NOT NULL REFERENCES patients(id),
+    kind VARCHAR(50) NOT NULL CHECK (kind IN ('appointment_reminder', 'slot_offer')),
+    event_key VARCHAR(255) NOT NULL,
+    channel VARCHAR(10) CHECK (channel IN ('', 'email', 'sms')),
+    recipient VARCHAR(255),
+    subject VARCHAR(255),
+    body TEXT,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'sent', 'failed', 'suppressed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_patient_id ON notifications(patient_id);
+CREATE INDEX idx_notifications_status_next_attempt ON notifications(status, next_attempt_at);
+
+-- Each event is notified once
+CREATE UNIQUE INDEX idx_notifications_event_key ON notifications(event_key);
+
+-- Create contact_preferences table
+CREATE TABLE IF NOT EXISTS contact_preferences (
+    patient_id INTEGER PRIMARY KEY REFERENCES patients(id),
+    preferred_channel VARCHAR(10) CHECK (preferred_channel IN ('', 'email', 'sms')),
+    email_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    sms_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    reminders_opt_out BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by INTEGER REFERENCES users(id),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
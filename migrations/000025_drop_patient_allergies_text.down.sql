@@ -0,0 +1,9 @@
+-- Restore the free-text allergies column from the active structured allergies
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS allergies TEXT;
+
+UPDATE patients p
+SET allergies = (
+    SELECT STRING_AGG(a.substance, ', ' ORDER BY a.id)
+    FROM allergies a
+    WHERE a.patient_id = p.id AND a.status = 'active' AND a.deleted_at IS NULL
+);
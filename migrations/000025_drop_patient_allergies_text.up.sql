@@ -0,0 +1,12 @@
+-- Preserve free-text allergies written since they were first migrated
+INSERT INTO allergies (patient_id, substance, criticality, status, note, recorded_by, created_at, updated_at)
+SELECT p.id, p.allergies, 'unable-to-assess', 'active', 'Migrated from the free-text allergies field', p.registered_by, p.updated_at, p.updated_at
+FROM patients p
+WHERE p.deleted_at IS NULL AND TRIM(COALESCE(p.allergies, '')) <> ''
+AND NOT EXISTS (
+    SELECT 1 FROM allergies a
+    WHERE a.patient_id = p.id AND a.substance = p.allergies AND a.deleted_at IS NULL
+);
+
+-- The structured allergies are the only record of a patient's allergies
+ALTER TABLE patients DROP COLUMN IF EXISTS allergies;
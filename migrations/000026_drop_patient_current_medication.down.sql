@@ -0,0 +1,9 @@
+-- Restore the free-text current medication column from the active medications
+ALTER TABLE patients ADD COLUMN IF NOT EXISTS current_medication TEXT;
+
+UPDATE patients p
+SET current_medication = (
+    SELECT STRING_AGG(m.drug_name, ', ' ORDER BY m.id)
+    FROM medications m
+    WHERE m.patient_id = p.id AND m.status = 'active' AND m.deleted_at IS NULL
+);
@@ -0,0 +1,12 @@
+-- Preserve free-text current medication written since it was first migrated
+INSERT INTO medications (patient_id, kind, drug_name, start_date, status, instructions, recorded_by, created_at, updated_at)
+SELECT p.id, 'statement', p.current_medication, p.updated_at, 'active', 'Migrated from the free-text current medication field', p.registered_by, p.updated_at, p.updated_at
+FROM patients p
+WHERE p.deleted_at IS NULL AND TRIM(COALESCE(p.current_medication, '')) <> ''
+AND NOT EXISTS (
+    SELECT 1 FROM medications m
+    WHERE m.patient_id = p.id AND m.drug_name = p.current_medication AND m.deleted_at IS NULL
+);
+
+-- The structured medications are the only record of a patient's medication
+ALTER TABLE patients DROP COLUMN IF EXISTS current_medication;
@@ -0,0 +1,2 @@
+-- Drop the interaction warnings of medications
+ALTER TABLE medications DROP COLUMN IF EXISTS interaction_warnings;
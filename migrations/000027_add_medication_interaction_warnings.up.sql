@@ -0,0 +1,2 @@
+-- Keep the interaction warnings shown when a medication was prescribed
+ALTER TABLE medications ADD COLUMN IF NOT EXISTS interaction_warnings JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,4 @@
+-- Drop the appointment link of notifications
+DROP INDEX IF EXISTS idx_notifications_appointment_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS appointment_start;
+ALTER TABLE notifications DROP COLUMN IF EXISTS appointment_id;
@@ -0,0 +1,6 @@
+-- Link appointment reminders to the booking they are about, so they are not
+-- sent once the appointment is cancelled or moved
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS appointment_id INTEGER REFERENCES appointments(id);
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS appointment_start TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_appointment_id ON notifications(appointment_id);
@@ -0,0 +1,2 @@
+-- Drop the message data of notifications
+ALTER TABLE notifications DROP COLUMN IF EXISTS message_data;
@@ -0,0 +1,3 @@
+-- Keep what each notification was rendered from, so it can be rendered again
+-- for another channel when it is delivered
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS message_data JSONB;